@@ -0,0 +1,204 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+const attachmentManifestFileName = "attachments.json"
+
+// attachmentManifest lists the files mirrored with --attachments-only
+type attachmentManifest struct {
+	Attachments []attachmentEntry `json:"attachments"`
+}
+
+type attachmentEntry struct {
+	PageID    string `json:"pageId"`
+	PageTitle string `json:"pageTitle"`
+	FileName  string `json:"fileName"`
+	Path      string `json:"path"` // relative to the output directory
+	MediaType string `json:"mediaType,omitempty"`
+	Size      int64  `json:"size"`
+	Version   int    `json:"version,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// attachmentDir returns the directory that mirrors a page's attachments, named after its markdown file
+func attachmentDir(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+}
+
+// mirrorPageAttachments downloads every attachment of the page into dir, with paths fitted into the path limits.
+// Failed downloads are recorded in the returned entries rather than aborting the page.
+func mirrorPageAttachments(client confluence.Client, page *confluenceModel.ConfluencePage, dir, outputDir string, opts *resolvedOptions) ([]attachmentEntry, error) {
+	if len(page.Attachments) == 0 {
+		return nil, nil
+	}
+
+	entries := make([]attachmentEntry, 0, len(page.Attachments))
+	for i := range page.Attachments {
+		attachment := &page.Attachments[i]
+		filePath := opts.ShortenedPaths.shortenBelow(opts.PathLimits, outputDir, filepath.Join(dir, sanitizeAttachmentName(attachment.Title)))
+		if err := outputFS.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			return entries, fmt.Errorf("failed to create attachment directory: %w", err)
+		}
+
+		entry := attachmentEntry{
+			PageID:    page.ID,
+			PageTitle: page.Title,
+			FileName:  attachment.Title,
+			MediaType: attachment.MediaType,
+			Size:      attachment.FileSize,
+			Version:   attachment.Version,
+		}
+		if rel, err := filepath.Rel(outputDir, filePath); err == nil {
+			entry.Path = filepath.ToSlash(rel)
+		}
+
+		if err := streamAttachment(client, attachment, filePath); err != nil {
+			fmt.Printf("  ❌ Failed to mirror %s: %v\n", attachment.Title, err)
+			entry.Error = err.Error()
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// streamAttachment copies the attachment into filePath without holding it in memory, removing a partial file on failure
+func streamAttachment(client confluence.Client, attachment *confluenceModel.ConfluenceAttachment, filePath string) error {
+	file, err := outputFS.Create(filePath, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", filePath, err)
+	}
+	_, err = client.DownloadAttachmentTo(attachment, file, 0, attachmentProgress(attachment))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = outputFS.Remove(filePath)
+		return err
+	}
+	return nil
+}
+
+// largeAttachmentBytes is the size above which mirroring prints download progress
+const largeAttachmentBytes = 100 * 1024 * 1024
+
+// attachmentProgress prints every 25% of a large attachment's download, or returns nil for small ones
+func attachmentProgress(attachment *confluenceModel.ConfluenceAttachment) confluence.ProgressFunc {
+	if attachment.FileSize < largeAttachmentBytes {
+		return nil
+	}
+	reported := int64(0)
+	return func(written, total int64) {
+		if total <= 0 {
+			return
+		}
+		if percent := written * 100 / total; percent >= reported+25 {
+			reported = percent - percent%25
+			fmt.Printf("  ⏬ %s: %d%% of %d MiB\n", attachment.Title, reported, total/(1024*1024))
+		}
+	}
+}
+
+// sanitizeAttachmentName keeps the attachment's file name but strips path separators
+func sanitizeAttachmentName(name string) string {
+	name = strings.NewReplacer("/", "_", "\\", "_").Replace(name)
+	if name == "" || name == "." || name == ".." {
+		return "attachment"
+	}
+	return name
+}
+
+// writeAttachmentManifest writes the mirrored attachments as indented JSON into the output directory
+func writeAttachmentManifest(outputDir string, entries []attachmentEntry) (string, error) {
+	manifest := attachmentManifest{Attachments: entries}
+	if manifest.Attachments == nil {
+		manifest.Attachments = []attachmentEntry{}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode attachment manifest: %w", err)
+	}
+
+	path := filepath.Join(outputDir, attachmentManifestFileName)
+	if err := outputFS.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write attachment manifest: %w", err)
+	}
+	return path, nil
+}
+
+// mirrorTreeAttachments mirrors the attachments of every page in the tree, keeping the page hierarchy
+func mirrorTreeAttachments(client confluence.Client, node *PageNode, outputDir string, opts *TreeOptions, entries []attachmentEntry) []attachmentEntry {
+	if node == nil {
+		return entries
+	}
+
+	page, err := client.GetPage(node.ID)
+	if err != nil {
+		fmt.Printf("  ❌ Failed to fetch %s: %v\n", node.Title, err)
+	} else if outputPath, err := getOutputPath(node, page, outputDir, &opts.resolvedOptions); err != nil {
+		fmt.Printf("  ❌ Failed to resolve output path for %s: %v\n", node.Title, err)
+	} else {
+		fmt.Printf("📎 Mirroring %d attachments: %s\n", len(page.Attachments), node.Title)
+		pageEntries, err := mirrorPageAttachments(client, page, attachmentDir(outputPath), opts.OutputDir, &opts.resolvedOptions)
+		if err != nil {
+			fmt.Printf("  ❌ %v\n", err)
+		}
+		entries = append(entries, pageEntries...)
+	}
+
+	for _, child := range node.Children {
+		entries = mirrorTreeAttachments(client, child, outputDir, opts, entries)
+	}
+	return entries
+}
+
+// performTreeAttachmentMirror mirrors the attachments of all trees and writes the manifest
+func performTreeAttachmentMirror(client confluence.Client, trees []*PageNode, opts *TreeOptions) error {
+	var entries []attachmentEntry
+	perSpace := opts.SpaceDirs || spansMultipleSpaces(trees)
+	for _, tree := range trees {
+		outputDir := opts.OutputDir
+		if perSpace && tree.SpaceKey != "" {
+			outputDir = filepath.Join(outputDir, sanitizeSpaceDir(tree.SpaceKey))
+		}
+		entries = mirrorTreeAttachments(client, tree, outputDir, opts, entries)
+	}
+
+	return finishAttachmentMirror(opts.OutputDir, entries)
+}
+
+// finishAttachmentMirror writes the manifest and summarizes the mirrored attachments
+func finishAttachmentMirror(outputDir string, entries []attachmentEntry) error {
+	manifestPath, err := writeAttachmentManifest(outputDir, entries)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, entry := range entries {
+		if entry.Error != "" {
+			failed++
+		}
+	}
+
+	fmt.Printf("✅ Attachments mirrored!\n")
+	fmt.Printf("  Successful: %d files\n", len(entries)-failed)
+	if failed > 0 {
+		fmt.Printf("  Failed: %d files\n", failed)
+	}
+	fmt.Printf("  Manifest: %s\n", manifestPath)
+
+	if failed > 0 {
+		return fmt.Errorf("attachment mirroring completed with errors")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"path/filepath"
+
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// plannedOutputPath returns where the job's page would be written, planned from the tree before the page is fetched
+func plannedOutputPath(job *treeJob, opts *resolvedOptions) (string, bool) {
+	relPath, err := plannedRelativePath(job.node, nodePage(job.node), job.outputDir, opts)
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(job.outputDir, relPath), true
+}
+
+// keepPreviousExport returns the unchanged result for a page last exported to path at the given version, or nil
+// when it must be converted: the version must be current and the path the one the page is written to now.
+// Pages with inlined children are always converted, since the children's versions are not recorded.
+func keepPreviousExport(job *treeJob, opts *resolvedOptions, version int, path string) *PageConversionResult {
+	if len(job.inline) > 0 || version != job.node.Version {
+		return nil
+	}
+	if planned, ok := plannedOutputPath(job, opts); !ok || filepath.Clean(path) != planned {
+		return nil
+	}
+	return &PageConversionResult{
+		PageID:     job.node.ID,
+		Title:      job.node.Title,
+		OutputPath: path,
+		Unchanged:  true,
+	}
+}
+
+// unchangedResult returns the result for a page whose file at its planned path carries the page's ID and current
+// version in its frontmatter, or nil when it must be converted
+func unchangedResult(job *treeJob, opts *resolvedOptions) *PageConversionResult {
+	path, ok := plannedOutputPath(job, opts)
+	if !ok {
+		return nil
+	}
+	content, err := outputFS.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	doc, err := convModel.ParseMarkdownDocument(string(content))
+	if err != nil || doc.Frontmatter.Confluence.PageID != job.node.ID {
+		return nil
+	}
+	return keepPreviousExport(job, opts, doc.Frontmatter.Confluence.Version, path)
+}
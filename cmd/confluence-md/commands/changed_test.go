@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestKeepPreviousExport(t *testing.T) {
+	planned := filepath.Join("out", "home", "guide.md")
+
+	tests := []struct {
+		name    string
+		version int
+		path    string
+		inline  bool
+		want    bool
+	}{
+		{"current version at the planned path", 3, planned, false, true},
+		{"unclean path to the planned file", 3, filepath.Join("out", "home", ".", "guide.md"), false, true},
+		{"version bumped", 2, planned, false, false},
+		{"moved path", 3, filepath.Join("out", "home", "old-guide.md"), false, false},
+		{"inlined children", 3, planned, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := addTestPage(nil, "1", "Home")
+			page := addTestPage(root, "2", "Guide")
+			page.Version = 3
+			job := &treeJob{node: page, outputDir: "out"}
+			if tt.inline {
+				// Inlined children's versions are not recorded, so their edits would be missed
+				job.inline = []*PageNode{addTestPage(page, "3", "Leaf")}
+			}
+
+			result := keepPreviousExport(job, &resolvedOptions{}, tt.version, tt.path)
+			if (result != nil) != tt.want {
+				t.Fatalf("keepPreviousExport() = %+v, want kept %v", result, tt.want)
+			}
+			if result != nil && (!result.Unchanged || result.PageID != "2" || result.OutputPath != tt.path) {
+				t.Errorf("result = %+v, want the unchanged page at %s", result, tt.path)
+			}
+		})
+	}
+}
+
+func TestUnchangedResult(t *testing.T) {
+	planned := filepath.Join("out", "home", "guide.md")
+
+	tests := []struct {
+		name    string
+		pageID  string // page ID in the frontmatter, empty for no file
+		version int
+		want    bool
+	}{
+		{"file at the current version", "2", 3, true},
+		{"no file", "", 0, false},
+		{"older version", "2", 2, false},
+		{"file of another page", "7", 3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := useMemOutput(t)
+			if tt.pageID != "" {
+				writeExportedPage(t, mem, planned, tt.pageID, tt.version)
+			}
+			root := addTestPage(nil, "1", "Home")
+			page := addTestPage(root, "2", "Guide")
+			page.Version = 3
+
+			result := unchangedResult(&treeJob{node: page, outputDir: "out"}, &resolvedOptions{})
+			if (result != nil) != tt.want {
+				t.Fatalf("unchangedResult() = %+v, want kept %v", result, tt.want)
+			}
+		})
+	}
+}
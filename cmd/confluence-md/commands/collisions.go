@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// pathCollision is a page renamed because its output file or directory matched an earlier page's
+type pathCollision struct {
+	Node        *PageNode
+	Path        string    // path the page is written to instead
+	ClashesWith *PageNode // page that kept the name
+}
+
+// resolvePathCollisions gives pages whose output file or child directory matches an earlier page's,
+// ignoring case as macOS and Windows do, a -<page ID> suffix on both. Jobs are visited in tree order,
+// so the first page keeps its name and the result is the same on every export.
+func resolvePathCollisions(jobs []*treeJob, opts *resolvedOptions) []pathCollision {
+	claimed := make(map[string]*PageNode)
+	var collisions []pathCollision
+
+	for _, job := range jobs {
+		node := job.node
+		keys, path := plannedPathKeys(node, job.outputDir, opts)
+
+		var clash *PageNode
+		for _, key := range keys {
+			if owner := claimed[key]; owner != nil && owner.ID != node.ID {
+				clash = owner
+				break
+			}
+		}
+		if clash != nil {
+			node.Disambiguator = node.ID
+			keys, path = plannedPathKeys(node, job.outputDir, opts)
+			collisions = append(collisions, pathCollision{Node: node, Path: path, ClashesWith: clash})
+		}
+
+		for _, key := range keys {
+			if claimed[key] == nil {
+				claimed[key] = node
+			}
+		}
+	}
+	return collisions
+}
+
+// plannedPathKeys returns the case-folded output file of a node and, when it has children, the directory
+// they are written to, along with the output file path
+func plannedPathKeys(node *PageNode, outputDir string, opts *resolvedOptions) ([]string, string) {
+	relPath, err := plannedRelativePath(node, nodePage(node), outputDir, opts)
+	if err != nil {
+		return nil, ""
+	}
+	path := filepath.Join(outputDir, relPath)
+	keys := []string{strings.ToLower(path)}
+
+	if len(node.Children) > 0 {
+		child := node.Children[0]
+		if childPath, err := plannedRelativePath(child, nodePage(child), outputDir, opts); err == nil {
+			keys = append(keys, strings.ToLower(filepath.Dir(filepath.Join(outputDir, childPath)))+string(filepath.Separator))
+		}
+	}
+	return keys, path
+}
+
+// nodePage builds the page fields known from the tree, used to plan paths before the page is fetched
+func nodePage(node *PageNode) *confluenceModel.ConfluencePage {
+	page := &confluenceModel.ConfluencePage{ID: node.ID, Title: node.Title, SpaceKey: node.SpaceKey, Version: node.Version}
+	for _, label := range node.Labels {
+		page.Metadata.Labels = append(page.Metadata.Labels, confluenceModel.Label{Name: label})
+	}
+	return page
+}
+
+// ancestorDirTitles returns the titles naming the node's ancestor directories, with the
+// disambiguators of renamed ancestors appended
+func ancestorDirTitles(node *PageNode) []string {
+	if len(node.Path) <= 1 {
+		return nil
+	}
+	ancestors := append([]string(nil), node.Path[:len(node.Path)-1]...)
+	parent := node.Parent
+	for i := len(ancestors) - 1; i >= 0 && parent != nil; i-- {
+		if parent.Disambiguator != "" {
+			ancestors[i] += " " + parent.Disambiguator
+		}
+		parent = parent.Parent
+	}
+	return ancestors
+}
+
+// withDisambiguator inserts -<suffix> before the file extension
+func withDisambiguator(relPath, suffix string) string {
+	ext := filepath.Ext(relPath)
+	return strings.TrimSuffix(relPath, ext) + "-" + suffix + ext
+}
+
+// printPathCollisions lists the pages renamed to avoid overwriting each other
+func printPathCollisions(collisions []pathCollision) {
+	if len(collisions) == 0 {
+		return
+	}
+	fmt.Printf("⚠️  Renamed %d pages whose output path matched another page's (ignoring case):\n", len(collisions))
+	for _, collision := range collisions {
+		fmt.Printf("    %s (%s) → %s, clashed with %s (%s)\n",
+			collision.Node.Title, collision.Node.ID, collision.Path, collision.ClashesWith.Title, collision.ClashesWith.ID)
+	}
+}
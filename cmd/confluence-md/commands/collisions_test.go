@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// addTestPage adds a page below parent, or a root page when parent is nil
+func addTestPage(parent *PageNode, id, title string) *PageNode {
+	node := &PageNode{ID: id, Title: title, Version: 1, Path: []string{title}, Parent: parent}
+	if parent != nil {
+		node.Path = append(append([]string(nil), parent.Path...), title)
+		node.Level = parent.Level + 1
+		parent.Children = append(parent.Children, node)
+	}
+	return node
+}
+
+// treeOrderJobs returns one job per page in depth-first order, all written below out
+func treeOrderJobs(nodes ...*PageNode) []*treeJob {
+	var jobs []*treeJob
+	for _, node := range nodes {
+		jobs = append(jobs, &treeJob{order: len(jobs), node: node, outputDir: "out"})
+	}
+	return jobs
+}
+
+func TestResolvePathCollisions(t *testing.T) {
+	tests := []struct {
+		name  string
+		jobs  func() []*treeJob
+		want  map[string]string // renamed page ID to the path it is written to instead
+		clash map[string]string // renamed page ID to the ID of the page that kept the name
+	}{
+		{
+			name: "distinct titles",
+			jobs: func() []*treeJob {
+				root := addTestPage(nil, "1", "Home")
+				return treeOrderJobs(root, addTestPage(root, "2", "Guide"), addTestPage(root, "3", "Setup"))
+			},
+		},
+		{
+			name: "titles differing only in case",
+			jobs: func() []*treeJob {
+				root := addTestPage(nil, "1", "Home")
+				return treeOrderJobs(root, addTestPage(root, "2", "Guide"), addTestPage(root, "3", "GUIDE"))
+			},
+			want:  map[string]string{"3": filepath.Join("out", "home", "guide-3.md")},
+			clash: map[string]string{"3": "2"},
+		},
+		{
+			name: "first page in tree order keeps the name",
+			jobs: func() []*treeJob {
+				root := addTestPage(nil, "1", "Home")
+				later := addTestPage(root, "9", "FAQ")
+				earlier := addTestPage(root, "5", "faq")
+				return treeOrderJobs(root, later, earlier)
+			},
+			want:  map[string]string{"5": filepath.Join("out", "home", "faq-5.md")},
+			clash: map[string]string{"5": "9"},
+		},
+		{
+			name: "colliding parents keep their children apart",
+			jobs: func() []*treeJob {
+				root := addTestPage(nil, "1", "Home")
+				first := addTestPage(root, "2", "API")
+				firstChild := addTestPage(first, "3", "Auth")
+				second := addTestPage(root, "4", "api")
+				secondChild := addTestPage(second, "5", "Auth")
+				return treeOrderJobs(root, first, firstChild, second, secondChild)
+			},
+			want:  map[string]string{"4": filepath.Join("out", "home", "api-4.md")},
+			clash: map[string]string{"4": "2"},
+		},
+		{
+			name: "page listed twice is not a collision",
+			jobs: func() []*treeJob {
+				root := addTestPage(nil, "1", "Home")
+				page := addTestPage(root, "2", "Guide")
+				return treeOrderJobs(root, page, page)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jobs := tt.jobs()
+			collisions := resolvePathCollisions(jobs, &resolvedOptions{})
+			if len(collisions) != len(tt.want) {
+				t.Fatalf("got %d collisions %+v, want %d", len(collisions), collisions, len(tt.want))
+			}
+			for _, collision := range collisions {
+				if want := tt.want[collision.Node.ID]; collision.Path != want {
+					t.Errorf("page %s renamed to %q, want %q", collision.Node.ID, collision.Path, want)
+				}
+				if want := tt.clash[collision.Node.ID]; collision.ClashesWith.ID != want {
+					t.Errorf("page %s clashes with %s, want %s", collision.Node.ID, collision.ClashesWith.ID, want)
+				}
+			}
+
+			// Every page now has its own output path
+			seen := make(map[string]string)
+			for _, job := range jobs {
+				path, ok := plannedOutputPath(job, &resolvedOptions{})
+				if !ok {
+					t.Fatalf("no planned path for page %s", job.node.ID)
+				}
+				if owner, taken := seen[path]; taken && owner != job.node.ID {
+					t.Errorf("pages %s and %s are both written to %s", owner, job.node.ID, path)
+				}
+				seen[path] = job.node.ID
+			}
+		})
+	}
+}
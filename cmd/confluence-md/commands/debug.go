@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/spf13/cobra"
+)
+
+// debugCmd groups tools for investigating conversion problems
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Tools for investigating conversion problems",
+}
+
+// debugRenderCmd writes every stage of a page's conversion for bug reports
+var debugRenderCmd = &cobra.Command{
+	Use:   "render <page-url>",
+	Short: "Write a page's storage HTML, preprocessed HTML and Markdown side by side",
+	Long: `Convert a single page and write each stage of the conversion into
+<output>/<page-id>/, so a conversion bug can be reported or reproduced as a fixture:
+
+  storage.html       the page's storage format as returned by the API
+  preprocessed.html  the HTML handed to the Markdown converter
+  output.md          the final Markdown
+
+The conversion flags of the page command apply, so the result matches an export.
+
+Examples:
+  confluence-md debug render https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --output ./debug`,
+	RunE: runDebugRender,
+}
+
+var debugRenderOpts PageOptions
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugRenderCmd)
+
+	debugRenderOpts.authOptions.InitFlags(debugRenderCmd)
+	debugRenderOpts.commonOptions.InitFlags(debugRenderCmd)
+}
+
+func runDebugRender(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: page URL")
+	}
+
+	pageInfo, err := urlToPageInfo(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid Confluence URL: %w", err)
+	}
+	if err := debugRenderOpts.resolve(debugRenderOpts.commonOptions); err != nil {
+		return err
+	}
+
+	client := confluence.NewClient(pageInfo.BaseURL, debugRenderOpts.APIKey, clientOptions()...)
+	pageIDs, err := resolvePageIDs(client, []confluenceModel.PageURLInfo{pageInfo})
+	if err != nil {
+		return err
+	}
+	page, err := client.GetPage(pageIDs[0])
+	if err != nil {
+		return fmt.Errorf("failed to get page: %w", err)
+	}
+
+	dir := filepath.Join(debugRenderOpts.OutputDir, page.ID)
+	conv := converter.NewConverter(client, buildConverterOptions(debugRenderOpts)...)
+	written, err := conv.RenderDebug(page, converter.WriteOptions{
+		BaseURL:     pageInfo.BaseURL,
+		OutputDir:   dir,
+		Frontmatter: debugRenderOpts.IncludeMetadata,
+	}, dir)
+	if err != nil {
+		return fmt.Errorf("failed to render page: %w", err)
+	}
+
+	fmt.Printf("🔍 Conversion stages of %s:\n", page.Title)
+	for _, path := range written {
+		fmt.Printf("  %s\n", path)
+	}
+	return nil
+}
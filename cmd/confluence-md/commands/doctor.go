@@ -0,0 +1,299 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// doctorProbeFile is written and removed again to test the output directory
+	doctorProbeFile = ".confluence-md-doctor"
+
+	// rateLimitWarnRatio warns when less than this share of the rate limit is left
+	rateLimitWarnRatio = 0.1
+)
+
+// DoctorOptions contains all options for the doctor command
+type DoctorOptions struct {
+	authOptions
+
+	OutputDir string // Directory whose write permission is checked
+}
+
+var doctorOpts DoctorOptions
+
+// doctorCmd checks the environment and the Confluence instance before a first export
+var doctorCmd = &cobra.Command{
+	Use:   "doctor <base-url>",
+	Short: "Check connectivity, authentication and the output directory",
+	Long: `Diagnose common setup problems before running an export: whether Confluence
+is reachable, whether the API token is accepted with the chosen authentication
+mode (Bearer, or Basic with --email), which REST API versions are available, how
+much of the rate limit is left, and whether the output directory is writable.
+Every failed check prints a suggested fix.
+
+Examples:
+  # Check a Confluence Cloud site with an Atlassian API token
+  confluence-md doctor https://example.atlassian.net/wiki --email me@example.com --api-token your-api-token
+
+  # Check a Data Center instance with a personal access token and output directory
+  confluence-md doctor https://confluence.example.com --api-token your-token --output ./docs`,
+	RunE: runDoctorCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorOpts.authOptions.InitFlags(doctorCmd)
+	doctorCmd.Flags().StringVarP(&doctorOpts.OutputDir, "output", "o", "./output", "Output directory to check for write permission")
+}
+
+// doctorStatus is the outcome of a single check
+type doctorStatus int
+
+const (
+	doctorOK doctorStatus = iota
+	doctorWarn
+	doctorFail
+)
+
+// doctorCheck is a check result with a suggested fix for warnings and failures
+type doctorCheck struct {
+	Name   string
+	Status doctorStatus
+	Detail string
+	Fix    string
+}
+
+func runDoctorCommand(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: Confluence base URL")
+	}
+	baseURL := strings.TrimSuffix(args[0], "/")
+
+	client := confluence.NewClient(baseURL, doctorOpts.APIKey, clientOptions()...)
+	fmt.Printf("🩺 Checking %s\n", baseURL)
+
+	checks := runDoctorChecks(client, baseURL, &doctorOpts)
+	failed := 0
+	for _, check := range checks {
+		printDoctorCheck(check)
+		if check.Status == doctorFail {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d problems", failed)
+	}
+	fmt.Printf("\n✅ Everything looks good\n")
+	return nil
+}
+
+// runDoctorChecks runs every check in order, skipping the instance checks when Confluence is unreachable
+func runDoctorChecks(client confluence.Client, baseURL string, opts *DoctorOptions) []doctorCheck {
+	v1, err := client.Probe("/rest/api/space?limit=1")
+	if err != nil {
+		return []doctorCheck{{
+			Name:   "Connectivity",
+			Status: doctorFail,
+			Detail: err.Error(),
+			Fix:    "Check the base URL, DNS, VPN and proxy settings (HTTPS_PROXY)",
+		}, checkOutputDir(opts.OutputDir)}
+	}
+
+	checks := []doctorCheck{{
+		Name:   "Connectivity",
+		Detail: fmt.Sprintf("reached %s in %s", baseURL, v1.Duration.Round(time.Millisecond)),
+	}}
+
+	auth, user := checkAuthentication(client, baseURL, opts.APIKey)
+	checks = append(checks, auth)
+
+	v2, _ := client.Probe("/api/v2/spaces?limit=1")
+	checks = append(checks, checkAPIVersions(baseURL, v1, v2, user))
+	checks = append(checks, checkRateLimit(v1, v2))
+	checks = append(checks, checkOutputDir(opts.OutputDir))
+	return checks
+}
+
+// checkAuthentication verifies the token with the configured mode and, when that fails,
+// tries the other mode to suggest the right one. The user is nil unless authentication succeeded.
+func checkAuthentication(client confluence.Client, baseURL, apiToken string) (doctorCheck, *confluenceModel.ConfluenceUser) {
+	check := doctorCheck{Name: "Authentication"}
+	if apiToken == "" {
+		check.Status = doctorWarn
+		check.Detail = "no --api-token given, requests are sent anonymously"
+		check.Fix = "Pass --api-token to read pages that are not public"
+		return check, nil
+	}
+
+	mode := "Bearer token"
+	if useBasicAuth() {
+		mode = "Basic authentication as " + email
+	}
+
+	user, status := probeCurrentUser(client)
+	if user != nil {
+		check.Detail = fmt.Sprintf("authenticated as %s using %s", user.DisplayName, mode)
+		return check, user
+	}
+
+	check.Status = doctorFail
+	check.Detail = fmt.Sprintf("the token was rejected using %s (HTTP %d)", mode, status)
+	switch {
+	case !useBasicAuth() && isCloudURL(baseURL):
+		check.Fix = "Atlassian Cloud API tokens need Basic authentication: add --email with the address of the account that created the token"
+		if email != "" {
+			check.Fix = "Atlassian Cloud API tokens need Basic authentication: remove --auth-type bearer"
+		}
+	case useBasicAuth():
+		// A Data Center personal access token is only accepted as a Bearer token
+		bearer := confluence.NewClient(baseURL, apiToken, confluence.WithUserAgent(userAgent))
+		if bearerUser, _ := probeCurrentUser(bearer); bearerUser != nil {
+			check.Fix = "The token is a personal access token that works as a Bearer token: remove --email or pass --auth-type bearer"
+		} else {
+			check.Fix = "Check that --email is the account that created the token and that the token was not revoked"
+		}
+	default:
+		check.Fix = "Check that the personal access token is valid and not expired (Profile › Personal Access Tokens)"
+	}
+	return check, nil
+}
+
+// probeCurrentUser returns the authenticated user, or nil and the response status when the request
+// failed or Confluence answered as the anonymous user
+func probeCurrentUser(client confluence.Client) (*confluenceModel.ConfluenceUser, int) {
+	result, err := client.Probe("/rest/api/user/current")
+	if err != nil {
+		return nil, 0
+	}
+	if result.StatusCode != http.StatusOK {
+		return nil, result.StatusCode
+	}
+	var user confluenceModel.ConfluenceUser
+	if err := json.Unmarshal(result.Body, &user); err != nil || user.Type == "anonymous" {
+		// Cloud answers requests with an unusable token as the anonymous user
+		return nil, http.StatusUnauthorized
+	}
+	return &user, result.StatusCode
+}
+
+// checkAPIVersions reports whether the REST API v1 the exports use and the newer v2 answer below the base URL
+func checkAPIVersions(baseURL string, v1, v2 *confluence.ProbeResult, user *confluenceModel.ConfluenceUser) doctorCheck {
+	check := doctorCheck{Name: "API versions"}
+
+	switch {
+	case v1.StatusCode == http.StatusNotFound:
+		check.Status = doctorFail
+		check.Detail = "REST API v1 not found below the base URL"
+		check.Fix = "Use the Confluence base URL, e.g. https://example.atlassian.net/wiki for Cloud or the context path such as /confluence for Data Center"
+		if isCloudURL(baseURL) && !strings.HasSuffix(baseURL, "/wiki") {
+			check.Fix = "Cloud base URLs end in /wiki: try " + baseURL + "/wiki"
+		}
+		return check
+	case v1.StatusCode == http.StatusUnauthorized || v1.StatusCode == http.StatusForbidden:
+		if user == nil {
+			check.Status = doctorWarn
+			check.Detail = fmt.Sprintf("REST API v1 requires authentication (HTTP %d)", v1.StatusCode)
+			check.Fix = "Fix authentication first, the instance does not allow anonymous access"
+			return check
+		}
+	case v1.StatusCode != http.StatusOK:
+		check.Status = doctorFail
+		check.Detail = fmt.Sprintf("REST API v1 answered HTTP %d", v1.StatusCode)
+		check.Fix = "Check that the base URL points at Confluence and not at a login page or proxy"
+		return check
+	}
+
+	check.Detail = "REST API v1 available"
+	if v2 != nil && v2.StatusCode == http.StatusOK {
+		check.Detail += ", v2 available"
+	} else {
+		check.Detail += ", v2 not available (Data Center or older Cloud site; not needed for exports)"
+	}
+	return check
+}
+
+// checkRateLimit reports the lowest rate-limit headroom seen in the probe responses
+func checkRateLimit(results ...*confluence.ProbeResult) doctorCheck {
+	check := doctorCheck{Name: "Rate limit"}
+
+	var lowest *confluence.ProbeResult
+	for _, result := range results {
+		if result == nil || result.RateLimit <= 0 || result.RateLimitRemaining < 0 {
+			continue
+		}
+		if lowest == nil || result.RateLimitRemaining*lowest.RateLimit < lowest.RateLimitRemaining*result.RateLimit {
+			lowest = result
+		}
+	}
+	if lowest == nil {
+		check.Detail = "not reported by this instance"
+		return check
+	}
+
+	check.Detail = fmt.Sprintf("%d of %d requests left", lowest.RateLimitRemaining, lowest.RateLimit)
+	if float64(lowest.RateLimitRemaining) < rateLimitWarnRatio*float64(lowest.RateLimit) {
+		check.Status = doctorWarn
+		check.Fix = "Wait for the limit to reset or lower --parallel for large exports"
+		if reset := lowest.Header.Get("X-RateLimit-Reset"); reset != "" {
+			check.Fix = fmt.Sprintf("Wait for the limit to reset (%s) or lower --parallel for large exports", reset)
+		}
+	}
+	return check
+}
+
+// checkOutputDir creates the output directory if needed and writes and removes a probe file in it
+func checkOutputDir(outputDir string) doctorCheck {
+	check := doctorCheck{Name: "Output directory"}
+	fix := "Choose a writable --output directory or fix its permissions"
+
+	if err := outputFS.MkdirAll(outputDir, 0755); err != nil {
+		check.Status, check.Detail, check.Fix = doctorFail, fmt.Sprintf("failed to create %s: %v", outputDir, err), fix
+		return check
+	}
+	probe := filepath.Join(outputDir, doctorProbeFile)
+	if err := outputFS.WriteFile(probe, []byte("ok\n"), 0644); err != nil {
+		check.Status, check.Detail, check.Fix = doctorFail, fmt.Sprintf("failed to write to %s: %v", outputDir, err), fix
+		return check
+	}
+	_ = outputFS.Remove(probe)
+
+	check.Detail = outputDir + " is writable"
+	return check
+}
+
+// isCloudURL reports whether the base URL belongs to an Atlassian Cloud site
+func isCloudURL(baseURL string) bool {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	return strings.HasSuffix(host, ".atlassian.net") || strings.HasSuffix(host, ".jira.com")
+}
+
+// printDoctorCheck prints a check result and its suggested fix
+func printDoctorCheck(check doctorCheck) {
+	icon := "✅"
+	switch check.Status {
+	case doctorWarn:
+		icon = "⚠️ "
+	case doctorFail:
+		icon = "❌"
+	}
+	fmt.Printf("  %s %s: %s\n", icon, check.Name, check.Detail)
+	if check.Fix != "" {
+		fmt.Printf("     → %s\n", check.Fix)
+	}
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// duplicateJob is a later occurrence of a page that is reachable through several parents,
+// e.g. when overlapping trees are exported together
+type duplicateJob struct {
+	job       *treeJob
+	canonical *treeJob // first occurrence in tree order, which is converted
+}
+
+// splitDuplicateJobs keeps the first job of every page and returns the later ones as duplicates of it
+func splitDuplicateJobs(jobs []*treeJob) ([]*treeJob, []duplicateJob) {
+	first := make(map[string]*treeJob)
+	var unique []*treeJob
+	var duplicates []duplicateJob
+	for _, job := range jobs {
+		if canonical := first[job.node.ID]; canonical != nil {
+			duplicates = append(duplicates, duplicateJob{job: job, canonical: canonical})
+			continue
+		}
+		first[job.node.ID] = job
+		unique = append(unique, job)
+	}
+	return unique, duplicates
+}
+
+// writeDuplicateStubs writes a file at each duplicate's position linking to where the page was converted
+func writeDuplicateStubs(duplicates []duplicateJob, opts *TreeOptions, results *ConversionResults) {
+	for _, duplicate := range duplicates {
+		result := writeDuplicateStub(duplicate, opts)
+		if result.Stubbed {
+			fmt.Printf("🔗 Wrote stub for duplicate page: %s\n", result.OutputPath)
+			fmt.Printf("   Converted at: %s\n\n", duplicate.canonical.result.OutputPath)
+		} else {
+			printConversionResult(result)
+		}
+		results.record(result)
+	}
+}
+
+// writeDuplicateStub links a duplicate to the page's converted file. When that page was not
+// converted, the duplicate is skipped instead, as the first occurrence already reports the problem,
+// and so is a duplicate planned at the converted file's own path, which a stub would overwrite.
+func writeDuplicateStub(duplicate duplicateJob, opts *TreeOptions) *PageConversionResult {
+	node := duplicate.job.node
+	result := &PageConversionResult{PageID: node.ID, Title: node.Title}
+
+	canonical := duplicate.canonical.result
+	if canonical == nil || !(canonical.Success || canonical.Unchanged) || canonical.OutputPath == "" {
+		result.Skipped = true
+		result.Error = fmt.Errorf("duplicate of a page that was not exported at its first position")
+		return result
+	}
+
+	if duplicate.job.outputPath == "" {
+		page := &confluenceModel.ConfluencePage{ID: node.ID, Title: node.Title, SpaceKey: node.SpaceKey}
+		outputPath, err := getOutputPath(node, page, duplicate.job.outputDir, &opts.resolvedOptions)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+		duplicate.job.outputPath = outputPath
+	}
+	outputPath := duplicate.job.outputPath
+	if outputPath == canonical.OutputPath {
+		result.Skipped = true
+		result.Error = fmt.Errorf("duplicate is planned at the converted file's path %s", outputPath)
+		return result
+	}
+	result.OutputPath = outputPath
+
+	link, err := filepath.Rel(filepath.Dir(outputPath), canonical.OutputPath)
+	if err != nil {
+		result.Error = fmt.Errorf("failed to link duplicate stub: %w", err)
+		return result
+	}
+
+	doc := &convModel.MarkdownDocument{
+		Frontmatter: convModel.Frontmatter{
+			Title: node.Title,
+			// Without a version --changed-only keeps finding the converted file rather than this stub
+			Confluence: convModel.ConfluenceRef{PageID: node.ID, SpaceKey: node.SpaceKey},
+		},
+		Content: fmt.Sprintf("# %s\n\nThis page is also listed under another parent and was exported to %s.\n",
+			converter.EscapeLinkText(node.Title), converter.MarkdownLink(node.Title, filepath.ToSlash(link))),
+	}
+	if err := converter.SaveMarkdownDocument(outputFS, doc, outputPath, opts.IncludeMetadata); err != nil {
+		result.Error = fmt.Errorf("failed to write duplicate stub: %w", err)
+		return result
+	}
+
+	result.Stubbed = true
+	return result
+}
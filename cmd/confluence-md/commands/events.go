@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter"
+)
+
+// cliEvents prints converter progress to out. Page results are printed by
+// printConversionResult once the document is written, so page events stay silent.
+type cliEvents struct {
+	converter.NopEvents
+	out io.Writer
+}
+
+func (e cliEvents) AttachmentDownloaded(_ *confluenceModel.ConfluencePage, fileName, path string, _ int64) {
+	_, _ = fmt.Fprintf(e.out, "  🖼️  Downloaded %s to %s\n", fileName, path)
+}
+
+func (e cliEvents) Warning(page *confluenceModel.ConfluencePage, message string) {
+	if page != nil {
+		_, _ = fmt.Fprintf(e.out, "  ⚠️  Warning (%s): %s\n", page.Title, message)
+		return
+	}
+	_, _ = fmt.Fprintf(e.out, "  ⚠️  Warning: %s\n", message)
+}
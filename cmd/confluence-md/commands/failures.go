@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// failureGroup is the failed pages sharing an error category, in conversion order
+type failureGroup struct {
+	Category string
+	Pages    []*PageConversionResult
+}
+
+// failureGroups groups the failed pages by error category, largest group first
+func (r *ConversionResults) failureGroups() []failureGroup {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var groups []failureGroup
+	index := make(map[string]int)
+	for _, result := range r.Pages {
+		if !result.failed() {
+			continue
+		}
+		category := errorCategory(result.Error)
+		i, ok := index[category]
+		if !ok {
+			i = len(groups)
+			index[category] = i
+			groups = append(groups, failureGroup{Category: category})
+		}
+		groups[i].Pages = append(groups[i].Pages, result)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		return len(groups[i].Pages) > len(groups[j].Pages)
+	})
+	return groups
+}
+
+// printFailureSummary lists the failed pages by error category with their Confluence URLs
+func printFailureSummary(groups []failureGroup, baseURL string) {
+	for _, group := range groups {
+		fmt.Printf("  ❌ %s (%d):\n", group.Category, len(group.Pages))
+		for _, result := range group.Pages {
+			fmt.Printf("    - %s %s: %v\n", result.Title, failedPageURL(result, baseURL), result.Error)
+		}
+	}
+}
+
+// failedPageURL returns the page's view URL, or its ID when the URL cannot be built
+func failedPageURL(result *PageConversionResult, baseURL string) string {
+	page := &confluenceModel.ConfluencePage{ID: result.PageID}
+	pageURL, err := page.GetURL(baseURL)
+	if err != nil || result.PageID == "" {
+		return fmt.Sprintf("(page %s)", result.PageID)
+	}
+	return "(" + pageURL + ")"
+}
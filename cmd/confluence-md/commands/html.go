@@ -35,13 +35,15 @@ Examples:
 }
 
 var htmlOptions struct {
-	output      string
-	imageFolder string
+	output         string
+	imageFolder    string
+	numberHeadings bool
 }
 
 func init() {
 	htmlCmd.Flags().StringVarP(&htmlOptions.output, "output", "o", "", "Output file (default: stdout)")
 	htmlCmd.Flags().StringVar(&htmlOptions.imageFolder, "image-folder", "assets", "Folder path for images in markdown")
+	htmlCmd.Flags().BoolVar(&htmlOptions.numberHeadings, "number-headings", false, "Prefix headings with hierarchical numbers (1., 1.1, 1.1.1)")
 
 	rootCmd.AddCommand(htmlCmd)
 }
@@ -71,7 +73,15 @@ func runHTMLConvert(cmd *cobra.Command, args []string) error {
 	}
 
 	// Create converter (using nil client for HTML-only conversion)
-	conv := converter.NewConverter(nil, converter.WithDownloadAttachments(htmlOptions.imageFolder))
+	options := []converter.Option{
+		converter.WithFS(outputFS),
+		converter.WithEvents(cliEvents{out: os.Stderr}),
+		converter.WithDownloadAttachments(htmlOptions.imageFolder),
+	}
+	if htmlOptions.numberHeadings {
+		options = append(options, converter.WithNumberedHeadings())
+	}
+	conv := converter.NewConverter(nil, options...)
 
 	// Convert HTML to Markdown
 	markdown, err := conv.ConvertHTML(string(htmlContent))
@@ -84,12 +94,12 @@ func runHTMLConvert(cmd *cobra.Command, args []string) error {
 		// Create output directory if needed
 		outputDir := filepath.Dir(htmlOptions.output)
 		if outputDir != "." && outputDir != "" {
-			if err := os.MkdirAll(outputDir, 0755); err != nil {
+			if err := outputFS.MkdirAll(outputDir, 0755); err != nil {
 				return fmt.Errorf("failed to create output directory: %w", err)
 			}
 		}
 
-		if err := os.WriteFile(htmlOptions.output, []byte(markdown), 0644); err != nil {
+		if err := outputFS.WriteFile(htmlOptions.output, []byte(markdown), 0644); err != nil {
 			return fmt.Errorf("failed to write output file: %w", err)
 		}
 
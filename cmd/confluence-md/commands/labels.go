@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	"github.com/spf13/cobra"
+)
+
+// LabelsOptions contains all options for the labels command
+type LabelsOptions struct {
+	authOptions
+
+	MaxDepth int      // -1 for unlimited
+	Exclude  []string // Glob patterns to exclude
+	Examples int      // Example pages listed per label
+}
+
+var labelsOpts LabelsOptions
+
+// labelsCmd lists the labels used in page trees with page counts
+var labelsCmd = &cobra.Command{
+	Use:   "labels <page-url> [page-url...]",
+	Short: "List the labels used in a page tree",
+	Long: `List every label used by a Confluence page and its descendants, with the
+number of pages carrying it and a few example pages.
+
+Use it to design --exclude filters and tag mappings before a large export.
+
+Examples:
+  confluence-md labels https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title
+
+  # Only look at the first two levels and show up to 5 example pages per label
+  confluence-md labels https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --depth 2 --examples 5`,
+	RunE: runLabelsCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(labelsCmd)
+
+	labelsOpts.authOptions.InitFlags(labelsCmd)
+
+	labelsCmd.Flags().IntVar(&labelsOpts.MaxDepth, "depth", -1, "Maximum depth to traverse (-1 for unlimited)")
+	labelsCmd.Flags().StringSliceVar(&labelsOpts.Exclude, "exclude", []string{}, "Glob patterns to exclude pages")
+	labelsCmd.Flags().IntVar(&labelsOpts.Examples, "examples", 3, "Number of example pages listed per label")
+}
+
+// labelStat counts the pages carrying a label
+type labelStat struct {
+	Name     string
+	Pages    int
+	Examples []string
+}
+
+func runLabelsCommand(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: page URL")
+	}
+	if labelsOpts.MaxDepth < -1 {
+		return fmt.Errorf("invalid options: depth must be -1 (unlimited) or greater, got: %d", labelsOpts.MaxDepth)
+	}
+	if labelsOpts.Examples < 0 {
+		return fmt.Errorf("invalid options: examples must be 0 or greater, got: %d", labelsOpts.Examples)
+	}
+
+	pageInfos, err := urlsToPageInfos(args)
+	if err != nil {
+		return err
+	}
+
+	client := confluence.NewClient(pageInfos[0].BaseURL, labelsOpts.APIKey, clientOptions()...)
+
+	rootPageIDs, err := resolvePageIDs(client, pageInfos)
+	if err != nil {
+		return err
+	}
+
+	trees, err := fetchPageTrees(client, rootPageIDs, &TreeOptions{MaxDepth: labelsOpts.MaxDepth, Exclude: labelsOpts.Exclude})
+	if err != nil {
+		return err
+	}
+
+	stats, totalPages, unlabeled := collectLabelStats(trees, labelsOpts.Examples)
+
+	fmt.Printf("🏷️  %d labels across %d pages (%d without labels)\n\n", len(stats), totalPages, unlabeled)
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "LABEL\tPAGES\tEXAMPLES")
+	for _, stat := range stats {
+		_, _ = fmt.Fprintf(writer, "%s\t%d\t%s\n", stat.Name, stat.Pages, strings.Join(stat.Examples, ", "))
+	}
+	return writer.Flush()
+}
+
+// collectLabelStats counts label usage across the trees, most used labels first.
+// It also returns the number of pages visited and how many of them had no labels.
+func collectLabelStats(trees []*PageNode, examples int) ([]labelStat, int, int) {
+	byName := make(map[string]*labelStat)
+	totalPages, unlabeled := 0, 0
+
+	var walk func(node *PageNode)
+	walk = func(node *PageNode) {
+		if node == nil || node.Error != nil {
+			return
+		}
+		totalPages++
+		if len(node.Labels) == 0 {
+			unlabeled++
+		}
+		for _, name := range node.Labels {
+			stat, ok := byName[name]
+			if !ok {
+				stat = &labelStat{Name: name}
+				byName[name] = stat
+			}
+			stat.Pages++
+			if len(stat.Examples) < examples {
+				stat.Examples = append(stat.Examples, node.Title)
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	for _, tree := range trees {
+		walk(tree)
+	}
+
+	stats := make([]labelStat, 0, len(byName))
+	for _, stat := range byName {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Pages != stats[j].Pages {
+			return stats[i].Pages > stats[j].Pages
+		}
+		return stats[i].Name < stats[j].Name
+	})
+	return stats, totalPages, unlabeled
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	"github.com/jackchuka/confluence-md/internal/metrics"
+)
+
+// serviceMetrics are exposed on /metrics by long-running commands
+type serviceMetrics struct {
+	registry          *metrics.Registry
+	pagesConverted    *metrics.Counter
+	pageFailures      *metrics.Counter
+	apiRequests       *metrics.Counter
+	apiFailures       *metrics.Counter
+	rateLimitHits     *metrics.Counter
+	conversionSeconds *metrics.Histogram
+	apiSeconds        *metrics.Histogram
+}
+
+func newServiceMetrics() *serviceMetrics {
+	registry := metrics.NewRegistry()
+	return &serviceMetrics{
+		registry:          registry,
+		pagesConverted:    registry.Counter("confluence_md_pages_converted_total", "Pages converted successfully."),
+		pageFailures:      registry.Counter("confluence_md_page_failures_total", "Pages that failed to convert."),
+		apiRequests:       registry.Counter("confluence_md_api_requests_total", "Confluence API requests sent."),
+		apiFailures:       registry.Counter("confluence_md_api_failures_total", "Confluence API requests that failed or returned an error status."),
+		rateLimitHits:     registry.Counter("confluence_md_rate_limit_hits_total", "Confluence API requests rejected with HTTP 429."),
+		conversionSeconds: registry.Histogram("confluence_md_conversion_duration_seconds", "Time to fetch, convert and write one page.", metrics.DefaultBuckets),
+		apiSeconds:        registry.Histogram("confluence_md_api_request_duration_seconds", "Confluence API request latency.", metrics.DefaultBuckets),
+	}
+}
+
+// clientOption counts the client's API requests
+func (m *serviceMetrics) clientOption() confluence.Option {
+	return confluence.WithRequestObserver(func(_ string, status int, elapsed time.Duration) {
+		m.apiRequests.Inc()
+		m.apiSeconds.Observe(elapsed.Seconds())
+		if status == 0 || status >= http.StatusBadRequest {
+			m.apiFailures.Inc()
+		}
+		if status == http.StatusTooManyRequests {
+			m.rateLimitHits.Inc()
+		}
+	})
+}
+
+// observeConversion records one page conversion; safe to call when metrics are disabled
+func (m *serviceMetrics) observeConversion(success bool, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.conversionSeconds.Observe(elapsed.Seconds())
+	if success {
+		m.pagesConverted.Inc()
+	} else {
+		m.pageFailures.Inc()
+	}
+}
+
+// serveMetrics exposes /metrics on addr in the background
+func serveMetrics(addr string, m *serviceMetrics) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.registry.Handler())
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Printf("❌ Metrics server stopped: %v\n", err)
+		}
+	}()
+
+	fmt.Printf("📈 Serving metrics on http://%s/metrics\n", listener.Addr())
+	return nil
+}
@@ -1,29 +1,384 @@
 package commands
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	"github.com/jackchuka/confluence-md/internal/writefs"
 	"github.com/spf13/cobra"
 )
 
+// Values of --format
+const (
+	formatMarkdown = "markdown"
+	formatDocx     = "docx"
+)
+
+// macroTemplateExt is the file extension of --macro-template-dir templates
+const macroTemplateExt = ".tmpl"
+
+// outputFS receives every exported file; library users and tests can swap in another filesystem
+var outputFS writefs.FS = writefs.OS
+
 type authOptions struct {
 	APIKey string
 }
 
 func (a *authOptions) InitFlags(cmd *cobra.Command) {
-	cmd.Flags().StringVarP(&a.APIKey, "api-token", "t", "", "Confluence API token (required)")
+	cmd.Flags().StringVarP(&a.APIKey, "api-token", "t", "", "Confluence API token (omit to read public pages anonymously)")
 }
 
 type commonOptions struct {
-	DownloadImages     bool
-	ImageFolder        string
-	IncludeMetadata    bool
-	OutputDir          string
-	OutputNameTemplate string
+	DownloadImages      bool
+	DownloadEmojis      bool
+	ImageFolder         string
+	IncludeMetadata     bool
+	OutputDir           string
+	OutputNameTemplate  string
+	SlugLang            string
+	StripTitlePrefixes  []string
+	RenameTitles        []string
+	TransliterationFile string
+	CalendarEventDays   int
+	ExecuteSearch       bool
+	NumberHeadings      bool
+	SplitByHeading      string
+	SourceComments      bool
+	JiraBaseURL         string
+	RewriteLinks        []string
+	AllowLinkHosts      []string
+	DenyLinkHosts       []string
+	ReportPath          string
+	UserPlaceholder     string
+	AttachmentsOnly     bool
+	ExportTimestamp     bool
+	Provenance          bool
+	UnknownMacro        string
+	TableModeName       string
+	DialectName         string
+	FencedDivs          bool
+	InlineNoformat      int
+	Format              string
+	Pandoc              string
+	Preview             bool
+	ServePreview        string
+	RowHeaders          string
+	AnchorStyleName     string
+	HeadingIDsName      string
+	Lang                string
+	LabelsFile          string
+	Accessibility       string
+	StripMarked         bool
+	StripMarkers        []string
+	StripLabels         []string
+	RedactionsFile      string
+	LinkMapFile         string
+	DropMacros          []string
+	OnlyMacros          []string
+	MacroTemplateDir    string
+	WorkflowStatus      bool
+	WorkflowBanner      bool
+	PageTimeout         time.Duration
+	MaxPathLength       int
+	MaxSegmentLength    int
 }
 
 func (c *commonOptions) InitFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&c.DownloadImages, "download-images", true, "Download images locally")
+	cmd.Flags().BoolVar(&c.DownloadEmojis, "download-emojis", false, "Download custom emoji images into the image folder and embed them inline (requires --download-images)")
 	cmd.Flags().StringVar(&c.ImageFolder, "image-folder", "assets", "Folder for downloaded images")
 	cmd.Flags().BoolVar(&c.IncludeMetadata, "include-metadata", true, "Include YAML frontmatter")
 	cmd.Flags().StringVarP(&c.OutputDir, "output", "o", "./output", "Output directory")
-	cmd.Flags().StringVar(&c.OutputNameTemplate, "output-name-template", "", "Go template for output filename; available data: {{ .Page.* }}, {{ .SlugTitle }}, {{ .LabelNames }}")
+	cmd.Flags().StringVar(&c.OutputNameTemplate, "output-name-template", "", "Go template for output filename; available data: {{ .Page.* }}, {{ .SlugTitle }}, {{ .SpaceKey }}, {{ .LabelNames }}")
+	cmd.Flags().StringVar(&c.SlugLang, "slug-lang", "", "Language whose transliteration rules turn titles into file and directory names, e.g. de, el or tr, or auto to only follow lang-<code> page labels, which take precedence (default en)")
+	cmd.Flags().StringVar(&c.TransliterationFile, "transliteration", "", "JSON file mapping characters to their ASCII replacement in file and directory names (e.g. {\"щ\": \"shch\"}), applied before --slug-lang")
+	cmd.Flags().StringArrayVar(&c.StripTitlePrefixes, "strip-title-prefix", nil, "Remove this prefix from page titles before they become file names, frontmatter titles and navigation labels (repeatable)")
+	cmd.Flags().StringArrayVar(&c.RenameTitles, "rename-title", nil, "Rewrite page titles as regexp=replacement, applied after --strip-title-prefix; the replacement may use $1 (repeatable)")
+	cmd.Flags().IntVar(&c.MaxPathLength, "max-path-length", defaultMaxPathLength(), "Shorten output paths longer than this many characters with a hash suffix (0 for no limit; default 259 on Windows)")
+	cmd.Flags().IntVar(&c.MaxSegmentLength, "max-segment-length", 0, "Shorten file and directory names longer than this many characters with a hash suffix (0 for no limit)")
+	cmd.Flags().IntVar(&c.CalendarEventDays, "calendar-events", 0, "List Team Calendars events for this many upcoming days (0 to only link the calendar)")
+	cmd.Flags().BoolVar(&c.ExecuteSearch, "execute-search", false, "Run livesearch/search/search-results/content-by-label macro queries once and list the results")
+	cmd.Flags().BoolVar(&c.NumberHeadings, "number-headings", false, "Prefix headings with hierarchical numbers (1., 1.1, 1.1.1)")
+	cmd.Flags().StringVar(&c.SplitByHeading, "split-by-heading", "", "Split each page into one file per section at this heading level (h1 or h2) plus an index file")
+	cmd.Flags().BoolVar(&c.SourceComments, "source-comments", false, "Annotate converted macros and tables with HTML comments referencing the source page and element")
+	cmd.Flags().StringVar(&c.JiraBaseURL, "jira-base-url", "", "Jira base URL used to link issue keys from jira macros (e.g. https://jira.example.com)")
+	cmd.Flags().StringArrayVar(&c.RewriteLinks, "rewrite-link", nil, "Rewrite link URL prefixes as old-prefix=new-prefix (repeatable)")
+	cmd.Flags().StringVar(&c.LinkMapFile, "link-map", "", "CSV (source,target) or YAML (source: target) file mapping Confluence page IDs or URLs to destination URLs")
+	cmd.Flags().StringSliceVar(&c.AllowLinkHosts, "allow-link-host", nil, "Only keep external links to hosts matching these glob patterns")
+	cmd.Flags().StringSliceVar(&c.DenyLinkHosts, "deny-link-host", nil, "Strip external links to hosts matching these glob patterns (link text is kept)")
+	cmd.Flags().StringVar(&c.UserPlaceholder, "unresolved-user-placeholder", "former-user", "Name rendered as @name for mentions of deleted or anonymized users")
+	cmd.Flags().BoolVar(&c.AttachmentsOnly, "attachments-only", false, "Skip Markdown generation and mirror every page attachment to disk with an attachments.json manifest")
+	cmd.Flags().BoolVar(&c.ExportTimestamp, "export-timestamp", false, "Record the export time as exportedAt in the frontmatter (off by default so repeated exports are byte-identical)")
+	cmd.Flags().BoolVar(&c.Provenance, "provenance", false, "Record the run ID, tool version, source URL and export time under export in the frontmatter and write export-info.json")
+	cmd.Flags().StringVar(&c.UnknownMacro, "unknown-macro", "comment", "Render unsupported macros as an HTML comment, a visible warning, a raw parameter dump or not at all: comment, warn, raw or drop")
+	cmd.Flags().StringVar(&c.DialectName, "dialect", "gfm", "Markdown flavour of the output: gfm, commonmark (tables and strikethrough as HTML, task lists as ☐/☑ items) or pandoc (heading ids, anchors and table captions in Pandoc syntax)")
+	cmd.Flags().StringVar(&c.Format, "format", formatMarkdown, "Output format: markdown, or docx to also convert each written Markdown file into a Word document with pandoc, images embedded")
+	cmd.Flags().StringVar(&c.Pandoc, "pandoc", "pandoc", "pandoc command used by --format docx")
+	cmd.Flags().BoolVar(&c.Preview, "preview", false, "Also render each written Markdown file as a standalone .html beside it, with a minimal stylesheet and links between the previews")
+	cmd.Flags().StringVar(&c.ServePreview, "serve-preview", "", "After the export, serve the output directory on this address to browse the previews (implies --preview); :8080 listens on 127.0.0.1 only, use 0.0.0.0:8080 to expose it")
+	cmd.Flags().BoolVar(&c.FencedDivs, "fenced-divs", false, "Write panels, expands and page layout sections and cells as Pandoc fenced divs (::: {.info title=\"...\"}) instead of blockquotes")
+	cmd.Flags().IntVar(&c.InlineNoformat, "inline-noformat", 0, "Write noformat macros holding a single line of at most this many characters as inline code instead of a code block (0 to always use blocks)")
+	cmd.Flags().StringVar(&c.TableModeName, "table-mode", "auto", "Write tables as Markdown, as HTML, or as HTML only when cells hold nested tables or several paragraphs: auto, markdown or html")
+	cmd.Flags().StringVar(&c.RowHeaders, "row-headers", "bold", "Write tables with headers only in the first column with that column in bold, as a key-value list (two-column tables), or with the first row as header: bold, list or none")
+	cmd.Flags().StringVar(&c.AnchorStyleName, "anchor-style", "slug", "Normalize anchors and links to headings as an ASCII slug, GitHub-style ids without emoji, or GitHub-style ids keeping emoji: slug, strip or keep")
+	cmd.Flags().StringVar(&c.HeadingIDsName, "heading-ids", "none", "Keep Confluence's heading anchors as {#id} attributes or <a name> shims so old bookmarks keep working: none, attr or anchor")
+	cmd.Flags().StringVar(&c.Lang, "lang", "", "Language of generated labels such as panel titles: en, de, es, fr, ja or zh (default en)")
+	cmd.Flags().StringVar(&c.LabelsFile, "labels-file", "", "JSON file mapping label IDs (e.g. info, warning) to custom text, applied on top of --lang")
+	cmd.Flags().StringVar(&c.Accessibility, "accessibility", "", "Check converted pages for images without alt text, skipped heading levels and empty links (check), or also repair them (fix)")
+	cmd.Flags().BoolVar(&c.StripMarked, "strip-marked", false, "Remove internal content: skip pages with a --strip-label label, drop noprint macros and sections whose heading starts with a --strip-marker")
+	cmd.Flags().StringSliceVar(&c.StripMarkers, "strip-marker", converter.DefaultContentFilter.Markers, "Heading prefixes marking sections removed by --strip-marked")
+	cmd.Flags().StringSliceVar(&c.StripLabels, "strip-label", converter.DefaultContentFilter.Labels, "Page labels excluding a page from export with --strip-marked")
+	cmd.Flags().StringVar(&c.RedactionsFile, "redactions", "", "JSON file of redaction rules ([{\"name\", \"pattern\", \"replacement\"}]) applied to every written file, frontmatter included")
+	cmd.Flags().StringSliceVar(&c.DropMacros, "drop-macro", nil, "Remove these macros from the output entirely (e.g. jira,viewtracker)")
+	cmd.Flags().StringSliceVar(&c.OnlyMacros, "only-macros", nil, "Remove every macro except these from the output")
+	cmd.Flags().StringVar(&c.MacroTemplateDir, "macro-template-dir", "", "Directory of Go templates named <macro>.tmpl that render those macros, e.g. internal or vendor macros without built-in support")
+	cmd.Flags().BoolVar(&c.WorkflowStatus, "workflow-status", false, "Fetch each page's Comala Document Management state and approvers into the frontmatter")
+	cmd.Flags().BoolVar(&c.WorkflowBanner, "workflow-banner", false, "Show the Comala workflow state as a banner at the top of each page (implies --workflow-status)")
+	cmd.Flags().DurationVar(&c.PageTimeout, "page-timeout", 0, "Abandon a page whose conversion takes longer than this (e.g. 2m) and record it as failed (0 for no limit)")
+	cmd.Flags().StringVar(&c.ReportPath, "report", "", "Write a JSON conversion report (pages, errors, external links) to this file")
+}
+
+// resolvedOptions holds values derived from commonOptions once flags are parsed
+type resolvedOptions struct {
+	OutputNamer  converter.OutputNamer
+	PathNamer    converter.PathNamer
+	Slugger      plugin.Slugger // slugs file, directory and section file names
+	TitleRules   converter.TitleRules
+	SplitLevel   int
+	LinkRewrites []converter.LinkRewriteRule
+	LinkMap      converter.LinkMap
+
+	UnknownMacroMode plugin.UnknownMacroMode
+	TableMode        plugin.TableMode
+	Dialect          plugin.Dialect
+	RowHeaderStyle   plugin.RowHeaderStyle
+	AnchorStyle      plugin.AnchorStyle
+	HeadingIDStyle   plugin.HeadingIDStyle
+	Labels           plugin.Labels // nil keeps the English labels
+	MacroHandlers    map[string]plugin.MacroHandler
+	Redactions       []converter.RedactionRule
+	Run              *exportRun // nil unless --provenance is set
+	PathLimits       converter.PathLimits
+	ShortenedPaths   *shortenedPaths // nil unless a path length limit is set
+	WritePreviews    bool            // --preview or --serve-preview
+	PageContext      context.Context // deadline of the page being converted with --page-timeout, nil otherwise
+}
+
+func (r *resolvedOptions) resolve(c commonOptions) error {
+	transliteration, err := loadTransliteration(c.SlugLang, c.TransliterationFile)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	r.Slugger = plugin.DefaultSlugger
+	if transliteration != nil {
+		r.Slugger = transliteration
+	}
+
+	r.TitleRules, err = converter.ParseTitleRules(c.StripTitlePrefixes, c.RenameTitles)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	namingOptions := []converter.NamingOption{converter.WithSlugger(r.Slugger), converter.WithTitleRules(r.TitleRules)}
+	namer, err := buildOutputNamer(c.OutputNameTemplate, namingOptions...)
+	if err != nil {
+		return fmt.Errorf("invalid output name template: %w", err)
+	}
+	r.OutputNamer = namer
+	r.PathNamer = converter.HierarchyPathNamer(namer, namingOptions...)
+
+	r.SplitLevel, err = parseSplitHeading(c.SplitByHeading)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	r.LinkRewrites, err = converter.ParseLinkRewriteRules(c.RewriteLinks)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	if c.LinkMapFile != "" {
+		if r.LinkMap, err = loadLinkMap(c.LinkMapFile); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	r.UnknownMacroMode, err = plugin.ParseUnknownMacroMode(c.UnknownMacro)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	r.TableMode, err = plugin.ParseTableMode(c.TableModeName)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	r.Dialect, err = plugin.ParseDialect(c.DialectName)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+	if !r.Dialect.SupportsExtensions() && r.TableMode == plugin.TableModeMarkdown {
+		return fmt.Errorf("invalid options: --table-mode markdown needs pipe tables, which --dialect %s does not have", r.Dialect)
+	}
+
+	switch c.Format {
+	case "", formatMarkdown:
+	case formatDocx:
+		if _, err := exec.LookPath(c.Pandoc); err != nil {
+			return fmt.Errorf("invalid options: --format docx requires pandoc (https://pandoc.org/installing.html) or --pandoc: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid options: format must be markdown or docx, got: %s", c.Format)
+	}
+
+	r.WritePreviews = c.Preview || c.ServePreview != ""
+
+	r.RowHeaderStyle, err = plugin.ParseRowHeaderStyle(c.RowHeaders)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	r.AnchorStyle, err = plugin.ParseAnchorStyle(c.AnchorStyleName)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	r.HeadingIDStyle, err = plugin.ParseHeadingIDStyle(c.HeadingIDsName)
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	if c.MaxPathLength < 0 || c.MaxSegmentLength < 0 {
+		return fmt.Errorf("invalid options: max-path-length and max-segment-length must be 0 (no limit) or greater")
+	}
+	r.PathLimits = converter.PathLimits{MaxSegment: c.MaxSegmentLength, MaxPath: c.MaxPathLength}
+	if r.PathLimits.Enabled() {
+		r.ShortenedPaths = newShortenedPaths()
+	}
+
+	if c.InlineNoformat < 0 {
+		return fmt.Errorf("invalid options: inline-noformat must be 0 (always blocks) or greater, got: %d", c.InlineNoformat)
+	}
+
+	if c.PageTimeout < 0 {
+		return fmt.Errorf("invalid options: page-timeout must be 0 (no limit) or greater, got: %s", c.PageTimeout)
+	}
+
+	switch c.Accessibility {
+	case "", "check", "fix":
+	default:
+		return fmt.Errorf("invalid options: accessibility must be check or fix, got: %s", c.Accessibility)
+	}
+
+	if c.RedactionsFile != "" {
+		data, err := os.ReadFile(c.RedactionsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read redactions file: %w", err)
+		}
+		if r.Redactions, err = converter.ParseRedactionRules(data); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	if c.Provenance {
+		r.Run = newExportRun()
+	}
+
+	if c.Lang != "" || c.LabelsFile != "" {
+		if r.Labels, err = loadLabels(c.Lang, c.LabelsFile); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	if c.MacroTemplateDir != "" {
+		if r.MacroHandlers, err = loadMacroTemplates(c.MacroTemplateDir, r.Slugger); err != nil {
+			return fmt.Errorf("invalid options: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// loadMacroTemplates parses every <macro>.tmpl file in dir into a handler for that macro, slugging with slugger
+func loadMacroTemplates(dir string, slugger plugin.Slugger) (map[string]plugin.MacroHandler, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read macro template directory: %w", err)
+	}
+
+	handlers := make(map[string]plugin.MacroHandler)
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), macroTemplateExt)
+		if entry.IsDir() || !ok || name == "" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read macro template: %w", err)
+		}
+		if handlers[name], err = plugin.NewTemplateMacroHandler(name, string(data), slugger); err != nil {
+			return nil, err
+		}
+	}
+	return handlers, nil
+}
+
+// loadLabels returns the labels of lang overridden by the JSON object in labelsFile, if set
+func loadLabels(lang, labelsFile string) (plugin.Labels, error) {
+	var custom map[string]string
+	if labelsFile != "" {
+		data, err := os.ReadFile(labelsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read labels file: %w", err)
+		}
+		if err := json.Unmarshal(data, &custom); err != nil {
+			return nil, fmt.Errorf("failed to parse labels file: %w", err)
+		}
+	}
+	return plugin.LoadLabels(lang, custom)
+}
+
+// loadTransliteration returns the slug language and the custom table in tableFile, or nil for the English defaults
+func loadTransliteration(lang, tableFile string) (*converter.Transliteration, error) {
+	if lang == "" && tableFile == "" {
+		return nil, nil
+	}
+	transliteration := &converter.Transliteration{Lang: strings.ToLower(lang)}
+	if transliteration.Lang == "auto" {
+		// Pages without a language label fall back to English
+		transliteration.Lang = ""
+	}
+	if tableFile != "" {
+		data, err := os.ReadFile(tableFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transliteration table: %w", err)
+		}
+		if transliteration.Table, err = converter.ParseTransliterationTable(data); err != nil {
+			return nil, err
+		}
+	}
+	return transliteration, nil
+}
+
+// loadLinkMap reads a link map, choosing the YAML format for .yaml and .yml files and CSV otherwise
+func loadLinkMap(path string) (converter.LinkMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read link map: %w", err)
+	}
+	format := "csv"
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		format = "yaml"
+	}
+	return converter.ParseLinkMap(data, format)
 }
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// orphansDirName is the directory, relative to the space directory, that orphaned page trees are exported into
+const orphansDirName = "_orphans"
+
+// findOrphanRoots returns the IDs of the topmost space pages that are not descendants of the home page.
+// Exporting their trees covers every orphaned page once.
+func findOrphanRoots(pages []*confluenceModel.ConfluencePage, homepageID string) []string {
+	orphans := make(map[string]bool)
+	for _, page := range pages {
+		if page.ID != homepageID && !slices.Contains(page.AncestorIDs, homepageID) {
+			orphans[page.ID] = true
+		}
+	}
+
+	var roots []string
+	for _, page := range pages {
+		if !orphans[page.ID] {
+			continue
+		}
+		// A page whose parent is itself an orphan is exported as part of that parent's tree
+		if n := len(page.AncestorIDs); n > 0 && orphans[page.AncestorIDs[n-1]] {
+			continue
+		}
+		roots = append(roots, page.ID)
+	}
+	return roots
+}
+
+// exportOrphans exports the space's pages that are unreachable from the home page into <output>/_orphans
+func exportOrphans(client confluence.Client, baseURL string, space confluenceModel.ConfluenceSpace, opts *TreeOptions) (*ConversionResults, error) {
+	pages, err := client.ListSpacePages(space.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := findOrphanRoots(pages, space.HomepageID)
+	if len(roots) == 0 {
+		return &ConversionResults{}, nil
+	}
+	fmt.Printf("  🧩 Found %d orphaned page trees not reachable from the home page\n", len(roots))
+
+	orphanOpts := *opts
+	orphanOpts.OutputDir = filepath.Join(opts.OutputDir, orphansDirName)
+	if err := outputFS.MkdirAll(orphanOpts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	trees, err := fetchPageTrees(client, roots, &orphanOpts)
+	if err != nil {
+		return nil, err
+	}
+	return convertTrees(client, baseURL, trees, &orphanOpts)
+}
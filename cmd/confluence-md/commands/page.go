@@ -2,7 +2,7 @@ package commands
 
 import (
 	"fmt"
-	"os"
+	"path/filepath"
 
 	"github.com/jackchuka/confluence-md/internal/confluence"
 	"github.com/jackchuka/confluence-md/internal/converter"
@@ -38,8 +38,7 @@ var pageOpts PageOptions
 type PageOptions struct {
 	authOptions
 	commonOptions
-
-	OutputNamer converter.OutputNamer
+	resolvedOptions
 }
 
 func init() {
@@ -47,9 +46,6 @@ func init() {
 
 	pageOpts.authOptions.InitFlags(pageCmd)
 	pageOpts.commonOptions.InitFlags(pageCmd)
-
-	// Required flags
-	_ = pageCmd.MarkFlagRequired("api-token")
 }
 
 func runPage(_ *cobra.Command, args []string) error {
@@ -65,14 +61,12 @@ func runPage(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid Confluence URL: %w", err)
 	}
 
-	namer, err := buildOutputNamer(pageOpts.OutputNameTemplate)
-	if err != nil {
-		return fmt.Errorf("invalid output name template: %w", err)
+	if err := pageOpts.resolve(pageOpts.commonOptions); err != nil {
+		return err
 	}
-	pageOpts.OutputNamer = namer
 
 	// Create Confluence client
-	client := confluence.NewClient(pageInfo.BaseURL, pageOpts.APIKey)
+	client := confluence.NewClient(pageInfo.BaseURL, pageOpts.APIKey, clientOptions()...)
 
 	if pageInfo.PageID == "" {
 		pageInfo.PageID, err = client.RetrievePageID(pageInfo.SpaceKey, pageInfo.Title)
@@ -87,10 +81,22 @@ func runPage(_ *cobra.Command, args []string) error {
 	}
 
 	// Create output directory
-	if err := os.MkdirAll(pageOpts.OutputDir, 0755); err != nil {
+	if err := outputFS.MkdirAll(pageOpts.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	if pageOpts.AttachmentsOnly {
+		fileName, err := converter.GenerateFileName(page, pageOpts.OutputNamer)
+		if err != nil {
+			return fmt.Errorf("failed to generate output filename: %w", err)
+		}
+		entries, err := mirrorPageAttachments(client, page, attachmentDir(filepath.Join(pageOpts.OutputDir, fileName)), pageOpts.OutputDir, &pageOpts.resolvedOptions)
+		if err != nil {
+			return err
+		}
+		return finishAttachmentMirror(pageOpts.OutputDir, entries)
+	}
+
 	// Use shared conversion pipeline
 	result := convertSinglePage(
 		client,
@@ -102,9 +108,21 @@ func runPage(_ *cobra.Command, args []string) error {
 	// Print results
 	printConversionResult(result)
 
-	if !result.Success {
+	if pageOpts.ReportPath != "" {
+		if err := writeReport(pageOpts.ReportPath, []*PageConversionResult{result}); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	}
+
+	results := &ConversionResults{}
+	results.record(result)
+	if err := writeExportInfo(pageOpts.Run, pageOpts.OutputDir, pageInfo.BaseURL, results); err != nil {
+		return err
+	}
+
+	if !result.Success && !result.Skipped {
 		return fmt.Errorf("conversion failed: %v", result.Error)
 	}
 
-	return nil
+	return servePreview(pageOpts.ServePreview, pageOpts.OutputDir, result.PreviewPath)
 }
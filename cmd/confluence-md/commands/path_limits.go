@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/jackchuka/confluence-md/internal/converter"
+)
+
+// shortenedPathsShown is how many shortened paths the end-of-run warning lists
+const shortenedPathsShown = 20
+
+// defaultMaxPathLength applies the MAX_PATH limit by default on Windows only
+func defaultMaxPathLength() int {
+	if runtime.GOOS == "windows" {
+		return converter.WindowsMaxPath
+	}
+	return 0
+}
+
+// shortenedPaths collects the output paths shortened to fit --max-path-length and --max-segment-length
+type shortenedPaths struct {
+	mu    sync.Mutex
+	paths map[string]string // original path -> shortened path
+}
+
+func newShortenedPaths() *shortenedPaths {
+	return &shortenedPaths{paths: make(map[string]string)}
+}
+
+// shorten fits relPath below baseDir into the limits, recording it when it had to be shortened
+func (s *shortenedPaths) shorten(limits converter.PathLimits, baseDir, relPath string) string {
+	shortened, changed := limits.Shorten(baseDir, relPath)
+	if changed && s != nil {
+		s.mu.Lock()
+		s.paths[filepath.Join(baseDir, relPath)] = filepath.Join(baseDir, shortened)
+		s.mu.Unlock()
+	}
+	return shortened
+}
+
+// shortenBelow fits path, a file below baseDir, into the limits and returns the possibly shortened path
+func (s *shortenedPaths) shortenBelow(limits converter.PathLimits, baseDir, path string) string {
+	rel, err := filepath.Rel(baseDir, path)
+	if err != nil || !filepath.IsLocal(rel) {
+		return path
+	}
+	return filepath.Join(baseDir, s.shorten(limits, baseDir, rel))
+}
+
+// printWarning lists the shortened paths, if any
+func (s *shortenedPaths) printWarning() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.paths) == 0 {
+		return
+	}
+
+	originals := make([]string, 0, len(s.paths))
+	for original := range s.paths {
+		originals = append(originals, original)
+	}
+	sort.Strings(originals)
+
+	fmt.Printf("⚠️  %d paths were shortened to fit the path length limits:\n", len(originals))
+	for i, original := range originals {
+		if i == shortenedPathsShown {
+			fmt.Printf("    ... and %d more\n", len(originals)-shortenedPathsShown)
+			break
+		}
+		fmt.Printf("    %s\n      → %s\n", original, s.paths[original])
+	}
+}
@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// treeJob carries one output document through the fetch → convert → write pipeline
+type treeJob struct {
+	order     int // position in the depth-first page order, used to keep results stable
+	node      *PageNode
+	outputDir string
+	inline    []*PageNode // leaf children appended to this page's document
+
+	page       *confluenceModel.ConfluencePage
+	children   []*confluenceModel.ConfluencePage
+	outputPath string
+	doc        *convModel.MarkdownDocument
+	result     *PageConversionResult
+}
+
+// settled reports whether an earlier stage already recorded an error for the job or found it unchanged
+func (j *treeJob) settled() bool {
+	return j.result != nil && (j.result.Error != nil || j.result.Unchanged)
+}
+
+// planTreeJobs flattens a page tree into jobs in depth-first order, folding inlined leaves into their parent's job
+func planTreeJobs(node *PageNode, outputDir string, opts *TreeOptions, jobs []*treeJob) []*treeJob {
+	if node == nil {
+		return jobs
+	}
+
+	job := &treeJob{order: len(jobs), node: node, outputDir: outputDir}
+	jobs = append(jobs, job)
+
+	var remaining []*PageNode
+	for _, child := range node.Children {
+		if shouldInlineChild(child, opts.InlineChildrenBelowDepth) {
+			job.inline = append(job.inline, child)
+		} else {
+			remaining = append(remaining, child)
+		}
+	}
+
+	for _, child := range remaining {
+		jobs = planTreeJobs(child, outputDir, opts, jobs)
+	}
+	return jobs
+}
+
+// runTreePipeline fetches, converts, and writes the jobs with independent worker pools per stage.
+// Stages are connected by channels buffered to the size of the next pool, so a slow stage
+// applies backpressure instead of letting fetched pages or converted documents pile up in memory.
+// Documents are written as soon as they are converted and their page content is released afterwards.
+func runTreePipeline(client confluence.Client, jobs []*treeJob, baseURL string, opts *TreeOptions, results *ConversionResults) {
+	conversionOpts := PageOptions{
+		authOptions:     authOptions{APIKey: opts.APIKey},
+		commonOptions:   opts.commonOptions,
+		resolvedOptions: opts.resolvedOptions,
+	}
+
+	queued := make(chan *treeJob)
+	fetched := make(chan *treeJob, opts.ConvertWorkers)
+	converted := make(chan *treeJob, opts.WriteWorkers)
+	done := make(chan *treeJob, opts.WriteWorkers)
+
+	go func() {
+		for _, job := range jobs {
+			queued <- job
+		}
+		close(queued)
+	}()
+
+	runPipelineStage(opts.Parallel, queued, fetched, func(job *treeJob, emit func(*treeJob)) {
+		fetchTreeJob(client, job, opts, emit)
+	})
+	runPipelineStage(opts.ConvertWorkers, fetched, converted, func(job *treeJob, emit func(*treeJob)) {
+		if !job.settled() {
+			job.doc, job.result = convertPageDocument(client, job.page, job.children, baseURL, job.outputPath, conversionOpts)
+		}
+		emit(job)
+	})
+	runPipelineStage(opts.WriteWorkers, converted, done, func(job *treeJob, emit func(*treeJob)) {
+		if !job.settled() {
+			savePageDocument(job.doc, job.result, conversionOpts)
+		}
+		// Only the result is kept until the summary, so a large space does not hold every page body in memory
+		job.doc, job.page, job.children = nil, nil, nil
+		emit(job)
+	})
+
+	var finished []*treeJob
+	for job := range done {
+		printConversionResult(job.result)
+		finished = append(finished, job)
+	}
+
+	sort.SliceStable(finished, func(i, j int) bool {
+		return finished[i].order < finished[j].order
+	})
+	resolveLocalLinks(finished, conversionOpts)
+	for _, job := range finished {
+		// Recorded after the link pass so the state file hashes the final file
+		opts.state.record(job.node, job.result)
+		results.record(job.result)
+	}
+}
+
+// resolveLocalLinks rewrites confluence://pageId links in the files written by this run to relative paths of the
+// exported pages they point at, so the tree can be browsed offline. Inlined children resolve to their parent's file.
+func resolveLocalLinks(finished []*treeJob, opts PageOptions) {
+	paths := make(map[string]string)
+	for _, job := range finished {
+		result := job.result
+		if !result.Success && !result.Unchanged && !result.Stubbed {
+			continue
+		}
+		paths[job.node.ID] = result.OutputPath
+		for _, child := range job.inline {
+			paths[child.ID] = result.OutputPath
+		}
+	}
+
+	for _, job := range finished {
+		result := job.result
+		if !result.Success || result.Unchanged || result.Stubbed {
+			continue
+		}
+		for _, path := range append([]string{result.OutputPath}, result.sectionPaths...) {
+			if err := resolveFileLinks(path, result, paths, opts); err != nil {
+				fmt.Printf("  ⚠️  Failed to resolve local links in %s: %v\n", path, err)
+			}
+		}
+	}
+}
+
+// resolveFileLinks rewrites one written Markdown file and regenerates its Word document and preview when it changed
+func resolveFileLinks(path string, result *PageConversionResult, paths map[string]string, opts PageOptions) error {
+	data, err := outputFS.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read markdown file: %w", err)
+	}
+	resolved, changed := converter.ResolveLocalLinks(string(data), path, paths)
+	if !changed {
+		return nil
+	}
+	if err := outputFS.WriteFile(path, []byte(resolved), 0644); err != nil {
+		return fmt.Errorf("failed to write markdown file: %w", err)
+	}
+
+	if opts.Format == formatDocx && path == result.OutputPath {
+		ctx, cancel := docxContext(opts)
+		defer cancel()
+		if _, err := converter.ConvertToDocx(ctx, outputFS, opts.Pandoc, path, opts.Dialect); err != nil {
+			return err
+		}
+	}
+	if opts.WritePreviews {
+		if _, err := converter.RenderPreview(outputFS, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runPipelineStage starts workers that handle every job from in and closes out once all of them return
+func runPipelineStage(workers int, in <-chan *treeJob, out chan<- *treeJob, handle func(*treeJob, func(*treeJob))) {
+	emit := func(job *treeJob) {
+		out <- job
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range in {
+				handle(job, emit)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+}
+
+// fetchTreeJob loads the page and its inlined children and resolves the output path.
+// Children that fail to load for inlining are emitted as jobs of their own.
+func fetchTreeJob(client confluence.Client, job *treeJob, opts *TreeOptions, emit func(*treeJob)) {
+	node := job.node
+
+	if opts.ChangedOnly {
+		if result := unchangedResult(job, &opts.resolvedOptions); result != nil {
+			job.result = result
+			emit(job)
+			return
+		}
+	}
+	if result := opts.state.unchangedResult(job, &opts.resolvedOptions); result != nil {
+		job.result = result
+		emit(job)
+		return
+	}
+
+	page, err := client.GetPage(node.ID)
+	if err != nil {
+		fmt.Printf("  ❌ Failed to fetch %s: %v\n", node.Title, err)
+		job.result = &PageConversionResult{PageID: node.ID, Title: node.Title, Error: err}
+		if reason := unreadableReason(err); reason != "" && opts.StubUnreadable {
+			if stub, stubErr := writePageStub(node, reason, opts, job.outputDir); stubErr != nil {
+				fmt.Printf("  ❌ Failed to write stub for %s: %v\n", node.Title, stubErr)
+			} else {
+				job.result = stub
+			}
+		}
+		emit(job)
+		return
+	}
+	job.page = page
+
+	// Generate hierarchical output path
+	job.outputPath, err = getOutputPath(node, page, job.outputDir, &opts.resolvedOptions)
+	if err != nil {
+		fmt.Printf("  ❌ Failed to resolve output path for %s: %v\n", node.Title, err)
+		job.result = &PageConversionResult{PageID: node.ID, Title: node.Title, Error: err}
+		emit(job)
+		return
+	}
+
+	var separate []*treeJob
+	for _, child := range job.inline {
+		childPage, err := client.GetPage(child.ID)
+		if err != nil {
+			fmt.Printf("  ⚠️  Failed to fetch %s for inlining, converting separately: %v\n", child.Title, err)
+			separate = append(separate, &treeJob{order: job.order, node: child, outputDir: job.outputDir})
+			continue
+		}
+		job.children = append(job.children, childPage)
+	}
+
+	emit(job)
+	for _, childJob := range separate {
+		fetchTreeJob(client, childJob, opts, emit)
+	}
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPlanTreeJobs(t *testing.T) {
+	leaf := &PageNode{ID: "leaf", Level: 2}
+	deep := &PageNode{ID: "deep", Level: 1, Children: []*PageNode{leaf}}
+	sibling := &PageNode{ID: "sibling", Level: 1}
+	root := &PageNode{ID: "root", Level: 0, Children: []*PageNode{deep, sibling}}
+
+	jobs := planTreeJobs(root, "out", &TreeOptions{InlineChildrenBelowDepth: 1}, nil)
+
+	var ids []string
+	for i, job := range jobs {
+		if job.order != i {
+			t.Errorf("job %s has order %d, want %d", job.node.ID, job.order, i)
+		}
+		ids = append(ids, job.node.ID)
+	}
+	if got, want := ids, []string{"root", "deep", "sibling"}; !slices.Equal(got, want) {
+		t.Fatalf("jobs = %v, want %v", got, want)
+	}
+	if inline := jobs[1].inline; len(inline) != 1 || inline[0] != leaf {
+		t.Errorf("deep inlines %v, want the leaf", inline)
+	}
+	if len(jobs[0].inline) != 0 {
+		t.Errorf("root inlines %v, want none", jobs[0].inline)
+	}
+}
+
+func TestRunPipelineStage(t *testing.T) {
+	const workers = 2
+	in := make(chan *treeJob)
+	out := make(chan *treeJob, workers)
+
+	var running, peak atomic.Int32
+	runPipelineStage(workers, in, out, func(job *treeJob, emit func(*treeJob)) {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		emit(job)
+	})
+
+	go func() {
+		for i := 0; i < 10; i++ {
+			in <- &treeJob{order: i}
+		}
+		close(in)
+	}()
+
+	seen := make(map[int]bool)
+	for job := range out {
+		seen[job.order] = true
+	}
+	if len(seen) != 10 {
+		t.Fatalf("got %d jobs out, want 10", len(seen))
+	}
+	if got := peak.Load(); got > workers {
+		t.Errorf("%d jobs ran at once, want at most %d", got, workers)
+	}
+}
@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+)
+
+// orderByPopularity sorts the jobs by page views, most viewed first, so interrupted exports have the pages people read.
+// Jobs keep their depth-first order field, so results are still reported in tree order.
+// Once the Analytics API reports it is unavailable no more views are requested; pages without
+// view counts follow the others in tree order.
+func orderByPopularity(client confluence.Client, jobs []*treeJob, workers int) []*treeJob {
+	views := make([]int, len(jobs))
+	known := make([]bool, len(jobs))
+	var unavailable bool
+	var mu sync.Mutex
+	isUnavailable := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return unavailable
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if isUnavailable() {
+					continue
+				}
+				count, err := client.GetPageViews(jobs[i].node.ID)
+				if errors.Is(err, confluence.ErrNotFound) {
+					mu.Lock()
+					unavailable = true
+					mu.Unlock()
+					continue
+				}
+				if err != nil {
+					fmt.Printf("⚠️  Warning: Failed to fetch views for %s: %v\n", jobs[i].node.Title, err)
+					continue
+				}
+				views[i], known[i] = count, true
+			}
+		}()
+	}
+	for i := range jobs {
+		if isUnavailable() {
+			break
+		}
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	counted := 0
+	for _, ok := range known {
+		if ok {
+			counted++
+		}
+	}
+	if unavailable {
+		fmt.Println("⚠️  Warning: Page analytics are not available, converting pages without views in tree order")
+	}
+	if counted == 0 {
+		return jobs
+	}
+
+	indexes := make([]int, len(jobs))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(a, b int) bool {
+		if known[indexes[a]] != known[indexes[b]] {
+			return known[indexes[a]]
+		}
+		return views[indexes[a]] > views[indexes[b]]
+	})
+
+	ordered := make([]*treeJob, len(jobs))
+	for i, index := range indexes {
+		ordered[i] = jobs[index]
+	}
+	fmt.Printf("📈 Converting %d of %d pages most viewed first\n", counted, len(jobs))
+	return ordered
+}
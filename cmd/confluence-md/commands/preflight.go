@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+)
+
+// preflightPermissions probes the root pages and up to sample descendants before the full tree is
+// fetched, so a token that cannot read the tree fails fast instead of hundreds of pages failing mid-run.
+// An unreadable root page is an error; unreadable descendants only produce a warning.
+func preflightPermissions(client confluence.Client, rootPageIDs []string, sample int) error {
+	for _, pageID := range rootPageIDs {
+		if _, err := client.GetPage(pageID); errors.Is(err, confluence.ErrForbidden) {
+			return fmt.Errorf("root page %s is not readable by this token: %w", pageID, err)
+		}
+	}
+	if sample <= 0 {
+		return nil
+	}
+
+	pageIDs := sampleDescendants(client, rootPageIDs, sample)
+	forbidden := 0
+	for _, pageID := range pageIDs {
+		if _, err := client.GetPage(pageID); errors.Is(err, confluence.ErrForbidden) {
+			forbidden++
+		}
+	}
+
+	if forbidden > 0 {
+		fmt.Printf("⚠️  Pre-flight: %d of %d sampled pages in this tree are not readable by this token\n", forbidden, len(pageIDs))
+	} else if len(pageIDs) > 0 {
+		fmt.Printf("🔑 Pre-flight: all %d sampled pages are readable\n", len(pageIDs))
+	}
+	return nil
+}
+
+// sampleDescendants lists child pages breadth-first until limit IDs are collected
+func sampleDescendants(client confluence.Client, rootPageIDs []string, limit int) []string {
+	var sampled []string
+	queue := append([]string(nil), rootPageIDs...)
+	for len(queue) > 0 && len(sampled) < limit {
+		parentID := queue[0]
+		queue = queue[1:]
+
+		children, err := client.GetChildPages(parentID)
+		if err != nil {
+			continue
+		}
+		for _, child := range children {
+			if len(sampled) == limit {
+				break
+			}
+			sampled = append(sampled, child.ID)
+			queue = append(queue, child.ID)
+		}
+	}
+	return sampled
+}
+
+// reportUnreadablePages warns up front about fetched tree pages the token may not read
+func reportUnreadablePages(trees []*PageNode) {
+	forbidden := 0
+	var walk func(node *PageNode)
+	walk = func(node *PageNode) {
+		if node == nil {
+			return
+		}
+		if errors.Is(node.Error, confluence.ErrForbidden) {
+			forbidden++
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	for _, tree := range trees {
+		walk(tree)
+	}
+
+	if forbidden > 0 {
+		fmt.Printf("⚠️  %d pages in this tree are not readable by this token\n", forbidden)
+	}
+}
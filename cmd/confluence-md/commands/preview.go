@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// servePreview serves the output directory on addr until interrupted, for --serve-preview.
+// start is an optional file below dir whose URL is printed, e.g. the preview of an exported page.
+// An address without a host, such as :8080, only listens on the loopback interface.
+func servePreview(addr, dir, start string) error {
+	if addr == "" {
+		return nil
+	}
+	if host, port, err := net.SplitHostPort(addr); err == nil && host == "" {
+		addr = net.JoinHostPort("127.0.0.1", port)
+	}
+	if !isLoopbackAddr(addr) {
+		fmt.Printf("⚠️  Warning: The preview of %s is reachable from other machines on %s\n", dir, addr)
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to serve preview: %w", err)
+	}
+	httpServer := &http.Server{
+		Handler:           http.FileServer(http.Dir(dir)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	url := previewURL(listener.Addr(), dir, start)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+	fmt.Printf("🌐 Serving the preview of %s at %s (Ctrl+C to stop)\n", dir, url)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("preview server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down preview server: %w", err)
+	}
+	fmt.Println("👋 Preview server stopped")
+	return nil
+}
+
+// previewURL returns the browser URL of start, or of the served directory, on the listening address
+func previewURL(listening net.Addr, dir, start string) string {
+	host, port, err := net.SplitHostPort(listening.String())
+	if err != nil {
+		return "http://" + listening.String() + "/"
+	}
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "localhost"
+	}
+	url := "http://" + net.JoinHostPort(host, port) + "/"
+
+	if start == "" {
+		return url
+	}
+	if rel, err := filepath.Rel(dir, start); err == nil {
+		url += filepath.ToSlash(rel)
+	}
+	return url
+}
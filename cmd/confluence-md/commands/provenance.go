@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/version"
+)
+
+const (
+	exportInfoFileName = "export-info.json"
+	exportToolName     = "confluence-md"
+)
+
+// exportRun identifies one invocation of an export command for --provenance
+type exportRun struct {
+	ID        string
+	StartedAt time.Time
+}
+
+// newExportRun starts a run whose ID sorts by start time and is unique across concurrent runs
+func newExportRun() *exportRun {
+	startedAt := time.Now().UTC()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	return &exportRun{
+		ID:        startedAt.Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix),
+		StartedAt: startedAt,
+	}
+}
+
+// stamp records the run in the document's frontmatter
+func (r *exportRun) stamp(doc *convModel.MarkdownDocument, baseURL string) {
+	if r == nil || doc == nil {
+		return
+	}
+	doc.Frontmatter.Export = convModel.ExportRef{
+		RunID:       r.ID,
+		Tool:        exportToolName,
+		ToolVersion: version.Short(),
+		Source:      baseURL,
+		ExportedAt:  time.Now(),
+	}
+}
+
+// exportInfo is the run summary written to export-info.json at the top of the output directory
+type exportInfo struct {
+	RunID       string    `json:"runId"`
+	Tool        string    `json:"tool"`
+	ToolVersion string    `json:"toolVersion"`
+	Source      string    `json:"source"`
+	StartedAt   time.Time `json:"startedAt"`
+	FinishedAt  time.Time `json:"finishedAt"`
+	Pages       int       `json:"pages"`
+	Failed      int       `json:"failed"`
+}
+
+// writeExportInfo writes the run summary into outputDir; it does nothing without --provenance
+func writeExportInfo(r *exportRun, outputDir, baseURL string, results *ConversionResults) error {
+	if r == nil {
+		return nil
+	}
+
+	info := exportInfo{
+		RunID:       r.ID,
+		Tool:        exportToolName,
+		ToolVersion: version.Short(),
+		Source:      baseURL,
+		StartedAt:   r.StartedAt,
+		FinishedAt:  time.Now().UTC(),
+		Pages:       results.Success,
+		Failed:      results.Failed,
+	}
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode export info: %w", err)
+	}
+
+	path := filepath.Join(outputDir, exportInfoFileName)
+	if err := outputFS.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write export info: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,441 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/publisher"
+	"github.com/spf13/cobra"
+)
+
+// PushOptions contains all options for the push command
+type PushOptions struct {
+	authOptions
+
+	BaseURL string // Confluence base URL, the only host the API token is sent to
+	Force   bool   // Overwrite pages that changed remotely since export
+	Create  bool   // Create new pages for files without a confluence.page_id
+	Parent  string // Parent page ID for created pages, overriding the frontmatter parent_id
+
+	Mermaid         string // "macro" or "png"
+	MermaidRenderer string // mermaid-cli compatible command used with --mermaid png
+
+	DryRun bool // Only report which pages would be created or updated
+	Diff   bool // Print a storage format diff against the remote page (implies DryRun)
+
+	parentSpaces map[string]string // space key of each parent page, resolved when creating
+}
+
+var pushOpts PushOptions
+
+// pushCmd publishes edited Markdown files back to their Confluence pages
+var pushCmd = &cobra.Command{
+	Use:   "push <file.md|dir> [file.md|dir...]",
+	Short: "Push Markdown files back to their Confluence pages",
+	Long: `Convert exported Markdown files back to Confluence storage format and update
+the pages they were exported from. Directories are searched for .md files.
+
+Each file is matched to its page through the confluence frontmatter keys
+(page_id, space, version, url). The API token is only sent to --base-url;
+files whose frontmatter url names another host are refused. Before updating, the remote version is compared
+with the version recorded in the file; if a colleague edited the page since the
+export, the file is skipped so their changes are not silently overwritten.
+Use --force to overwrite anyway.
+
+Local images referenced by the Markdown are uploaded as page attachments
+(changed files become new attachment versions) and referenced from the page
+with ac:image/ri:attachment.
+
+Mermaid code blocks become mermaid-macro macros. On sites without the Mermaid
+app, use --mermaid png to render them with mermaid-cli (mmdc) and attach the
+images instead.
+
+Use --dry-run to list the pages that would be created or updated without
+changing anything, and --diff to also print a storage format diff against each
+remote page (--diff implies --dry-run).
+
+After a successful push the file's frontmatter version is updated to the new
+page version.
+
+With --create, files without a confluence.page_id become new pages under the
+--parent page, or under the confluence.parent_id recorded by the exporter, in
+the parent's space. Local images they reference are uploaded as attachments, and the
+new page's confluence keys are written into the file's frontmatter so later
+pushes update it.
+
+Examples:
+  confluence-md push docs/page.md --base-url https://confluence.example.com --api-token $TOKEN
+
+  # Publish new Markdown files as children of page 12345
+  confluence-md push ./docs --create --parent 12345 --base-url https://confluence.example.com --api-token $TOKEN
+
+  # Publish files whose frontmatter names their parent (confluence.parent_id)
+  confluence-md push ./docs --create --base-url https://confluence.example.com --api-token $TOKEN
+
+  # Review the changes before pushing
+  confluence-md push ./output --base-url https://confluence.example.com --api-token $TOKEN --diff
+
+  # Push a whole export, overwriting remote changes
+  confluence-md push ./output --base-url https://confluence.example.com --api-token $TOKEN --force`,
+	Annotations: map[string]string{mutatingAnnotation: "true"},
+	RunE:        runPushCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(pushCmd)
+
+	pushOpts.authOptions.InitFlags(pushCmd)
+	_ = pushCmd.MarkFlagRequired("api-token")
+	_ = pushCmd.MarkFlagRequired("base-url")
+
+	pushCmd.Flags().StringVar(&pushOpts.BaseURL, "base-url", "", "Confluence base URL the pages are pushed to; files whose frontmatter url names another host are refused")
+	pushCmd.Flags().BoolVar(&pushOpts.Force, "force", false, "Overwrite pages that changed remotely since the file was exported")
+	pushCmd.Flags().BoolVar(&pushOpts.Create, "create", false, "Create new pages for files without a confluence.page_id under --parent or their frontmatter confluence.parent_id")
+	pushCmd.Flags().StringVar(&pushOpts.Parent, "parent", "", "Parent page ID for pages created with --create (default: the frontmatter confluence.parent_id)")
+	pushCmd.Flags().StringVar(&pushOpts.Mermaid, "mermaid", "macro", "How to publish mermaid code blocks: macro (Mermaid app) or png (rendered image attachments)")
+	pushCmd.Flags().StringVar(&pushOpts.MermaidRenderer, "mermaid-renderer", "mmdc", "mermaid-cli compatible command used to render diagrams with --mermaid png")
+	pushCmd.Flags().BoolVar(&pushOpts.DryRun, "dry-run", false, "Print which pages would be created or updated without changing anything")
+	pushCmd.Flags().BoolVar(&pushOpts.Diff, "diff", false, "Print a storage format diff against each remote page (implies --dry-run)")
+}
+
+func runPushCommand(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: Markdown file or directory")
+	}
+
+	if pushOpts.Mermaid != "macro" && pushOpts.Mermaid != "png" {
+		return fmt.Errorf("invalid options: mermaid must be macro or png, got: %s", pushOpts.Mermaid)
+	}
+	if pushOpts.Diff {
+		pushOpts.DryRun = true
+	}
+
+	if _, err := pushBaseHost(pushOpts.BaseURL); err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+	pushOpts.BaseURL = strings.TrimSuffix(pushOpts.BaseURL, "/")
+
+	files, err := collectMarkdownFiles(args)
+	if err != nil {
+		return err
+	}
+
+	client := confluence.NewClient(pushOpts.BaseURL, pushOpts.APIKey, clientOptions()...)
+	pushOpts.parentSpaces = make(map[string]string)
+	if pushOpts.Create && pushOpts.Parent != "" {
+		if _, err := parentSpaceKey(client, pushOpts.Parent, &pushOpts); err != nil {
+			return err
+		}
+	}
+
+	pushed, conflicts, failed := 0, 0, 0
+	for _, file := range files {
+		err := pushMarkdownFile(client, file, &pushOpts)
+		switch {
+		case err == nil:
+			pushed++
+		case errors.Is(err, publisher.ErrConflict):
+			fmt.Printf("  ⚠️  Skipped %s: %v\n", file, err)
+			conflicts++
+		default:
+			fmt.Printf("  ❌ Failed to push %s: %v\n", file, err)
+			failed++
+		}
+	}
+
+	if pushOpts.DryRun {
+		fmt.Printf("✅ Dry run completed, nothing was changed!\n")
+		fmt.Printf("  Would push: %d files\n", pushed)
+	} else {
+		fmt.Printf("✅ Push completed!\n")
+		fmt.Printf("  Pushed: %d files\n", pushed)
+	}
+	if conflicts > 0 {
+		fmt.Printf("  Conflicts: %d files (re-export them or use --force)\n", conflicts)
+	}
+	if failed > 0 {
+		fmt.Printf("  Failed: %d files\n", failed)
+	}
+
+	if conflicts > 0 || failed > 0 {
+		return fmt.Errorf("push completed with errors")
+	}
+	return nil
+}
+
+// collectMarkdownFiles expands directory arguments into the .md files they contain
+func collectMarkdownFiles(args []string) ([]string, error) {
+	var files []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", arg, err)
+		}
+		if !info.IsDir() {
+			files = append(files, arg)
+			continue
+		}
+
+		err = filepath.WalkDir(arg, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(path), ".md") {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", arg, err)
+		}
+	}
+	return files, nil
+}
+
+// pushMarkdownFile updates the page a Markdown file was exported from, or creates one with --create
+func pushMarkdownFile(client confluence.Client, path string, opts *PushOptions) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Files edited on Windows may use CRLF line endings
+	text := strings.ReplaceAll(string(content), "\r\n", "\n")
+	doc := &convModel.MarkdownDocument{Content: text}
+	if strings.HasPrefix(text, "---\n") {
+		doc, err = convModel.ParseMarkdownDocument(text)
+		if err != nil {
+			return fmt.Errorf("failed to parse frontmatter: %w", err)
+		}
+	}
+
+	ref := doc.Frontmatter.Confluence
+	if err := checkPushTarget(ref, opts); err != nil {
+		return err
+	}
+	if ref.PageID == "" {
+		if !opts.Create {
+			return fmt.Errorf("frontmatter has no confluence.page_id (use --create to publish it as a new page)")
+		}
+		return createMarkdownPage(client, path, doc, opts)
+	}
+
+	remote, err := client.GetPage(ref.PageID)
+	if err != nil {
+		return err
+	}
+	if !opts.Force {
+		if err := publisher.CheckVersion(ref, remote); err != nil {
+			return err
+		}
+	}
+
+	title := doc.Frontmatter.Title
+	if title == "" {
+		title = remote.Title
+	}
+
+	storage := publisher.ToStorage(doc.Content, opts.storageOptions()...)
+	if opts.DryRun {
+		fmt.Printf("🔍 Would update %s → %s (version %d → %d)\n", path, title, remote.Version, remote.Version+1)
+		if opts.Diff {
+			printStorageDiff(remote.Content.Storage.Value, storage, "remote: "+remote.Title, path)
+		}
+		return nil
+	}
+
+	// Upload images first so the updated page's attachment references resolve
+	uploads := uploadPushImages(client, remote, path, doc, opts)
+	storage = publisher.ToStorage(doc.Content, opts.storageOptions(uploads...)...)
+
+	updated, err := client.UpdatePage(remote.ID, title, remote.Version+1, storage)
+	if err != nil {
+		return err
+	}
+	reportImageUploads(uploads)
+
+	// Record the new version so the next push of this file is not reported as a conflict
+	doc.Frontmatter.Confluence.Version = updated.Version
+	if err := writePushedDocument(path, doc); err != nil {
+		return err
+	}
+
+	fmt.Printf("⬆️  Pushed %s → %s (version %d)\n", path, title, updated.Version)
+	return nil
+}
+
+// createMarkdownPage publishes the file as a new page under the parent and uploads its local images
+func createMarkdownPage(client confluence.Client, path string, doc *convModel.MarkdownDocument, opts *PushOptions) error {
+	parentID := opts.Parent
+	if parentID == "" {
+		parentID = doc.Frontmatter.Confluence.ParentID
+	}
+	if parentID == "" {
+		return fmt.Errorf("no parent page: set confluence.parent_id in the frontmatter or pass --parent")
+	}
+
+	spaceKey, err := parentSpaceKey(client, parentID, opts)
+	if err != nil {
+		return err
+	}
+
+	title := doc.Frontmatter.Title
+	if title == "" {
+		title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	storage := publisher.ToStorage(doc.Content, opts.storageOptions()...)
+	if opts.DryRun {
+		fmt.Printf("🔍 Would create %s → %s (under page %s)\n", path, title, parentID)
+		if opts.Diff {
+			printStorageDiff("", storage, "/dev/null", path)
+		}
+		return nil
+	}
+
+	page, err := client.CreatePage(spaceKey, parentID, title, storage)
+	if err != nil {
+		return err
+	}
+
+	uploads := uploadPushImages(client, page, path, doc, opts)
+	reportImageUploads(uploads)
+	// The page was created before its diagrams could be attached; drop references to the ones that failed
+	if retry := publisher.ToStorage(doc.Content, opts.storageOptions(uploads...)...); retry != storage {
+		if page, err = client.UpdatePage(page.ID, title, page.Version+1, retry); err != nil {
+			return fmt.Errorf("failed to remove missing diagram images: %w", err)
+		}
+	}
+
+	pageURL, err := page.GetURL(opts.BaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to generate page URL: %w", err)
+	}
+	doc.Frontmatter.Title = title
+	doc.Frontmatter.Confluence = convModel.ConfluenceRef{
+		PageID:   page.ID,
+		SpaceKey: page.SpaceKey,
+		ParentID: parentID,
+		Version:  page.Version,
+		URL:      pageURL,
+	}
+	if err := writePushedDocument(path, doc); err != nil {
+		return err
+	}
+
+	fmt.Printf("🆕 Created %s → %s (page %s)\n", path, title, page.ID)
+	return nil
+}
+
+// storageOptions maps the push flags onto storage format options. Diagrams whose images failed
+// to render or upload are kept as code blocks instead of referencing a missing attachment.
+func (o *PushOptions) storageOptions(uploads ...publisher.ImageUpload) []publisher.Option {
+	var opts []publisher.Option
+	if o.Mermaid == "png" {
+		opts = append(opts, publisher.WithMermaidImages())
+		var failed []string
+		for _, upload := range uploads {
+			if upload.Error != nil {
+				failed = append(failed, upload.FileName)
+			}
+		}
+		if len(failed) > 0 {
+			opts = append(opts, publisher.WithoutMermaidImages(failed...))
+		}
+	}
+	return opts
+}
+
+// uploadPushImages attaches the file's local images and, with --mermaid png, its rendered diagrams
+func uploadPushImages(client confluence.Client, page *confluenceModel.ConfluencePage, path string, doc *convModel.MarkdownDocument, opts *PushOptions) []publisher.ImageUpload {
+	uploads := publisher.UploadImages(client, page, filepath.Dir(path), doc.Content)
+	if opts.Mermaid == "png" {
+		uploads = append(uploads, publisher.UploadMermaidImages(client, page, opts.MermaidRenderer, doc.Content)...)
+	}
+	return uploads
+}
+
+// printStorageDiff prints the storage format diff, or a note when the content is unchanged
+func printStorageDiff(remote, local, remoteName, localName string) {
+	diff := publisher.DiffStorage(remote, local, remoteName, localName)
+	if diff == "" {
+		fmt.Printf("  (no content changes)\n")
+		return
+	}
+	fmt.Print(diff)
+}
+
+// reportImageUploads prints failed image uploads and a count of the attachments that changed
+func reportImageUploads(uploads []publisher.ImageUpload) {
+	changed := 0
+	for _, upload := range uploads {
+		switch {
+		case upload.Error != nil:
+			fmt.Printf("  ⚠️  Failed to upload image %s: %v\n", upload.Path, upload.Error)
+		case upload.Action != publisher.ImageUnchanged:
+			changed++
+		}
+	}
+	if changed > 0 {
+		fmt.Printf("  📎 Uploaded %d images\n", changed)
+	}
+}
+
+// writePushedDocument rewrites the file with its updated frontmatter
+func writePushedDocument(path string, doc *convModel.MarkdownDocument) error {
+	output, err := doc.WithFrontmatter()
+	if err != nil {
+		return fmt.Errorf("failed to render frontmatter: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to update frontmatter: %w", err)
+	}
+	return nil
+}
+
+// pushBaseHost returns the host of --base-url, which must be an absolute http(s) URL
+func pushBaseHost(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("base-url must be an absolute http(s) URL, got: %q", baseURL)
+	}
+	return u.Host, nil
+}
+
+// checkPushTarget refuses files exported from another Confluence host than --base-url, so a file's frontmatter
+// can never direct the API token elsewhere. Files without a url are pushed to --base-url.
+func checkPushTarget(ref convModel.ConfluenceRef, opts *PushOptions) error {
+	if ref.URL == "" {
+		return nil
+	}
+	baseHost, err := pushBaseHost(opts.BaseURL)
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(ref.URL)
+	if err != nil || !strings.EqualFold(u.Host, baseHost) {
+		return fmt.Errorf("frontmatter confluence.url %q is not on --base-url host %s", ref.URL, baseHost)
+	}
+	return nil
+}
+
+// parentSpaceKey returns the space of a parent page for created pages, fetching each parent once
+func parentSpaceKey(client confluence.Client, parentID string, opts *PushOptions) (string, error) {
+	if spaceKey, ok := opts.parentSpaces[parentID]; ok {
+		return spaceKey, nil
+	}
+	parent, err := client.GetPage(parentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get parent page: %w", err)
+	}
+	opts.parentSpaces[parentID] = parent.SpaceKey
+	return parent.SpaceKey, nil
+}
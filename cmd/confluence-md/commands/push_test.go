@@ -0,0 +1,41 @@
+package commands
+
+import (
+	"testing"
+
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+func TestCheckPushTarget(t *testing.T) {
+	opts := &PushOptions{BaseURL: "https://example.atlassian.net/wiki"}
+
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"", false},
+		{"https://example.atlassian.net/wiki/spaces/DOCS/pages/1/Home", false},
+		{"https://EXAMPLE.atlassian.net/wiki/spaces/DOCS/pages/1/Home", false},
+		{"https://attacker.example/wiki/spaces/DOCS/pages/1/Home", true},
+		{"https://example.atlassian.net.attacker.example/pages/1", true},
+		{"https://example.atlassian.net:8443/pages/1", true},
+		{"://broken", true},
+	}
+	for _, tt := range tests {
+		err := checkPushTarget(convModel.ConfluenceRef{PageID: "1", URL: tt.url}, opts)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkPushTarget(%q) error = %v, want error %v", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestPushBaseHost(t *testing.T) {
+	if host, err := pushBaseHost("https://confluence.example.com/"); err != nil || host != "confluence.example.com" {
+		t.Errorf("pushBaseHost() = %q, %v", host, err)
+	}
+	for _, baseURL := range []string{"", "confluence.example.com", "file:///etc"} {
+		if _, err := pushBaseHost(baseURL); err == nil {
+			t.Errorf("pushBaseHost(%q) accepted an unusable base URL", baseURL)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	"github.com/jackchuka/confluence-md/internal/converter"
+	"github.com/spf13/cobra"
+)
+
+const questionsDir = "questions"
+
+// QuestionsOptions contains all options for the questions command
+type QuestionsOptions struct {
+	authOptions
+	commonOptions
+	resolvedOptions
+
+	Spaces     []string // Space keys whose questions are exported
+	AllAnswers bool     // Include every answer, not only the accepted one
+}
+
+var questionsOpts QuestionsOptions
+
+// questionsCmd exports Confluence Questions questions and answers
+var questionsCmd = &cobra.Command{
+	Use:   "questions <base-url>",
+	Short: "Export Confluence Questions questions and answers",
+	Long: `Export the questions asked in spaces with the Confluence Questions app into
+<output>/questions/, one Markdown file per question with its accepted answer.
+Authors, dates, votes and topics are recorded in the frontmatter.
+
+Use --all-answers to include every answer, ordered by votes after the
+accepted one. Spaces without the Questions app are reported and skipped.
+
+Examples:
+  confluence-md questions https://confluence.example.com --space DOCS --output ./docs
+
+  # Keep every answer
+  confluence-md questions https://confluence.example.com --space DOCS --all-answers`,
+	RunE: runQuestionsCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(questionsCmd)
+
+	questionsOpts.authOptions.InitFlags(questionsCmd)
+	questionsOpts.commonOptions.InitFlags(questionsCmd)
+
+	questionsCmd.Flags().StringSliceVar(&questionsOpts.Spaces, "space", nil, "Space keys whose questions are exported (required)")
+	questionsCmd.Flags().BoolVar(&questionsOpts.AllAnswers, "all-answers", false, "Include every answer, not only the accepted one")
+}
+
+func runQuestionsCommand(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: Confluence base URL")
+	}
+	if len(questionsOpts.Spaces) == 0 {
+		return fmt.Errorf("invalid options: at least one --space is required")
+	}
+	baseURL := strings.TrimSuffix(args[0], "/")
+
+	if err := questionsOpts.resolve(questionsOpts.commonOptions); err != nil {
+		return err
+	}
+
+	client := confluence.NewClient(baseURL, questionsOpts.APIKey, clientOptions()...)
+	pageOpts := PageOptions{authOptions: questionsOpts.authOptions, commonOptions: questionsOpts.commonOptions, resolvedOptions: questionsOpts.resolvedOptions}
+	conv := converter.NewConverter(client, buildConverterOptions(pageOpts)...)
+
+	outputDir := filepath.Join(questionsOpts.OutputDir, questionsDir)
+	exported, failed, unavailable := 0, 0, 0
+	for _, spaceKey := range questionsOpts.Spaces {
+		questions, err := client.ListQuestions(spaceKey)
+		if errors.Is(err, confluence.ErrNotFound) {
+			fmt.Printf("⚠️  The Questions app is not available for space %s\n", spaceKey)
+			unavailable++
+			continue
+		}
+		if err != nil {
+			fmt.Printf("❌ Failed to list questions in space %s: %v\n", spaceKey, err)
+			failed++
+			continue
+		}
+		fmt.Printf("❓ Exporting %d questions from space %s\n", len(questions), spaceKey)
+
+		for _, question := range questions {
+			answers, err := client.GetAnswers(question.ID)
+			if err != nil {
+				fmt.Printf("  ❌ Failed to fetch answers for %s: %v\n", question.Title, err)
+				failed++
+				continue
+			}
+
+			doc, err := conv.ConvertQuestion(&question, answers, baseURL, questionsOpts.AllAnswers)
+			if err != nil {
+				fmt.Printf("  ❌ Failed to convert %s: %v\n", question.Title, err)
+				failed++
+				continue
+			}
+			doc.Frontmatter.Confluence.SpaceKey = spaceKey
+
+			outputPath := filepath.Join(outputDir, fmt.Sprintf("%s-%s.md", question.ID, questionsOpts.Slugger.Slug(question.Title, nil)))
+			if err := converter.SaveMarkdownDocument(outputFS, doc, outputPath, questionsOpts.IncludeMetadata); err != nil {
+				fmt.Printf("  ❌ Failed to write %s: %v\n", question.Title, err)
+				failed++
+				continue
+			}
+			fmt.Printf("  ✅ %s → %s\n", question.Title, outputPath)
+			exported++
+		}
+	}
+
+	fmt.Printf("✅ Questions export complete!\n")
+	fmt.Printf("  Exported: %d questions\n", exported)
+	if failed > 0 {
+		fmt.Printf("  Failed: %d\n", failed)
+	}
+	if unavailable > 0 {
+		fmt.Printf("  Spaces without Questions: %d\n", unavailable)
+	}
+	fmt.Printf("  Output: %s\n", outputDir)
+
+	if failed > 0 {
+		return fmt.Errorf("questions export completed with errors")
+	}
+	return nil
+}
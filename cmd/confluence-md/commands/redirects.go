@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/converter"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+// Redirect modes for pages whose output path changed after a rename
+const (
+	redirectNone    = "none"    // leave the previous file in place
+	redirectStub    = "stub"    // replace the previous file with a link to the new one
+	redirectAliases = "aliases" // remove the previous file and list its URL path under aliases: in the new one
+)
+
+// renameTracker remembers where each page was written so a rename can leave a redirect at the old path
+type renameTracker struct {
+	mode    string
+	baseDir string
+	paths   map[string]string   // page ID -> last output path
+	aliases map[string][]string // page ID -> previous URL paths, kept so later rewrites retain them
+}
+
+func newRenameTracker(mode, baseDir string) *renameTracker {
+	return &renameTracker{mode: mode, baseDir: baseDir, paths: make(map[string]string), aliases: make(map[string][]string)}
+}
+
+// seed records the pages an earlier run wrote below baseDir, found by the confluence page ID and version in their
+// frontmatter, so a page renamed while the watcher was stopped still redirects from its old file. Their aliases are
+// kept as well. Redirect and duplicate stubs have no version and are skipped.
+func (t *renameTracker) seed() error {
+	err := writefs.WalkDir(outputFS, t.baseDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(path), ".md") {
+			return nil
+		}
+
+		content, err := outputFS.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		doc, err := convModel.ParseMarkdownDocument(string(content))
+		if err != nil || doc.Frontmatter.Confluence.PageID == "" || doc.Frontmatter.Confluence.Version == 0 {
+			return nil
+		}
+		pageID := doc.Frontmatter.Confluence.PageID
+		t.paths[pageID] = path
+		t.aliases[pageID] = doc.Frontmatter.Aliases
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read previous output: %w", err)
+	}
+	return nil
+}
+
+// validateRedirectMode checks the value of a --redirects flag
+func validateRedirectMode(mode string) error {
+	switch mode {
+	case redirectNone, redirectStub, redirectAliases:
+		return nil
+	}
+	return fmt.Errorf("invalid options: redirects must be none, stub or aliases, got: %s", mode)
+}
+
+// prepare returns the page's previous output path when it differs from outputPath.
+// In aliases mode it also records that path and sets the document's aliases.
+func (t *renameTracker) prepare(pageID, outputPath string, doc *convModel.MarkdownDocument) string {
+	previous := t.paths[pageID]
+	if previous == outputPath {
+		previous = ""
+	}
+
+	if t.mode == redirectAliases {
+		if previous != "" {
+			if alias := t.urlPath(previous); !slices.Contains(t.aliases[pageID], alias) {
+				t.aliases[pageID] = append(t.aliases[pageID], alias)
+			}
+		}
+		// A page renamed back to an earlier title must not redirect to itself
+		current := t.urlPath(outputPath)
+		t.aliases[pageID] = slices.DeleteFunc(t.aliases[pageID], func(alias string) bool { return alias == current })
+		doc.Frontmatter.Aliases = t.aliases[pageID]
+	}
+	return previous
+}
+
+// finish records the page's output path and handles the file left at its previous path
+func (t *renameTracker) finish(pageID, previous string, result *PageConversionResult) error {
+	t.paths[pageID] = result.OutputPath
+	if previous == "" {
+		return nil
+	}
+
+	switch t.mode {
+	case redirectStub:
+		link, err := filepath.Rel(filepath.Dir(previous), result.OutputPath)
+		if err != nil {
+			return fmt.Errorf("failed to link redirect stub: %w", err)
+		}
+		content := fmt.Sprintf("# %s\n\nThis page has moved to %s.\n",
+			converter.EscapeLinkText(result.Title), converter.MarkdownLink(result.Title, filepath.ToSlash(link)))
+		if err := outputFS.WriteFile(previous, []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write redirect stub: %w", err)
+		}
+		fmt.Printf("  ↪️  Renamed: %s now redirects to %s\n", previous, result.OutputPath)
+	case redirectAliases:
+		if err := outputFS.Remove(previous); err != nil {
+			return fmt.Errorf("failed to remove previous file: %w", err)
+		}
+		fmt.Printf("  ↪️  Renamed: %s moved to %s (kept as alias)\n", previous, result.OutputPath)
+	}
+	return nil
+}
+
+// urlPath turns an output file path into the site URL path static site generators serve it at
+func (t *renameTracker) urlPath(path string) string {
+	rel, err := filepath.Rel(t.baseDir, path)
+	if err != nil {
+		rel = filepath.Base(path)
+	}
+	return "/" + strings.TrimSuffix(filepath.ToSlash(rel), ".md") + "/"
+}
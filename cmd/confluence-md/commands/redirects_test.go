@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+// useMemOutput swaps outputFS for an in-memory filesystem for the duration of the test
+func useMemOutput(t *testing.T) *writefs.MemFS {
+	t.Helper()
+	mem := writefs.NewMemFS()
+	saved := outputFS
+	outputFS = mem
+	t.Cleanup(func() { outputFS = saved })
+	return mem
+}
+
+// writeExportedPage writes a page file with the confluence frontmatter an export leaves behind
+func writeExportedPage(t *testing.T, mem *writefs.MemFS, path, pageID string, version int, aliases ...string) {
+	t.Helper()
+	doc := &convModel.MarkdownDocument{
+		Frontmatter: convModel.Frontmatter{
+			Title:      "Page " + pageID,
+			Aliases:    aliases,
+			Confluence: convModel.ConfluenceRef{PageID: pageID, Version: version},
+		},
+		Content: "body\n",
+	}
+	content, err := doc.WithFrontmatter()
+	if err != nil {
+		t.Fatalf("WithFrontmatter() error = %v", err)
+	}
+	if err := mem.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestRenameTrackerSeed(t *testing.T) {
+	mem := useMemOutput(t)
+	baseDir := "out"
+	writeExportedPage(t, mem, filepath.Join(baseDir, "docs", "old-title.md"), "1", 3, "/docs/older-title/")
+	writeExportedPage(t, mem, filepath.Join(baseDir, "stub.md"), "2", 0)
+	if err := mem.WriteFile(filepath.Join(baseDir, "docs", "notes.txt"), []byte("page_id: 3"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tracker := newRenameTracker(redirectAliases, baseDir)
+	if err := tracker.seed(); err != nil {
+		t.Fatalf("seed() error = %v", err)
+	}
+
+	if got, want := tracker.paths["1"], filepath.Join(baseDir, "docs", "old-title.md"); got != want {
+		t.Errorf("seeded path = %q, want %q", got, want)
+	}
+	if got := tracker.aliases["1"]; !slices.Equal(got, []string{"/docs/older-title/"}) {
+		t.Errorf("seeded aliases = %v", got)
+	}
+	if _, ok := tracker.paths["2"]; ok {
+		t.Errorf("stub without a version was seeded")
+	}
+
+	if err := newRenameTracker(redirectStub, "missing").seed(); err != nil {
+		t.Errorf("seed() of a missing directory error = %v, want none", err)
+	}
+}
+
+func TestRenameTrackerPrepareAliases(t *testing.T) {
+	tracker := newRenameTracker(redirectAliases, "out")
+	tracker.paths["1"] = filepath.Join("out", "docs", "old.md")
+
+	doc := &convModel.MarkdownDocument{}
+	newPath := filepath.Join("out", "docs", "new.md")
+	if previous := tracker.prepare("1", newPath, doc); previous != tracker.paths["1"] {
+		t.Fatalf("prepare() = %q, want the previous path", previous)
+	}
+	if !slices.Equal(doc.Frontmatter.Aliases, []string{"/docs/old/"}) {
+		t.Fatalf("aliases = %v, want [/docs/old/]", doc.Frontmatter.Aliases)
+	}
+
+	// Preparing the same rename again, e.g. on the next watch cycle, must not duplicate the alias
+	tracker.prepare("1", newPath, doc)
+	if !slices.Equal(doc.Frontmatter.Aliases, []string{"/docs/old/"}) {
+		t.Fatalf("aliases after repeat = %v, want [/docs/old/]", doc.Frontmatter.Aliases)
+	}
+
+	// Renamed back to the old title: the old path is current again and must not redirect to itself
+	tracker.paths["1"] = newPath
+	if previous := tracker.prepare("1", filepath.Join("out", "docs", "old.md"), doc); previous != newPath {
+		t.Fatalf("prepare() = %q, want %q", previous, newPath)
+	}
+	if !slices.Equal(doc.Frontmatter.Aliases, []string{"/docs/new/"}) {
+		t.Fatalf("aliases after renaming back = %v, want [/docs/new/]", doc.Frontmatter.Aliases)
+	}
+
+	if previous := tracker.prepare("2", newPath, &convModel.MarkdownDocument{}); previous != "" {
+		t.Errorf("prepare() of an unknown page = %q, want none", previous)
+	}
+}
+
+func TestRenameTrackerFinish(t *testing.T) {
+	previous := filepath.Join("out", "docs", "old.md")
+	result := &PageConversionResult{Title: "New [draft]", OutputPath: filepath.Join("out", "guides", "new page.md")}
+
+	tests := []struct {
+		mode        string
+		wantContent string // content left at the previous path, empty when it is removed
+	}{
+		{redirectNone, "old"},
+		{redirectStub, "# New \\[draft\\]\n\nThis page has moved to [New \\[draft\\]](<../guides/new page.md>).\n"},
+		{redirectAliases, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			mem := useMemOutput(t)
+			if err := mem.WriteFile(previous, []byte("old"), 0644); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			tracker := newRenameTracker(tt.mode, "out")
+			if err := tracker.finish("1", previous, result); err != nil {
+				t.Fatalf("finish() error = %v", err)
+			}
+			if tracker.paths["1"] != result.OutputPath {
+				t.Errorf("tracked path = %q, want %q", tracker.paths["1"], result.OutputPath)
+			}
+
+			content, err := mem.ReadFile(previous)
+			if tt.wantContent == "" {
+				if !errors.Is(err, fs.ErrNotExist) {
+					t.Fatalf("previous file still exists: %q, %v", content, err)
+				}
+				return
+			}
+			if err != nil || string(content) != tt.wantContent {
+				t.Fatalf("previous file = %q, %v, want %q", content, err, tt.wantContent)
+			}
+		})
+	}
+}
+
+func TestRenameTrackerURLPath(t *testing.T) {
+	tracker := newRenameTracker(redirectAliases, "out")
+	if got := tracker.urlPath(filepath.Join("out", "docs", "Page.md")); got != "/docs/Page/" {
+		t.Errorf("urlPath() = %q, want /docs/Page/", got)
+	}
+	if got := tracker.urlPath(filepath.Join("out", "index.md")); got != "/index/" {
+		t.Errorf("urlPath() = %q, want /index/", got)
+	}
+}
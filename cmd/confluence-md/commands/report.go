@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// conversionReport is the machine-readable summary written with --report
+type conversionReport struct {
+	Pages []pageReport `json:"pages"`
+}
+
+type pageReport struct {
+	PageID          string              `json:"pageId"`
+	Title           string              `json:"title"`
+	OutputPath      string              `json:"outputPath,omitempty"`
+	Success         bool                `json:"success"`
+	Unchanged       bool                `json:"unchanged,omitempty"`
+	Stubbed         bool                `json:"stubbed,omitempty"`
+	Skipped         bool                `json:"skipped,omitempty"`
+	Error           string              `json:"error,omitempty"`
+	ErrorCategory   string              `json:"errorCategory,omitempty"`
+	ExternalLinks   []convModel.LinkRef `json:"externalLinks,omitempty"`
+	UnresolvedUsers []string            `json:"unresolvedUsers,omitempty"`
+
+	AccessibilityIssues []convModel.AccessibilityIssue `json:"accessibilityIssues,omitempty"`
+	Redactions          map[string]int                 `json:"redactions,omitempty"`
+	Fidelity            *convModel.Fidelity            `json:"fidelity,omitempty"`
+}
+
+func newPageReport(result *PageConversionResult) pageReport {
+	report := pageReport{
+		PageID:          result.PageID,
+		Title:           result.Title,
+		OutputPath:      result.OutputPath,
+		Success:         result.Success,
+		Unchanged:       result.Unchanged,
+		Stubbed:         result.Stubbed,
+		Skipped:         result.Skipped,
+		ExternalLinks:   result.ExternalLinks,
+		UnresolvedUsers: result.UnresolvedUsers,
+
+		AccessibilityIssues: result.Accessibility,
+		Redactions:          result.Redactions,
+		Fidelity:            result.Fidelity,
+	}
+	if result.Error != nil {
+		report.Error = result.Error.Error()
+	}
+	if result.failed() {
+		report.ErrorCategory = errorCategory(result.Error)
+	}
+	return report
+}
+
+// writeReport writes the conversion results as indented JSON
+func writeReport(path string, results []*PageConversionResult) error {
+	report := conversionReport{Pages: make([]pageReport, 0, len(results))}
+	for _, result := range results {
+		report.Pages = append(report.Pages, newPageReport(result))
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := outputFS.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+
+	return outputFS.WriteFile(path, append(data, '\n'), 0644)
+}
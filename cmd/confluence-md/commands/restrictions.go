@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// restrictionRow is one principal allowed to view or edit a restricted page
+type restrictionRow struct {
+	PageID        string
+	Title         string
+	Path          string
+	Operation     string // "view" or "edit"
+	PrincipalType string
+	Principal     string
+	AccountID     string
+	InheritedFrom string // ID of the ancestor page the view restriction is inherited from
+}
+
+// restrictionOperations maps API operation names onto the names shown in the Confluence UI
+var restrictionOperations = map[string]string{"read": "view", "update": "edit"}
+
+// collectRestrictionRows lists the restrictions of every page in the trees.
+// View restrictions apply to all descendants, so they are repeated on child pages with the ancestor's ID.
+func collectRestrictionRows(client confluence.Client, trees []*PageNode) []restrictionRow {
+	var rows []restrictionRow
+
+	var walk func(node *PageNode, inherited []restrictionRow)
+	walk = func(node *PageNode, inherited []restrictionRow) {
+		if node == nil || node.Error != nil {
+			return
+		}
+
+		path := strings.Join(node.Path, " / ")
+		for _, row := range inherited {
+			row.PageID, row.Title, row.Path = node.ID, node.Title, path
+			rows = append(rows, row)
+		}
+
+		restrictions, err := client.GetPageRestrictions(node.ID)
+		if err != nil {
+			fmt.Printf("⚠️  Warning: Failed to fetch restrictions for %s: %v\n", node.Title, err)
+		}
+
+		childInherited := inherited
+		for _, restriction := range restrictions {
+			row := newRestrictionRow(node, path, restriction)
+			rows = append(rows, row)
+			if restriction.Operation == "read" {
+				row.InheritedFrom = node.ID
+				childInherited = append(childInherited[:len(childInherited):len(childInherited)], row)
+			}
+		}
+
+		for _, child := range node.Children {
+			walk(child, childInherited)
+		}
+	}
+	for _, tree := range trees {
+		walk(tree, nil)
+	}
+	return rows
+}
+
+func newRestrictionRow(node *PageNode, path string, restriction confluenceModel.PageRestriction) restrictionRow {
+	operation := restrictionOperations[restriction.Operation]
+	if operation == "" {
+		operation = restriction.Operation
+	}
+	return restrictionRow{
+		PageID:        node.ID,
+		Title:         node.Title,
+		Path:          path,
+		Operation:     operation,
+		PrincipalType: restriction.PrincipalType,
+		Principal:     restriction.Principal,
+		AccountID:     restriction.AccountID,
+	}
+}
+
+// writeRestrictionsReport audits the trees' page restrictions and writes them as CSV
+func writeRestrictionsReport(client confluence.Client, trees []*PageNode, path string) error {
+	rows := collectRestrictionRows(client, trees)
+
+	if dir := filepath.Dir(path); dir != "." && dir != "" {
+		if err := outputFS.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create report directory: %w", err)
+		}
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{"pageId", "title", "path", "operation", "principalType", "principal", "accountId", "inheritedFrom"})
+	for _, row := range rows {
+		_ = writer.Write([]string{row.PageID, row.Title, row.Path, row.Operation, row.PrincipalType, row.Principal, row.AccountID, row.InheritedFrom})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to encode restrictions report: %w", err)
+	}
+	if err := outputFS.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write restrictions report: %w", err)
+	}
+
+	fmt.Printf("🔒 Restrictions report written: %s (%d entries)\n", path, len(rows))
+	return nil
+}
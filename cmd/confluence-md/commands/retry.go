@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+)
+
+// failedPagesFileName is the checkpoint of failed pages written into the output directory, read back by --retry-failed
+const failedPagesFileName = "failed-pages.json"
+
+// errorCategory classifies why a page failed, so transient failures can be told apart from permanent ones
+func errorCategory(err error) string {
+	var apiErr *confluence.APIError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, errPageTimeout):
+		return "timeout"
+	case errors.Is(err, confluence.ErrNotFound):
+		return "not-found"
+	case errors.Is(err, confluence.ErrForbidden):
+		return "forbidden"
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests:
+		return "rate-limited"
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= 500:
+		return "server"
+	case errors.As(err, &apiErr):
+		return "api"
+	case errors.As(err, &netErr):
+		return "network"
+	}
+	return "conversion"
+}
+
+// failed reports whether the page neither converted nor was deliberately left out
+func (r *PageConversionResult) failed() bool {
+	return !r.Success && !r.Unchanged && !r.Stubbed && !r.Skipped
+}
+
+// writeFailedPages saves the failed pages of a run into outputDir, or removes a stale checkpoint when nothing failed
+func writeFailedPages(outputDir string, results *ConversionResults) (string, error) {
+	path := filepath.Join(outputDir, failedPagesFileName)
+
+	var failed []*PageConversionResult
+	for _, result := range results.Pages {
+		if result.failed() {
+			failed = append(failed, result)
+		}
+	}
+	if len(failed) == 0 {
+		if err := outputFS.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to remove failed pages checkpoint: %w", err)
+		}
+		return "", nil
+	}
+
+	if err := writeReport(path, failed); err != nil {
+		return "", fmt.Errorf("failed to write failed pages checkpoint: %w", err)
+	}
+	return path, nil
+}
+
+// loadRetryPageIDs reads the IDs of the failed pages from a checkpoint or --report file
+func loadRetryPageIDs(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var report conversionReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	ids := make(map[string]bool)
+	for _, page := range report.Pages {
+		if !page.Success && !page.Unchanged && !page.Stubbed && !page.Skipped {
+			ids[page.PageID] = true
+		}
+	}
+	return ids, nil
+}
+
+// retryJobs keeps the jobs of the pages to retry
+func retryJobs(jobs []*treeJob, ids map[string]bool) []*treeJob {
+	var kept []*treeJob
+	for _, job := range jobs {
+		if ids[job.node.ID] {
+			kept = append(kept, job)
+		}
+	}
+	return kept
+}
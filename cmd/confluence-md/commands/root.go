@@ -3,10 +3,46 @@ package commands
 import (
 	"fmt"
 	"os"
+	"strconv"
 
+	"github.com/jackchuka/confluence-md/internal/confluence"
 	"github.com/spf13/cobra"
 )
 
+const (
+	// mutatingAnnotation marks commands that modify Confluence
+	mutatingAnnotation = "mutating"
+
+	// Values of --auth-type
+	authTypeAuto   = "auto"
+	authTypeBasic  = "basic"
+	authTypeBearer = "bearer"
+
+	// readOnlyEnv enables --require-read-only for every invocation, e.g. from a wrapper script
+	readOnlyEnv = "CONFLUENCE_MD_REQUIRE_READ_ONLY"
+)
+
+var (
+	// requireReadOnly refuses mutating commands and makes every client reject non-GET requests
+	requireReadOnly bool
+
+	// debugHTTP traces every API request to stderr, debugHTTPDump also stores redacted bodies
+	debugHTTP     bool
+	debugHTTPDump string
+
+	// userAgent overrides the User-Agent header sent to Confluence
+	userAgent string
+
+	// email switches to Basic authentication, which Atlassian Cloud API tokens require
+	email string
+
+	// authType forces Basic or Bearer authentication instead of picking Basic whenever email is set
+	authType string
+
+	// cacheDir keeps page and attachment responses to revalidate them with ETag and Last-Modified
+	cacheDir string
+)
+
 var rootCmd = &cobra.Command{
 	Use:   "confluence-md",
 	Short: "Convert Confluence pages to Markdown format",
@@ -20,8 +56,73 @@ Examples:
   confluence-md tree <page-url>
   confluence-md version`,
 
-	SilenceUsage:  true,
-	SilenceErrors: true,
+	SilenceUsage:      true,
+	SilenceErrors:     true,
+	PersistentPreRunE: checkGlobalFlags,
+}
+
+func init() {
+	defaultReadOnly, _ := strconv.ParseBool(os.Getenv(readOnlyEnv))
+	rootCmd.PersistentFlags().BoolVar(&requireReadOnly, "require-read-only", defaultReadOnly,
+		"Refuse to run commands that modify Confluence, such as push (default from "+readOnlyEnv+")")
+	rootCmd.PersistentFlags().BoolVar(&debugHTTP, "debug-http", false, "Log method, URL, status, duration and rate-limit headers of every API request to stderr (tokens redacted)")
+	rootCmd.PersistentFlags().StringVar(&debugHTTPDump, "debug-http-dump", "", "Also write redacted request and response dumps including bodies into this directory (implies --debug-http)")
+	rootCmd.PersistentFlags().StringVar(&userAgent, "user-agent", "", "User-Agent header sent with every API request (default ConfluenceMd/<version>)")
+	rootCmd.PersistentFlags().StringVar(&email, "email", "", "Atlassian account email; sends the API token with Basic authentication as Confluence Cloud requires (default Bearer, for Data Center personal access tokens)")
+	rootCmd.PersistentFlags().StringVar(&authType, "auth-type", authTypeAuto, "How the API token is sent: auto (Basic when --email is set, Bearer otherwise), basic or bearer")
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "Cache page and attachment responses in this directory and revalidate them with If-None-Match/If-Modified-Since")
+}
+
+// checkGlobalFlags validates the persistent flags before any command runs
+func checkGlobalFlags(cmd *cobra.Command, args []string) error {
+	switch authType {
+	case authTypeAuto, authTypeBearer:
+	case authTypeBasic:
+		if email == "" {
+			return fmt.Errorf("invalid options: --auth-type basic requires --email")
+		}
+	default:
+		return fmt.Errorf("invalid options: auth-type must be auto, basic or bearer, got: %s", authType)
+	}
+	return checkReadOnly(cmd, args)
+}
+
+// useBasicAuth reports whether the API token is sent with Basic authentication as --email
+func useBasicAuth() bool {
+	return email != "" && authType != authTypeBearer
+}
+
+// checkReadOnly rejects mutating commands under --require-read-only
+func checkReadOnly(cmd *cobra.Command, _ []string) error {
+	// The environment variable cannot be switched off from the command line
+	if envReadOnly, _ := strconv.ParseBool(os.Getenv(readOnlyEnv)); envReadOnly {
+		requireReadOnly = true
+	}
+	if !requireReadOnly {
+		return nil
+	}
+	if cmd.Annotations[mutatingAnnotation] == "true" {
+		return fmt.Errorf("%s modifies Confluence and is disabled by --require-read-only", cmd.Name())
+	}
+	return nil
+}
+
+// clientOptions returns the Confluence client options implied by the global flags
+func clientOptions() []confluence.Option {
+	opts := []confluence.Option{confluence.WithUserAgent(userAgent)}
+	if useBasicAuth() {
+		opts = append(opts, confluence.WithBasicAuth(email))
+	}
+	if requireReadOnly {
+		opts = append(opts, confluence.WithReadOnly())
+	}
+	if debugHTTP || debugHTTPDump != "" {
+		opts = append(opts, confluence.WithDebugHTTP(os.Stderr, debugHTTPDump))
+	}
+	if cacheDir != "" {
+		opts = append(opts, confluence.WithResponseCache(cacheDir))
+	}
+	return opts
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -0,0 +1,389 @@
+package commands
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// readinessCacheTTL limits how often /readyz calls Confluence
+	readinessCacheTTL = 15 * time.Second
+
+	// maxConvertRequestBytes bounds POST /convert request bodies
+	maxConvertRequestBytes = 10 << 20
+
+	// serveTokenEnv sets --auth-token without exposing it in the process list
+	serveTokenEnv = "CONFLUENCE_MD_SERVE_TOKEN"
+)
+
+// ServeOptions contains all options for the serve command
+type ServeOptions struct {
+	authOptions
+
+	Addr      string // Listen address
+	BaseURL   string // Confluence base URL
+	AuthToken string // Bearer secret clients must send, required off loopback
+}
+
+var serveOpts ServeOptions
+
+// serveCmd runs the converter as a long-lived HTTP service
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP server for sidecar deployments",
+	Long: `Run confluence-md as a long-lived HTTP service, for example as a conversion
+sidecar on Kubernetes.
+
+Endpoints:
+  POST /convert  Convert a page (url or pageId) or raw storage HTML (storage) to Markdown
+  GET /healthz   Liveness: the process is up
+  GET /readyz    Readiness: Confluence is reachable and the API token is accepted
+  GET /metrics   Prometheus metrics
+
+POST /convert takes a JSON body such as {"url": "<page-url>", "frontmatter": true}
+or {"storage": "<p>Hello</p>"} and returns {"content", "metadata", "warnings"}.
+Images are referenced but not downloaded.
+
+The server listens on 127.0.0.1 by default. Listening on any other address
+requires --auth-token (or CONFLUENCE_MD_SERVE_TOKEN); clients then send it as
+"Authorization: Bearer <token>" on every endpoint except /healthz and /readyz.
+
+Examples:
+  confluence-md serve --base-url https://confluence.example.com --api-token $TOKEN
+
+  CONFLUENCE_MD_SERVE_TOKEN=$SECRET confluence-md serve --base-url https://confluence.example.com --api-token $TOKEN --addr :8080
+
+  curl -s localhost:8080/convert -d '{"pageId": "12345"}'`,
+	RunE: runServeCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	serveOpts.authOptions.InitFlags(serveCmd)
+
+	serveCmd.Flags().StringVar(&serveOpts.Addr, "addr", "127.0.0.1:8080", "Address to listen on; addresses other than loopback require --auth-token")
+	serveCmd.Flags().StringVar(&serveOpts.AuthToken, "auth-token", "", "Bearer secret clients must send (default $"+serveTokenEnv+")")
+	serveCmd.Flags().StringVar(&serveOpts.BaseURL, "base-url", "", "Confluence base URL (required)")
+	_ = serveCmd.MarkFlagRequired("base-url")
+}
+
+func runServeCommand(_ *cobra.Command, _ []string) error {
+	baseURL := strings.TrimSuffix(serveOpts.BaseURL, "/")
+
+	authToken := serveOpts.AuthToken
+	if authToken == "" {
+		authToken = os.Getenv(serveTokenEnv)
+	}
+	if authToken == "" && !isLoopbackAddr(serveOpts.Addr) {
+		return fmt.Errorf("invalid options: --auth-token or %s is required to listen on %s, which is not a loopback address", serveTokenEnv, serveOpts.Addr)
+	}
+
+	stats := newServiceMetrics()
+	client := confluence.NewClient(baseURL, serveOpts.APIKey, append(clientOptions(), stats.clientOption())...)
+	server := &conversionServer{client: client, baseURL: baseURL, metrics: stats, authToken: authToken}
+
+	httpServer := &http.Server{
+		Addr:              serveOpts.Addr,
+		Handler:           server.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.ListenAndServe()
+	}()
+	fmt.Printf("🚀 Serving on %s for %s (Ctrl+C to stop)\n", serveOpts.Addr, baseURL)
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	fmt.Println("👋 Server stopped")
+	return nil
+}
+
+// conversionServer handles the serve command's HTTP endpoints
+type conversionServer struct {
+	client    confluence.Client
+	baseURL   string
+	metrics   *serviceMetrics
+	authToken string // bearer secret required by requireToken, empty to allow every request
+
+	mu         sync.Mutex
+	checkedAt  time.Time
+	readyError error
+}
+
+func (s *conversionServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /convert", s.handleConvert)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReady)
+	mux.Handle("GET /metrics", s.metrics.registry.Handler())
+	return s.requireToken(mux)
+}
+
+// requireToken rejects requests without the bearer secret, except for the health probes
+func (s *conversionServer) requireToken(next http.Handler) http.Handler {
+	if s.authToken == "" {
+		return next
+	}
+	want := []byte("Bearer " + s.authToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/healthz" && r.URL.Path != "/readyz" &&
+			subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isLoopbackAddr reports whether a listen address only accepts local connections
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// handleHealth reports liveness without contacting Confluence
+func (s *conversionServer) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("ok\n"))
+}
+
+// handleReady reports whether Confluence is reachable and accepts the API token
+func (s *conversionServer) handleReady(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := s.checkReady(); err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = fmt.Fprintf(w, "not ready: %v\n", err)
+		return
+	}
+	_, _ = w.Write([]byte("ready\n"))
+}
+
+// checkReady verifies connectivity and authentication, reusing the last result for readinessCacheTTL
+func (s *conversionServer) checkReady() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.checkedAt.IsZero() && time.Since(s.checkedAt) < readinessCacheTTL {
+		return s.readyError
+	}
+
+	_, err := s.client.GetCurrentUser()
+	s.checkedAt, s.readyError = time.Now(), err
+	return err
+}
+
+// convertRequest is the body of POST /convert; set url or pageId to convert a page, or storage for raw HTML
+type convertRequest struct {
+	URL         string `json:"url,omitempty"`
+	PageID      string `json:"pageId,omitempty"`
+	Storage     string `json:"storage,omitempty"`
+	Title       string `json:"title,omitempty"`       // Title reported for raw storage HTML
+	Frontmatter bool   `json:"frontmatter,omitempty"` // Prefix converted pages with YAML frontmatter
+}
+
+// convertResponse is the result of POST /convert
+type convertResponse struct {
+	Content  string          `json:"content"`
+	Metadata convertMetadata `json:"metadata"`
+	Warnings []string        `json:"warnings"`
+}
+
+// convertMetadata describes the converted page; page fields are empty for raw storage HTML
+type convertMetadata struct {
+	Title         string              `json:"title,omitempty"`
+	PageID        string              `json:"pageId,omitempty"`
+	SpaceKey      string              `json:"spaceKey,omitempty"`
+	Version       int                 `json:"version,omitempty"`
+	URL           string              `json:"url,omitempty"`
+	Author        string              `json:"author,omitempty"`
+	Labels        []string            `json:"labels,omitempty"`
+	ExternalLinks []convModel.LinkRef `json:"externalLinks,omitempty"`
+}
+
+// warningCollector gathers converter warnings for a single request
+type warningCollector struct {
+	converter.NopEvents
+	warnings []string
+}
+
+func (w *warningCollector) Warning(_ *confluenceModel.ConfluencePage, message string) {
+	w.warnings = append(w.warnings, message)
+}
+
+// handleConvert converts a page or raw storage HTML and returns the Markdown as JSON
+func (s *conversionServer) handleConvert(w http.ResponseWriter, r *http.Request) {
+	var req convertRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxConvertRequestBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	hasPage := req.URL != "" || req.PageID != ""
+	if hasPage == (req.Storage != "") {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("set either url/pageId or storage"))
+		return
+	}
+
+	start := time.Now()
+	collector := &warningCollector{}
+	conv := converter.NewConverter(s.client, converter.WithEvents(collector))
+
+	var (
+		resp   *convertResponse
+		status int
+		err    error
+	)
+	if hasPage {
+		resp, status, err = s.convertPage(conv, req)
+	} else {
+		resp, status, err = s.convertStorage(conv, req)
+	}
+	s.metrics.observeConversion(err == nil, time.Since(start))
+	if err != nil {
+		writeJSONError(w, status, err)
+		return
+	}
+
+	resp.Warnings = append(resp.Warnings, collector.warnings...)
+	if resp.Warnings == nil {
+		resp.Warnings = []string{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// convertPage fetches and converts the page addressed by the request's url or pageId
+func (s *conversionServer) convertPage(conv *converter.Converter, req convertRequest) (*convertResponse, int, error) {
+	pageID := req.PageID
+	if req.URL != "" {
+		pageInfo, err := urlToPageInfo(req.URL)
+		if err != nil {
+			return nil, http.StatusBadRequest, fmt.Errorf("invalid Confluence URL: %w", err)
+		}
+		if pageInfo.BaseURL != s.baseURL {
+			return nil, http.StatusBadRequest, fmt.Errorf("URL must belong to %s, got %s", s.baseURL, pageInfo.BaseURL)
+		}
+		pageIDs, err := resolvePageIDs(s.client, []confluenceModel.PageURLInfo{pageInfo})
+		if err != nil {
+			return nil, http.StatusBadGateway, err
+		}
+		pageID = pageIDs[0]
+	}
+	// The ID becomes a path segment of the API request, so anything else could reach other endpoints
+	if !isPageID(pageID) {
+		return nil, http.StatusBadRequest, fmt.Errorf("pageId must be numeric, got %q", pageID)
+	}
+
+	page, err := s.client.GetPage(pageID)
+	if errors.Is(err, confluence.ErrNotFound) {
+		return nil, http.StatusNotFound, err
+	}
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+
+	doc, err := conv.ConvertPage(page, s.baseURL, "")
+	if err != nil {
+		return nil, http.StatusUnprocessableEntity, fmt.Errorf("failed to convert page: %w", err)
+	}
+
+	content, err := doc.Render(req.Frontmatter)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to render frontmatter: %w", err)
+	}
+
+	resp := &convertResponse{Content: content, Metadata: newConvertMetadata(doc)}
+	for _, accountID := range doc.UnresolvedUsers {
+		resp.Warnings = append(resp.Warnings, fmt.Sprintf("Unresolved user mention: %s", accountID))
+	}
+	return resp, http.StatusOK, nil
+}
+
+// isPageID reports whether id is a Confluence content ID, which is all digits
+func isPageID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// convertStorage converts raw storage format HTML
+func (s *conversionServer) convertStorage(conv *converter.Converter, req convertRequest) (*convertResponse, int, error) {
+	content, err := conv.ConvertHTML(req.Storage)
+	if err != nil {
+		return nil, http.StatusUnprocessableEntity, fmt.Errorf("failed to convert HTML: %w", err)
+	}
+	return &convertResponse{Content: content, Metadata: convertMetadata{Title: req.Title}}, http.StatusOK, nil
+}
+
+func newConvertMetadata(doc *convModel.MarkdownDocument) convertMetadata {
+	return convertMetadata{
+		Title:         doc.Frontmatter.Title,
+		PageID:        doc.Frontmatter.Confluence.PageID,
+		SpaceKey:      doc.Frontmatter.Confluence.SpaceKey,
+		Version:       doc.Frontmatter.Confluence.Version,
+		URL:           doc.Frontmatter.Confluence.URL,
+		Author:        doc.Frontmatter.Author,
+		Labels:        doc.Frontmatter.Labels,
+		ExternalLinks: doc.ExternalLinks,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
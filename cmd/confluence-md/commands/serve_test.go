@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// stubServeClient answers the readiness check and records the pages requested
+type stubServeClient struct {
+	confluence.Client
+	requested []string
+}
+
+func (c *stubServeClient) GetCurrentUser() (*confluenceModel.ConfluenceUser, error) {
+	return &confluenceModel.ConfluenceUser{}, nil
+}
+
+func (c *stubServeClient) GetPage(pageID string) (*confluenceModel.ConfluencePage, error) {
+	c.requested = append(c.requested, pageID)
+	return nil, confluence.ErrNotFound
+}
+
+func newTestServer(authToken string) (*conversionServer, *stubServeClient) {
+	client := &stubServeClient{}
+	return &conversionServer{
+		client:    client,
+		baseURL:   "https://confluence.example.com",
+		metrics:   newServiceMetrics(),
+		authToken: authToken,
+	}, client
+}
+
+func TestRequireToken(t *testing.T) {
+	server, _ := newTestServer("s3cret")
+	handler := server.routes()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		auth   string
+		want   int
+	}{
+		{"missing token", http.MethodGet, "/metrics", "", http.StatusUnauthorized},
+		{"wrong token", http.MethodGet, "/metrics", "Bearer wrong", http.StatusUnauthorized},
+		{"token without scheme", http.MethodGet, "/metrics", "s3cret", http.StatusUnauthorized},
+		{"convert without token", http.MethodPost, "/convert", "", http.StatusUnauthorized},
+		{"valid token", http.MethodGet, "/metrics", "Bearer s3cret", http.StatusOK},
+		{"healthz is exempt", http.MethodGet, "/healthz", "", http.StatusOK},
+		{"readyz is exempt", http.MethodGet, "/readyz", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{}`))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.want == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("missing WWW-Authenticate challenge")
+			}
+		})
+	}
+}
+
+func TestRequireTokenDisabledWithoutSecret(t *testing.T) {
+	server, _ := newTestServer("")
+	rec := httptest.NewRecorder()
+	server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8080": true,
+		"127.1.2.3:80":   true,
+		"localhost:8080": true,
+		"[::1]:8080":     true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"[::]:8080":      false,
+		"10.0.0.5:8080":  false,
+		"example.com:80": false,
+		"127.0.0.1":      false,
+	}
+	for addr, want := range tests {
+		if got := isLoopbackAddr(addr); got != want {
+			t.Errorf("isLoopbackAddr(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestServeListensOnLoopbackByDefault(t *testing.T) {
+	addr := serveCmd.Flags().Lookup("addr").DefValue
+	if !isLoopbackAddr(addr) {
+		t.Fatalf("default --addr %q is not a loopback address", addr)
+	}
+
+	saved := serveOpts
+	defer func() { serveOpts = saved }()
+	t.Setenv(serveTokenEnv, "")
+	serveOpts.Addr = ":8080"
+	serveOpts.AuthToken = ""
+	serveOpts.BaseURL = "https://confluence.example.com"
+	if err := runServeCommand(nil, nil); err == nil || !strings.Contains(err.Error(), "--auth-token") {
+		t.Fatalf("runServeCommand() error = %v, want --auth-token to be required off loopback", err)
+	}
+}
+
+func TestHandleConvertValidatesBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"neither page nor storage", `{}`, http.StatusBadRequest},
+		{"both url and storage", `{"url":"https://confluence.example.com/pages/1","storage":"<p>x</p>"}`, http.StatusBadRequest},
+		{"both pageId and storage", `{"pageId":"1","storage":"<p>x</p>"}`, http.StatusBadRequest},
+		{"unknown field", `{"page":"1"}`, http.StatusBadRequest},
+		{"path in pageId", `{"pageId":"1/../../user/current"}`, http.StatusBadRequest},
+		{"query in pageId", `{"pageId":"1?expand=body"}`, http.StatusBadRequest},
+		{"numeric pageId", `{"pageId":"12345"}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := newTestServer("")
+			rec := httptest.NewRecorder()
+			server.routes().ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/convert", strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusBadRequest && len(client.requested) > 0 {
+				t.Errorf("rejected request still fetched %v", client.requested)
+			}
+		})
+	}
+}
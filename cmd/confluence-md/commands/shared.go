@@ -1,38 +1,28 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/url"
+	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
-	"github.com/gosimple/slug"
 	"github.com/jackchuka/confluence-md/internal/confluence"
 	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
 	"github.com/jackchuka/confluence-md/internal/converter"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
 )
 
-// sanitizeFileName uses the mature gosimple/slug library for robust filename sanitization
-func sanitizeFileName(name string) string {
-	if name == "" {
-		return "untitled"
-	}
-
-	sanitized := slug.MakeLang(name, "en")
-
-	if sanitized == "" {
-		return name
-	}
-
-	return sanitized
-}
-
-func buildOutputNamer(template string) (converter.OutputNamer, error) {
+func buildOutputNamer(template string, opts ...converter.NamingOption) (converter.OutputNamer, error) {
 	if strings.TrimSpace(template) == "" {
-		return nil, nil
+		return converter.DefaultOutputNamer(opts...), nil
 	}
 
-	namer, err := converter.NewTemplateOutputNamer(template)
+	namer, err := converter.NewTemplateOutputNamer(template, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -40,14 +30,61 @@ func buildOutputNamer(template string) (converter.OutputNamer, error) {
 	return namer, nil
 }
 
+// parseSplitHeading converts the --split-by-heading value into a heading level (0 disables splitting)
+func parseSplitHeading(value string) (int, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "":
+		return 0, nil
+	case "h1":
+		return 1, nil
+	case "h2":
+		return 2, nil
+	default:
+		return 0, fmt.Errorf("split heading must be h1 or h2, got: %s", value)
+	}
+}
+
 // PageConversionResult represents the result of converting a single page
 type PageConversionResult struct {
-	OutputPath  string
-	PageID      string
-	Title       string
-	ImagesCount int
-	Success     bool
-	Error       error
+	OutputPath      string
+	DocxPath        string // Word document converted from the output with --format docx
+	PreviewPath     string // HTML preview of the output with --preview
+	PageID          string
+	Title           string
+	ImagesCount     int
+	SectionsCount   int
+	InlinedCount    int
+	ExternalLinks   []convModel.LinkRef
+	UnresolvedUsers []string
+	Accessibility   []convModel.AccessibilityIssue
+	Fidelity        *convModel.Fidelity // nil unless the page was converted
+	Redactions      map[string]int      // matches replaced per redaction rule
+	Success         bool
+	Unchanged       bool // --changed-only found the previously exported version current
+	Stubbed         bool // a placeholder was written because the page is unreadable or missing
+	Skipped         bool // the page is excluded from export by --strip-marked
+	Error           error
+
+	sectionPaths []string // section files written beside the output with --split-level
+}
+
+// addRedactions adds a document's redaction counts to the result
+func (r *PageConversionResult) addRedactions(counts map[string]int) {
+	for name, count := range counts {
+		if r.Redactions == nil {
+			r.Redactions = make(map[string]int)
+		}
+		r.Redactions[name] += count
+	}
+}
+
+// redactionCount returns the number of matches replaced across all redaction rules
+func (r *PageConversionResult) redactionCount() int {
+	total := 0
+	for _, count := range r.Redactions {
+		total += count
+	}
+	return total
 }
 
 // convertSinglePage handles the full conversion pipeline for a single page
@@ -57,6 +94,68 @@ func convertSinglePage(client confluence.Client, page *confluenceModel.Confluenc
 
 // convertSinglePageWithPath handles conversion with a custom output path (for tree structure)
 func convertSinglePageWithPath(client confluence.Client, page *confluenceModel.ConfluencePage, baseURL, outputPath string, opts PageOptions) *PageConversionResult {
+	return convertPageWithInlinedChildren(client, page, nil, baseURL, outputPath, opts)
+}
+
+// convertPageWithInlinedChildren converts a page and appends the given child pages as sections of the same document
+func convertPageWithInlinedChildren(client confluence.Client, page *confluenceModel.ConfluencePage, children []*confluenceModel.ConfluencePage, baseURL, outputPath string, opts PageOptions) *PageConversionResult {
+	doc, result := convertPageDocument(client, page, children, baseURL, outputPath, opts)
+	if result.Error != nil {
+		return result
+	}
+
+	savePageDocument(doc, result, opts)
+	return result
+}
+
+// errPageTimeout is recorded for pages abandoned after --page-timeout
+var errPageTimeout = errors.New("page conversion timed out")
+
+// convertPageDocument converts a page and its inlined children into a document without writing it.
+// With --page-timeout, a page that takes too long is recorded as failed and its context is cancelled,
+// which aborts its API requests and stops the conversion at the next step.
+func convertPageDocument(client confluence.Client, page *confluenceModel.ConfluencePage, children []*confluenceModel.ConfluencePage, baseURL, outputPath string, opts PageOptions) (*convModel.MarkdownDocument, *PageConversionResult) {
+	if opts.PageTimeout <= 0 {
+		return convertPageDocumentNow(client, page, children, baseURL, outputPath, opts)
+	}
+
+	type converted struct {
+		doc    *convModel.MarkdownDocument
+		result *PageConversionResult
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), opts.PageTimeout)
+	defer cancel()
+	opts.PageContext = ctx
+
+	done := make(chan converted, 1)
+	go func() {
+		doc, result := convertPageDocumentNow(client.WithContext(ctx), page, children, baseURL, outputPath, opts)
+		done <- converted{doc, result}
+	}()
+
+	select {
+	case c := <-done:
+		return c.doc, c.result
+	case <-ctx.Done():
+		return nil, &PageConversionResult{
+			PageID:     page.ID,
+			Title:      page.Title,
+			OutputPath: outputPath,
+			Error:      fmt.Errorf("%w after %s", errPageTimeout, opts.PageTimeout),
+		}
+	}
+}
+
+// docxContext bounds a page's pandoc run by --page-timeout, counted from the start of the run
+func docxContext(opts PageOptions) (context.Context, context.CancelFunc) {
+	if opts.PageTimeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), opts.PageTimeout)
+}
+
+// convertPageDocumentNow converts a page and its inlined children without a time limit
+func convertPageDocumentNow(client confluence.Client, page *confluenceModel.ConfluencePage, children []*confluenceModel.ConfluencePage, baseURL, outputPath string, opts PageOptions) (*convModel.MarkdownDocument, *PageConversionResult) {
 	result := &PageConversionResult{
 		PageID: page.ID,
 		Title:  page.Title,
@@ -66,32 +165,201 @@ func convertSinglePageWithPath(client confluence.Client, page *confluenceModel.C
 		fileName, err := converter.GenerateFileName(page, opts.OutputNamer)
 		if err != nil {
 			result.Error = fmt.Errorf("failed to generate output filename: %w", err)
-			return result
+			return nil, result
 		}
-		outputPath = filepath.Join(opts.OutputDir, fileName)
+		outputPath = filepath.Join(opts.OutputDir, opts.ShortenedPaths.shorten(opts.PathLimits, opts.OutputDir, fileName))
 	}
 	result.OutputPath = outputPath
 
 	// Create converter and convert page
-	var options []converter.Option
-	if opts.DownloadImages {
-		options = append(options, converter.WithDownloadAttachments(opts.ImageFolder))
-	}
-	conv := converter.NewConverter(client, options...)
+	conv := converter.NewConverter(client, buildConverterOptions(opts)...)
 	doc, err := conv.ConvertPage(page, baseURL, filepath.Dir(outputPath))
+	if errors.Is(err, converter.ErrExcludedPage) {
+		result.Skipped = true
+		result.Error = err
+		return nil, result
+	}
 	if err != nil {
 		result.Error = fmt.Errorf("failed to convert page: %w", err)
-		return result
+		return nil, result
+	}
+	if opts.ExportTimestamp {
+		doc.Frontmatter.ExportedAt = time.Now()
 	}
+	opts.Run.stamp(doc, baseURL)
 	result.ImagesCount = len(doc.Images)
+	result.ExternalLinks = doc.ExternalLinks
+	result.UnresolvedUsers = doc.UnresolvedUsers
+	result.Accessibility = doc.AccessibilityIssues
+	fidelity := doc.Fidelity
+
+	for _, child := range children {
+		childDoc, err := conv.ConvertPage(child, baseURL, filepath.Dir(outputPath))
+		if errors.Is(err, converter.ErrExcludedPage) {
+			// An excluded child is left out while the parent keeps its other children
+			fmt.Printf("  ⏭️  Skipped inlined child %s: %v\n", child.Title, err)
+			continue
+		}
+		if err != nil {
+			result.Error = fmt.Errorf("failed to convert inlined child %s: %w", child.Title, err)
+			return nil, result
+		}
+		converter.AppendSection(doc, opts.TitleRules.Apply(child.Title), childDoc.Content)
+		result.ImagesCount += len(childDoc.Images)
+		result.ExternalLinks = append(result.ExternalLinks, childDoc.ExternalLinks...)
+		result.UnresolvedUsers = append(result.UnresolvedUsers, childDoc.UnresolvedUsers...)
+		result.Accessibility = append(result.Accessibility, childDoc.AccessibilityIssues...)
+		result.addRedactions(childDoc.Redactions)
+		fidelity = fidelity.Combine(childDoc.Fidelity)
+		result.InlinedCount++
+	}
+	result.Fidelity = &fidelity
 
-	if err := converter.SaveMarkdownDocument(doc, outputPath, opts.IncludeMetadata); err != nil {
+	return doc, result
+}
+
+// savePageDocument writes a converted document to the result's output path and marks the result as successful
+func savePageDocument(doc *convModel.MarkdownDocument, result *PageConversionResult, opts PageOptions) {
+	written := []string{result.OutputPath}
+	if opts.SplitLevel > 0 {
+		var err error
+		written, err = converter.SaveSplitMarkdownDocument(outputFS, doc, result.OutputPath, opts.IncludeMetadata, opts.SplitLevel, converter.WithSlugger(opts.Slugger), converter.WithPathLimits(opts.PathLimits))
+		if err != nil {
+			result.Error = fmt.Errorf("failed to save document: %w", err)
+			return
+		}
+		result.SectionsCount = len(written) - 1
+		result.sectionPaths = written[1:]
+	} else if err := converter.SaveMarkdownDocument(outputFS, doc, result.OutputPath, opts.IncludeMetadata); err != nil {
 		result.Error = fmt.Errorf("failed to save document: %w", err)
-		return result
+		return
+	}
+	// Counted after saving, which also redacts the frontmatter and inlined page titles
+	result.addRedactions(doc.Redactions)
+
+	if opts.Format == formatDocx {
+		ctx, cancel := docxContext(opts)
+		docxPath, err := converter.ConvertToDocx(ctx, outputFS, opts.Pandoc, result.OutputPath, opts.Dialect)
+		cancel()
+		if err != nil {
+			result.Error = err
+			return
+		}
+		result.DocxPath = docxPath
+	}
+
+	if opts.WritePreviews {
+		// Every section file is previewed so the index's links to them keep working
+		for _, path := range written {
+			previewPath, err := converter.RenderPreview(outputFS, path)
+			if err != nil {
+				result.Error = err
+				return
+			}
+			if path == result.OutputPath {
+				result.PreviewPath = previewPath
+			}
+		}
 	}
 
 	result.Success = true
-	return result
+}
+
+// buildConverterOptions maps command options onto converter options
+func buildConverterOptions(opts PageOptions) []converter.Option {
+	options := []converter.Option{converter.WithFS(outputFS), converter.WithEvents(cliEvents{out: os.Stdout})}
+	if opts.PageContext != nil {
+		options = append(options, converter.WithContext(opts.PageContext))
+	}
+	if opts.DownloadImages {
+		options = append(options, converter.WithDownloadAttachments(opts.ImageFolder), converter.WithAssetPathLimits(opts.PathLimits))
+		if opts.DownloadEmojis {
+			options = append(options, converter.WithEmojiImages())
+		}
+	}
+	if opts.CalendarEventDays > 0 {
+		options = append(options, converter.WithCalendarEvents(opts.CalendarEventDays))
+	}
+	if opts.ExecuteSearch {
+		options = append(options, converter.WithSearchExecution())
+	}
+	if opts.NumberHeadings {
+		options = append(options, converter.WithNumberedHeadings())
+	}
+	if opts.SourceComments {
+		options = append(options, converter.WithSourceComments())
+	}
+	if opts.JiraBaseURL != "" {
+		options = append(options, converter.WithJiraBaseURL(opts.JiraBaseURL))
+	}
+	if opts.UserPlaceholder != "" {
+		options = append(options, converter.WithUnresolvedUserPlaceholder(opts.UserPlaceholder))
+	}
+	if len(opts.LinkMap) > 0 {
+		options = append(options, converter.WithLinkMap(opts.LinkMap))
+	}
+	if len(opts.LinkRewrites) > 0 {
+		options = append(options, converter.WithLinkRewrites(opts.LinkRewrites))
+	}
+	if opts.UnknownMacroMode != "" {
+		options = append(options, converter.WithUnknownMacroMode(opts.UnknownMacroMode))
+	}
+	if opts.TableMode != "" {
+		options = append(options, converter.WithTableMode(opts.TableMode))
+	}
+	if opts.Dialect != "" {
+		options = append(options, converter.WithDialect(opts.Dialect))
+	}
+	if opts.FencedDivs {
+		options = append(options, converter.WithFencedDivs())
+	}
+	if opts.InlineNoformat > 0 {
+		options = append(options, converter.WithInlineNoformat(opts.InlineNoformat))
+	}
+	if opts.RowHeaderStyle != "" {
+		options = append(options, converter.WithRowHeaderStyle(opts.RowHeaderStyle))
+	}
+	if opts.AnchorStyle != "" {
+		options = append(options, converter.WithAnchorStyle(opts.AnchorStyle))
+	}
+	if opts.HeadingIDStyle != "" {
+		options = append(options, converter.WithHeadingIDs(opts.HeadingIDStyle))
+	}
+	if opts.Accessibility != "" {
+		options = append(options, converter.WithAccessibilityCheck(opts.Accessibility == "fix"))
+	}
+	if len(opts.Redactions) > 0 {
+		options = append(options, converter.WithRedactions(opts.Redactions))
+	}
+	if opts.StripMarked {
+		options = append(options, converter.WithContentFilter(converter.ContentFilter{
+			Labels:  opts.StripLabels,
+			Macros:  converter.DefaultContentFilter.Macros,
+			Markers: opts.StripMarkers,
+		}))
+	}
+	if opts.Labels != nil {
+		options = append(options, converter.WithLabels(opts.Labels))
+	}
+	if opts.WorkflowStatus || opts.WorkflowBanner {
+		options = append(options, converter.WithWorkflowStatus(opts.WorkflowBanner))
+	}
+	if len(opts.DropMacros) > 0 || len(opts.OnlyMacros) > 0 {
+		options = append(options, converter.WithMacroFilter(opts.DropMacros, opts.OnlyMacros))
+	}
+	if len(opts.MacroHandlers) > 0 {
+		options = append(options, converter.WithMacroHandlers(opts.MacroHandlers))
+	}
+	if len(opts.TitleRules) > 0 {
+		options = append(options, converter.WithDocumentTitleRules(opts.TitleRules))
+	}
+	if len(opts.AllowLinkHosts) > 0 || len(opts.DenyLinkHosts) > 0 {
+		options = append(options, converter.WithLinkPolicy(converter.LinkPolicy{
+			Allow: opts.AllowLinkHosts,
+			Deny:  opts.DenyLinkHosts,
+		}))
+	}
+	return options
 }
 
 // printConversionResult prints the result of a page conversion in a consistent format
@@ -103,6 +371,37 @@ func printConversionResult(result *PageConversionResult) {
 		if result.ImagesCount > 0 {
 			fmt.Printf("   📥 Images downloaded: %d\n", result.ImagesCount)
 		}
+		if result.DocxPath != "" {
+			fmt.Printf("   📄 Word document: %s\n", result.DocxPath)
+		}
+		if result.PreviewPath != "" {
+			fmt.Printf("   🌐 Preview: %s\n", result.PreviewPath)
+		}
+		if result.SectionsCount > 0 {
+			fmt.Printf("   ✂️  Sections written: %d\n", result.SectionsCount)
+		}
+		if result.InlinedCount > 0 {
+			fmt.Printf("   📎 Child pages inlined: %d\n", result.InlinedCount)
+		}
+		if total := result.redactionCount(); total > 0 {
+			fmt.Printf("   🕶️  Redactions: %d\n", total)
+		}
+		if len(result.Accessibility) > 0 {
+			fmt.Printf("   ♿ Accessibility issues: %d\n", len(result.Accessibility))
+		}
+		if result.Fidelity != nil && result.Fidelity.Score < 1 {
+			fmt.Printf("   🎯 Fidelity: %.0f%% (review recommended)\n", result.Fidelity.Score*100)
+		}
+	} else if result.Unchanged {
+		fmt.Printf("⏩ Unchanged since the previous export: %s\n", result.OutputPath)
+	} else if result.Skipped {
+		fmt.Printf("⏭️  Skipped page excluded from export: %s\n", result.Title)
+		fmt.Printf("   Page ID: %s\n", result.PageID)
+		fmt.Printf("   Reason: %v\n", result.Error)
+	} else if result.Stubbed {
+		fmt.Printf("📝 Wrote stub for unexported page: %s\n", result.OutputPath)
+		fmt.Printf("   Page ID: %s\n", result.PageID)
+		fmt.Printf("   Reason: %v\n", result.Error)
 	} else {
 		fmt.Printf("❌ Failed to convert page: %s\n", result.Title)
 		if result.Error != nil {
@@ -112,6 +411,38 @@ func printConversionResult(result *PageConversionResult) {
 	fmt.Println()
 }
 
+// urlsToPageInfos parses several page URLs that must all belong to the same Confluence instance
+func urlsToPageInfos(pageURLs []string) ([]confluenceModel.PageURLInfo, error) {
+	var pageInfos []confluenceModel.PageURLInfo
+	for _, pageURL := range pageURLs {
+		pageInfo, err := urlToPageInfo(pageURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Confluence URL: %w", err)
+		}
+		if len(pageInfos) > 0 && pageInfo.BaseURL != pageInfos[0].BaseURL {
+			return nil, fmt.Errorf("all page URLs must belong to the same Confluence instance, got %s and %s", pageInfos[0].BaseURL, pageInfo.BaseURL)
+		}
+		pageInfos = append(pageInfos, pageInfo)
+	}
+	return pageInfos, nil
+}
+
+// resolvePageIDs returns the page ID of each URL, looking up pages addressed by space and title
+func resolvePageIDs(client confluence.Client, pageInfos []confluenceModel.PageURLInfo) ([]string, error) {
+	pageIDs := make([]string, 0, len(pageInfos))
+	for _, pageInfo := range pageInfos {
+		if pageInfo.PageID == "" {
+			pageID, err := client.RetrievePageID(pageInfo.SpaceKey, pageInfo.Title)
+			if err != nil {
+				return nil, fmt.Errorf("failed to retrieve page ID: %w", err)
+			}
+			pageInfo.PageID = pageID
+		}
+		pageIDs = append(pageIDs, pageInfo.PageID)
+	}
+	return pageIDs, nil
+}
+
 func urlToPageInfo(pageURL string) (confluenceModel.PageURLInfo, error) {
 	if pageURL == "" {
 		return confluenceModel.PageURLInfo{}, fmt.Errorf("URL is empty")
@@ -131,13 +462,32 @@ func urlToPageInfo(pageURL string) (confluenceModel.PageURLInfo, error) {
 	// Path format: 
 	// /display/SPACE/Title
 	// /pages/viewpage.action?pageId=622848016
-	if strings.HasPrefix(u.Path, "/display/") {
+	// /wiki/spaces/SPACE/pages/12345/Title (Cloud; personal spaces use ~accountid as the key)
+	if contextPath, rest, ok := strings.Cut(u.Path, "/spaces/"); ok && strings.Contains(rest, "/pages/") {
+		parts := strings.Split(rest, "/")
+		if len(parts) < 3 || parts[0] == "" || parts[1] != "pages" {
+			return confluenceModel.PageURLInfo{}, fmt.Errorf("could not extract page space and ID from URL")
+		}
+		baseURL += contextPath
+		spaceKey = parts[0]
+		if parts[2] == "edit-v2" && len(parts) > 3 {
+			parts = parts[1:]
+		}
+		pageID = parts[2]
+		if len(parts) > 3 {
+			title = parts[3]
+		}
+		if _, err := strconv.ParseUint(pageID, 10, 64); err != nil {
+			return confluenceModel.PageURLInfo{}, fmt.Errorf("could not extract page id from URL")
+		}
+	} else if contextPath, rest, ok := strings.Cut(u.Path, "/display/"); ok {
 		// 去除前缀后按 "/" 分割
 		// TrimPrefix 变成 "SPACE/Title"
 		// SplitN 限制分割次数，防止 Title 中包含 "/" 导致被截断（尽管 Title 通常不含 /）
-		parts := strings.SplitN(strings.TrimPrefix(u.Path, "/display/"), "/", 2)
+		parts := strings.SplitN(rest, "/", 2)
 
 		if len(parts) == 2 {
+			baseURL += contextPath
 			spaceKey = parts[0]
 			title = parts[1] 
 			// 注意：u.Path 已经被自动解码了（例如 %20 会变成空格），所以这里不需要额外解码
@@ -146,12 +496,13 @@ func urlToPageInfo(pageURL string) (confluenceModel.PageURLInfo, error) {
 		}
 
 		// 情况 2: /pages/viewpage.action?pageId=...
-	} else if strings.Contains(u.Path, "viewpage.action") {
+	} else if contextPath, _, ok := strings.Cut(u.Path, "/pages/viewpage.action"); ok {
 		// 获取查询参数 (query params)
 		queryParams := u.Query()
 		if pageID = queryParams.Get("pageId"); pageID == "" {
 			return confluenceModel.PageURLInfo{}, fmt.Errorf("could not extract page id from URL")
 		}
+		baseURL += contextPath
 	} else {
 		return confluenceModel.PageURLInfo{}, fmt.Errorf("Invalid URL")
 	}
@@ -159,7 +510,16 @@ func urlToPageInfo(pageURL string) (confluenceModel.PageURLInfo, error) {
 	return confluenceModel.PageURLInfo{
 		BaseURL:  baseURL,
 		PageID:   pageID,
-		SpaceKey: spaceKey,
+		SpaceKey: normalizeSpaceKey(spaceKey),
 		Title:    title,
 	}, nil
 }
+
+// normalizeSpaceKey upper-cases global space keys, which URLs may spell in any case.
+// Personal space keys (~username or ~accountid) are kept as they are.
+func normalizeSpaceKey(key string) string {
+	if key == "" || strings.HasPrefix(key, "~") {
+		return key
+	}
+	return strings.ToUpper(key)
+}
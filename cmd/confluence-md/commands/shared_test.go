@@ -0,0 +1,20 @@
+package commands
+
+import "testing"
+
+func TestNormalizeSpaceKey(t *testing.T) {
+	tests := map[string]string{
+		"":                          "",
+		"eng":                       "ENG",
+		"Eng":                       "ENG",
+		"DOCS":                      "DOCS",
+		"~jsmith":                   "~jsmith",
+		"~557058:f2c1e3a0-AbCd":     "~557058:f2c1e3a0-AbCd",
+		"~5b10ac8d82e05b22cc7d4ef5": "~5b10ac8d82e05b22cc7d4ef5",
+	}
+	for key, want := range tests {
+		if got := normalizeSpaceKey(key); got != want {
+			t.Errorf("normalizeSpaceKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	"github.com/jackchuka/confluence-md/internal/converter"
+)
+
+const (
+	sidebarFileName    = "_sidebar.md"
+	spaceIndexFileName = "index.md"
+)
+
+// spaceRoot groups the page trees exported into one output directory
+type spaceRoot struct {
+	outputDir string
+	spaceKey  string
+	trees     []*PageNode
+}
+
+// addSpaceRoot appends the tree to the root for outputDir, creating it on first use
+func addSpaceRoot(roots []*spaceRoot, outputDir string, tree *PageNode) []*spaceRoot {
+	for _, root := range roots {
+		if root.outputDir == outputDir {
+			root.trees = append(root.trees, tree)
+			return roots
+		}
+	}
+	return append(roots, &spaceRoot{outputDir: outputDir, spaceKey: tree.SpaceKey, trees: []*PageNode{tree}})
+}
+
+// writeSpaceSidebar writes a navigation file with the space shortcuts and the exported page hierarchy
+func writeSpaceSidebar(client confluence.Client, baseURL string, root *spaceRoot, results *ConversionResults, opts *TreeOptions) (string, error) {
+	shortcuts, err := client.GetSpaceShortcuts(root.spaceKey)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to fetch shortcuts for space %s: %v\n", root.spaceKey, err)
+	}
+
+	outputPaths := exportedOutputPaths(results)
+
+	var entries []converter.NavEntry
+	var walk func(node *PageNode, depth int)
+	walk = func(node *PageNode, depth int) {
+		if node == nil || node.Error != nil {
+			return
+		}
+		entries = append(entries, navEntry(node, depth, root.outputDir, outputPaths, opts.TitleRules))
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+	}
+	for _, tree := range root.trees {
+		walk(tree, 0)
+	}
+
+	path := filepath.Join(root.outputDir, sidebarFileName)
+	if err := outputFS.MkdirAll(root.outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+	content := converter.RenderSidebar(root.spaceKey, baseURL, shortcuts, entries)
+	if err := outputFS.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write sidebar: %w", err)
+	}
+	return path, nil
+}
+
+// exportedOutputPaths maps the IDs of pages with a file of their own to its path
+func exportedOutputPaths(results *ConversionResults) map[string]string {
+	outputPaths := make(map[string]string)
+	for _, page := range results.Pages {
+		if page.Success || page.Unchanged {
+			outputPaths[page.PageID] = page.OutputPath
+		}
+	}
+	return outputPaths
+}
+
+// navEntry lists a page in a navigation file written to dir, linking its file when it has one
+func navEntry(node *PageNode, depth int, dir string, outputPaths map[string]string, titleRules converter.TitleRules) converter.NavEntry {
+	entry := converter.NavEntry{Title: titleRules.Apply(node.Title), Depth: depth}
+	if outputPath, ok := outputPaths[node.ID]; ok {
+		if rel, err := filepath.Rel(dir, outputPath); err == nil {
+			entry.Path = filepath.ToSlash(rel)
+		}
+	}
+	return entry
+}
+
+// writeSpaceIndex writes an index.md with the space description, labels, categories and admins and links to the root pages
+func writeSpaceIndex(client confluence.Client, baseURL string, root *spaceRoot, results *ConversionResults, opts *TreeOptions) (string, error) {
+	space, err := client.GetSpace(root.spaceKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch space %s: %w", root.spaceKey, err)
+	}
+
+	outputPaths := exportedOutputPaths(results)
+	var entries []converter.NavEntry
+	for _, tree := range root.trees {
+		if tree != nil && tree.Error == nil {
+			entries = append(entries, navEntry(tree, 0, root.outputDir, outputPaths, opts.TitleRules))
+		}
+	}
+
+	path := filepath.Join(root.outputDir, spaceIndexFileName)
+	doc := converter.NewSpaceIndexDocument(space, baseURL, entries)
+	if err := converter.SaveMarkdownDocument(outputFS, doc, path, opts.IncludeMetadata); err != nil {
+		return "", fmt.Errorf("failed to write space index: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,249 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/spf13/cobra"
+)
+
+const spaceManifestFileName = "manifest.json"
+
+// SiteOptions contains all options for the site command
+type SiteOptions struct {
+	TreeOptions
+
+	SpaceType     string   // "global", "personal" or "all"
+	IncludeSpaces []string // Glob patterns of space keys to export
+	ExcludeSpaces []string // Glob patterns of space keys to skip
+	Orphans       bool     // Also export pages not reachable from the space home page
+	BlogPosts     bool     // Also export blog posts into _blog/<year>/
+	Attachments   bool     // Also download attachments whose page or blog post was not exported into _attachments/
+}
+
+var siteOpts SiteOptions
+
+// siteCmd exports every readable space of a Confluence instance
+var siteCmd = &cobra.Command{
+	Use:   "site <base-url>",
+	Short: "Export every space of a Confluence site",
+	Long: `Enumerate every space the API token can read and export each space's page
+tree into <output>/<SPACEKEY>/, with a manifest.json per space listing the
+exported pages and any errors. Intended for full-instance archival, for example
+before decommissioning a site.
+
+Pages that are not below a space's home page are skipped unless --orphans is
+set, which exports them into <output>/<SPACEKEY>/_orphans/. Use --blogposts to
+also export blog posts into <output>/<SPACEKEY>/_blog/<year>/, and
+--standalone-attachments to download attachments whose page or blog post was
+not exported into <output>/<SPACEKEY>/_attachments/<container-id>/.
+
+Examples:
+  # Archive all global spaces, skipping personal spaces
+  confluence-md site https://confluence.example.com --space-type global --output ./archive
+
+  # Only export spaces whose key starts with ENG, except ENGOLD
+  confluence-md site https://confluence.example.com --include-space 'ENG*' --exclude-space ENGOLD`,
+	RunE: runSiteCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(siteCmd)
+
+	siteOpts.authOptions.InitFlags(siteCmd)
+	siteOpts.commonOptions.InitFlags(siteCmd)
+
+	siteCmd.Flags().StringVar(&siteOpts.SpaceType, "space-type", "all", "Spaces to export: global, personal or all")
+	siteCmd.Flags().StringSliceVar(&siteOpts.IncludeSpaces, "include-space", nil, "Only export spaces whose key matches these glob patterns")
+	siteCmd.Flags().StringSliceVar(&siteOpts.ExcludeSpaces, "exclude-space", nil, "Skip spaces whose key matches these glob patterns")
+	siteCmd.Flags().BoolVar(&siteOpts.Orphans, "orphans", false, "Also export pages not reachable from the space home page into _orphans/")
+	siteCmd.Flags().BoolVar(&siteOpts.BlogPosts, "blogposts", false, "Also export each space's blog posts into _blog/<year>/")
+	siteCmd.Flags().BoolVar(&siteOpts.Attachments, "standalone-attachments", false, "Also download attachments whose page or blog post was not exported into _attachments/")
+
+	siteCmd.Flags().IntVar(&siteOpts.MaxDepth, "depth", -1, "Maximum depth to traverse (-1 for unlimited)")
+	siteCmd.Flags().IntVar(&siteOpts.Parallel, "parallel", 3, "Number of parallel page fetches")
+	siteCmd.Flags().IntVar(&siteOpts.ConvertWorkers, "convert-workers", runtime.NumCPU(), "Number of pages converted concurrently")
+	siteCmd.Flags().IntVar(&siteOpts.WriteWorkers, "write-workers", 2, "Number of documents written to disk concurrently")
+	siteCmd.Flags().BoolVar(&siteOpts.PopularFirst, "popular-first", false, "Convert the most viewed pages of each space first (Confluence Analytics API)")
+	siteCmd.Flags().StringSliceVar(&siteOpts.Exclude, "exclude", []string{}, "Glob patterns to exclude pages")
+	siteCmd.Flags().IntVar(&siteOpts.InlineChildrenBelowDepth, "inline-children-below-depth", -1, "Append leaf pages deeper than this depth to their parent document (-1 to disable)")
+	siteCmd.Flags().BoolVar(&siteOpts.SpaceSidebar, "space-sidebar", false, "Write a _sidebar.md per space with its sidebar shortcuts and the exported page hierarchy")
+	siteCmd.Flags().BoolVar(&siteOpts.SpaceIndex, "space-index", false, "Write an index.md per space with its description, labels, categories and admins in the frontmatter")
+	siteCmd.Flags().StringVar(&siteOpts.StateFile, "state-file", "", "Record each exported page's version and content hash in this file and skip pages that have not changed since")
+	siteCmd.Flags().StringVar(&siteOpts.RestrictionsReport, "restrictions-report", "", "Write a CSV of restricted pages and their principals with this file name into each space directory")
+}
+
+func runSiteCommand(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: Confluence base URL")
+	}
+	baseURL := strings.TrimSuffix(args[0], "/")
+
+	spaceType := siteOpts.SpaceType
+	switch spaceType {
+	case "all":
+		spaceType = ""
+	case "global", "personal":
+	default:
+		return fmt.Errorf("invalid options: space-type must be global, personal or all, got: %s", siteOpts.SpaceType)
+	}
+
+	if siteOpts.MaxDepth < -1 {
+		return fmt.Errorf("invalid options: depth must be -1 (unlimited) or greater, got: %d", siteOpts.MaxDepth)
+	}
+	if siteOpts.Parallel < 1 || siteOpts.ConvertWorkers < 1 || siteOpts.WriteWorkers < 1 {
+		return fmt.Errorf("invalid options: parallel, convert-workers and write-workers must be at least 1")
+	}
+
+	if err := siteOpts.resolve(siteOpts.commonOptions); err != nil {
+		return err
+	}
+
+	client := confluence.NewClient(baseURL, siteOpts.APIKey, clientOptions()...)
+
+	spaces, err := client.ListSpaces(spaceType)
+	if err != nil {
+		return err
+	}
+	if siteOpts.state, err = loadSyncState(siteOpts.StateFile, siteOpts.OutputDir); err != nil {
+		return err
+	}
+	spaces = filterSpaces(spaces, siteOpts.IncludeSpaces, siteOpts.ExcludeSpaces)
+	fmt.Printf("🌐 Exporting %d spaces from %s\n", len(spaces), baseURL)
+
+	siteResults := &ConversionResults{}
+	failedSpaces := 0
+	for i, space := range spaces {
+		fmt.Printf("\n📚 [%d/%d] %s (%s)\n", i+1, len(spaces), space.Name, space.Key)
+
+		results, err := exportSpace(client, baseURL, space, &siteOpts)
+		// Saving after every space keeps the finished ones when a long run is interrupted
+		if saveErr := siteOpts.state.save(); saveErr != nil {
+			return saveErr
+		}
+		if err != nil {
+			fmt.Printf("  ❌ Failed to export space %s: %v\n", space.Key, err)
+			failedSpaces++
+			continue
+		}
+
+		for _, result := range results.Pages {
+			siteResults.record(result)
+		}
+		fmt.Printf("  📊 %s: %d pages exported, %d failed (overall %d/%d spaces, %d pages)\n",
+			space.Key, results.Success, results.Failed, i+1, len(spaces), siteResults.Success)
+	}
+
+	if err := writeExportInfo(siteOpts.Run, siteOpts.OutputDir, baseURL, siteResults); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n✅ Site export complete!\n")
+	fmt.Printf("  Spaces: %d\n", len(spaces)-failedSpaces)
+	fmt.Printf("  Pages: %d\n", siteResults.Success)
+	if siteResults.Unchanged > 0 {
+		fmt.Printf("  Unchanged since the previous export: %d pages\n", siteResults.Unchanged)
+	}
+	if failedSpaces > 0 || siteResults.Failed > 0 {
+		fmt.Printf("  Failed: %d spaces, %d pages (see space manifests)\n", failedSpaces, siteResults.Failed)
+		printFailureSummary(siteResults.failureGroups(), baseURL)
+	}
+	fmt.Printf("  Output: %s\n", siteOpts.OutputDir)
+	siteOpts.ShortenedPaths.printWarning()
+
+	if failedSpaces > 0 || siteResults.Failed > 0 {
+		return fmt.Errorf("site export completed with errors")
+	}
+	return servePreview(siteOpts.ServePreview, siteOpts.OutputDir, "")
+}
+
+// filterSpaces keeps the spaces whose key matches an include pattern (all when none) and no exclude pattern
+func filterSpaces(spaces []confluenceModel.ConfluenceSpace, include, exclude []string) []confluenceModel.ConfluenceSpace {
+	var filtered []confluenceModel.ConfluenceSpace
+	for _, space := range spaces {
+		if len(include) > 0 && !shouldExclude(space.Key, include) {
+			continue
+		}
+		if shouldExclude(space.Key, exclude) {
+			continue
+		}
+		filtered = append(filtered, space)
+	}
+	return filtered
+}
+
+// exportSpace exports the page tree below the space's home page into <output>/<SPACEKEY> with a manifest.
+// Orphaned pages, blog posts and standalone attachments are exported into their own subtrees when enabled.
+func exportSpace(client confluence.Client, baseURL string, space confluenceModel.ConfluenceSpace, site *SiteOptions) (*ConversionResults, error) {
+	opts := &site.TreeOptions
+	if space.HomepageID == "" {
+		return nil, fmt.Errorf("space has no home page")
+	}
+
+	spaceOpts := *opts
+	spaceOpts.OutputDir = filepath.Join(opts.OutputDir, sanitizeSpaceDir(space.Key))
+	spaceOpts.SpaceDirs = false
+	if err := outputFS.MkdirAll(spaceOpts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	trees, err := fetchPageTrees(client, []string{space.HomepageID}, &spaceOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := convertTrees(client, baseURL, trees, &spaceOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if site.Orphans {
+		orphanResults, err := exportOrphans(client, baseURL, space, &spaceOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export orphaned pages: %w", err)
+		}
+		for _, result := range orphanResults.Pages {
+			results.record(result)
+		}
+	}
+
+	if site.BlogPosts {
+		blogResults, err := exportBlogPosts(client, baseURL, space, &spaceOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export blog posts: %w", err)
+		}
+		for _, result := range blogResults.Pages {
+			results.record(result)
+		}
+	}
+
+	if site.Attachments {
+		if err := exportStandaloneAttachments(client, space, exportedContentIDs(trees, results), spaceOpts.OutputDir, &spaceOpts.resolvedOptions); err != nil {
+			fmt.Printf("  ⚠️  Warning: %v\n", err)
+		}
+	}
+
+	if err := writeReport(filepath.Join(spaceOpts.OutputDir, spaceManifestFileName), results.Pages); err != nil {
+		return nil, fmt.Errorf("failed to write space manifest: %w", err)
+	}
+
+	if opts.RestrictionsReport != "" {
+		reportPath := filepath.Join(spaceOpts.OutputDir, filepath.Base(opts.RestrictionsReport))
+		if err := writeRestrictionsReport(client, trees, reportPath); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// sanitizeSpaceDir names personal space directories (key ~user) personal-user, avoiding a leading tilde
+func sanitizeSpaceDir(key string) string {
+	if user, ok := strings.CutPrefix(key, "~"); ok {
+		return "personal-" + user
+	}
+	return key
+}
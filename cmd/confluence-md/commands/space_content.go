@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+const (
+	// blogDirName is the directory, relative to the space directory, that blog posts are exported into by year
+	blogDirName = "_blog"
+
+	// standaloneAttachmentsDirName holds attachments whose page or blog post is not part of the export
+	standaloneAttachmentsDirName = "_attachments"
+)
+
+// blogPostNodes turns blog posts into root nodes filed under the year they were published
+func blogPostNodes(posts []*confluenceModel.ConfluencePage) []*PageNode {
+	nodes := make([]*PageNode, 0, len(posts))
+	for _, post := range posts {
+		path := []string{post.Title}
+		if !post.CreatedAt.IsZero() {
+			path = []string{strconv.Itoa(post.CreatedAt.Year()), post.Title}
+		}
+		nodes = append(nodes, &PageNode{
+			ID:       post.ID,
+			Title:    post.Title,
+			SpaceKey: post.SpaceKey,
+			Labels:   post.GetLabelNames(),
+			Version:  post.Version,
+			Path:     path,
+		})
+	}
+	return nodes
+}
+
+// exportBlogPosts exports the space's blog posts into <output>/_blog/<year>/
+func exportBlogPosts(client confluence.Client, baseURL string, space confluenceModel.ConfluenceSpace, opts *TreeOptions) (*ConversionResults, error) {
+	posts, err := client.ListSpaceBlogPosts(space.Key)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return &ConversionResults{}, nil
+	}
+	fmt.Printf("  📰 Found %d blog posts\n", len(posts))
+
+	blogOpts := *opts
+	blogOpts.OutputDir = filepath.Join(opts.OutputDir, blogDirName)
+	// The sidebar and index describe the page tree, not the blog
+	blogOpts.SpaceSidebar = false
+	blogOpts.SpaceIndex = false
+	if err := outputFS.MkdirAll(blogOpts.OutputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	return convertTrees(client, baseURL, blogPostNodes(posts), &blogOpts)
+}
+
+// exportStandaloneAttachments downloads the space's attachments whose container was not exported
+// into <output>/_attachments/<container-id>/ with an attachments.json manifest, with paths fitted into the path limits
+func exportStandaloneAttachments(client confluence.Client, space confluenceModel.ConfluenceSpace, exported map[string]bool, outputDir string, opts *resolvedOptions) error {
+	attachments, err := client.ListSpaceAttachments(space.Key)
+	if err != nil {
+		return err
+	}
+
+	var standalone []confluenceModel.ConfluenceAttachment
+	for _, attachment := range attachments {
+		if !exported[attachment.ContainerID] {
+			standalone = append(standalone, attachment)
+		}
+	}
+	if len(standalone) == 0 {
+		return nil
+	}
+
+	dir := filepath.Join(outputDir, standaloneAttachmentsDirName)
+	fmt.Printf("  📎 Downloading %d standalone attachments into %s\n", len(standalone), dir)
+
+	entries := make([]attachmentEntry, 0, len(standalone))
+	failed := 0
+	for i := range standalone {
+		attachment := &standalone[i]
+		containerDir := filepath.Join(dir, sanitizeAttachmentName(attachment.ContainerID))
+		filePath := opts.ShortenedPaths.shortenBelow(opts.PathLimits, outputDir, filepath.Join(containerDir, sanitizeAttachmentName(attachment.Title)))
+
+		entry := attachmentEntry{
+			PageID:    attachment.ContainerID,
+			FileName:  attachment.Title,
+			MediaType: attachment.MediaType,
+			Size:      attachment.FileSize,
+			Version:   attachment.Version,
+		}
+		if rel, err := filepath.Rel(dir, filePath); err == nil {
+			entry.Path = filepath.ToSlash(rel)
+		}
+
+		err := outputFS.MkdirAll(filepath.Dir(filePath), 0755)
+		if err == nil {
+			err = streamAttachment(client, attachment, filePath)
+		}
+		if err != nil {
+			fmt.Printf("  ❌ Failed to download %s: %v\n", attachment.Title, err)
+			entry.Error = err.Error()
+			failed++
+		}
+		entries = append(entries, entry)
+	}
+
+	if _, err := writeAttachmentManifest(dir, entries); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d standalone attachments failed to download", failed, len(entries))
+	}
+	return nil
+}
+
+// exportedContentIDs collects the IDs of every page in the trees and every page with a result
+func exportedContentIDs(trees []*PageNode, results *ConversionResults) map[string]bool {
+	ids := make(map[string]bool)
+
+	var walk func(node *PageNode)
+	walk = func(node *PageNode) {
+		if node == nil {
+			return
+		}
+		ids[node.ID] = true
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	for _, tree := range trees {
+		walk(tree)
+	}
+
+	for _, result := range results.Pages {
+		ids[result.PageID] = true
+	}
+	return ids
+}
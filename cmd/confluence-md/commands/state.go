@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// syncStateVersion is bumped when the state file layout changes; older state files are ignored
+const syncStateVersion = 2
+
+// syncState is the --state-file of an incremental export, recording what was written for each page.
+// record may be called from several goroutines.
+type syncState struct {
+	mu    sync.Mutex
+	path  string
+	root  string // output directory the recorded paths are relative to
+	pages map[string]syncedPage
+}
+
+// syncedPage is the state of one exported page
+type syncedPage struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Version int    `json:"version"`
+	Path    string `json:"path"` // relative to the output directory, with forward slashes
+	Hash    string `json:"sha256"`
+}
+
+// syncStateFile is the JSON layout of a state file
+type syncStateFile struct {
+	Version int          `json:"version"`
+	Pages   []syncedPage `json:"pages"`
+}
+
+// loadSyncState reads the state file at path for an export into outputDir. A missing file starts an empty state,
+// and an empty path disables it.
+func loadSyncState(path, outputDir string) (*syncState, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	state := &syncState{path: path, root: outputDir, pages: make(map[string]syncedPage)}
+	data, err := outputFS.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var file syncStateFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", path, err)
+	}
+	if file.Version != syncStateVersion {
+		fmt.Printf("⚠️  Warning: ignoring state file %s written by another version, exporting every page\n", path)
+		return state, nil
+	}
+	for _, page := range file.Pages {
+		state.pages[page.ID] = page
+	}
+	return state, nil
+}
+
+// unchangedResult returns the result for a page whose recorded version and path are current and whose file is
+// still as written, or nil when it must be converted
+func (s *syncState) unchangedResult(job *treeJob, opts *resolvedOptions) *PageConversionResult {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	previous, ok := s.pages[job.node.ID]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	path := filepath.Join(s.root, filepath.FromSlash(previous.Path))
+	result := keepPreviousExport(job, opts, previous.Version, path)
+	if result == nil {
+		return nil
+	}
+	// A deleted or locally edited file is written again
+	if hash, err := hashFile(path); err != nil || hash != previous.Hash {
+		return nil
+	}
+	return result
+}
+
+// record stores the version and content hash of a page written by this run
+func (s *syncState) record(node *PageNode, result *PageConversionResult) {
+	if s == nil || !result.Success {
+		return
+	}
+
+	path, err := filepath.Rel(s.root, result.OutputPath)
+	var hash string
+	if err == nil {
+		hash, err = hashFile(result.OutputPath)
+	}
+	if err != nil {
+		fmt.Printf("  ⚠️  Failed to record %s in the state file: %v\n", node.Title, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages[node.ID] = syncedPage{
+		ID:      node.ID,
+		Title:   node.Title,
+		Version: node.Version,
+		Path:    filepath.ToSlash(path),
+		Hash:    hash,
+	}
+}
+
+// save writes the state file, keeping the pages of earlier runs that this run did not visit
+func (s *syncState) save() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	file := syncStateFile{Version: syncStateVersion, Pages: make([]syncedPage, 0, len(s.pages))}
+	for _, page := range s.pages {
+		file.Pages = append(file.Pages, page)
+	}
+	s.mu.Unlock()
+	sort.Slice(file.Pages, func(i, j int) bool {
+		return file.Pages[i].ID < file.Pages[j].ID
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state file: %w", err)
+	}
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := outputFS.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create state file directory: %w", err)
+		}
+	}
+	if err := outputFS.WriteFile(s.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+// hashFile returns the hex SHA-256 of a written file
+func hashFile(path string) (string, error) {
+	data, err := outputFS.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
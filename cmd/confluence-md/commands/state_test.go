@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncStateUnchangedResult(t *testing.T) {
+	planned := filepath.Join("out", "home", "guide.md")
+
+	tests := []struct {
+		name string
+		// change edits the recorded state or the written file after the page was recorded
+		change func(t *testing.T, state *syncState)
+		want   bool
+	}{
+		{"unchanged", func(t *testing.T, state *syncState) {}, true},
+		{"file edited locally", func(t *testing.T, state *syncState) {
+			if err := outputFS.WriteFile(planned, []byte("edited"), 0644); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+		}, false},
+		{"file deleted", func(t *testing.T, state *syncState) {
+			if err := outputFS.Remove(planned); err != nil {
+				t.Fatalf("Remove() error = %v", err)
+			}
+		}, false},
+		{"hash mismatch", func(t *testing.T, state *syncState) {
+			page := state.pages["2"]
+			page.Hash = "0000"
+			state.pages["2"] = page
+		}, false},
+		{"moved path", func(t *testing.T, state *syncState) {
+			page := state.pages["2"]
+			page.Path = "home/old-guide.md"
+			state.pages["2"] = page
+		}, false},
+		{"version bump", func(t *testing.T, state *syncState) {
+			page := state.pages["2"]
+			page.Version = 2
+			state.pages["2"] = page
+		}, false},
+		{"page not recorded", func(t *testing.T, state *syncState) {
+			delete(state.pages, "2")
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := useMemOutput(t)
+			writeExportedPage(t, mem, planned, "2", 3)
+			root := addTestPage(nil, "1", "Home")
+			page := addTestPage(root, "2", "Guide")
+			page.Version = 3
+
+			state := &syncState{root: "out", pages: make(map[string]syncedPage)}
+			state.record(page, &PageConversionResult{Success: true, OutputPath: planned})
+			if got := state.pages["2"]; got.Path != "home/guide.md" || got.Version != 3 || got.Hash == "" {
+				t.Fatalf("recorded %+v, want the relative path, version and hash", got)
+			}
+			tt.change(t, state)
+
+			result := state.unchangedResult(&treeJob{node: page, outputDir: "out"}, &resolvedOptions{})
+			if (result != nil) != tt.want {
+				t.Fatalf("unchangedResult() = %+v, want kept %v", result, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncStateRecordSkipsFailures(t *testing.T) {
+	useMemOutput(t)
+	page := addTestPage(nil, "1", "Home")
+
+	state := &syncState{root: "out", pages: make(map[string]syncedPage)}
+	state.record(page, &PageConversionResult{Success: false, OutputPath: filepath.Join("out", "home.md")})
+	state.record(page, &PageConversionResult{Success: true, OutputPath: filepath.Join("out", "missing.md")})
+	if len(state.pages) != 0 {
+		t.Fatalf("recorded %+v, want failed and unreadable pages skipped", state.pages)
+	}
+
+	var disabled *syncState
+	disabled.record(page, &PageConversionResult{Success: true})
+	if result := disabled.unchangedResult(&treeJob{node: page, outputDir: "out"}, &resolvedOptions{}); result != nil {
+		t.Errorf("unchangedResult() without a state file = %+v, want nil", result)
+	}
+}
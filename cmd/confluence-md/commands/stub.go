@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// unreadableReason describes why a page could not be fetched, or returns "" when a stub does not apply
+func unreadableReason(err error) string {
+	switch {
+	case errors.Is(err, confluence.ErrForbidden):
+		return "not readable by this API token"
+	case errors.Is(err, confluence.ErrNotFound):
+		return "not found; it may have been deleted or moved"
+	}
+	return ""
+}
+
+// writePageStub writes a placeholder document for a page that could not be exported, so the
+// exported hierarchy stays navigable and the gap is visible
+func writePageStub(node *PageNode, reason string, opts *TreeOptions, outputDir string) (*PageConversionResult, error) {
+	page := &confluenceModel.ConfluencePage{ID: node.ID, Title: node.Title, SpaceKey: node.SpaceKey}
+	outputPath, err := getOutputPath(node, page, outputDir, &opts.resolvedOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &convModel.MarkdownDocument{
+		Frontmatter: convModel.Frontmatter{
+			Title:      node.Title,
+			Confluence: convModel.ConfluenceRef{PageID: node.ID, SpaceKey: node.SpaceKey},
+		},
+		Content: fmt.Sprintf("# %s\n\n> ⚠️ This page was not exported: %s.\n\nConfluence page ID: %s\n", converter.EscapeLinkText(node.Title), reason, node.ID),
+	}
+	if err := converter.SaveMarkdownDocument(outputFS, doc, outputPath, opts.IncludeMetadata); err != nil {
+		return nil, fmt.Errorf("failed to write stub: %w", err)
+	}
+
+	return &PageConversionResult{
+		OutputPath: outputPath,
+		PageID:     node.ID,
+		Title:      node.Title,
+		Stubbed:    true,
+		Error:      fmt.Errorf("page %s", reason),
+	}, nil
+}
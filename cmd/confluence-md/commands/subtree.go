@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+)
+
+// locateSubtrees replaces each root page with its shallowest descendant whose title matches pattern,
+// so an export can be rooted at a named page without knowing its URL
+func locateSubtrees(client confluence.Client, rootPageIDs []string, pattern string) ([]string, error) {
+	located := make([]string, 0, len(rootPageIDs))
+	for _, rootPageID := range rootPageIDs {
+		pageID, path, err := findDescendant(client, rootPageID, pattern)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Printf("📍 Exporting the subtree of %s\n", strings.Join(path, " › "))
+		located = append(located, pageID)
+	}
+	return located, nil
+}
+
+// findDescendant searches the descendants of a page breadth-first, so the match closest to the root wins,
+// and returns the matching page and the titles leading to it
+func findDescendant(client confluence.Client, rootPageID, pattern string) (string, []string, error) {
+	type candidate struct {
+		id   string
+		path []string
+	}
+
+	root, err := client.GetPage(rootPageID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get page %s: %w", rootPageID, err)
+	}
+
+	queue := []candidate{{id: root.ID, path: []string{root.Title}}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		children, err := client.GetChildPages(current.id)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get child pages of %s: %w", current.path[len(current.path)-1], err)
+		}
+		for _, child := range children {
+			path := append(current.path[:len(current.path):len(current.path)], child.Title)
+			if titleMatches(child.Title, pattern) {
+				return child.ID, path, nil
+			}
+			queue = append(queue, candidate{id: child.ID, path: path})
+		}
+	}
+	return "", nil, fmt.Errorf("no page matching %q found below %s", pattern, root.Title)
+}
+
+// titleMatches reports whether a title equals pattern ignoring case or matches it as a glob pattern
+func titleMatches(title, pattern string) bool {
+	if strings.EqualFold(title, pattern) {
+		return true
+	}
+	matched, _ := filepath.Match(pattern, title)
+	return matched
+}
+
+// applyMinDepth drops the pages above minDepth and makes the pages at minDepth the roots of the export,
+// so they are written at the top of the output directory
+func applyMinDepth(trees []*PageNode, minDepth int) []*PageNode {
+	if minDepth <= 0 {
+		return trees
+	}
+
+	var roots []*PageNode
+	var collect func(node *PageNode)
+	collect = func(node *PageNode) {
+		if node.Level == minDepth {
+			node.Parent = nil
+			rebasePaths(node, minDepth)
+			roots = append(roots, node)
+			return
+		}
+		for _, child := range node.Children {
+			collect(child)
+		}
+	}
+	for _, tree := range trees {
+		collect(tree)
+	}
+	return roots
+}
+
+// rebasePaths removes the titles of the dropped ancestors from the paths of a node and its descendants
+func rebasePaths(node *PageNode, dropped int) {
+	if len(node.Path) > dropped {
+		node.Path = node.Path[dropped:]
+	}
+	for _, child := range node.Children {
+		rebasePaths(child, dropped)
+	}
+}
@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/spf13/cobra"
+)
+
+// globalTemplatesDir holds the global templates, which belong to no space
+const globalTemplatesDir = "_global"
+
+// TemplatesOptions contains all options for the templates command
+type TemplatesOptions struct {
+	authOptions
+	commonOptions
+	resolvedOptions
+
+	Spaces     []string // Space keys whose templates are exported
+	Global     bool     // Also export the global templates
+	Blueprints bool     // Also export blueprint templates
+	List       bool     // Only list the templates
+}
+
+var templatesOpts TemplatesOptions
+
+// templatesCmd lists or exports page templates and blueprints
+var templatesCmd = &cobra.Command{
+	Use:   "templates <base-url>",
+	Short: "List or export page templates as Markdown",
+	Long: `List or export the page templates of Confluence spaces and the global
+templates, so authoring templates survive a migration off Confluence.
+
+Templates are written to <output>/<SPACEKEY>/ and global templates to
+<output>/_global/. Template variables become {{name}} and instructional
+placeholder text becomes HTML comments. Blueprint templates are included with
+--blueprints; blueprints whose body is not available through the API are
+skipped.
+
+Examples:
+  # List the templates of a space and the global templates
+  confluence-md templates https://confluence.example.com --space DOCS --global --list
+
+  # Export the templates and blueprints of two spaces
+  confluence-md templates https://confluence.example.com --space DOCS --space ENG --blueprints --output ./templates`,
+	RunE: runTemplatesCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(templatesCmd)
+
+	templatesOpts.authOptions.InitFlags(templatesCmd)
+	templatesOpts.commonOptions.InitFlags(templatesCmd)
+
+	templatesCmd.Flags().StringSliceVar(&templatesOpts.Spaces, "space", nil, "Space keys whose templates are exported (only global templates when empty)")
+	templatesCmd.Flags().BoolVar(&templatesOpts.Global, "global", false, "Also export the global templates (implied without --space)")
+	templatesCmd.Flags().BoolVar(&templatesOpts.Blueprints, "blueprints", false, "Also export blueprint templates")
+	templatesCmd.Flags().BoolVar(&templatesOpts.List, "list", false, "Only list the templates without exporting them")
+}
+
+func runTemplatesCommand(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: Confluence base URL")
+	}
+	baseURL := strings.TrimSuffix(args[0], "/")
+
+	if err := templatesOpts.resolve(templatesOpts.commonOptions); err != nil {
+		return err
+	}
+
+	client := confluence.NewClient(baseURL, templatesOpts.APIKey, clientOptions()...)
+
+	templates, err := listTemplates(client, &templatesOpts)
+	if err != nil {
+		return err
+	}
+
+	if templatesOpts.List {
+		return printTemplates(templates)
+	}
+
+	if err := outputFS.MkdirAll(templatesOpts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	fmt.Printf("🧩 Exporting %d templates from %s\n", len(templates), baseURL)
+	exported, skipped, failed := 0, 0, 0
+	for _, template := range templates {
+		if template.Body == "" {
+			fmt.Printf("  ⏭️  Skipped %s: no body available\n", template.Name)
+			skipped++
+			continue
+		}
+		outputPath, err := exportTemplate(client, template, baseURL, &templatesOpts)
+		if err != nil {
+			fmt.Printf("  ❌ Failed to export %s: %v\n", template.Name, err)
+			failed++
+			continue
+		}
+		fmt.Printf("  ✅ %s → %s\n", template.Name, outputPath)
+		exported++
+	}
+
+	fmt.Printf("✅ Template export complete!\n")
+	fmt.Printf("  Exported: %d templates\n", exported)
+	if skipped > 0 {
+		fmt.Printf("  Skipped (no body): %d templates\n", skipped)
+	}
+	if failed > 0 {
+		fmt.Printf("  Failed: %d templates\n", failed)
+	}
+	fmt.Printf("  Output: %s\n", templatesOpts.OutputDir)
+
+	if failed > 0 {
+		return fmt.Errorf("template export completed with errors")
+	}
+	return nil
+}
+
+// listTemplates collects the page (and optionally blueprint) templates of the selected spaces and the global templates
+func listTemplates(client confluence.Client, opts *TemplatesOptions) ([]confluenceModel.PageTemplate, error) {
+	spaceKeys := opts.Spaces
+	if opts.Global || len(spaceKeys) == 0 {
+		spaceKeys = append([]string{""}, spaceKeys...)
+	}
+	templateTypes := []string{"page"}
+	if opts.Blueprints {
+		templateTypes = append(templateTypes, "blueprint")
+	}
+
+	var templates []confluenceModel.PageTemplate
+	for _, spaceKey := range spaceKeys {
+		for _, templateType := range templateTypes {
+			found, err := client.ListTemplates(spaceKey, templateType)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, found...)
+		}
+	}
+	return templates, nil
+}
+
+// printTemplates writes the templates as a table
+func printTemplates(templates []confluenceModel.PageTemplate) error {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "SPACE\tTYPE\tID\tNAME\tDESCRIPTION")
+	for _, template := range templates {
+		space := template.SpaceKey
+		if space == "" {
+			space = "(global)"
+		}
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", space, template.Type, template.ID, template.Name, template.Description)
+	}
+	return writer.Flush()
+}
+
+// exportTemplate converts a template like a page and writes it below its space directory
+func exportTemplate(client confluence.Client, template confluenceModel.PageTemplate, baseURL string, opts *TemplatesOptions) (string, error) {
+	dir := template.SpaceKey
+	if dir == "" {
+		dir = globalTemplatesDir
+	}
+
+	page := &confluenceModel.ConfluencePage{
+		ID:       template.ID,
+		Title:    template.Name,
+		SpaceKey: dir,
+		Content: confluenceModel.ConfluenceContent{
+			Storage: confluenceModel.ContentStorage{Value: template.Body, Representation: "storage"},
+		},
+	}
+	for _, label := range template.Labels {
+		page.Metadata.Labels = append(page.Metadata.Labels, confluenceModel.Label{Name: label})
+	}
+
+	fileName, err := converter.GenerateFileName(page, opts.OutputNamer)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate output filename: %w", err)
+	}
+	outputPath := filepath.Join(opts.OutputDir, dir, fileName)
+
+	pageOpts := PageOptions{authOptions: opts.authOptions, commonOptions: opts.commonOptions, resolvedOptions: opts.resolvedOptions}
+	conv := converter.NewConverter(client, buildConverterOptions(pageOpts)...)
+	doc, err := conv.ConvertPage(page, baseURL, filepath.Dir(outputPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to convert template: %w", err)
+	}
+
+	// Templates are not pages, so the file must not point push at a page ID
+	doc.Frontmatter.Confluence = convModel.ConfluenceRef{}
+	doc.Frontmatter.Custom = map[string]any{
+		"template_id":    template.ID,
+		"template_type":  template.Type,
+		"template_space": template.SpaceKey,
+	}
+	if template.Description != "" {
+		doc.Frontmatter.Custom["description"] = template.Description
+	}
+
+	if err := converter.SaveMarkdownDocument(outputFS, doc, outputPath, opts.IncludeMetadata); err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
@@ -1,10 +1,12 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/jackchuka/confluence-md/internal/confluence"
 	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
@@ -16,23 +18,50 @@ import (
 type TreeOptions struct {
 	authOptions
 	commonOptions
-
-	OutputNamer converter.OutputNamer
+	resolvedOptions
 
 	// Processing options
-	MaxDepth int      // -1 for unlimited, default: 3
-	Parallel int      // Concurrent fetches, default: 3
-	Exclude  []string // Glob patterns to exclude
+	MaxDepth       int      // -1 for unlimited, default: 3
+	MinDepth       int      // Pages above this depth are not exported, their descendants at it become the roots
+	Parallel       int      // Concurrent fetches, default: 3
+	ConvertWorkers int      // Concurrent conversions, default: number of CPUs
+	WriteWorkers   int      // Concurrent file writes, default: 2
+	Exclude        []string // Glob patterns to exclude
+	SubtreeOf      string   // Title or glob pattern of a descendant to root the export at
+
+	MaxPages           int // Abort when the tree has more pages, 0 for unlimited
+	MaxChildrenPerPage int // Abort when a page has more children, 0 for unlimited
+
+	InlineChildrenBelowDepth int // Leaf pages deeper than this are appended to their parent, -1 disables
+
+	PopularFirst bool // Convert the most viewed pages first, using the Analytics API
 
 	// Output options
-	DryRun bool // Preview without converting
+	DryRun       bool // Preview without converting
+	SpaceDirs    bool // Always nest output under <output>/<SPACEKEY>/
+	SpaceSidebar bool // Write a _sidebar.md with space shortcuts and the page hierarchy per output root
+	SpaceIndex   bool // Write an index.md with the space description, labels and admins per output root
+
+	RestrictionsReport string // CSV file listing restricted pages and their principals
+
+	PreflightSample int  // Descendants probed for read permission before fetching the tree, 0 disables
+	StubUnreadable  bool // Write placeholder files for pages that return 403 or 404
+	StubDuplicates  bool // Write a link to the converted file for later occurrences of a page reachable through several parents
+
+	ChangedOnly bool // Skip pages whose file at their planned path records the current version
+
+	StateFile string     // JSON file recording the version and content hash of every exported page
+	state     *syncState // loaded --state-file, nil when disabled
+
+	RetryFailed  string          // Checkpoint or report whose failed pages are converted again
+	retryPageIDs map[string]bool // pages to retry, nil converts every page
 }
 
 var treeOpts TreeOptions
 
 // treeCmd represents the tree command for recursive page conversion
 var treeCmd = &cobra.Command{
-	Use:   "tree",
+	Use:   "tree <page-url> [page-url...]",
 	Short: "Convert a Confluence page tree recursively",
 	Long: `Convert a Confluence page and all its child pages recursively.
 	
@@ -45,8 +74,26 @@ Examples:
 
   confluence-md tree https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --depth 2
 
+  # Export the children and grandchildren of a page without the page itself
+  confluence-md tree https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --min-depth 1 --depth 2
+
+  # Export the "Runbooks" page found somewhere below the space home page
+  confluence-md tree https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Home --subtree-of Runbooks
+
   # Preview what would be converted
-  confluence-md tree https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --dry-run`,
+  confluence-md tree https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --dry-run
+
+  # Only convert pages that changed since the previous export into ./docs
+  confluence-md tree https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --output ./docs --changed-only
+
+  # Mirror a space from cron, only converting pages whose version changed since the last run
+  confluence-md tree https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --output ./docs --state-file ./docs/.sync-state.json
+
+  # Re-attempt only the pages that failed in the previous run
+  confluence-md tree https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --retry-failed output/failed-pages.json
+
+  # Export trees from several spaces into output/<SPACEKEY>/...
+  confluence-md tree https://example.atlassian.net/wiki/spaces/ONE/pages/1/Home https://example.atlassian.net/wiki/spaces/TWO/pages/2/Home`,
 	RunE: runTreeCommand,
 }
 
@@ -56,55 +103,75 @@ func init() {
 	treeOpts.authOptions.InitFlags(treeCmd)
 	treeOpts.commonOptions.InitFlags(treeCmd)
 
-	// Required flags
-	_ = treeCmd.MarkFlagRequired("api-token")
-
 	// Processing flags
 	treeCmd.Flags().IntVar(&treeOpts.MaxDepth, "depth", -1, "Maximum depth to traverse (-1 for unlimited)")
+	treeCmd.Flags().IntVar(&treeOpts.MinDepth, "min-depth", 0, "Only export pages at this depth or deeper; pages at it become the top of the output (1 skips the root page)")
+	treeCmd.Flags().StringVar(&treeOpts.SubtreeOf, "subtree-of", "", "Export the subtree of the closest descendant whose title matches this name or glob pattern")
 	treeCmd.Flags().IntVar(&treeOpts.Parallel, "parallel", 3, "Number of parallel page fetches")
+	treeCmd.Flags().IntVar(&treeOpts.ConvertWorkers, "convert-workers", runtime.NumCPU(), "Number of pages converted concurrently")
+	treeCmd.Flags().IntVar(&treeOpts.WriteWorkers, "write-workers", 2, "Number of documents written to disk concurrently")
 	treeCmd.Flags().StringSliceVar(&treeOpts.Exclude, "exclude", []string{}, "Glob patterns to exclude pages")
+	treeCmd.Flags().IntVar(&treeOpts.MaxPages, "max-pages", 0, "Abort when the tree contains more pages than this (0 for unlimited)")
+	treeCmd.Flags().IntVar(&treeOpts.MaxChildrenPerPage, "max-children-per-page", 0, "Abort when a page has more direct children than this (0 for unlimited)")
+	treeCmd.Flags().IntVar(&treeOpts.InlineChildrenBelowDepth, "inline-children-below-depth", -1, "Append leaf pages deeper than this depth to their parent document (-1 to disable)")
+	treeCmd.Flags().BoolVar(&treeOpts.PopularFirst, "popular-first", false, "Convert the most viewed pages first (Confluence Analytics API) so interrupted exports have the most read content")
+	treeCmd.Flags().BoolVar(&treeOpts.StubUnreadable, "stub-unreadable", false, "Write a placeholder file with the title, ID and reason for pages that return 403 or 404")
+	treeCmd.Flags().BoolVar(&treeOpts.StubDuplicates, "stub-duplicates", false, "Convert a page reachable through several parents or page URLs once and write stubs linking to it at its other positions")
+	treeCmd.Flags().IntVar(&treeOpts.PreflightSample, "preflight-sample", 20, "Probe this many pages for read permission before exporting (0 to only check the root pages)")
 
 	// Output flags
 	treeCmd.Flags().BoolVar(&treeOpts.DryRun, "dry-run", false, "Preview without converting")
+	treeCmd.Flags().BoolVar(&treeOpts.SpaceDirs, "space-dirs", false, "Nest output under a directory per space key (automatic when trees span several spaces)")
+	treeCmd.Flags().BoolVar(&treeOpts.SpaceSidebar, "space-sidebar", false, "Write a _sidebar.md per space with its sidebar shortcuts and the exported page hierarchy")
+	treeCmd.Flags().BoolVar(&treeOpts.SpaceIndex, "space-index", false, "Write an index.md per space with its description, labels, categories and admins in the frontmatter")
+	treeCmd.Flags().StringVar(&treeOpts.RetryFailed, "retry-failed", "", "Only convert the pages that failed in this failed-pages.json checkpoint or --report file")
+	treeCmd.Flags().BoolVar(&treeOpts.ChangedOnly, "changed-only", false, "Skip pages whose version matches the frontmatter of the file already at their output path")
+	treeCmd.Flags().StringVar(&treeOpts.StateFile, "state-file", "", "Record each exported page's version and content hash in this file and skip pages that have not changed since")
+	treeCmd.Flags().StringVar(&treeOpts.RestrictionsReport, "restrictions-report", "", "Write a CSV of pages with view/edit restrictions and their principals to this file")
 }
 
 func runTreeCommand(_ *cobra.Command, args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("missing required argument: page URL")
 	}
-	pageURL := args[0]
 
-	pageInfo, err := urlToPageInfo(pageURL)
+	pageInfos, err := urlsToPageInfos(args)
 	if err != nil {
-		return fmt.Errorf("invalid Confluence URL: %w", err)
+		return err
 	}
+	baseURL := pageInfos[0].BaseURL
 
 	// Validate input options
 	if err := validateTreeOptions(); err != nil {
 		return fmt.Errorf("invalid options: %w", err)
 	}
 
-	namer, err := buildOutputNamer(treeOpts.OutputNameTemplate)
-	if err != nil {
-		return fmt.Errorf("invalid output name template: %w", err)
+	if err := treeOpts.resolve(treeOpts.commonOptions); err != nil {
+		return err
 	}
-	treeOpts.OutputNamer = namer
 
-	client := confluence.NewClient(pageInfo.BaseURL, treeOpts.APIKey)
+	client := confluence.NewClient(baseURL, treeOpts.APIKey, clientOptions()...)
 
-	if pageInfo.PageID == "" {
-		pageInfo.PageID, err = client.RetrievePageID(pageInfo.SpaceKey, pageInfo.Title)
-		if err != nil {
-			return fmt.Errorf("failed to retrieve page ID: %w", err)
+	rootPageIDs, err := resolvePageIDs(client, pageInfos)
+	if err != nil {
+		return err
+	}
+
+	if treeOpts.SubtreeOf != "" {
+		if rootPageIDs, err = locateSubtrees(client, rootPageIDs, treeOpts.SubtreeOf); err != nil {
+			return err
 		}
 	}
 
 	if treeOpts.DryRun {
 		fmt.Println("🔍 Dry run mode - analyzing page tree...")
-		return performDryRun(client, pageInfo.PageID, &treeOpts)
+		return performDryRun(client, rootPageIDs, &treeOpts)
 	}
 
-	return performTreeConversion(client, pageInfo.BaseURL, pageInfo.PageID, &treeOpts)
+	if err := performTreeConversion(client, baseURL, rootPageIDs, &treeOpts); err != nil {
+		return err
+	}
+	return servePreview(treeOpts.ServePreview, treeOpts.OutputDir, "")
 }
 
 func validateTreeOptions() error {
@@ -113,28 +180,60 @@ func validateTreeOptions() error {
 		return fmt.Errorf("depth must be -1 (unlimited) or greater, got: %d", treeOpts.MaxDepth)
 	}
 
+	if treeOpts.MinDepth < 0 {
+		return fmt.Errorf("min-depth must be 0 or greater, got: %d", treeOpts.MinDepth)
+	}
+	if treeOpts.MaxDepth != -1 && treeOpts.MinDepth > treeOpts.MaxDepth {
+		return fmt.Errorf("min-depth (%d) must not be greater than depth (%d)", treeOpts.MinDepth, treeOpts.MaxDepth)
+	}
+
+	if treeOpts.InlineChildrenBelowDepth < -1 {
+		return fmt.Errorf("inline-children-below-depth must be -1 (disabled) or greater, got: %d", treeOpts.InlineChildrenBelowDepth)
+	}
+
+	if treeOpts.MaxPages < 0 || treeOpts.MaxChildrenPerPage < 0 {
+		return fmt.Errorf("max-pages and max-children-per-page must be 0 (unlimited) or greater")
+	}
+
+	if treeOpts.PreflightSample < 0 {
+		return fmt.Errorf("preflight-sample must be 0 or greater, got: %d", treeOpts.PreflightSample)
+	}
+
 	// Validate parallel
 	if treeOpts.Parallel < 1 {
 		return fmt.Errorf("parallel must be at least 1, got: %d", treeOpts.Parallel)
 	}
 
+	if treeOpts.ConvertWorkers < 1 || treeOpts.WriteWorkers < 1 {
+		return fmt.Errorf("convert-workers and write-workers must be at least 1")
+	}
+
 	return nil
 }
 
-func performDryRun(client confluence.Client, rootPageID string, opts *TreeOptions) error {
+func performDryRun(client confluence.Client, rootPageIDs []string, opts *TreeOptions) error {
 	fmt.Println("\n📊 Page tree structure:")
 
 	// Fetch and display tree structure
-	tree, err := fetchPageTree(client, rootPageID, opts.MaxDepth, 0, opts.Exclude)
+	trees, err := fetchPageTrees(client, rootPageIDs, opts)
 	if err != nil {
-		return fmt.Errorf("failed to fetch page tree: %w", err)
+		return err
 	}
 
-	// Display tree
-	displayTree(tree, 0)
+	stats := &TreeStats{}
+	for _, tree := range trees {
+		// Display tree
+		displayTree(tree, 0)
+
+		treeStats := calculateTreeStats(tree)
+		stats.TotalPages += treeStats.TotalPages
+		stats.EstimatedSize += treeStats.EstimatedSize
+		if treeStats.MaxDepth > stats.MaxDepth {
+			stats.MaxDepth = treeStats.MaxDepth
+		}
+	}
 
 	// Show statistics
-	stats := calculateTreeStats(tree)
 	fmt.Printf("\n📈 Statistics:\n")
 	fmt.Printf("  Total pages: %d\n", stats.TotalPages)
 	fmt.Printf("  Max depth: %d\n", stats.MaxDepth)
@@ -143,30 +242,90 @@ func performDryRun(client confluence.Client, rootPageID string, opts *TreeOption
 	return nil
 }
 
-func performTreeConversion(client confluence.Client, baseURL, rootPageID string, opts *TreeOptions) error {
+func performTreeConversion(client confluence.Client, baseURL string, rootPageIDs []string, opts *TreeOptions) error {
 	// Create output directory
-	if err := os.MkdirAll(opts.OutputDir, 0755); err != nil {
+	if err := outputFS.MkdirAll(opts.OutputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
-	// Fetch page tree
-	tree, err := fetchPageTree(client, rootPageID, opts.MaxDepth, 0, opts.Exclude)
+	if err := preflightPermissions(client, rootPageIDs, opts.PreflightSample); err != nil {
+		return err
+	}
+
+	// Fetch page trees
+	trees, err := fetchPageTrees(client, rootPageIDs, opts)
 	if err != nil {
-		return fmt.Errorf("failed to fetch page tree: %w", err)
+		return err
 	}
+	reportUnreadablePages(trees)
 
-	// Convert tree recursively using shared pipeline
-	results := &ConversionResults{}
-	err = convertPageTree(client, tree, opts.OutputDir, baseURL, opts, results)
+	if opts.AttachmentsOnly {
+		return performTreeAttachmentMirror(client, trees, opts)
+	}
+
+	if opts.RetryFailed != "" {
+		if opts.retryPageIDs, err = loadRetryPageIDs(opts.RetryFailed); err != nil {
+			return err
+		}
+	}
+
+	if opts.state, err = loadSyncState(opts.StateFile, opts.OutputDir); err != nil {
+		return err
+	}
+
+	results, sidebarErr := convertTrees(client, baseURL, trees, opts)
+	if sidebarErr != nil {
+		return sidebarErr
+	}
+
+	if stateErr := opts.state.save(); stateErr != nil {
+		return stateErr
+	}
+
+	checkpoint, checkpointErr := writeFailedPages(opts.OutputDir, results)
+	if checkpointErr != nil {
+		return checkpointErr
+	}
+
+	if infoErr := writeExportInfo(opts.Run, opts.OutputDir, baseURL, results); infoErr != nil {
+		return infoErr
+	}
+
+	if opts.RestrictionsReport != "" {
+		if reportErr := writeRestrictionsReport(client, trees, opts.RestrictionsReport); reportErr != nil {
+			return reportErr
+		}
+	}
 
 	// Display results
 	fmt.Printf("✅ Conversion complete!\n")
 	fmt.Printf("  Successful: %d pages\n", results.Success)
+	if results.Unchanged > 0 {
+		fmt.Printf("  Unchanged since the previous export: %d pages\n", results.Unchanged)
+	}
+	if results.Inlined > 0 {
+		fmt.Printf("  Inlined into parents: %d pages\n", results.Inlined)
+	}
+	if results.Stubbed > 0 {
+		fmt.Printf("  Stubbed (unreadable, missing or duplicate): %d pages\n", results.Stubbed)
+	}
+	if results.Skipped > 0 {
+		fmt.Printf("  Skipped (excluded from export): %d pages\n", results.Skipped)
+	}
 	if results.Failed > 0 {
 		fmt.Printf("  Failed: %d pages\n", results.Failed)
-		fmt.Printf("  See error details above\n")
+		printFailureSummary(results.failureGroups(), baseURL)
+		fmt.Printf("  Retry them with: --retry-failed %s\n", checkpoint)
 	}
 	fmt.Printf("  Output: %s\n", opts.OutputDir)
+	opts.ShortenedPaths.printWarning()
+
+	if opts.ReportPath != "" {
+		if reportErr := writeReport(opts.ReportPath, results.Pages); reportErr != nil {
+			return fmt.Errorf("failed to write report: %w", reportErr)
+		}
+		fmt.Printf("  Report: %s\n", opts.ReportPath)
+	}
 
 	if err != nil {
 		return fmt.Errorf("conversion completed with errors")
@@ -175,15 +334,113 @@ func performTreeConversion(client confluence.Client, baseURL, rootPageID string,
 	return nil
 }
 
+// convertTrees plans one job per output document, then fetches, converts, and writes them concurrently
+func convertTrees(client confluence.Client, baseURL string, trees []*PageNode, opts *TreeOptions) (*ConversionResults, error) {
+	var jobs []*treeJob
+	var roots []*spaceRoot
+	perSpace := opts.SpaceDirs || spansMultipleSpaces(trees)
+	for _, tree := range trees {
+		outputDir := opts.OutputDir
+		if perSpace && tree.SpaceKey != "" {
+			outputDir = filepath.Join(outputDir, sanitizeSpaceDir(tree.SpaceKey))
+		}
+		jobs = planTreeJobs(tree, outputDir, opts, jobs)
+		roots = addSpaceRoot(roots, outputDir, tree)
+	}
+	printPathCollisions(resolvePathCollisions(jobs, &opts.resolvedOptions))
+
+	if opts.retryPageIDs != nil {
+		jobs = retryJobs(jobs, opts.retryPageIDs)
+		fmt.Printf("🔁 Retrying %d failed pages\n", len(jobs))
+	}
+
+	// Duplicates are split off in tree order, so the first occurrence stays canonical under --popular-first
+	var duplicates []duplicateJob
+	if opts.StubDuplicates {
+		jobs, duplicates = splitDuplicateJobs(jobs)
+	}
+
+	if opts.PopularFirst {
+		jobs = orderByPopularity(client, jobs, opts.Parallel)
+	}
+
+	results := &ConversionResults{}
+	runTreePipeline(client, jobs, baseURL, opts, results)
+	writeDuplicateStubs(duplicates, opts, results)
+
+	// A retry only converts some pages, so the navigation files of the full run are kept
+	if opts.SpaceSidebar && opts.retryPageIDs == nil {
+		for _, root := range roots {
+			path, err := writeSpaceSidebar(client, baseURL, root, results, opts)
+			if err != nil {
+				return results, err
+			}
+			fmt.Printf("🧭 Sidebar written: %s\n", path)
+		}
+	}
+
+	if opts.SpaceIndex && opts.retryPageIDs == nil {
+		for _, root := range roots {
+			path, err := writeSpaceIndex(client, baseURL, root, results, opts)
+			if err != nil {
+				return results, err
+			}
+			fmt.Printf("🏠 Space index written: %s\n", path)
+		}
+	}
+
+	return results, nil
+}
+
+// fetchPageTrees fetches the page tree below each root page, skipping excluded roots
+func fetchPageTrees(client confluence.Client, rootPageIDs []string, opts *TreeOptions) ([]*PageNode, error) {
+	limits := &treeLimits{maxPages: opts.MaxPages, maxChildren: opts.MaxChildrenPerPage}
+
+	var trees []*PageNode
+	for _, rootPageID := range rootPageIDs {
+		tree, err := fetchPageTree(client, rootPageID, opts.MaxDepth, 0, opts.Exclude, opts.TitleRules, limits, opts.Parallel)
+		if errors.Is(err, errTreeLimitExceeded) {
+			fmt.Println("\n📊 Page tree fetched before the limit was reached:")
+			for _, fetched := range append(trees, tree) {
+				displayTree(fetched, 0)
+			}
+			return nil, fmt.Errorf("%w; narrow the export with --depth or --exclude, or raise the limit", err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch page tree: %w", err)
+		}
+		if tree != nil {
+			trees = append(trees, tree)
+		}
+	}
+	return applyMinDepth(trees, opts.MinDepth), nil
+}
+
+// spansMultipleSpaces reports whether the root pages belong to more than one space
+func spansMultipleSpaces(trees []*PageNode) bool {
+	for _, tree := range trees {
+		if tree.SpaceKey != trees[0].SpaceKey {
+			return true
+		}
+	}
+	return false
+}
+
 // PageNode represents a page in the tree structure
 type PageNode struct {
 	ID       string
 	Title    string
+	SpaceKey string
+	Labels   []string
+	Version  int
 	Level    int
 	Parent   *PageNode // Reference to parent node
 	Path     []string  // Full hierarchical path from root to this page
 	Children []*PageNode
 	Error    error
+
+	// Disambiguator is appended to the page's file and directory names when they collide with another page's
+	Disambiguator string
 }
 
 // TreeStats holds statistics about the page tree
@@ -193,69 +450,216 @@ type TreeStats struct {
 	EstimatedSize int
 }
 
-// ConversionResults tracks conversion progress
+// ConversionResults tracks conversion progress. record may be called from several goroutines.
 type ConversionResults struct {
-	Success int
-	Failed  int
-	Errors  []error
+	mu sync.Mutex
+
+	Success   int
+	Unchanged int
+	Inlined   int
+	Stubbed   int
+	Skipped   int
+	Failed    int
+	Errors    []error
+	Pages     []*PageConversionResult
+}
+
+// record adds a page result to the totals
+func (r *ConversionResults) record(result *PageConversionResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Pages = append(r.Pages, result)
+	if result.Success {
+		r.Success++
+		r.Inlined += result.InlinedCount
+		return
+	}
+	if result.Unchanged {
+		r.Unchanged++
+		return
+	}
+	if result.Stubbed {
+		r.Stubbed++
+		return
+	}
+	if result.Skipped {
+		r.Skipped++
+		return
+	}
+	r.Failed++
+	r.Errors = append(r.Errors, result.Error)
+}
+
+// treeLimits guards against runaway exports while fetching a page tree. It is shared by the goroutines of a fetch.
+type treeLimits struct {
+	mu          sync.Mutex
+	maxPages    int   // 0 for unlimited
+	maxChildren int   // 0 for unlimited
+	pages       int   // pages fetched so far
+	exceeded    error // set once a limit is reached, stopping the remaining fetches
+}
+
+// countPage counts a fetched page against --max-pages
+func (l *treeLimits) countPage() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.exceeded != nil {
+		return l.exceeded
+	}
+	l.pages++
+	if l.maxPages > 0 && l.pages > l.maxPages {
+		l.exceeded = fmt.Errorf("%w: more than %d pages (--max-pages)", errTreeLimitExceeded, l.maxPages)
+		return l.exceeded
+	}
+	return nil
+}
+
+// checkChildren checks a page's child count against --max-children-per-page
+func (l *treeLimits) checkChildren(title string, count int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.exceeded != nil {
+		return l.exceeded
+	}
+	if l.maxChildren > 0 && count > l.maxChildren {
+		l.exceeded = fmt.Errorf("%w: %s has %d child pages, more than %d (--max-children-per-page)",
+			errTreeLimitExceeded, title, count, l.maxChildren)
+		return l.exceeded
+	}
+	return nil
+}
+
+// errTreeLimitExceeded is returned when a tree exceeds --max-pages or --max-children-per-page
+var errTreeLimitExceeded = errors.New("page tree limit exceeded")
+
+// treeFetcher walks a page tree, fetching sibling subtrees concurrently. At most cap(slots) API requests
+// are in flight at once; children keep the order of the child listing.
+type treeFetcher struct {
+	client     confluence.Client
+	maxDepth   int
+	exclude    []string
+	titleRules converter.TitleRules // exclude patterns match the renamed titles
+	limits     *treeLimits
+	slots      chan struct{}
+}
+
+func fetchPageTree(client confluence.Client, pageID string, maxDepth int, currentDepth int, excludePatterns []string, titleRules converter.TitleRules, limits *treeLimits, parallel int) (*PageNode, error) {
+	if limits == nil {
+		limits = &treeLimits{}
+	}
+	f := &treeFetcher{
+		client:     client,
+		maxDepth:   maxDepth,
+		exclude:    excludePatterns,
+		titleRules: titleRules,
+		limits:     limits,
+		slots:      make(chan struct{}, max(parallel, 1)),
+	}
+	return f.fetch(pageID, currentDepth, nil, []string{})
 }
 
-func fetchPageTree(client confluence.Client, pageID string, maxDepth int, currentDepth int, excludePatterns []string) (*PageNode, error) {
-	return fetchPageTreeWithParent(client, pageID, maxDepth, currentDepth, excludePatterns, nil, []string{})
+// request runs an API call once a request slot is free
+func (f *treeFetcher) request(call func()) {
+	f.slots <- struct{}{}
+	defer func() { <-f.slots }()
+	call()
 }
 
-func fetchPageTreeWithParent(client confluence.Client, pageID string, maxDepth int, currentDepth int, excludePatterns []string, parent *PageNode, parentPath []string) (*PageNode, error) {
+func (f *treeFetcher) fetch(pageID string, currentDepth int, parent *PageNode, parentPath []string) (*PageNode, error) {
 	// Check depth limit
-	if maxDepth != -1 && currentDepth > maxDepth {
+	if f.maxDepth != -1 && currentDepth > f.maxDepth {
 		return nil, nil
 	}
 
 	// Fetch page details
-	page, err := client.GetPage(pageID)
+	var page *confluenceModel.ConfluencePage
+	var err error
+	f.request(func() { page, err = f.client.GetPage(pageID) })
 	if err != nil {
 		return &PageNode{
 			ID:     pageID,
 			Title:  "Error loading page",
 			Level:  currentDepth,
 			Parent: parent,
-			Path:   append(parentPath, "Error loading page"),
+			Path:   append(parentPath[:len(parentPath):len(parentPath)], "Error loading page"),
 			Error:  err,
 		}, nil
 	}
 
-	// Check exclusion patterns
-	if shouldExclude(page.Title, excludePatterns) {
+	// Check exclusion patterns against the title the page is exported under
+	if shouldExclude(f.titleRules.Apply(page.Title), f.exclude) {
 		return nil, nil
 	}
 
-	// Build path for current node
-	currentPath := append(parentPath, page.Title)
+	if err := f.limits.countPage(); err != nil {
+		return nil, err
+	}
+
+	// Build path for current node; siblings share parentPath, so copy it
+	currentPath := append(parentPath[:len(parentPath):len(parentPath)], page.Title)
 
 	node := &PageNode{
-		ID:     pageID,
-		Title:  page.Title,
-		Level:  currentDepth,
-		Parent: parent,
-		Path:   currentPath,
+		ID:       pageID,
+		Title:    page.Title,
+		SpaceKey: page.SpaceKey,
+		Labels:   page.GetLabelNames(),
+		Version:  page.Version,
+		Level:    currentDepth,
+		Parent:   parent,
+		Path:     currentPath,
 	}
 
 	// Fetch children if within depth limit
-	if maxDepth == -1 || currentDepth < maxDepth {
-		children, err := client.GetChildPages(pageID)
-		if err != nil {
-			// Log error but continue
-			fmt.Printf("⚠️  Warning: Failed to fetch children for %s: %v\n", page.Title, err)
-		} else {
-			for _, child := range children {
-				childNode, err := fetchPageTreeWithParent(client, child.ID, maxDepth, currentDepth+1, excludePatterns, node, currentPath)
-				if err != nil {
-					fmt.Printf("⚠️  Warning: Failed to process child %s: %v\n", child.Title, err)
-					continue
-				}
-				if childNode != nil {
-					node.Children = append(node.Children, childNode)
-				}
+	if f.maxDepth != -1 && currentDepth >= f.maxDepth {
+		return node, nil
+	}
+
+	var children []*confluenceModel.ConfluencePage
+	f.request(func() { children, err = f.client.GetChildPages(pageID) })
+	if err != nil {
+		// Log error but continue
+		fmt.Printf("⚠️  Warning: Failed to fetch children for %s: %v\n", page.Title, err)
+		return node, nil
+	}
+	if err := f.limits.checkChildren(page.Title, len(children)); err != nil {
+		return node, err
+	}
+
+	childNodes := make([]*PageNode, len(children))
+	childErrs := make([]error, len(children))
+	var wg sync.WaitGroup
+	for i, child := range children {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			childNodes[i], childErrs[i] = f.fetch(child.ID, currentDepth+1, node, currentPath)
+		}()
+	}
+	wg.Wait()
+
+	for i, child := range children {
+		childNode, err := childNodes[i], childErrs[i]
+		if childNode != nil && childNode.Error != nil && child.Title != "" {
+			// Keep the title from the child listing so stubs and logs name the unreadable page
+			childNode.Title = child.Title
+			childNode.Path = append(currentPath[:len(currentPath):len(currentPath)], child.Title)
+			childNode.SpaceKey = page.SpaceKey
+		}
+		if errors.Is(err, errTreeLimitExceeded) {
+			if childNode != nil {
+				node.Children = append(node.Children, childNode)
 			}
+			return node, err
+		}
+		if err != nil {
+			fmt.Printf("⚠️  Warning: Failed to process child %s: %v\n", child.Title, err)
+			continue
+		}
+		if childNode != nil {
+			node.Children = append(node.Children, childNode)
 		}
 	}
 
@@ -316,78 +720,36 @@ func calculateTreeStats(node *PageNode) *TreeStats {
 	return stats
 }
 
-func convertPageTree(client confluence.Client, node *PageNode, outputDir string, baseURL string, opts *TreeOptions, results *ConversionResults) error {
-	if node == nil {
-		return nil
+// shouldInlineChild reports whether a node is a leaf page deeper than the inline threshold
+func shouldInlineChild(node *PageNode, belowDepth int) bool {
+	if belowDepth < 0 || node == nil || node.Error != nil {
+		return false
 	}
+	return node.Level > belowDepth && len(node.Children) == 0
+}
 
-	// Convert current page
-	fmt.Printf("📄 Converting: %s\n", node.Title)
-
-	page, err := client.GetPage(node.ID)
-	if err != nil {
-		fmt.Printf("  ❌ Failed to fetch: %v\n", err)
-		results.Failed++
-		results.Errors = append(results.Errors, err)
-
-		return nil
-	}
-	// Generate hierarchical output path
-	outputPath, err := getOutputPath(node, page, outputDir, opts.OutputNamer)
+// getOutputPath resolves the page's output path below baseDir from its tree position and creates its directory
+func getOutputPath(node *PageNode, page *confluenceModel.ConfluencePage, baseDir string, opts *resolvedOptions) (string, error) {
+	relPath, err := plannedRelativePath(node, page, baseDir, opts)
 	if err != nil {
-		fmt.Printf("  ❌ Failed to resolve output path: %v\n", err)
-		results.Failed++
-		results.Errors = append(results.Errors, err)
-		return nil
+		return "", err
 	}
 
-	// Create options for tree conversion (inherit from tree options)
-	conversionOpts := PageOptions{
-		authOptions:   authOptions{APIKey: opts.APIKey},
-		commonOptions: opts.commonOptions,
-		OutputNamer:   opts.OutputNamer,
+	path := filepath.Join(baseDir, relPath)
+	if err := outputFS.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
-
-	// Use shared conversion pipeline with custom path
-	result := convertSinglePageWithPath(client, page, baseURL, outputPath, conversionOpts)
-
-	// Use shared result display
-	printConversionResult(result)
-
-	if result.Success {
-		results.Success++
-	} else {
-		results.Failed++
-		results.Errors = append(results.Errors, result.Error)
-	}
-
-	// Convert children
-	for _, child := range node.Children {
-		if err := convertPageTree(client, child, outputDir, baseURL, opts, results); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return path, nil
 }
 
-func getOutputPath(node *PageNode, page *confluenceModel.ConfluencePage, baseDir string, namer converter.OutputNamer) (string, error) {
-	path := baseDir
-
-	if len(node.Path) > 1 {
-		dirPath := node.Path[:len(node.Path)-1]
-		for _, pathElement := range dirPath {
-			path = filepath.Join(path, sanitizeFileName(pathElement))
-		}
-		if err := os.MkdirAll(path, 0755); err != nil {
-			return "", fmt.Errorf("failed to create output directory: %w", err)
-		}
-	}
-
-	fileName, err := converter.GenerateFileName(page, namer)
+// plannedRelativePath returns the page's output path relative to baseDir, disambiguated and shortened
+func plannedRelativePath(node *PageNode, page *confluenceModel.ConfluencePage, baseDir string, opts *resolvedOptions) (string, error) {
+	relPath, err := converter.GenerateRelativePath(page, ancestorDirTitles(node), opts.PathNamer)
 	if err != nil {
 		return "", err
 	}
-
-	return filepath.Join(path, fileName), nil
+	if node.Disambiguator != "" {
+		relPath = withDisambiguator(relPath, node.Disambiguator)
+	}
+	return opts.ShortenedPaths.shorten(opts.PathLimits, baseDir, relPath), nil
 }
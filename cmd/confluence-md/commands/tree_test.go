@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// stubTreeClient serves a page tree from a map of page IDs to child IDs
+type stubTreeClient struct {
+	confluence.Client
+	children map[string][]string
+}
+
+func (c *stubTreeClient) GetPage(pageID string) (*confluenceModel.ConfluencePage, error) {
+	return &confluenceModel.ConfluencePage{ID: pageID, Title: "Page " + pageID}, nil
+}
+
+func (c *stubTreeClient) GetChildPages(pageID string) ([]*confluenceModel.ConfluencePage, error) {
+	var pages []*confluenceModel.ConfluencePage
+	for _, id := range c.children[pageID] {
+		pages = append(pages, &confluenceModel.ConfluencePage{ID: id, Title: "Page " + id})
+	}
+	return pages, nil
+}
+
+// wideTree returns a root page with n child pages
+func wideTree(n int) *stubTreeClient {
+	client := &stubTreeClient{children: map[string][]string{}}
+	for i := 1; i <= n; i++ {
+		client.children["root"] = append(client.children["root"], strconv.Itoa(i))
+	}
+	return client
+}
+
+func TestFetchPageTreeMaxPages(t *testing.T) {
+	limits := &treeLimits{maxPages: 3}
+	tree, err := fetchPageTree(wideTree(5), "root", -1, 0, nil, nil, limits, 1)
+	if !errors.Is(err, errTreeLimitExceeded) {
+		t.Fatalf("err = %v, want %v", err, errTreeLimitExceeded)
+	}
+	if !strings.Contains(err.Error(), "--max-pages") {
+		t.Errorf("err = %q, does not name the flag", err)
+	}
+	if tree == nil || tree.ID != "root" {
+		t.Fatalf("tree = %+v, want the partial tree below root", tree)
+	}
+	if got := len(tree.Children); got > 2 {
+		t.Errorf("fetched %d children, want at most 2 besides the root", got)
+	}
+}
+
+func TestFetchPageTreeMaxChildren(t *testing.T) {
+	limits := &treeLimits{maxChildren: 2}
+	tree, err := fetchPageTree(wideTree(3), "root", -1, 0, nil, nil, limits, 3)
+	if !errors.Is(err, errTreeLimitExceeded) {
+		t.Fatalf("err = %v, want %v", err, errTreeLimitExceeded)
+	}
+	if !strings.Contains(err.Error(), "Page root has 3 child pages") {
+		t.Errorf("err = %q, does not name the page and its child count", err)
+	}
+	if tree == nil || len(tree.Children) != 0 {
+		t.Fatalf("tree = %+v, want the root without children", tree)
+	}
+}
+
+func TestFetchPageTreeWithinLimits(t *testing.T) {
+	limits := &treeLimits{maxPages: 4, maxChildren: 3}
+	tree, err := fetchPageTree(wideTree(3), "root", -1, 0, nil, nil, limits, 3)
+	if err != nil {
+		t.Fatalf("fetchPageTree returned error: %v", err)
+	}
+	if got := len(tree.Children); got != 3 {
+		t.Fatalf("got %d children, want 3", got)
+	}
+	for i, child := range tree.Children {
+		if want := strconv.Itoa(i + 1); child.ID != want {
+			t.Errorf("child %d has ID %s, want %s", i, child.ID, want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	"github.com/spf13/cobra"
+)
+
+// UsersOptions contains all options for the users command
+type UsersOptions struct {
+	authOptions
+
+	MaxDepth int      // -1 for unlimited
+	Exclude  []string // Glob patterns to exclude
+	Output   string   // users.csv or users.yaml
+}
+
+var usersOpts UsersOptions
+
+// usersCmd scans page trees for user mentions and writes a user mapping file
+var usersCmd = &cobra.Command{
+	Use:   "users <page-url> [page-url...]",
+	Short: "Export the users mentioned in a page tree",
+	Long: `Scan a Confluence page and all its descendants for user mentions and page
+authors, and write every account found to a CSV or YAML mapping file with the
+account ID, display name, and email (when visible to the API token).
+
+The output format follows the file extension (.csv, .yaml or .yml).
+
+Examples:
+  # Write users.csv for a page tree
+  confluence-md users https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title
+
+  # Write a YAML mapping for the first two levels of a tree
+  confluence-md users https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --depth 2 --output users.yaml`,
+	RunE: runUsersCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(usersCmd)
+
+	usersOpts.authOptions.InitFlags(usersCmd)
+
+	usersCmd.Flags().IntVar(&usersOpts.MaxDepth, "depth", -1, "Maximum depth to traverse (-1 for unlimited)")
+	usersCmd.Flags().StringSliceVar(&usersOpts.Exclude, "exclude", []string{}, "Glob patterns to exclude pages")
+	usersCmd.Flags().StringVarP(&usersOpts.Output, "output", "o", "users.csv", "Output file (.csv, .yaml or .yml)")
+}
+
+// userRecord is one row of the exported user mapping
+type userRecord struct {
+	AccountID   string
+	DisplayName string
+	Email       string
+}
+
+func runUsersCommand(_ *cobra.Command, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing required argument: page URL")
+	}
+
+	format := strings.ToLower(filepath.Ext(usersOpts.Output))
+	if format != ".csv" && format != ".yaml" && format != ".yml" {
+		return fmt.Errorf("invalid options: output must end in .csv, .yaml or .yml, got: %s", usersOpts.Output)
+	}
+	if usersOpts.MaxDepth < -1 {
+		return fmt.Errorf("invalid options: depth must be -1 (unlimited) or greater, got: %d", usersOpts.MaxDepth)
+	}
+
+	pageInfos, err := urlsToPageInfos(args)
+	if err != nil {
+		return err
+	}
+
+	client := confluence.NewClient(pageInfos[0].BaseURL, usersOpts.APIKey, clientOptions()...)
+
+	rootPageIDs, err := resolvePageIDs(client, pageInfos)
+	if err != nil {
+		return err
+	}
+
+	trees, err := fetchPageTrees(client, rootPageIDs, &TreeOptions{MaxDepth: usersOpts.MaxDepth, Exclude: usersOpts.Exclude})
+	if err != nil {
+		return err
+	}
+
+	accountIDs := make(map[string]bool)
+	for _, tree := range trees {
+		collectMentionedUsers(client, tree, accountIDs)
+	}
+
+	records := resolveUserRecords(client, accountIDs)
+
+	if dir := filepath.Dir(usersOpts.Output); dir != "." && dir != "" {
+		if err := outputFS.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	var content string
+	if format == ".csv" {
+		content, err = renderUsersCSV(records)
+		if err != nil {
+			return err
+		}
+	} else {
+		content = renderUsersYAML(records)
+	}
+	if err := outputFS.WriteFile(usersOpts.Output, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write users file: %w", err)
+	}
+
+	fmt.Printf("✅ Exported %d users to %s\n", len(records), usersOpts.Output)
+	return nil
+}
+
+// collectMentionedUsers adds the authors and mentioned accounts of every page in the tree
+func collectMentionedUsers(client confluence.Client, node *PageNode, accountIDs map[string]bool) {
+	if node == nil || node.Error != nil {
+		return
+	}
+
+	page, err := client.GetPage(node.ID)
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to fetch %s: %v\n", node.Title, err)
+	} else {
+		for _, accountID := range plugin.ExtractUserAccountIDs(page.Content.Storage.Value) {
+			accountIDs[accountID] = true
+		}
+		for _, accountID := range []string{page.CreatedBy.AccountID, page.UpdatedBy.AccountID} {
+			if accountID != "" {
+				accountIDs[accountID] = true
+			}
+		}
+	}
+
+	for _, child := range node.Children {
+		collectMentionedUsers(client, child, accountIDs)
+	}
+}
+
+// resolveUserRecords looks up each account, keeping accounts that cannot be resolved with an empty name
+func resolveUserRecords(client confluence.Client, accountIDs map[string]bool) []userRecord {
+	records := make([]userRecord, 0, len(accountIDs))
+	for accountID := range accountIDs {
+		record := userRecord{AccountID: accountID}
+		user, err := client.GetUser(accountID)
+		if err != nil {
+			fmt.Printf("⚠️  Warning: Failed to resolve user %s: %v\n", accountID, err)
+		} else if !user.IsAnonymized() {
+			record.DisplayName = user.DisplayName
+			if record.DisplayName == "" {
+				record.DisplayName = user.PublicName
+			}
+			record.Email = user.Email
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].AccountID < records[j].AccountID
+	})
+	return records
+}
+
+// renderUsersCSV renders the records as CSV with a header row
+func renderUsersCSV(records []userRecord) (string, error) {
+	var builder strings.Builder
+	writer := csv.NewWriter(&builder)
+	_ = writer.Write([]string{"accountId", "displayName", "email"})
+	for _, record := range records {
+		_ = writer.Write([]string{record.AccountID, record.DisplayName, record.Email})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return "", fmt.Errorf("failed to encode users: %w", err)
+	}
+	return builder.String(), nil
+}
+
+// renderUsersYAML renders the records as a YAML list under a users key
+func renderUsersYAML(records []userRecord) string {
+	var builder strings.Builder
+	builder.WriteString("users:\n")
+	for _, record := range records {
+		builder.WriteString(fmt.Sprintf("  - accountId: %s\n", convModel.YAMLString(record.AccountID)))
+		builder.WriteString(fmt.Sprintf("    displayName: %s\n", convModel.YAMLString(record.DisplayName)))
+		if record.Email != "" {
+			builder.WriteString(fmt.Sprintf("    email: %s\n", convModel.YAMLString(record.Email)))
+		}
+	}
+	return builder.String()
+}
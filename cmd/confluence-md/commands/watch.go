@@ -0,0 +1,317 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/spf13/cobra"
+)
+
+// WatchOptions contains all options for the watch command
+type WatchOptions struct {
+	authOptions
+	commonOptions
+	resolvedOptions
+
+	Interval time.Duration // Time between polls
+	MaxDepth int           // -1 for unlimited
+	Exclude  []string      // Glob patterns to exclude
+	CQL      string        // Watch the pages matching a CQL query instead of page trees
+	Limit    int           // Maximum pages returned by the CQL query
+	BaseURL  string        // Confluence base URL, required with --cql
+	Hook     string        // Shell command run after a poll converted changed pages
+
+	Redirects string // What renamed pages leave at their previous path: none, stub or aliases
+
+	MetricsAddr string // Address serving Prometheus metrics on /metrics, empty to disable
+}
+
+var watchOpts WatchOptions
+
+// watchCmd polls page trees or a CQL query and converts pages whose version changed
+var watchCmd = &cobra.Command{
+	Use:   "watch [page-url...]",
+	Short: "Poll pages and convert them when they change",
+	Long: `Poll Confluence page trees (or the pages matching a CQL query) at a fixed
+interval and convert only the pages whose version changed since the last poll.
+The first poll converts every page.
+
+Use --hook to run a shell command after each poll that converted pages, for
+example to commit the export. The number of converted pages is available to
+the hook as CONFLUENCE_MD_CHANGED. This is meant for environments where
+Confluence webhooks are not available. Stop watching with Ctrl+C.
+
+When a page is renamed, its file moves to a new path. Use --redirects stub to
+replace the old file with a link to the new one, or --redirects aliases to
+remove it and list the old URL path under aliases: in the new file's
+frontmatter (Hugo style), so existing links keep working.
+
+Use --metrics-addr to expose Prometheus metrics (pages converted, failures,
+API requests, rate-limit hits and latency histograms) at /metrics.
+
+Examples:
+  # Poll a page tree every 10 minutes
+  confluence-md watch https://example.atlassian.net/wiki/spaces/SPACE/pages/12345/Title --interval 10m
+
+  # Watch a CQL query and commit every change
+  confluence-md watch --base-url https://confluence.example.com --cql 'space = DOCS and label = public' \
+    --hook 'git add -A && git commit -m "Sync from Confluence" && git push'`,
+	RunE: runWatchCommand,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchOpts.authOptions.InitFlags(watchCmd)
+	watchOpts.commonOptions.InitFlags(watchCmd)
+
+	watchCmd.Flags().DurationVar(&watchOpts.Interval, "interval", 5*time.Minute, "Time between polls")
+	watchCmd.Flags().IntVar(&watchOpts.MaxDepth, "depth", -1, "Maximum depth to traverse (-1 for unlimited)")
+	watchCmd.Flags().StringSliceVar(&watchOpts.Exclude, "exclude", []string{}, "Glob patterns to exclude pages")
+	watchCmd.Flags().StringVar(&watchOpts.CQL, "cql", "", "Watch the pages matching this CQL query instead of page trees (requires --base-url)")
+	watchCmd.Flags().IntVar(&watchOpts.Limit, "limit", 100, "Maximum number of pages returned by --cql")
+	watchCmd.Flags().StringVar(&watchOpts.BaseURL, "base-url", "", "Confluence base URL used with --cql")
+	watchCmd.Flags().StringVar(&watchOpts.Hook, "hook", "", "Shell command run after a poll converted changed pages")
+	watchCmd.Flags().StringVar(&watchOpts.Redirects, "redirects", redirectNone, "What renamed pages leave at their previous path: none, stub or aliases")
+	watchCmd.Flags().StringVar(&watchOpts.MetricsAddr, "metrics-addr", "", "Serve Prometheus metrics on this address (e.g. :9090) at /metrics")
+}
+
+// watchedPage is a page found by a poll
+type watchedPage struct {
+	ID      string
+	Title   string
+	Version int
+	node    *PageNode // tree position for the hierarchical output path, nil for CQL results
+}
+
+func runWatchCommand(_ *cobra.Command, args []string) error {
+	if len(args) == 0 && watchOpts.CQL == "" {
+		return fmt.Errorf("missing required argument: page URL or --cql")
+	}
+	if watchOpts.Interval <= 0 {
+		return fmt.Errorf("invalid options: interval must be positive, got: %s", watchOpts.Interval)
+	}
+	if watchOpts.MaxDepth < -1 {
+		return fmt.Errorf("invalid options: depth must be -1 (unlimited) or greater, got: %d", watchOpts.MaxDepth)
+	}
+	if err := validateRedirectMode(watchOpts.Redirects); err != nil {
+		return err
+	}
+
+	if err := watchOpts.resolve(watchOpts.commonOptions); err != nil {
+		return err
+	}
+
+	baseURL := watchOpts.BaseURL
+	var pageInfos []confluenceModel.PageURLInfo
+	if len(args) > 0 {
+		var err error
+		pageInfos, err = urlsToPageInfos(args)
+		if err != nil {
+			return err
+		}
+		baseURL = pageInfos[0].BaseURL
+	}
+	if baseURL == "" {
+		return fmt.Errorf("invalid options: --cql requires --base-url")
+	}
+
+	var stats *serviceMetrics
+	options := clientOptions()
+	if watchOpts.MetricsAddr != "" {
+		stats = newServiceMetrics()
+		options = append(options, stats.clientOption())
+		if err := serveMetrics(watchOpts.MetricsAddr, stats); err != nil {
+			return err
+		}
+	}
+
+	client := confluence.NewClient(baseURL, watchOpts.APIKey, options...)
+
+	var rootPageIDs []string
+	if len(pageInfos) > 0 {
+		var err error
+		rootPageIDs, err = resolvePageIDs(client, pageInfos)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := outputFS.MkdirAll(watchOpts.OutputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	versions := make(map[string]int)
+	renames := newRenameTracker(watchOpts.Redirects, watchOpts.OutputDir)
+	if watchOpts.Redirects != redirectNone {
+		if err := renames.seed(); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("👀 Watching for changes every %s (Ctrl+C to stop)\n", watchOpts.Interval)
+	for {
+		if err := pollOnce(client, baseURL, rootPageIDs, versions, renames, &watchOpts, stats); err != nil {
+			fmt.Printf("  ❌ Poll failed: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Println("👋 Stopped watching")
+			return nil
+		case <-time.After(watchOpts.Interval):
+		}
+	}
+}
+
+// pollOnce converts the watched pages whose version differs from the last successful conversion
+func pollOnce(client confluence.Client, baseURL string, rootPageIDs []string, versions map[string]int, renames *renameTracker, opts *WatchOptions, stats *serviceMetrics) error {
+	pages, err := listWatchedPages(client, rootPageIDs, opts)
+	if err != nil {
+		return err
+	}
+
+	conversionOpts := PageOptions{
+		authOptions:     opts.authOptions,
+		commonOptions:   opts.commonOptions,
+		resolvedOptions: opts.resolvedOptions,
+	}
+	if opts.Run != nil {
+		// Every poll is an export run of its own
+		conversionOpts.Run = newExportRun()
+	}
+
+	changed, failed := 0, 0
+	for _, watched := range pages {
+		if versions[watched.ID] == watched.Version {
+			continue
+		}
+
+		start := time.Now()
+		page, err := client.GetPage(watched.ID)
+		if err != nil {
+			fmt.Printf("  ❌ Failed to fetch %s: %v\n", watched.Title, err)
+			stats.observeConversion(false, time.Since(start))
+			continue
+		}
+
+		// CQL results are written flat into the output directory
+		outputPath := ""
+		if watched.node != nil {
+			if outputPath, err = getOutputPath(watched.node, page, opts.OutputDir, &opts.resolvedOptions); err != nil {
+				fmt.Printf("  ❌ Failed to resolve output path for %s: %v\n", watched.Title, err)
+				continue
+			}
+		}
+
+		doc, result := convertPageDocument(client, page, nil, baseURL, outputPath, conversionOpts)
+		if result.Error == nil {
+			previous := renames.prepare(page.ID, result.OutputPath, doc)
+			savePageDocument(doc, result, conversionOpts)
+			if result.Success {
+				if err := renames.finish(page.ID, previous, result); err != nil {
+					fmt.Printf("  ⚠️  Warning: %v\n", err)
+				}
+			}
+		}
+		printConversionResult(result)
+		if result.Skipped {
+			// Excluded pages are not retried until their version changes
+			versions[watched.ID] = page.Version
+			continue
+		}
+		stats.observeConversion(result.Success, time.Since(start))
+		if !result.Success {
+			// Leave the version unrecorded so the page is retried on the next poll
+			failed++
+			continue
+		}
+		versions[watched.ID] = page.Version
+		changed++
+	}
+
+	fmt.Printf("🔄 %s: %d pages checked, %d converted\n", time.Now().Format(time.TimeOnly), len(pages), changed)
+
+	if changed > 0 {
+		if err := writeExportInfo(conversionOpts.Run, opts.OutputDir, baseURL, &ConversionResults{Success: changed, Failed: failed}); err != nil {
+			return err
+		}
+	}
+
+	if changed > 0 && opts.Hook != "" {
+		return runWatchHook(opts.Hook, changed)
+	}
+	return nil
+}
+
+// listWatchedPages returns the pages matching the CQL query, or every page of the watched trees
+func listWatchedPages(client confluence.Client, rootPageIDs []string, opts *WatchOptions) ([]watchedPage, error) {
+	var pages []watchedPage
+
+	if opts.CQL != "" {
+		results, err := client.SearchContent(opts.CQL, opts.Limit)
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range results {
+			if shouldExclude(opts.TitleRules.Apply(page.Title), opts.Exclude) {
+				continue
+			}
+			pages = append(pages, watchedPage{ID: page.ID, Title: page.Title, Version: page.Version})
+		}
+		return pages, nil
+	}
+
+	treeOptions := &TreeOptions{MaxDepth: opts.MaxDepth, Exclude: opts.Exclude}
+	treeOptions.TitleRules = opts.TitleRules
+	trees, err := fetchPageTrees(client, rootPageIDs, treeOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var walk func(node *PageNode)
+	walk = func(node *PageNode) {
+		if node == nil || node.Error != nil {
+			return
+		}
+		pages = append(pages, watchedPage{ID: node.ID, Title: node.Title, Version: node.Version, node: node})
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	for _, tree := range trees {
+		walk(tree)
+	}
+
+	// Collisions are resolved on every poll so renamed pages keep their path, but only reported by tree
+	jobs := make([]*treeJob, 0, len(pages))
+	for _, page := range pages {
+		jobs = append(jobs, &treeJob{node: page.node, outputDir: opts.OutputDir})
+	}
+	resolvePathCollisions(jobs, &opts.resolvedOptions)
+	return pages, nil
+}
+
+// runWatchHook runs the post-change hook through the shell, passing the number of converted pages
+func runWatchHook(hook string, changed int) error {
+	fmt.Printf("🪝 Running hook: %s\n", hook)
+
+	cmd := exec.Command("sh", "-c", hook)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "CONFLUENCE_MD_CHANGED="+strconv.Itoa(changed))
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook failed: %w", err)
+	}
+	return nil
+}
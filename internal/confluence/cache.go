@@ -0,0 +1,239 @@
+package confluence
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// cacheablePagePath matches single page requests such as /rest/api/content/12345
+var cacheablePagePath = regexp.MustCompile(`/rest/api/content/\d+$`)
+
+// uncachedHeaders carry session cookies or credentials and are never written to the cache
+var uncachedHeaders = []string{"Set-Cookie", "Authorization", "Proxy-Authorization", "WWW-Authenticate", "Proxy-Authenticate", "Authentication-Info"}
+
+// cacheDrainLimit is how far past the consumer's last read a closed body is read to reach EOF
+const cacheDrainLimit = 4096
+
+// WithResponseCache keeps page and attachment responses in dir and revalidates them with
+// If-None-Match and If-Modified-Since, serving the cached body when Confluence answers 304 Not Modified.
+func WithResponseCache(dir string) Option {
+	return func(c *client) {
+		c.httpClient.Transport = &cacheTransport{
+			next: c.httpClient.Transport,
+			dir:  dir,
+		}
+	}
+}
+
+// cacheEntry is the metadata of a cached response, stored as <key>.json next to the <key>.body file
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Header       http.Header `json:"header"`
+}
+
+// cacheTransport makes page and attachment requests conditional on the cached validators
+type cacheTransport struct {
+	next http.RoundTripper
+	dir  string
+}
+
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if !cacheableRequest(req) {
+		return next.RoundTrip(req)
+	}
+
+	key := cacheKey(req.URL.String())
+	entry := t.load(key)
+	if entry == nil {
+		resp, err := next.RoundTrip(req)
+		return t.record(key, resp, err)
+	}
+
+	conditional := req.Clone(req.Context())
+	if entry.ETag != "" {
+		conditional.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		conditional.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	resp, err := next.RoundTrip(conditional)
+	if err != nil || resp.StatusCode != http.StatusNotModified {
+		return t.record(key, resp, err)
+	}
+	_ = resp.Body.Close()
+
+	if cached := t.cachedResponse(key, entry, req); cached != nil {
+		return cached, nil
+	}
+	// The cached body is gone, so fetch the full response again
+	resp, err = next.RoundTrip(req)
+	return t.record(key, resp, err)
+}
+
+// cacheableRequest reports whether req fetches a single page or an attachment
+func cacheableRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	return cacheablePagePath.MatchString(req.URL.Path) || strings.Contains(req.URL.Path, "/download/")
+}
+
+// cacheKey names the cache files of a URL
+func cacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return hex.EncodeToString(sum[:])
+}
+
+func (t *cacheTransport) entryPath(key string) string {
+	return filepath.Join(t.dir, key+".json")
+}
+
+func (t *cacheTransport) bodyPath(key string) string {
+	return filepath.Join(t.dir, key+".body")
+}
+
+// load returns the cached entry for key, or nil when there is none
+func (t *cacheTransport) load(key string) *cacheEntry {
+	data, err := os.ReadFile(t.entryPath(key))
+	if err != nil {
+		return nil
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+	return &entry
+}
+
+// cachedResponse answers req from the cached body, or returns nil when the body cannot be read
+func (t *cacheTransport) cachedResponse(key string, entry *cacheEntry, req *http.Request) *http.Response {
+	body, err := os.Open(t.bodyPath(key))
+	if err != nil {
+		return nil
+	}
+	size := int64(-1)
+	if info, err := body.Stat(); err == nil {
+		size = info.Size()
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        cacheableHeader(entry.Header),
+		Body:          body,
+		ContentLength: size,
+		Request:       req,
+	}
+}
+
+// record passes the response through, saving the body to the cache as it is read
+// when the response carries an ETag or Last-Modified validator
+func (t *cacheTransport) record(key string, resp *http.Response, err error) (*http.Response, error) {
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+
+	entry := &cacheEntry{
+		URL:          resp.Request.URL.String(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Header:       cacheableHeader(resp.Header),
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return resp, nil
+	}
+
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		return resp, nil
+	}
+	file, err := os.CreateTemp(t.dir, key+"-*.tmp")
+	if err != nil {
+		return resp, nil
+	}
+	resp.Body = &cacheRecorder{ReadCloser: resp.Body, transport: t, key: key, entry: entry, file: file}
+	return resp, nil
+}
+
+// cacheRecorder copies a response body into a temporary file and stores it once the body was read to the end.
+// A body closed early is discarded.
+type cacheRecorder struct {
+	io.ReadCloser
+	transport *cacheTransport
+	key       string
+	entry     *cacheEntry
+	file      *os.File
+}
+
+func (r *cacheRecorder) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if r.file != nil && n > 0 {
+		if _, writeErr := r.file.Write(p[:n]); writeErr != nil {
+			r.discard()
+		}
+	}
+	if r.file != nil && err == io.EOF {
+		r.commit()
+	}
+	return n, err
+}
+
+func (r *cacheRecorder) Close() error {
+	if r.file != nil {
+		// JSON decoders stop right before EOF, so read a little further to tell a complete body from an abandoned one
+		_, _ = io.CopyN(io.Discard, r, cacheDrainLimit)
+	}
+	if r.file != nil {
+		r.discard()
+	}
+	return r.ReadCloser.Close()
+}
+
+// commit moves the complete body into place and writes the entry that points at it
+func (r *cacheRecorder) commit() {
+	tmp := r.file.Name()
+	closeErr := r.file.Close()
+	r.file = nil
+
+	data, err := json.Marshal(r.entry)
+	if closeErr != nil || err != nil {
+		_ = os.Remove(tmp)
+		return
+	}
+	if err := os.Rename(tmp, r.transport.bodyPath(r.key)); err != nil {
+		_ = os.Remove(tmp)
+		return
+	}
+	_ = os.WriteFile(r.transport.entryPath(r.key), data, 0644)
+}
+
+func (r *cacheRecorder) discard() {
+	tmp := r.file.Name()
+	_ = r.file.Close()
+	_ = os.Remove(tmp)
+	r.file = nil
+}
+
+// cacheableHeader returns a copy of header without the uncachedHeaders
+func cacheableHeader(header http.Header) http.Header {
+	clean := header.Clone()
+	for _, name := range uncachedHeaders {
+		clean.Del(name)
+	}
+	return clean
+}
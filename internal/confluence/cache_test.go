@@ -0,0 +1,146 @@
+package confluence
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestResponseCacheRevalidatesPages(t *testing.T) {
+	fullResponses, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/content/42":
+			if r.Header.Get("If-None-Match") == `"v3"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			fullResponses++
+			w.Header().Set("ETag", `"v3"`)
+			_, _ = w.Write([]byte(`{"id":"42","title":"Cached","version":{"number":3}}`))
+		case "/download/attachments/42/file.txt":
+			if r.Header.Get("If-Modified-Since") != "" {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			fullResponses++
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			_, _ = w.Write([]byte("attachment"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, "token", WithResponseCache(dir))
+	attachment := &model.ConfluenceAttachment{Title: "file.txt", DownloadLink: "/download/attachments/42/file.txt"}
+
+	for i := 0; i < 2; i++ {
+		page, err := client.GetPage("42")
+		if err != nil || page.Title != "Cached" || page.Version != 3 {
+			t.Fatalf("GetPage() #%d = %+v, %v", i+1, page, err)
+		}
+		data, err := client.DownloadAttachmentContent(attachment)
+		if err != nil || string(data) != "attachment" {
+			t.Fatalf("DownloadAttachmentContent() #%d = %q, %v", i+1, data, err)
+		}
+	}
+	if fullResponses != 2 || notModified != 2 {
+		t.Fatalf("expected 2 full and 2 not-modified responses, got %d and %d", fullResponses, notModified)
+	}
+
+	// A missing body falls back to an unconditional request
+	if err := os.Remove(filepath.Join(dir, cacheKey(server.URL+"/download/attachments/42/file.txt")+".body")); err != nil {
+		t.Fatalf("failed to remove cached body: %v", err)
+	}
+	if data, err := client.DownloadAttachmentContent(attachment); err != nil || string(data) != "attachment" {
+		t.Fatalf("DownloadAttachmentContent() after eviction = %q, %v", data, err)
+	}
+	if fullResponses != 3 {
+		t.Fatalf("expected a full response after eviction, got %d", fullResponses)
+	}
+}
+
+func TestResponseCacheStripsCredentialHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Set-Cookie", "JSESSIONID=secret")
+		w.Header().Set("WWW-Authenticate", "Bearer realm=secret")
+		_, _ = w.Write([]byte(`{"id":"42","title":"Cached"}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, "token", WithResponseCache(dir))
+	if _, err := client.GetPage("42"); err != nil {
+		t.Fatalf("GetPage() error = %v", err)
+	}
+
+	entries, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(entries) != 1 {
+		t.Fatalf("expected one cache entry, got %v", entries)
+	}
+	data, err := os.ReadFile(entries[0])
+	if err != nil {
+		t.Fatalf("failed to read cache entry: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Fatalf("cache entry keeps credential headers: %s", data)
+	}
+}
+
+func TestResponseCacheSkipsOtherRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("unexpected conditional request for %s", r.URL.Path)
+		}
+		w.Header().Set("ETag", `"list"`)
+		_, _ = w.Write([]byte(`{"results":[],"size":0}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, "token", WithResponseCache(dir))
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetChildPages("42"); err != nil {
+			t.Fatalf("GetChildPages() error = %v", err)
+		}
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("expected nothing cached for child page listings, got %d files", len(entries))
+	}
+}
+
+func TestResponseCacheDiscardsAbandonedBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"big"`)
+		_, _ = w.Write(make([]byte, 64*1024))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := NewClient(server.URL, "token", WithResponseCache(dir))
+	attachment := &model.ConfluenceAttachment{Title: "big.bin", DownloadLink: "/download/attachments/1/big.bin"}
+	if _, err := client.DownloadAttachmentTo(attachment, io.Discard, 1024, nil); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 0 {
+		t.Fatalf("expected the abandoned body not to be cached, got %d files", len(entries))
+	}
+}
@@ -2,9 +2,15 @@
 package confluence
 
 import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -19,21 +25,163 @@ type Client interface {
   RetrievePageID(spaceKey, pageName string) (string, error)
 	GetPage(pageID string) (*model.ConfluencePage, error)
 	GetChildPages(pageID string) ([]*model.ConfluencePage, error)
+	ListSpacePages(spaceKey string) ([]*model.ConfluencePage, error)
+	ListSpaceBlogPosts(spaceKey string) ([]*model.ConfluencePage, error)
+	ListSpaceAttachments(spaceKey string) ([]model.ConfluenceAttachment, error)
 	DownloadAttachmentContent(attachment *model.ConfluenceAttachment) ([]byte, error)
+	OpenAttachment(attachment *model.ConfluenceAttachment) (io.ReadCloser, error)
+	DownloadAttachmentTo(attachment *model.ConfluenceAttachment, w io.Writer, maxSize int64, progress ProgressFunc) (int64, error)
+	DownloadPublic(link string, w io.Writer, maxSize int64) (int64, error)
 	GetUser(accountID string) (*model.ConfluenceUser, error)
+	GetCurrentUser() (*model.ConfluenceUser, error)
+	Probe(path string) (*ProbeResult, error)
+	GetWorkflowStatus(pageID string) (*model.WorkflowStatus, error)
+	GetCalendarEvents(subCalendarID string, start, end time.Time) ([]model.CalendarEvent, error)
+	SearchContent(cql string, limit int) ([]*model.ConfluencePage, error)
+	GetSpaceShortcuts(spaceKey string) ([]model.SpaceShortcut, error)
+	ListSpaces(spaceType string) ([]model.ConfluenceSpace, error)
+	GetSpace(spaceKey string) (*model.ConfluenceSpace, error)
+	ListTemplates(spaceKey, templateType string) ([]model.PageTemplate, error)
+	ListQuestions(spaceKey string) ([]model.Question, error)
+	GetAnswers(questionID string) ([]model.Answer, error)
+	GetPageRestrictions(pageID string) ([]model.PageRestriction, error)
+	GetPageViews(pageID string) (int, error)
+	UpdatePage(pageID, title string, version int, storage string) (*model.ConfluencePage, error)
+	CreatePage(spaceKey, parentID, title, storage string) (*model.ConfluencePage, error)
+	UploadAttachment(pageID, fileName string, data []byte) (*model.ConfluenceAttachment, error)
+	UpdateAttachment(pageID, attachmentID, fileName string, data []byte) (*model.ConfluenceAttachment, error)
+	WithContext(ctx context.Context) Client
 }
 
+// ErrNotFound is returned when the requested resource does not exist or was deleted
+var ErrNotFound = errors.New("not found")
+
+// PageNotFoundError is returned by RetrievePageID when no page in the space has the title. It matches ErrNotFound.
+type PageNotFoundError struct {
+	SpaceKey string
+	Title    string
+}
+
+func (e *PageNotFoundError) Error() string {
+	return fmt.Sprintf("no page titled %q in space %s", e.Title, e.SpaceKey)
+}
+
+func (e *PageNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// AmbiguousPageError is returned by RetrievePageID when the title search matches several pages
+type AmbiguousPageError struct {
+	SpaceKey   string
+	Title      string
+	Candidates []*model.ConfluencePage
+}
+
+func (e *AmbiguousPageError) Error() string {
+	candidates := make([]string, 0, len(e.Candidates))
+	for _, page := range e.Candidates {
+		candidates = append(candidates, fmt.Sprintf("%s (%s)", page.Title, page.ID))
+	}
+	return fmt.Sprintf("%d pages in space %s match %q, use a page ID URL for one of: %s",
+		len(e.Candidates), e.SpaceKey, e.Title, strings.Join(candidates, ", "))
+}
+
+// ErrForbidden is returned when the API token may not access the requested resource
+var ErrForbidden = errors.New("permission denied")
+
+// ErrAttachmentTooLarge is returned when an attachment exceeds the size limit of DownloadAttachmentTo
+var ErrAttachmentTooLarge = errors.New("attachment too large")
+
+// ProgressFunc is called as attachment content is written, with total -1 when the size is unknown
+type ProgressFunc func(written, total int64)
+
+// APIError is an unsuccessful Confluence API response. It matches ErrNotFound and ErrForbidden with errors.Is.
+type APIError struct {
+	StatusCode int
+	RequestID  string // X-Request-Id sent with the failed request, for correlating with Atlassian support logs
+	message    string
+}
+
+func (e *APIError) Error() string {
+	return e.message
+}
+
+// Is maps the response status onto the package's sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrForbidden:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden
+	}
+	return false
+}
+
+// ErrReadOnly is returned for requests that would modify Confluence on a read-only client
+var ErrReadOnly = errors.New("refusing to modify Confluence in read-only mode")
+
 // client represents a Confluence API client
 type client struct {
 	baseURL    string
 	apiToken   string
+	email      string // set for Basic authentication with an Atlassian Cloud API token
 	httpClient *http.Client
 	userAgent  string
+	readOnly   bool
+	observer   RequestObserver
+	ctx        context.Context // cancels in-flight requests, nil for none
+}
+
+// Option configures a Confluence API client
+type Option func(*client)
+
+// WithReadOnly makes the client refuse every request other than GET
+func WithReadOnly() Option {
+	return func(c *client) {
+		c.readOnly = true
+	}
+}
+
+// RequestObserver is called after every API request with the response status (0 when the request failed)
+type RequestObserver func(method string, status int, elapsed time.Duration)
+
+// WithRequestObserver reports every request to observer, e.g. for metrics
+func WithRequestObserver(observer RequestObserver) Option {
+	return func(c *client) {
+		c.observer = observer
+	}
+}
+
+// WithUserAgent replaces the default ConfluenceMd/<version> User-Agent header
+func WithUserAgent(userAgent string) Option {
+	return func(c *client) {
+		if userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+// WithBasicAuth sends the API token as the password of email with Basic authentication,
+// as Atlassian Cloud API tokens require, instead of as a Bearer token
+func WithBasicAuth(email string) Option {
+	return func(c *client) {
+		c.email = email
+	}
+}
+
+// requestIDHeader carries a unique ID per API call, shown in error messages and debug logs
+const requestIDHeader = "X-Request-Id"
+
+// newRequestID returns a random 16 character hex ID
+func newRequestID() string {
+	id := make([]byte, 8)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
 }
 
 // NewClient creates a new Confluence API client
-func NewClient(baseURL, apiToken string) Client {
-	return &client{
+func NewClient(baseURL, apiToken string, opts ...Option) Client {
+	c := &client{
 		baseURL:  strings.TrimSuffix(baseURL, "/"),
 		apiToken: apiToken,
 		httpClient: &http.Client{
@@ -41,11 +189,50 @@ func NewClient(baseURL, apiToken string) Client {
 		},
 		userAgent: fmt.Sprintf("ConfluenceMd/%s", version.Short()),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
+// RetrievePageID looks up a page by space key and exact title. Titles from /display/ URLs may use
+// "+" for spaces. Without an exact match it falls back to a CQL title search and returns the single
+// match, a PageNotFoundError when nothing matches, or an AmbiguousPageError listing the candidates.
 func (c *client) RetrievePageID(spaceKey, pageName string) (string, error) {
-	endpoint := fmt.Sprintf("/rest/api/content?spaceKey=%s&title=%s", spaceKey, pageName)
-	fullURL := c.baseURL + endpoint
+	titles := []string{pageName}
+	if spaced := strings.ReplaceAll(pageName, "+", " "); spaced != pageName {
+		titles = append(titles, spaced)
+	}
+
+	for _, title := range titles {
+		pageID, err := c.findPageByTitle(spaceKey, title)
+		if err != nil {
+			return "", err
+		}
+		if pageID != "" {
+			return pageID, nil
+		}
+	}
+
+	cql := fmt.Sprintf(`space = "%s" and type = page and title ~ "%s"`, EscapeCQL(spaceKey), EscapeCQL(titles[len(titles)-1]))
+	candidates, err := c.SearchContent(cql, titleSearchLimit)
+	if err != nil {
+		return "", fmt.Errorf("failed to search for page %q: %w", pageName, err)
+	}
+	return choosePageCandidate(spaceKey, titles, candidates)
+}
+
+// titleSearchLimit caps the candidates considered by the RetrievePageID title search
+const titleSearchLimit = 25
+
+// findPageByTitle returns the ID of the page with exactly this title, or an empty ID when there is none
+func (c *client) findPageByTitle(spaceKey, title string) (string, error) {
+	params := url.Values{
+		"spaceKey": []string{spaceKey},
+		"title":    []string{title},
+		"type":     []string{"page"},
+	}
+	fullURL := c.baseURL + "/rest/api/content?" + params.Encode()
 
 	resp, err := c.makeRequest("GET", fullURL, nil)
 	if err != nil {
@@ -59,27 +246,52 @@ func (c *client) RetrievePageID(spaceKey, pageName string) (string, error) {
 		return "", c.handleErrorResponse(resp, "retrieve page ID")
 	}
 
-	var result map[string]interface{}
+	var result model.ConfluenceSearchResult
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return "", fmt.Errorf("failed to decode page ID response: %w", err)
 	}
+	if len(result.Results) == 0 {
+		return "", nil
+	}
+	return result.Results[0].ID, nil
+}
 
-	// Get page ID from response
-	pageID, ok := result["results"].([]interface{})[0].(map[string]interface{})["id"].(string)
-	if !ok {
-		return "", fmt.Errorf("failed to retrieve page ID: %w", err)
+// choosePageCandidate picks the title search match, preferring a case-insensitive title match when several pages match
+func choosePageCandidate(spaceKey string, titles []string, candidates []*model.ConfluencePage) (string, error) {
+	switch len(candidates) {
+	case 0:
+		return "", &PageNotFoundError{SpaceKey: spaceKey, Title: titles[0]}
+	case 1:
+		return candidates[0].ID, nil
+	}
+
+	var matches []*model.ConfluencePage
+	for _, candidate := range candidates {
+		for _, title := range titles {
+			if strings.EqualFold(candidate.Title, title) {
+				matches = append(matches, candidate)
+				break
+			}
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0].ID, nil
 	}
-	return pageID, nil
-	
+	return "", &AmbiguousPageError{SpaceKey: spaceKey, Title: titles[0], Candidates: candidates}
+}
+
+// EscapeCQL escapes a value for use inside a double-quoted CQL string
+func EscapeCQL(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
 }
 
 // GetPage retrieves a Confluence page by ID
 func (c *client) GetPage(pageID string) (*model.ConfluencePage, error) {
 	// Build URL with expansions to get all needed data
-	endpoint := fmt.Sprintf("/rest/api/content/%s", pageID)
+	endpoint := fmt.Sprintf("/rest/api/content/%s", url.PathEscape(pageID))
 	params := url.Values{
 		"expand": []string{
-			"body.storage,metadata.labels,version,space,history,children.attachment",
+			"body.storage,metadata.labels,version,space,history,children.attachment,ancestors",
 		},
 	}
 
@@ -112,7 +324,7 @@ const defaultChildPageLimit = 100
 
 // GetChildPages retrieves all child pages for a given page ID
 func (c *client) GetChildPages(pageID string) ([]*model.ConfluencePage, error) {
-	endpoint := fmt.Sprintf("/rest/api/content/%s/child/page", pageID)
+	endpoint := fmt.Sprintf("/rest/api/content/%s/child/page", url.PathEscape(pageID))
 	params := url.Values{
 		"expand": []string{"body.storage,metadata.labels,version,space,history"},
 		"limit":  []string{strconv.Itoa(defaultChildPageLimit)},
@@ -168,6 +380,144 @@ func (c *client) GetChildPages(pageID string) ([]*model.ConfluencePage, error) {
 	return childPages, nil
 }
 
+// SearchContent runs a CQL query and returns up to limit matching pages
+func (c *client) SearchContent(cql string, limit int) ([]*model.ConfluencePage, error) {
+	if limit <= 0 {
+		limit = defaultChildPageLimit
+	}
+	params := url.Values{
+		"cql":    []string{cql},
+		"expand": []string{"space,version"},
+		"limit":  []string{strconv.Itoa(limit)},
+	}
+	fullURL := c.baseURL + "/rest/api/content/search?" + params.Encode()
+
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search content: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("search content %q", cql))
+	}
+
+	var searchResult model.ConfluenceSearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&searchResult); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	pages := make([]*model.ConfluencePage, 0, len(searchResult.Results))
+	for _, apiPage := range searchResult.Results {
+		pages = append(pages, model.ConvertAPIPageToModel(&apiPage))
+	}
+
+	return pages, nil
+}
+
+// ListSpacePages lists every current page of a space with its ancestor IDs but without bodies
+func (c *client) ListSpacePages(spaceKey string) ([]*model.ConfluencePage, error) {
+	return c.listSpaceContent(spaceKey, "page", "ancestors,version,space", "pages")
+}
+
+// ListSpaceBlogPosts lists every blog post of the space with its creation date
+func (c *client) ListSpaceBlogPosts(spaceKey string) ([]*model.ConfluencePage, error) {
+	return c.listSpaceContent(spaceKey, "blogpost", "version,space,history", "blog posts")
+}
+
+// listSpaceContent lists all content of one type in the space, following the _links.next cursor
+func (c *client) listSpaceContent(spaceKey, contentType, expand, noun string) ([]*model.ConfluencePage, error) {
+	params := url.Values{
+		"spaceKey": []string{spaceKey},
+		"type":     []string{contentType},
+		"expand":   []string{expand},
+		"limit":    []string{strconv.Itoa(defaultChildPageLimit)},
+	}
+
+	var pages []*model.ConfluencePage
+	fullURL := c.baseURL + "/rest/api/content?" + params.Encode()
+
+	for fullURL != "" {
+		resp, err := c.makeRequest("GET", fullURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s of space %s: %w", noun, spaceKey, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := c.handleErrorResponse(resp, fmt.Sprintf("list %s of space %s", noun, spaceKey))
+			_ = resp.Body.Close()
+			return nil, err
+		}
+
+		var result model.ConfluenceSearchResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode space %s response: %w", noun, err)
+		}
+		_ = resp.Body.Close()
+
+		for _, apiPage := range result.Results {
+			pages = append(pages, model.ConvertAPIPageToModel(&apiPage))
+		}
+
+		fullURL = c.nextPageURL(result.Links.Next)
+	}
+
+	return pages, nil
+}
+
+// ListSpaceAttachments lists every attachment in the space with the page or blog post it belongs to.
+// Search results are paged with the _links.next cursor, since Cloud ignores deep start offsets.
+func (c *client) ListSpaceAttachments(spaceKey string) ([]model.ConfluenceAttachment, error) {
+	params := url.Values{
+		"cql":    []string{fmt.Sprintf(`space = "%s" and type = attachment`, EscapeCQL(spaceKey))},
+		"expand": []string{"container,version"},
+		"limit":  []string{strconv.Itoa(defaultChildPageLimit)},
+	}
+
+	var attachments []model.ConfluenceAttachment
+	fullURL := c.baseURL + "/rest/api/content/search?" + params.Encode()
+
+	for fullURL != "" {
+		resp, err := c.makeRequest("GET", fullURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list attachments of space %s: %w", spaceKey, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := c.handleErrorResponse(resp, fmt.Sprintf("list attachments of space %s", spaceKey))
+			_ = resp.Body.Close()
+			return nil, err
+		}
+
+		var result model.ConfluenceAttachmentResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode space attachments response: %w", err)
+		}
+		_ = resp.Body.Close()
+
+		for i := range result.Results {
+			attachments = append(attachments, model.ConvertAPIAttachmentToModel(&result.Results[i]))
+		}
+
+		fullURL = c.nextPageURL(result.Links.Next)
+	}
+
+	return attachments, nil
+}
+
+// nextPageURL resolves a listing's _links.next, a path relative to the base URL, or returns "" on the last page.
+// Links that are not such a path are ignored, so a response cannot send the credentials to another host.
+func (c *client) nextPageURL(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") {
+		return ""
+	}
+	return c.baseURL + next
+}
+
 // makeRequest makes an HTTP request with authentication
 func (c *client) makeRequest(method, url string, body io.Reader) (*http.Response, error) {
 	req, err := http.NewRequest(method, url, body)
@@ -176,20 +526,93 @@ func (c *client) makeRequest(method, url string, body io.Reader) (*http.Response
 	}
 
 	// Set headers
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiToken))
+	c.setAuthorization(req)
 	//req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", c.userAgent)
 
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
-	return c.httpClient.Do(req)
+	return c.do(req)
+}
+
+// do sends the request with the User-Agent and a new request ID, rejecting modifications when the client is read-only
+func (c *client) do(req *http.Request) (*http.Response, error) {
+	return c.send(c.httpClient, req)
+}
+
+// send is do with the given HTTP client
+func (c *client) send(httpClient *http.Client, req *http.Request) (*http.Response, error) {
+	if c.readOnly && req.Method != http.MethodGet {
+		return nil, fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, ErrReadOnly)
+	}
+
+	if c.ctx != nil {
+		req = req.WithContext(c.ctx)
+	}
+	requestID := newRequestID()
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set(requestIDHeader, requestID)
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if c.observer != nil {
+		status := 0
+		if err == nil {
+			status = resp.StatusCode
+		}
+		c.observer(req.Method, status, time.Since(start))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w (request id %s)", err, requestID)
+	}
+	return resp, nil
+}
+
+// WithContext returns a copy of the client whose requests are cancelled when ctx is done,
+// e.g. when a page exceeds its conversion deadline
+func (c *client) WithContext(ctx context.Context) Client {
+	clone := *c
+	clone.ctx = ctx
+	return &clone
+}
+
+// setAuthorization adds the bearer token, or Basic credentials when an email is set;
+// without a token requests are sent anonymously for public sites
+func (c *client) setAuthorization(req *http.Request) {
+	if c.apiToken == "" {
+		return
+	}
+	if c.email != "" {
+		req.SetBasicAuth(c.email, c.apiToken)
+		return
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiToken))
 }
 
-// DownloadAttachmentContent downloads attachment binary content
+// DownloadAttachmentContent downloads attachment binary content into memory.
+// Use OpenAttachment for attachments that may be large.
 func (c *client) DownloadAttachmentContent(attachment *model.ConfluenceAttachment) ([]byte, error) {
+	body, err := c.OpenAttachment(attachment)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment content: %w", err)
+	}
+
+	return data, nil
+}
+
+// OpenAttachment starts downloading attachment binary content and returns the response body
+// without buffering it. The caller must close the body.
+func (c *client) OpenAttachment(attachment *model.ConfluenceAttachment) (io.ReadCloser, error) {
 	if attachment == nil {
 		return nil, fmt.Errorf("attachment is nil")
 	}
@@ -211,28 +634,121 @@ func (c *client) DownloadAttachmentContent(attachment *model.ConfluenceAttachmen
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiToken))
+	// Absolute download links may point at other hosts, which must not see the token
+	if c.isBaseHost(req.URL) {
+		c.setAuthorization(req)
+	}
 	req.Header.Set("Accept", "*/*")
-	req.Header.Set("User-Agent", c.userAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to download attachment %s: %w", attachment.Title, err)
 	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("download attachment %s", attachment.Title))
+	}
+
+	return resp.Body, nil
+}
+
+// DownloadAttachmentTo streams attachment content into w and returns the number of bytes written.
+// With maxSize > 0 the download is rejected up front when the attachment metadata exceeds it, and
+// aborted mid-stream with ErrAttachmentTooLarge once more than maxSize bytes arrive.
+func (c *client) DownloadAttachmentTo(attachment *model.ConfluenceAttachment, w io.Writer, maxSize int64, progress ProgressFunc) (int64, error) {
+	if attachment != nil && maxSize > 0 && attachment.FileSize > maxSize {
+		return 0, fmt.Errorf("%w: %s is %d bytes (max %d)", ErrAttachmentTooLarge, attachment.Title, attachment.FileSize, maxSize)
+	}
+
+	body, err := c.OpenAttachment(attachment)
+	if err != nil {
+		return 0, err
+	}
 	defer func() {
-		_ = resp.Body.Close()
+		_ = body.Close()
 	}()
 
+	total := attachment.FileSize
+	if total <= 0 {
+		total = -1
+	}
+	dst := &limitedWriter{w: w, max: maxSize, total: total, progress: progress}
+	written, err := io.Copy(dst, body)
+	if errors.Is(err, ErrAttachmentTooLarge) {
+		return written, fmt.Errorf("%w: %s exceeds %d bytes", ErrAttachmentTooLarge, attachment.Title, maxSize)
+	}
+	if err != nil {
+		return written, fmt.Errorf("failed to read attachment content: %w", err)
+	}
+	return written, nil
+}
+
+// publicDownloadTimeout bounds a DownloadPublic request, body included, even when the client has no context
+const publicDownloadTimeout = 30 * time.Second
+
+// DownloadPublic streams an http(s) link hosted outside the base URL into w without credentials, failing with
+// ErrAttachmentTooLarge once more than maxSize bytes arrive. It uses its own HTTP client with a timeout over the
+// same transport chain, so the User-Agent, request ID, context and debug tracing still apply.
+func (c *client) DownloadPublic(link string, w io.Writer, maxSize int64) (int64, error) {
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return 0, fmt.Errorf("refusing to download %s: not an http(s) URL", redactURL(req.URL))
+	}
+	req.Header.Set("Accept", "*/*")
+
+	public := &http.Client{Transport: c.httpClient.Transport, Timeout: publicDownloadTimeout}
+	resp, err := c.send(public, req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to download %s: %w", redactURL(req.URL), err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
 	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleErrorResponse(resp, fmt.Sprintf("download attachment %s", attachment.Title))
+		return 0, c.handleErrorResponse(resp, "download "+redactURL(req.URL))
 	}
 
-	data, err := io.ReadAll(resp.Body)
+	written, err := io.Copy(&limitedWriter{w: w, max: maxSize, total: -1}, resp.Body)
+	if errors.Is(err, ErrAttachmentTooLarge) {
+		return written, fmt.Errorf("%w: %s exceeds %d bytes", ErrAttachmentTooLarge, redactURL(req.URL), maxSize)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to read attachment content: %w", err)
+		return written, fmt.Errorf("failed to read %s: %w", redactURL(req.URL), err)
 	}
+	return written, nil
+}
 
-	return data, nil
+// limitedWriter reports progress and fails before a write would take it past max bytes (0 for no limit)
+type limitedWriter struct {
+	w        io.Writer
+	max      int64
+	total    int64
+	written  int64
+	progress ProgressFunc
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	if l.max > 0 && l.written+int64(len(p)) > l.max {
+		return 0, ErrAttachmentTooLarge
+	}
+	n, err := l.w.Write(p)
+	l.written += int64(n)
+	if l.progress != nil {
+		l.progress(l.written, l.total)
+	}
+	return n, err
+}
+
+// isBaseHost reports whether u is on the same host as the client's base URL
+func (c *client) isBaseHost(u *url.URL) bool {
+	base, err := url.Parse(c.baseURL)
+	return err == nil && strings.EqualFold(u.Host, base.Host)
 }
 
 func (c *client) normalizeDownloadLink(link string) (string, error) {
@@ -277,6 +793,9 @@ func (c *client) GetUser(accountID string) (*model.ConfluenceUser, error) {
 		_ = resp.Body.Close()
 	}()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("failed to get user %s: %w", accountID, ErrNotFound)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, c.handleErrorResponse(resp, fmt.Sprintf("get user %s", accountID))
 	}
@@ -289,19 +808,571 @@ func (c *client) GetUser(accountID string) (*model.ConfluenceUser, error) {
 	return &user, nil
 }
 
+// GetCurrentUser returns the user the client authenticates as; anonymous clients get the anonymous user
+func (c *client) GetCurrentUser() (*model.ConfluenceUser, error) {
+	resp, err := c.makeRequest("GET", c.baseURL+"/rest/api/user/current", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, "get current user")
+	}
+
+	var user model.ConfluenceUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode current user response: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetWorkflowStatus retrieves the Comala Document Management state of a page.
+// ErrNotFound means the app is not installed or the page has no workflow.
+func (c *client) GetWorkflowStatus(pageID string) (*model.WorkflowStatus, error) {
+	fullURL := fmt.Sprintf("%s/rest/cw/1/content/%s/status?expand=approvals", c.baseURL, url.PathEscape(pageID))
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow status for %s: %w", pageID, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("get workflow status for %s", pageID))
+	}
+
+	var status model.ComalaStatusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow status response: %w", err)
+	}
+
+	return model.ConvertComalaStatusToModel(&status), nil
+}
+
 // handleErrorResponse handles error responses from the API
 func (c *client) handleErrorResponse(resp *http.Response, operation string) error {
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to %s: HTTP %d", operation, resp.StatusCode)
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if resp.Request != nil {
+		apiErr.RequestID = resp.Request.Header.Get(requestIDHeader)
 	}
 
-	// Try to parse error response
+	bodyBytes, err := io.ReadAll(resp.Body)
 	var errorResp model.ConfluenceErrorResponse
-	if err := json.Unmarshal(bodyBytes, &errorResp); err == nil {
-		return fmt.Errorf("failed to %s: %s", operation, errorResp.Message)
+	switch {
+	case err != nil:
+		apiErr.message = fmt.Sprintf("failed to %s: HTTP %d", operation, resp.StatusCode)
+	case json.Unmarshal(bodyBytes, &errorResp) == nil:
+		// Parsed error response
+		apiErr.message = fmt.Sprintf("failed to %s: %s", operation, errorResp.Message)
+	default:
+		// Fallback to HTTP status
+		apiErr.message = fmt.Sprintf("failed to %s: HTTP %d - %s", operation, resp.StatusCode, string(bodyBytes))
+	}
+
+	if apiErr.RequestID != "" {
+		apiErr.message += fmt.Sprintf(" (request id %s)", apiErr.RequestID)
+	}
+	return apiErr
+}
+
+// GetCalendarEvents retrieves Team Calendars events for a sub-calendar within the given window
+func (c *client) GetCalendarEvents(subCalendarID string, start, end time.Time) ([]model.CalendarEvent, error) {
+	endpoint := "/rest/calendar-services/1.0/calendar/events.json"
+	params := url.Values{
+		"subCalendarId":  []string{subCalendarID},
+		"userTimeZoneId": []string{"UTC"},
+		"start":          []string{start.UTC().Format(time.RFC3339)},
+		"end":            []string{end.UTC().Format(time.RFC3339)},
+	}
+	fullURL := c.baseURL + endpoint + "?" + params.Encode()
+
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get calendar events for %s: %w", subCalendarID, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("get calendar events for %s", subCalendarID))
+	}
+
+	var result model.CalendarEventsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode calendar events response: %w", err)
+	}
+
+	return result.Events, nil
+}
+
+// GetSpaceShortcuts retrieves the quick links shown in a space's sidebar
+func (c *client) GetSpaceShortcuts(spaceKey string) ([]model.SpaceShortcut, error) {
+	params := url.Values{"spaceKey": []string{spaceKey}}
+	fullURL := c.baseURL + "/rest/ia/1.0/space?" + params.Encode()
+
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get space shortcuts for %s: %w", spaceKey, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("get space shortcuts for %s", spaceKey))
+	}
+
+	var result model.SpaceSidebarResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode space sidebar response: %w", err)
+	}
+
+	return result.QuickLinks, nil
+}
+
+// ListSpaces retrieves every space the token can read; spaceType filters by "global" or "personal" when set
+func (c *client) ListSpaces(spaceType string) ([]model.ConfluenceSpace, error) {
+	params := url.Values{
+		"expand": []string{"homepage"},
+		"limit":  []string{strconv.Itoa(defaultChildPageLimit)},
+	}
+	if spaceType != "" {
+		params.Set("type", spaceType)
+	}
+
+	var spaces []model.ConfluenceSpace
+	start := 0
+
+	for {
+		params.Set("start", strconv.Itoa(start))
+		fullURL := c.baseURL + "/rest/api/space?" + params.Encode()
+
+		resp, err := c.makeRequest("GET", fullURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list spaces: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := c.handleErrorResponse(resp, "list spaces")
+			_ = resp.Body.Close()
+			return nil, err
+		}
+
+		var result model.ConfluenceSpaceListResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode spaces response: %w", err)
+		}
+		_ = resp.Body.Close()
+
+		for _, space := range result.Results {
+			spaces = append(spaces, model.ConfluenceSpace{
+				Key:        space.Key,
+				Name:       space.Name,
+				Type:       space.Type,
+				HomepageID: space.Homepage.ID,
+			})
+		}
+
+		limit := result.Limit
+		if limit <= 0 {
+			limit = defaultChildPageLimit
+		}
+		if len(result.Results) < limit {
+			break
+		}
+
+		start += limit
+	}
+
+	return spaces, nil
+}
+
+// GetSpace retrieves a space with its description, labels, categories and administrators
+func (c *client) GetSpace(spaceKey string) (*model.ConfluenceSpace, error) {
+	params := url.Values{"expand": []string{"description.plain,homepage,metadata.labels,permissions"}}
+	fullURL := c.baseURL + "/rest/api/space/" + url.PathEscape(spaceKey) + "?" + params.Encode()
+
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get space %s: %w", spaceKey, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("get space %s", spaceKey))
+	}
+
+	var result model.ConfluenceSpaceResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode space response: %w", err)
+	}
+
+	space := result.ToConfluenceSpace()
+	return &space, nil
+}
+
+// ListTemplates retrieves the page templates ("page") or blueprint templates ("blueprint") of a space with their bodies.
+// An empty spaceKey lists the global templates.
+func (c *client) ListTemplates(spaceKey, templateType string) ([]model.PageTemplate, error) {
+	params := url.Values{
+		"expand": []string{"body.storage"},
+		"limit":  []string{strconv.Itoa(defaultChildPageLimit)},
+	}
+	if spaceKey != "" {
+		params.Set("spaceKey", spaceKey)
+	}
+
+	var templates []model.PageTemplate
+	start := 0
+
+	for {
+		params.Set("start", strconv.Itoa(start))
+		fullURL := c.baseURL + "/rest/api/template/" + url.PathEscape(templateType) + "?" + params.Encode()
+
+		resp, err := c.makeRequest("GET", fullURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list templates: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := c.handleErrorResponse(resp, "list templates")
+			_ = resp.Body.Close()
+			return nil, err
+		}
+
+		var result model.ConfluenceTemplateListResult
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("failed to decode templates response: %w", err)
+		}
+		_ = resp.Body.Close()
+
+		for _, entry := range result.Results {
+			template := model.PageTemplate{
+				ID:          entry.TemplateID,
+				Name:        entry.Name,
+				Description: entry.Description,
+				Type:        templateType,
+				Body:        entry.Body.Storage.Value,
+			}
+			if entry.Space != nil {
+				template.SpaceKey = entry.Space.Key
+			}
+			for _, label := range entry.Labels {
+				template.Labels = append(template.Labels, label.Name)
+			}
+			templates = append(templates, template)
+		}
+
+		limit := result.Limit
+		if limit <= 0 {
+			limit = defaultChildPageLimit
+		}
+		if len(result.Results) < limit {
+			break
+		}
+
+		start += limit
+	}
+
+	return templates, nil
+}
+
+// ListQuestions retrieves the questions asked in a space with the Confluence Questions app.
+// ErrNotFound is returned when the app is not installed.
+func (c *client) ListQuestions(spaceKey string) ([]model.Question, error) {
+	var questions []model.Question
+	err := c.getQuestionsPages("/rest/questions/1.0/question", url.Values{"spaceKey": []string{spaceKey}}, "list questions", func(data []byte) (int, error) {
+		var results []model.ConfluenceQuestionResult
+		if err := json.Unmarshal(data, &results); err != nil {
+			return 0, fmt.Errorf("failed to decode questions response: %w", err)
+		}
+		for _, result := range results {
+			questions = append(questions, result.ToQuestion())
+		}
+		return len(results), nil
+	})
+	return questions, err
+}
+
+// GetAnswers retrieves the answers to a Confluence Questions question
+func (c *client) GetAnswers(questionID string) ([]model.Answer, error) {
+	var answers []model.Answer
+	path := fmt.Sprintf("/rest/questions/1.0/question/%s/answers", url.PathEscape(questionID))
+	err := c.getQuestionsPages(path, url.Values{}, fmt.Sprintf("get answers for question %s", questionID), func(data []byte) (int, error) {
+		var results []model.ConfluenceAnswerResult
+		if err := json.Unmarshal(data, &results); err != nil {
+			return 0, fmt.Errorf("failed to decode answers response: %w", err)
+		}
+		for _, result := range results {
+			answers = append(answers, result.ToAnswer())
+		}
+		return len(results), nil
+	})
+	return answers, err
+}
+
+// getQuestionsPages pages through a Questions API list endpoint, which returns plain JSON arrays.
+// decode returns the number of entries on the page, and paging stops at the first short page.
+func (c *client) getQuestionsPages(path string, params url.Values, operation string, decode func([]byte) (int, error)) error {
+	params.Set("limit", strconv.Itoa(defaultChildPageLimit))
+	start := 0
+
+	for {
+		params.Set("start", strconv.Itoa(start))
+		fullURL := c.baseURL + path + "?" + params.Encode()
+
+		resp, err := c.makeRequest("GET", fullURL, nil)
+		if err != nil {
+			return fmt.Errorf("failed to %s: %w", operation, err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := c.handleErrorResponse(resp, operation)
+			_ = resp.Body.Close()
+			return err
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read %s response: %w", operation, err)
+		}
+
+		count, err := decode(data)
+		if err != nil {
+			return err
+		}
+		if count < defaultChildPageLimit {
+			return nil
+		}
+		start += count
+	}
+}
+
+// GetPageViews retrieves the total view count of a page from the Confluence Analytics API.
+// ErrNotFound is returned where analytics are not available.
+func (c *client) GetPageViews(pageID string) (int, error) {
+	fullURL := c.baseURL + fmt.Sprintf("/rest/api/analytics/content/%s/views", url.PathEscape(pageID))
+
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get views for %s: %w", pageID, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, c.handleErrorResponse(resp, fmt.Sprintf("get views for %s", pageID))
+	}
+
+	var result model.ConfluenceViewsResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode views response: %w", err)
+	}
+	return result.Count, nil
+}
+
+// GetPageRestrictions retrieves the view and edit restrictions set directly on a page.
+// Restrictions inherited from ancestor pages are not included.
+func (c *client) GetPageRestrictions(pageID string) ([]model.PageRestriction, error) {
+	params := url.Values{"expand": []string{"restrictions.user,restrictions.group"}}
+	fullURL := c.baseURL + fmt.Sprintf("/rest/api/content/%s/restriction/byOperation?", url.PathEscape(pageID)) + params.Encode()
+
+	resp, err := c.makeRequest("GET", fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get restrictions for %s: %w", pageID, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("get restrictions for %s", pageID))
+	}
+
+	var result model.ConfluenceRestrictionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode restrictions response: %w", err)
+	}
+
+	var restrictions []model.PageRestriction
+	for _, operation := range []string{"read", "update"} {
+		entry, ok := result[operation]
+		if !ok {
+			continue
+		}
+		for _, user := range entry.Restrictions.User.Results {
+			name := user.DisplayName
+			if name == "" {
+				name = user.Username
+			}
+			restrictions = append(restrictions, model.PageRestriction{
+				Operation:     operation,
+				PrincipalType: "user",
+				Principal:     name,
+				AccountID:     user.AccountID,
+			})
+		}
+		for _, group := range entry.Restrictions.Group.Results {
+			restrictions = append(restrictions, model.PageRestriction{
+				Operation:     operation,
+				PrincipalType: "group",
+				Principal:     group.Name,
+			})
+		}
+	}
+
+	return restrictions, nil
+}
+
+// UpdatePage replaces a page's title and storage content; version must be the next version number
+func (c *client) UpdatePage(pageID, title string, version int, storage string) (*model.ConfluencePage, error) {
+	request := model.PageUpdateRequest{ID: pageID, Type: "page", Title: title}
+	request.Version.Number = version
+	request.Body.Storage.Value = storage
+	request.Body.Storage.Representation = "storage"
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page %s: %w", pageID, err)
+	}
+
+	fullURL := c.baseURL + fmt.Sprintf("/rest/api/content/%s", url.PathEscape(pageID))
+
+	resp, err := c.makeRequest("PUT", fullURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to update page %s: %w", pageID, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("update page %s", pageID))
+	}
+
+	var apiPage model.ConfluenceAPIPage
+	if err := json.NewDecoder(resp.Body).Decode(&apiPage); err != nil {
+		return nil, fmt.Errorf("failed to decode page response: %w", err)
+	}
+
+	return model.ConvertAPIPageToModel(&apiPage), nil
+}
+
+// CreatePage creates a page with storage content in the space, under parentID when it is set
+func (c *client) CreatePage(spaceKey, parentID, title, storage string) (*model.ConfluencePage, error) {
+	request := model.PageCreateRequest{Type: "page", Title: title}
+	request.Space.Key = spaceKey
+	if parentID != "" {
+		request.Ancestors = []model.PageAncestor{{ID: parentID}}
+	}
+	request.Body.Storage.Value = storage
+	request.Body.Storage.Representation = "storage"
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode page %s: %w", title, err)
+	}
+
+	resp, err := c.makeRequest("POST", c.baseURL+"/rest/api/content", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create page %s: %w", title, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("create page %s", title))
+	}
+
+	var apiPage model.ConfluenceAPIPage
+	if err := json.NewDecoder(resp.Body).Decode(&apiPage); err != nil {
+		return nil, fmt.Errorf("failed to decode page response: %w", err)
+	}
+
+	return model.ConvertAPIPageToModel(&apiPage), nil
+}
+
+// UploadAttachment adds a file as a new attachment to the page
+func (c *client) UploadAttachment(pageID, fileName string, data []byte) (*model.ConfluenceAttachment, error) {
+	fullURL := c.baseURL + fmt.Sprintf("/rest/api/content/%s/child/attachment", url.PathEscape(pageID))
+	return c.postAttachment(fullURL, fileName, data)
+}
+
+// UpdateAttachment uploads the file as a new version of an existing attachment
+func (c *client) UpdateAttachment(pageID, attachmentID, fileName string, data []byte) (*model.ConfluenceAttachment, error) {
+	fullURL := c.baseURL + fmt.Sprintf("/rest/api/content/%s/child/attachment/%s/data", url.PathEscape(pageID), url.PathEscape(attachmentID))
+	return c.postAttachment(fullURL, fileName, data)
+}
+
+// postAttachment sends the file as multipart form data and returns the stored attachment
+func (c *client) postAttachment(fullURL, fileName string, data []byte) (*model.ConfluenceAttachment, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode attachment %s: %w", fileName, err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to encode attachment %s: %w", fileName, err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode attachment %s: %w", fileName, err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fullURL, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setAuthorization(req)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	// Attachment uploads are rejected by XSRF protection without this header
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload attachment %s: %w", fileName, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp, fmt.Sprintf("upload attachment %s", fileName))
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment response: %w", err)
+	}
+
+	// New attachments come back as a result list, new versions as the attachment itself
+	var result model.ConfluenceAttachmentResult
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode attachment response: %w", err)
+	}
+	if len(result.Results) == 0 {
+		var apiAttachment model.ConfluenceAPIAttachment
+		if err := json.Unmarshal(respBody, &apiAttachment); err != nil || apiAttachment.ID == "" {
+			return nil, fmt.Errorf("failed to upload attachment %s: empty response", fileName)
+		}
+		result.Results = append(result.Results, apiAttachment)
 	}
 
-	// Fallback to HTTP status
-	return fmt.Errorf("failed to %s: HTTP %d - %s", operation, resp.StatusCode, string(bodyBytes))
+	attachment := model.ConvertAPIAttachmentToModel(&result.Results[0])
+	return &attachment, nil
 }
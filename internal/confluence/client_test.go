@@ -0,0 +1,556 @@
+package confluence
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestReadOnlyClientRejectsModifications(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"1","title":"Page","version":{"number":2}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", WithReadOnly())
+
+	if _, err := client.GetPage("1"); err != nil {
+		t.Fatalf("GetPage returned error: %v", err)
+	}
+	if _, err := client.UpdatePage("1", "Page", 3, "<p>x</p>"); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("UpdatePage error = %v, want ErrReadOnly", err)
+	}
+	if _, err := client.UploadAttachment("1", "a.png", []byte("png")); !errors.Is(err, ErrReadOnly) {
+		t.Fatalf("UploadAttachment error = %v, want ErrReadOnly", err)
+	}
+	if requests != 1 {
+		t.Fatalf("server received %d requests, want 1", requests)
+	}
+}
+
+func TestRequestObserver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	var statuses []int
+	client := NewClient(server.URL, "token", WithRequestObserver(func(method string, status int, _ time.Duration) {
+		if method != http.MethodGet {
+			t.Errorf("unexpected method %s", method)
+		}
+		statuses = append(statuses, status)
+	}))
+
+	if _, err := client.GetPage("1"); err == nil {
+		t.Fatal("expected error for rate-limited request")
+	}
+	if len(statuses) != 1 || statuses[0] != http.StatusTooManyRequests {
+		t.Fatalf("observed statuses %v, want [429]", statuses)
+	}
+}
+
+func TestAPIErrorMatchesSentinels(t *testing.T) {
+	for _, tt := range []struct {
+		status    int
+		forbidden bool
+		notFound  bool
+	}{
+		{http.StatusForbidden, true, false},
+		{http.StatusUnauthorized, true, false},
+		{http.StatusNotFound, false, true},
+		{http.StatusInternalServerError, false, false},
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			_, _ = w.Write([]byte(`{"message":"denied"}`))
+		}))
+
+		_, err := NewClient(server.URL, "token").GetPage("1")
+		server.Close()
+
+		if errors.Is(err, ErrForbidden) != tt.forbidden || errors.Is(err, ErrNotFound) != tt.notFound {
+			t.Errorf("HTTP %d: errors.Is(ErrForbidden) = %v, errors.Is(ErrNotFound) = %v", tt.status, errors.Is(err, ErrForbidden), errors.Is(err, ErrNotFound))
+		}
+	}
+}
+
+func TestListSpacePagesPaginatesWithAncestors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/content" || r.URL.Query().Get("spaceKey") != "DOCS" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"results":[{"id":"1","title":"Home"},{"id":"2","title":"Child","ancestors":[{"id":"1"}]}],"limit":2,"_links":{"next":"/rest/api/content?spaceKey=DOCS&type=page&limit=2&cursor=c2"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"id":"3","title":"Orphan"}],"limit":2}`))
+	}))
+	defer server.Close()
+
+	pages, err := NewClient(server.URL, "token").ListSpacePages("DOCS")
+	if err != nil {
+		t.Fatalf("ListSpacePages returned error: %v", err)
+	}
+	if len(pages) != 3 {
+		t.Fatalf("got %d pages, want 3", len(pages))
+	}
+	if len(pages[1].AncestorIDs) != 1 || pages[1].AncestorIDs[0] != "1" {
+		t.Errorf("ancestors of page 2 = %v, want [1]", pages[1].AncestorIDs)
+	}
+	if len(pages[2].AncestorIDs) != 0 {
+		t.Errorf("ancestors of page 3 = %v, want none", pages[2].AncestorIDs)
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/template/page" || r.URL.Query().Get("spaceKey") != "DOCS" || r.URL.Query().Get("expand") != "body.storage" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[{"templateId":"7","name":"Meeting notes","templateType":"page","labels":[{"name":"meeting"}],"space":{"key":"DOCS"},"body":{"storage":{"value":"<h2>Agenda</h2>"}}}],"limit":25}`))
+	}))
+	defer server.Close()
+
+	templates, err := NewClient(server.URL, "token").ListTemplates("DOCS", "page")
+	if err != nil {
+		t.Fatalf("ListTemplates returned error: %v", err)
+	}
+	if len(templates) != 1 {
+		t.Fatalf("got %d templates, want 1", len(templates))
+	}
+	template := templates[0]
+	if template.ID != "7" || template.SpaceKey != "DOCS" || template.Body != "<h2>Agenda</h2>" || len(template.Labels) != 1 || template.Labels[0] != "meeting" {
+		t.Errorf("unexpected template: %#v", template)
+	}
+}
+
+func TestGetSpace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/space/DOCS" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"key":"DOCS","name":"Docs","type":"global","homepage":{"id":"1"},
+			"description":{"plain":{"value":"Product documentation"}},
+			"metadata":{"labels":{"results":[{"prefix":"global","name":"public"},{"prefix":"team","name":"engineering"}]}},
+			"permissions":[
+				{"operation":{"operation":"administer","targetType":"space"},"subjects":{"user":{"results":[{"displayName":"Ada"}]}}},
+				{"operation":{"operation":"administer","targetType":"space"},"subjects":{"group":{"results":[{"name":"docs-admins"}]}}},
+				{"operation":{"operation":"read","targetType":"space"},"subjects":{"user":{"results":[{"displayName":"Bob"}]}}}
+			]}`))
+	}))
+	defer server.Close()
+
+	space, err := NewClient(server.URL, "token").GetSpace("DOCS")
+	if err != nil {
+		t.Fatalf("GetSpace returned error: %v", err)
+	}
+	if space.Description != "Product documentation" || space.HomepageID != "1" {
+		t.Errorf("unexpected space: %#v", space)
+	}
+	if strings.Join(space.Labels, ",") != "public" || strings.Join(space.Categories, ",") != "engineering" {
+		t.Errorf("unexpected labels %v or categories %v", space.Labels, space.Categories)
+	}
+	if strings.Join(space.Admins, ",") != "Ada,docs-admins" {
+		t.Errorf("unexpected admins: %v", space.Admins)
+	}
+}
+
+func TestListQuestionsAndAnswers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/questions/1.0/question":
+			if r.URL.Query().Get("spaceKey") != "DOCS" {
+				t.Errorf("unexpected request %s", r.URL)
+			}
+			_, _ = w.Write([]byte(`[{"id":12,"title":"How to deploy?","body":{"content":"<p>Steps?</p>"},"author":{"name":"ada","fullName":"Ada"},"dateAsked":1704067200000,"voteScore":3,"answersCount":1,"acceptedAnswerId":34,"topics":[{"name":"ops"}]}]`))
+		case "/rest/questions/1.0/question/12/answers":
+			_, _ = w.Write([]byte(`[{"id":34,"body":{"content":"<p>Run make.</p>"},"author":{"name":"bob"},"dateAnswered":1704153600000,"voteScore":5,"accepted":true}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	questions, err := client.ListQuestions("DOCS")
+	if err != nil {
+		t.Fatalf("ListQuestions returned error: %v", err)
+	}
+	if len(questions) != 1 {
+		t.Fatalf("got %d questions, want 1", len(questions))
+	}
+	question := questions[0]
+	if question.ID != "12" || question.Author != "Ada" || question.AcceptedAnswerID != "34" || question.Votes != 3 || !question.AskedAt.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected question: %#v", question)
+	}
+
+	answers, err := client.GetAnswers("12")
+	if err != nil {
+		t.Fatalf("GetAnswers returned error: %v", err)
+	}
+	if len(answers) != 1 || answers[0].ID != "34" || answers[0].Author != "bob" || !answers[0].Accepted {
+		t.Errorf("unexpected answers: %#v", answers)
+	}
+}
+
+func TestGetPageViews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/analytics/content/42/views" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":42,"count":17}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	views, err := client.GetPageViews("42")
+	if err != nil || views != 17 {
+		t.Fatalf("GetPageViews() = %d, %v; want 17", views, err)
+	}
+	if _, err := client.GetPageViews("43"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestOpenAttachmentStreamsBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/download/attachments/1/big.bin" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("binary-content"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token")
+	body, err := client.OpenAttachment(&model.ConfluenceAttachment{Title: "big.bin", DownloadLink: "/download/attachments/1/big.bin"})
+	if err != nil {
+		t.Fatalf("OpenAttachment() error = %v", err)
+	}
+	data, err := io.ReadAll(body)
+	_ = body.Close()
+	if err != nil || string(data) != "binary-content" {
+		t.Fatalf("read body = %q, %v", data, err)
+	}
+
+	if _, err := client.OpenAttachment(&model.ConfluenceAttachment{Title: "gone.bin", DownloadLink: "/download/attachments/1/gone.bin"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDownloadAttachmentToEnforcesSizeLimit(t *testing.T) {
+	content := strings.Repeat("x", 64*1024)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token")
+
+	var buf strings.Builder
+	var progress []int64
+	attachment := &model.ConfluenceAttachment{Title: "file.bin", DownloadLink: "/download/file.bin", FileSize: int64(len(content))}
+	written, err := client.DownloadAttachmentTo(attachment, &buf, 0, func(written, total int64) {
+		if total != int64(len(content)) {
+			t.Errorf("progress total = %d, want %d", total, len(content))
+		}
+		progress = append(progress, written)
+	})
+	if err != nil || written != int64(len(content)) || buf.Len() != len(content) {
+		t.Fatalf("DownloadAttachmentTo() = %d, %v; wrote %d bytes", written, err, buf.Len())
+	}
+	if len(progress) == 0 || progress[len(progress)-1] != int64(len(content)) {
+		t.Fatalf("unexpected progress reports: %v", progress)
+	}
+
+	// The metadata understates the size, so the limit is only hit mid-stream
+	buf.Reset()
+	attachment.FileSize = 10
+	written, err = client.DownloadAttachmentTo(attachment, &buf, 1024, nil)
+	if !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if written > 1024 || buf.Len() > 1024 {
+		t.Fatalf("expected at most 1024 bytes before aborting, wrote %d", buf.Len())
+	}
+
+	attachment.FileSize = 2048
+	if _, err := client.DownloadAttachmentTo(attachment, &buf, 1024, nil); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Fatalf("expected ErrAttachmentTooLarge from metadata, got %v", err)
+	}
+}
+
+func TestDownloadPublic(t *testing.T) {
+	var authorization, userAgent string
+	emojiHost := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization, userAgent = r.Header.Get("Authorization"), r.Header.Get("User-Agent")
+		_, _ = w.Write([]byte(strings.Repeat("x", 2048)))
+	}))
+	defer emojiHost.Close()
+
+	var log strings.Builder
+	client := NewClient("https://confluence.example.com", "secret-token", WithUserAgent("docs-bot/1.0"), WithDebugHTTP(&log, ""))
+
+	var buf strings.Builder
+	written, err := client.DownloadPublic(emojiHost.URL+"/emoji.png", &buf, 4096)
+	if err != nil || written != 2048 {
+		t.Fatalf("DownloadPublic() = %d, %v", written, err)
+	}
+	if authorization != "" {
+		t.Errorf("DownloadPublic sent credentials to another host: %q", authorization)
+	}
+	if userAgent != "docs-bot/1.0" {
+		t.Errorf("User-Agent = %q, want the configured one", userAgent)
+	}
+	if !strings.Contains(log.String(), "/emoji.png -> 200") {
+		t.Errorf("debug log %q does not trace the download", log.String())
+	}
+
+	if _, err := client.DownloadPublic(emojiHost.URL+"/emoji.png", io.Discard, 1024); !errors.Is(err, ErrAttachmentTooLarge) {
+		t.Errorf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if _, err := client.DownloadPublic("file:///etc/passwd", io.Discard, 1024); err == nil {
+		t.Errorf("expected non-http(s) links to be refused")
+	}
+}
+
+func TestDownloadPublicHonorsContext(t *testing.T) {
+	release := make(chan struct{})
+	slowHost := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slowHost.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client := NewClient("https://confluence.example.com", "token").WithContext(ctx)
+	if _, err := client.DownloadPublic(slowHost.URL+"/emoji.png", io.Discard, 1024); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context deadline to abort the download, got %v", err)
+	}
+}
+
+func TestClientEscapesPathSegments(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token")
+
+	const id = "1/../../user/current?x"
+	const escaped = "1%2F..%2F..%2Fuser%2Fcurrent%3Fx"
+	calls := map[string]func(){
+		"GetPage":             func() { _, _ = client.GetPage(id) },
+		"GetChildPages":       func() { _, _ = client.GetChildPages(id) },
+		"GetWorkflowStatus":   func() { _, _ = client.GetWorkflowStatus(id) },
+		"GetPageRestrictions": func() { _, _ = client.GetPageRestrictions(id) },
+		"UpdatePage":          func() { _, _ = client.UpdatePage(id, "Title", 2, "<p/>") },
+		"UploadAttachment":    func() { _, _ = client.UploadAttachment(id, "a.png", []byte("x")) },
+		"UpdateAttachment":    func() { _, _ = client.UpdateAttachment("1", id, "a.png", []byte("x")) },
+	}
+	for name, call := range calls {
+		paths = nil
+		call()
+		if len(paths) == 0 {
+			t.Errorf("%s sent no request", name)
+			continue
+		}
+		for _, path := range paths {
+			if !strings.Contains(path, escaped) || strings.Contains(path, "/user/current") {
+				t.Errorf("%s requested %q, want the ID escaped as one path segment", name, path)
+			}
+		}
+	}
+}
+
+func TestRequestIDAndUserAgent(t *testing.T) {
+	var userAgent, requestID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent, requestID = r.UserAgent(), r.Header.Get("X-Request-Id")
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", WithUserAgent("docs-sync/1.0"))
+	_, err := client.GetPage("42")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if userAgent != "docs-sync/1.0" {
+		t.Fatalf("User-Agent = %q, want docs-sync/1.0", userAgent)
+	}
+	if requestID == "" || apiErr.RequestID != requestID {
+		t.Fatalf("APIError.RequestID = %q, want the sent X-Request-Id %q", apiErr.RequestID, requestID)
+	}
+	if !strings.Contains(err.Error(), "request id "+requestID) {
+		t.Fatalf("expected the request id in the error message, got %v", err)
+	}
+
+	first := requestID
+	if _, err := client.GetPage("43"); err == nil || requestID == first || strings.Contains(err.Error(), first) {
+		t.Fatalf("expected a new request id per call, got %v", err)
+	}
+}
+
+func TestRetrievePageID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/rest/api/content":
+			if query.Get("spaceKey") == "DOCS" && query.Get("title") == "Q&A / FAQ" {
+				_, _ = w.Write([]byte(`{"results":[{"id":"1","title":"Q&A / FAQ"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"results":[]}`))
+		case "/rest/api/content/search":
+			cql := query.Get("cql")
+			switch {
+			case strings.Contains(cql, `title ~ "Release notes"`):
+				_, _ = w.Write([]byte(`{"results":[{"id":"2","title":"Release Notes 2024"}]}`))
+			case strings.Contains(cql, `title ~ "Setup"`):
+				_, _ = w.Write([]byte(`{"results":[{"id":"3","title":"Setup guide"},{"id":"4","title":"setup"},{"id":"5","title":"Setup FAQ"}]}`))
+			case strings.Contains(cql, `title ~ "Guide"`):
+				_, _ = w.Write([]byte(`{"results":[{"id":"6","title":"Admin Guide"},{"id":"7","title":"User Guide"}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"results":[]}`))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token")
+
+	for title, want := range map[string]string{"Q&A / FAQ": "1", "Release+notes": "2", "Setup": "4"} {
+		if id, err := client.RetrievePageID("DOCS", title); err != nil || id != want {
+			t.Errorf("RetrievePageID(%q) = %q, %v; want %q", title, id, err, want)
+		}
+	}
+
+	_, err := client.RetrievePageID("DOCS", "Guide")
+	var ambiguous *AmbiguousPageError
+	if !errors.As(err, &ambiguous) || len(ambiguous.Candidates) != 2 || !strings.Contains(err.Error(), "User Guide (7)") {
+		t.Fatalf("expected AmbiguousPageError listing candidates, got %v", err)
+	}
+
+	_, err = client.RetrievePageID("DOCS", "Missing")
+	var notFound *PageNotFoundError
+	if !errors.As(err, &notFound) || !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected PageNotFoundError matching ErrNotFound, got %v", err)
+	}
+}
+
+func TestEscapeCQL(t *testing.T) {
+	if got, want := EscapeCQL(`say "hi" \ bye`), `say \"hi\" \\ bye`; got != want {
+		t.Fatalf("EscapeCQL() = %q, want %q", got, want)
+	}
+}
+
+func TestListSpaceBlogPostsAndAttachments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/rest/api/content" && query.Get("type") == "blogpost" && query.Get("spaceKey") == "~jdoe":
+			_, _ = w.Write([]byte(`{"results":[{"id":"10","type":"blogpost","title":"Launch","history":{"createdDate":"2024-03-01T10:00:00Z"}}],"limit":100}`))
+		case r.URL.Path == "/rest/api/content/search" && query.Get("cql") == `space = "~jdoe" and type = attachment`:
+			switch query.Get("cursor") {
+			case "":
+				_, _ = w.Write([]byte(`{"results":[{"id":"att1","title":"a.pdf","container":{"id":"10","type":"blogpost"},"_links":{"download":"/download/a.pdf"}}],"limit":1,"_links":{"next":"/rest/api/content/search?cql=space+%3D+%22~jdoe%22+and+type+%3D+attachment&limit=1&cursor=c2"}}`))
+			case "c2":
+				// Cloud ignores deep start offsets, so only the cursor reaches this page
+				_, _ = w.Write([]byte(`{"results":[{"id":"att2","title":"b.pdf","container":{"id":"10","type":"blogpost"}}],"limit":1,"_links":{"next":"https://evil.example/steal"}}`))
+			default:
+				t.Errorf("unexpected cursor %q", query.Get("cursor"))
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	client := NewClient(server.URL, "token")
+
+	posts, err := client.ListSpaceBlogPosts("~jdoe")
+	if err != nil || len(posts) != 1 || posts[0].Title != "Launch" || posts[0].CreatedAt.Year() != 2024 {
+		t.Fatalf("ListSpaceBlogPosts() = %+v, %v", posts, err)
+	}
+
+	attachments, err := client.ListSpaceAttachments("~jdoe")
+	if err != nil || len(attachments) != 2 {
+		t.Fatalf("ListSpaceAttachments() = %+v, %v", attachments, err)
+	}
+	if attachments[0].ContainerID != "10" || attachments[0].ContainerType != "blogpost" || attachments[0].DownloadLink != "/download/a.pdf" {
+		t.Fatalf("unexpected attachment: %+v", attachments[0])
+	}
+}
+
+func TestProbeAndBasicAuth(t *testing.T) {
+	var user, password string
+	var basic bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, basic = r.BasicAuth()
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "7")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Unauthorized"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, "token", WithBasicAuth("me@example.com"))
+	result, err := client.Probe("/rest/api/user/current")
+	if err != nil {
+		t.Fatalf("Probe() error = %v", err)
+	}
+	if !basic || user != "me@example.com" || password != "token" {
+		t.Fatalf("expected Basic credentials, got %q/%q (basic %v)", user, password, basic)
+	}
+	if result.StatusCode != http.StatusUnauthorized || result.RateLimit != 100 || result.RateLimitRemaining != 7 {
+		t.Fatalf("Probe() = %+v", result)
+	}
+	if string(result.Body) != `{"message":"Unauthorized"}` {
+		t.Fatalf("unexpected probe body %q", result.Body)
+	}
+
+	server.Close()
+	if _, err := client.Probe("/rest/api/user/current"); err == nil {
+		t.Fatal("expected an error when Confluence is unreachable")
+	}
+}
+
+func TestClientWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id": "1"}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := NewClient(server.URL, "token")
+	if _, err := client.WithContext(ctx).GetPage("1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the request to be cancelled, got %v", err)
+	}
+	if _, err := client.GetPage("1"); errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the original client to ignore the context, got %v", err)
+	}
+}
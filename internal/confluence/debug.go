@@ -0,0 +1,237 @@
+package confluence
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// rateLimitHeaders are logged with every traced response when present
+var rateLimitHeaders = []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"}
+
+// sensitiveQueryParams are replaced in logged URLs
+var sensitiveQueryParams = []string{"token", "access_token", "api_token", "os_password"}
+
+// WithDebugHTTP logs method, URL, status, duration and rate-limit headers of every request to w.
+// When dumpDir is set, redacted request and response dumps including bodies are written there.
+func WithDebugHTTP(w io.Writer, dumpDir string) Option {
+	return func(c *client) {
+		c.httpClient.Transport = &debugTransport{
+			next:    c.httpClient.Transport,
+			out:     w,
+			dumpDir: dumpDir,
+		}
+	}
+}
+
+// debugTransport traces requests passing through the wrapped transport
+type debugTransport struct {
+	next    http.RoundTripper
+	out     io.Writer
+	dumpDir string
+
+	mu  sync.Mutex // serializes log lines from parallel fetches
+	seq atomic.Int64
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	seq := t.seq.Add(1)
+	var dumpName string
+	if t.dumpDir != "" {
+		if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+			dumpName = t.writeDump(seq, dumpBaseName(seq, req), "request", dump)
+		}
+	}
+
+	start := time.Now()
+	resp, err := next.RoundTrip(req)
+	elapsed := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		t.logf("[http %04d] %s %s -> error: %v (%s)%s\n", seq, req.Method, redactURL(req.URL), err, elapsed, requestIDSuffix(req))
+		return resp, err
+	}
+
+	var limits []string
+	for _, header := range rateLimitHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			limits = append(limits, header+"="+value)
+		}
+	}
+	line := fmt.Sprintf("[http %04d] %s %s -> %d (%s)", seq, req.Method, redactURL(req.URL), resp.StatusCode, elapsed)
+	if len(limits) > 0 {
+		line += " " + strings.Join(limits, " ")
+	}
+	t.logf("%s%s\n", line, requestIDSuffix(req))
+
+	if dumpName != "" {
+		t.dumpResponse(seq, dumpName, resp)
+	}
+	return resp, nil
+}
+
+// dumpResponse writes the response headers now and tees the body into the dump as the caller reads it,
+// so large downloads are never buffered in memory and stay subject to the caller's size limits
+func (t *debugTransport) dumpResponse(seq int64, name string, resp *http.Response) {
+	header, err := httputil.DumpResponse(resp, false)
+	if err != nil {
+		return
+	}
+	file, _ := t.createDump(seq, name, "response")
+	if file == nil {
+		return
+	}
+	if _, err := file.Write(redactDump(header)); err != nil {
+		t.logf("[http %04d] failed to write response dump: %v\n", seq, err)
+		_ = file.Close()
+		return
+	}
+	resp.Body = &dumpBody{ReadCloser: resp.Body, file: file}
+}
+
+// dumpBody copies a response body into its dump file as it is read
+type dumpBody struct {
+	io.ReadCloser
+	file *os.File
+	err  error
+}
+
+func (b *dumpBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && b.err == nil {
+		_, b.err = b.file.Write(p[:n])
+	}
+	return n, err
+}
+
+func (b *dumpBody) Close() error {
+	_ = b.file.Close()
+	return b.ReadCloser.Close()
+}
+
+// requestIDSuffix names the request's X-Request-Id in a log line
+func requestIDSuffix(req *http.Request) string {
+	if id := req.Header.Get(requestIDHeader); id != "" {
+		return " " + requestIDHeader + "=" + id
+	}
+	return ""
+}
+
+func (t *debugTransport) logf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = fmt.Fprintf(t.out, format, args...)
+}
+
+// dumpContentIDRegex finds the page, blog post or attachment ID in a content API path
+var dumpContentIDRegex = regexp.MustCompile(`/content/(\d+)`)
+
+// dumpBaseName names a request's dumps by sequence number and, when the URL names one, the content ID
+func dumpBaseName(seq int64, req *http.Request) string {
+	name := fmt.Sprintf("%04d", seq)
+	if m := dumpContentIDRegex.FindStringSubmatch(req.URL.Path); m != nil {
+		name += "-" + m[1]
+	}
+	return name
+}
+
+// writeDump stores a redacted request dump as <name>-<kind>.txt and returns the name used
+func (t *debugTransport) writeDump(seq int64, name, kind string, dump []byte) string {
+	file, name := t.createDump(seq, name, kind)
+	if file == nil {
+		return ""
+	}
+	_, err := file.Write(redactDump(dump))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		t.logf("[http %04d] failed to write %s dump: %v\n", seq, kind, err)
+		return ""
+	}
+	return name
+}
+
+// createDump opens <name>-<kind>.txt in the dump directory and returns it with the name used.
+// Dumps never overwrite earlier ones, e.g. from a previous run or another client: the request dump takes
+// the first free name with a numeric suffix, and the response dump reuses that name.
+func (t *debugTransport) createDump(seq int64, name, kind string) (*os.File, string) {
+	if err := os.MkdirAll(t.dumpDir, 0755); err != nil {
+		t.logf("[http %04d] failed to create dump directory: %v\n", seq, err)
+		return nil, ""
+	}
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if kind == "response" {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	base := name
+	for i := 2; ; i++ {
+		file, err := os.OpenFile(filepath.Join(t.dumpDir, name+"-"+kind+".txt"), flags, 0600)
+		if os.IsExist(err) {
+			name = fmt.Sprintf("%s-%d", base, i)
+			continue
+		}
+		if err != nil {
+			t.logf("[http %04d] failed to write %s dump: %v\n", seq, kind, err)
+			return nil, ""
+		}
+		return file, name
+	}
+}
+
+// redactURL hides credentials passed as query parameters or URL user info
+func redactURL(u *url.URL) string {
+	clean := *u
+	if clean.User != nil {
+		clean.User = url.User(redacted)
+	}
+	query := clean.Query()
+	changed := false
+	for _, param := range sensitiveQueryParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		clean.RawQuery = query.Encode()
+	}
+	return clean.String()
+}
+
+// redactDump masks Authorization, Proxy-Authorization and Cookie headers in a raw HTTP dump
+func redactDump(dump []byte) []byte {
+	header, body, found := strings.Cut(string(dump), "\r\n\r\n")
+	lines := strings.Split(header, "\r\n")
+	for i, line := range lines {
+		name, _, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "authorization", "proxy-authorization", "cookie", "set-cookie":
+			lines[i] = name + ": " + redacted
+		}
+	}
+	result := strings.Join(lines, "\r\n")
+	if found {
+		result += "\r\n\r\n" + body
+	}
+	return []byte(result)
+}
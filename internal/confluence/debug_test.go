@@ -0,0 +1,155 @@
+package confluence
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDebugHTTPLogsAndRedacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		_, _ = w.Write([]byte(`{"id":"1","title":"Page","version":{"number":2}}`))
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	dumpDir := t.TempDir()
+	client := NewClient(server.URL, "secret-token", WithDebugHTTP(&log, dumpDir))
+
+	if _, err := client.GetPage("1"); err != nil {
+		t.Fatalf("GetPage returned error: %v", err)
+	}
+
+	line := log.String()
+	for _, want := range []string{"GET ", "/rest/api/content/1", "-> 200", "X-RateLimit-Remaining=42"} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log %q does not contain %q", line, want)
+		}
+	}
+
+	request, err := os.ReadFile(filepath.Join(dumpDir, "0001-1-request.txt"))
+	if err != nil {
+		t.Fatalf("failed to read request dump: %v", err)
+	}
+	if strings.Contains(string(request), "secret-token") {
+		t.Errorf("request dump leaks the API token:\n%s", request)
+	}
+	if !strings.Contains(string(request), "Authorization: "+redacted) {
+		t.Errorf("request dump does not contain redacted Authorization header:\n%s", request)
+	}
+
+	response, err := os.ReadFile(filepath.Join(dumpDir, "0001-1-response.txt"))
+	if err != nil {
+		t.Fatalf("failed to read response dump: %v", err)
+	}
+	if !strings.Contains(string(response), `"title":"Page"`) {
+		t.Errorf("response dump does not contain the body:\n%s", response)
+	}
+}
+
+func TestDebugHTTPDumpsDoNotOverwrite(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"1","title":"Page","version":{"number":2}}`))
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	dumpDir := t.TempDir()
+	for range 2 {
+		client := NewClient(server.URL, "token", WithDebugHTTP(&log, dumpDir))
+		if _, err := client.GetPage("1"); err != nil {
+			t.Fatalf("GetPage returned error: %v", err)
+		}
+	}
+
+	for _, name := range []string{"0001-1-request.txt", "0001-1-response.txt", "0001-1-2-request.txt", "0001-1-2-response.txt"} {
+		if _, err := os.Stat(filepath.Join(dumpDir, name)); err != nil {
+			t.Errorf("missing dump %s: %v", name, err)
+		}
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := http.NewRequest(http.MethodGet, "https://user:pw@example.com/wiki?token=abc&limit=5", nil)
+	got := redactURL(u.URL)
+	if strings.Contains(got, "abc") || strings.Contains(got, "pw") {
+		t.Errorf("redactURL() = %q, credentials not redacted", got)
+	}
+	if !strings.Contains(got, "limit=5") {
+		t.Errorf("redactURL() = %q, lost other query parameters", got)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestDebugHTTPStreamsResponseDump(t *testing.T) {
+	body := strings.Repeat("x", 1<<20)
+	dumpDir := t.TempDir()
+	transport := &debugTransport{
+		out:     io.Discard,
+		dumpDir: dumpDir,
+		next: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode:    http.StatusOK,
+				ProtoMajor:    1,
+				ProtoMinor:    1,
+				Header:        http.Header{"Content-Type": {"application/octet-stream"}},
+				Body:          io.NopCloser(strings.NewReader(body)),
+				ContentLength: int64(len(body)),
+				Request:       req,
+			}, nil
+		}),
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/download/attachments/1/file.bin", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip returned error: %v", err)
+	}
+	responsePath := filepath.Join(dumpDir, "0001-response.txt")
+	dump, err := os.ReadFile(responsePath)
+	if err != nil {
+		t.Fatalf("failed to read response dump: %v", err)
+	}
+	if strings.Contains(string(dump), "xxxx") {
+		t.Fatalf("response body was dumped before the caller read it")
+	}
+
+	// The dump holds only what the caller reads, so a caller's size limit bounds it too
+	if _, err := io.Copy(io.Discard, io.LimitReader(resp.Body, 1024)); err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	_ = resp.Body.Close()
+	dump, err = os.ReadFile(responsePath)
+	if err != nil {
+		t.Fatalf("failed to read response dump: %v", err)
+	}
+	if !strings.Contains(string(dump), "200 OK") || !strings.HasSuffix(string(dump), strings.Repeat("x", 1024)) {
+		t.Errorf("response dump = %.80q..., want headers followed by the body read", dump)
+	}
+	if strings.Contains(string(dump), strings.Repeat("x", 1025)) {
+		t.Errorf("response dump holds more than the caller read")
+	}
+}
+
+func TestRedactDump(t *testing.T) {
+	dump := "GET / HTTP/1.1\r\nAuthorization: Bearer a\r\nProxy-Authorization: Basic b\r\nCookie: c=d\r\nAccept: */*\r\n\r\nAuthorization: body"
+	got := string(redactDump([]byte(dump)))
+	for _, secret := range []string{"Bearer a", "Basic b", "c=d"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("redactDump() leaks %q:\n%s", secret, got)
+		}
+	}
+	if !strings.Contains(got, "Accept: */*") || !strings.HasSuffix(got, "\r\n\r\nAuthorization: body") {
+		t.Errorf("redactDump() changed other headers or the body:\n%s", got)
+	}
+}
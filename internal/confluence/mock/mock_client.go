@@ -10,8 +10,12 @@
 package mock_confluence
 
 import (
+	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
+	confluence "github.com/jackchuka/confluence-md/internal/confluence"
 	model "github.com/jackchuka/confluence-md/internal/confluence/model"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,6 +44,21 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// CreatePage mocks base method.
+func (m *MockClient) CreatePage(spaceKey, parentID, title, storage string) (*model.ConfluencePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePage", spaceKey, parentID, title, storage)
+	ret0, _ := ret[0].(*model.ConfluencePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePage indicates an expected call of CreatePage.
+func (mr *MockClientMockRecorder) CreatePage(spaceKey, parentID, title, storage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePage", reflect.TypeOf((*MockClient)(nil).CreatePage), spaceKey, parentID, title, storage)
+}
+
 // DownloadAttachmentContent mocks base method.
 func (m *MockClient) DownloadAttachmentContent(attachment *model.ConfluenceAttachment) ([]byte, error) {
 	m.ctrl.T.Helper()
@@ -55,6 +74,66 @@ func (mr *MockClientMockRecorder) DownloadAttachmentContent(attachment any) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadAttachmentContent", reflect.TypeOf((*MockClient)(nil).DownloadAttachmentContent), attachment)
 }
 
+// DownloadAttachmentTo mocks base method.
+func (m *MockClient) DownloadAttachmentTo(attachment *model.ConfluenceAttachment, w io.Writer, maxSize int64, progress confluence.ProgressFunc) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadAttachmentTo", attachment, w, maxSize, progress)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadAttachmentTo indicates an expected call of DownloadAttachmentTo.
+func (mr *MockClientMockRecorder) DownloadAttachmentTo(attachment, w, maxSize, progress any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadAttachmentTo", reflect.TypeOf((*MockClient)(nil).DownloadAttachmentTo), attachment, w, maxSize, progress)
+}
+
+// DownloadPublic mocks base method.
+func (m *MockClient) DownloadPublic(link string, w io.Writer, maxSize int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadPublic", link, w, maxSize)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadPublic indicates an expected call of DownloadPublic.
+func (mr *MockClientMockRecorder) DownloadPublic(link, w, maxSize any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadPublic", reflect.TypeOf((*MockClient)(nil).DownloadPublic), link, w, maxSize)
+}
+
+// GetAnswers mocks base method.
+func (m *MockClient) GetAnswers(questionID string) ([]model.Answer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAnswers", questionID)
+	ret0, _ := ret[0].([]model.Answer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAnswers indicates an expected call of GetAnswers.
+func (mr *MockClientMockRecorder) GetAnswers(questionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAnswers", reflect.TypeOf((*MockClient)(nil).GetAnswers), questionID)
+}
+
+// GetCalendarEvents mocks base method.
+func (m *MockClient) GetCalendarEvents(subCalendarID string, start, end time.Time) ([]model.CalendarEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarEvents", subCalendarID, start, end)
+	ret0, _ := ret[0].([]model.CalendarEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarEvents indicates an expected call of GetCalendarEvents.
+func (mr *MockClientMockRecorder) GetCalendarEvents(subCalendarID, start, end any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarEvents", reflect.TypeOf((*MockClient)(nil).GetCalendarEvents), subCalendarID, start, end)
+}
+
 // GetChildPages mocks base method.
 func (m *MockClient) GetChildPages(pageID string) ([]*model.ConfluencePage, error) {
 	m.ctrl.T.Helper()
@@ -70,6 +149,21 @@ func (mr *MockClientMockRecorder) GetChildPages(pageID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildPages", reflect.TypeOf((*MockClient)(nil).GetChildPages), pageID)
 }
 
+// GetCurrentUser mocks base method.
+func (m *MockClient) GetCurrentUser() (*model.ConfluenceUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentUser")
+	ret0, _ := ret[0].(*model.ConfluenceUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentUser indicates an expected call of GetCurrentUser.
+func (mr *MockClientMockRecorder) GetCurrentUser() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentUser", reflect.TypeOf((*MockClient)(nil).GetCurrentUser))
+}
+
 // GetPage mocks base method.
 func (m *MockClient) GetPage(pageID string) (*model.ConfluencePage, error) {
 	m.ctrl.T.Helper()
@@ -84,3 +178,302 @@ func (mr *MockClientMockRecorder) GetPage(pageID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPage", reflect.TypeOf((*MockClient)(nil).GetPage), pageID)
 }
+
+// GetPageRestrictions mocks base method.
+func (m *MockClient) GetPageRestrictions(pageID string) ([]model.PageRestriction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPageRestrictions", pageID)
+	ret0, _ := ret[0].([]model.PageRestriction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPageRestrictions indicates an expected call of GetPageRestrictions.
+func (mr *MockClientMockRecorder) GetPageRestrictions(pageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPageRestrictions", reflect.TypeOf((*MockClient)(nil).GetPageRestrictions), pageID)
+}
+
+// GetPageViews mocks base method.
+func (m *MockClient) GetPageViews(pageID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPageViews", pageID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPageViews indicates an expected call of GetPageViews.
+func (mr *MockClientMockRecorder) GetPageViews(pageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPageViews", reflect.TypeOf((*MockClient)(nil).GetPageViews), pageID)
+}
+
+// GetSpace mocks base method.
+func (m *MockClient) GetSpace(spaceKey string) (*model.ConfluenceSpace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpace", spaceKey)
+	ret0, _ := ret[0].(*model.ConfluenceSpace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpace indicates an expected call of GetSpace.
+func (mr *MockClientMockRecorder) GetSpace(spaceKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpace", reflect.TypeOf((*MockClient)(nil).GetSpace), spaceKey)
+}
+
+// GetSpaceShortcuts mocks base method.
+func (m *MockClient) GetSpaceShortcuts(spaceKey string) ([]model.SpaceShortcut, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpaceShortcuts", spaceKey)
+	ret0, _ := ret[0].([]model.SpaceShortcut)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpaceShortcuts indicates an expected call of GetSpaceShortcuts.
+func (mr *MockClientMockRecorder) GetSpaceShortcuts(spaceKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpaceShortcuts", reflect.TypeOf((*MockClient)(nil).GetSpaceShortcuts), spaceKey)
+}
+
+// GetUser mocks base method.
+func (m *MockClient) GetUser(accountID string) (*model.ConfluenceUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", accountID)
+	ret0, _ := ret[0].(*model.ConfluenceUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockClientMockRecorder) GetUser(accountID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockClient)(nil).GetUser), accountID)
+}
+
+// GetWorkflowStatus mocks base method.
+func (m *MockClient) GetWorkflowStatus(pageID string) (*model.WorkflowStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkflowStatus", pageID)
+	ret0, _ := ret[0].(*model.WorkflowStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkflowStatus indicates an expected call of GetWorkflowStatus.
+func (mr *MockClientMockRecorder) GetWorkflowStatus(pageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkflowStatus", reflect.TypeOf((*MockClient)(nil).GetWorkflowStatus), pageID)
+}
+
+// ListQuestions mocks base method.
+func (m *MockClient) ListQuestions(spaceKey string) ([]model.Question, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuestions", spaceKey)
+	ret0, _ := ret[0].([]model.Question)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuestions indicates an expected call of ListQuestions.
+func (mr *MockClientMockRecorder) ListQuestions(spaceKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuestions", reflect.TypeOf((*MockClient)(nil).ListQuestions), spaceKey)
+}
+
+// ListSpaceAttachments mocks base method.
+func (m *MockClient) ListSpaceAttachments(spaceKey string) ([]model.ConfluenceAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpaceAttachments", spaceKey)
+	ret0, _ := ret[0].([]model.ConfluenceAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpaceAttachments indicates an expected call of ListSpaceAttachments.
+func (mr *MockClientMockRecorder) ListSpaceAttachments(spaceKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpaceAttachments", reflect.TypeOf((*MockClient)(nil).ListSpaceAttachments), spaceKey)
+}
+
+// ListSpaceBlogPosts mocks base method.
+func (m *MockClient) ListSpaceBlogPosts(spaceKey string) ([]*model.ConfluencePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpaceBlogPosts", spaceKey)
+	ret0, _ := ret[0].([]*model.ConfluencePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpaceBlogPosts indicates an expected call of ListSpaceBlogPosts.
+func (mr *MockClientMockRecorder) ListSpaceBlogPosts(spaceKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpaceBlogPosts", reflect.TypeOf((*MockClient)(nil).ListSpaceBlogPosts), spaceKey)
+}
+
+// ListSpacePages mocks base method.
+func (m *MockClient) ListSpacePages(spaceKey string) ([]*model.ConfluencePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpacePages", spaceKey)
+	ret0, _ := ret[0].([]*model.ConfluencePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpacePages indicates an expected call of ListSpacePages.
+func (mr *MockClientMockRecorder) ListSpacePages(spaceKey any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpacePages", reflect.TypeOf((*MockClient)(nil).ListSpacePages), spaceKey)
+}
+
+// ListSpaces mocks base method.
+func (m *MockClient) ListSpaces(spaceType string) ([]model.ConfluenceSpace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpaces", spaceType)
+	ret0, _ := ret[0].([]model.ConfluenceSpace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpaces indicates an expected call of ListSpaces.
+func (mr *MockClientMockRecorder) ListSpaces(spaceType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpaces", reflect.TypeOf((*MockClient)(nil).ListSpaces), spaceType)
+}
+
+// ListTemplates mocks base method.
+func (m *MockClient) ListTemplates(spaceKey, templateType string) ([]model.PageTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTemplates", spaceKey, templateType)
+	ret0, _ := ret[0].([]model.PageTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTemplates indicates an expected call of ListTemplates.
+func (mr *MockClientMockRecorder) ListTemplates(spaceKey, templateType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTemplates", reflect.TypeOf((*MockClient)(nil).ListTemplates), spaceKey, templateType)
+}
+
+// OpenAttachment mocks base method.
+func (m *MockClient) OpenAttachment(attachment *model.ConfluenceAttachment) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenAttachment", attachment)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenAttachment indicates an expected call of OpenAttachment.
+func (mr *MockClientMockRecorder) OpenAttachment(attachment any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenAttachment", reflect.TypeOf((*MockClient)(nil).OpenAttachment), attachment)
+}
+
+// Probe mocks base method.
+func (m *MockClient) Probe(path string) (*confluence.ProbeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Probe", path)
+	ret0, _ := ret[0].(*confluence.ProbeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Probe indicates an expected call of Probe.
+func (mr *MockClientMockRecorder) Probe(path any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Probe", reflect.TypeOf((*MockClient)(nil).Probe), path)
+}
+
+// RetrievePageID mocks base method.
+func (m *MockClient) RetrievePageID(spaceKey, pageName string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetrievePageID", spaceKey, pageName)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetrievePageID indicates an expected call of RetrievePageID.
+func (mr *MockClientMockRecorder) RetrievePageID(spaceKey, pageName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetrievePageID", reflect.TypeOf((*MockClient)(nil).RetrievePageID), spaceKey, pageName)
+}
+
+// SearchContent mocks base method.
+func (m *MockClient) SearchContent(cql string, limit int) ([]*model.ConfluencePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchContent", cql, limit)
+	ret0, _ := ret[0].([]*model.ConfluencePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchContent indicates an expected call of SearchContent.
+func (mr *MockClientMockRecorder) SearchContent(cql, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchContent", reflect.TypeOf((*MockClient)(nil).SearchContent), cql, limit)
+}
+
+// UpdateAttachment mocks base method.
+func (m *MockClient) UpdateAttachment(pageID, attachmentID, fileName string, data []byte) (*model.ConfluenceAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAttachment", pageID, attachmentID, fileName, data)
+	ret0, _ := ret[0].(*model.ConfluenceAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAttachment indicates an expected call of UpdateAttachment.
+func (mr *MockClientMockRecorder) UpdateAttachment(pageID, attachmentID, fileName, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAttachment", reflect.TypeOf((*MockClient)(nil).UpdateAttachment), pageID, attachmentID, fileName, data)
+}
+
+// UpdatePage mocks base method.
+func (m *MockClient) UpdatePage(pageID, title string, version int, storage string) (*model.ConfluencePage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePage", pageID, title, version, storage)
+	ret0, _ := ret[0].(*model.ConfluencePage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePage indicates an expected call of UpdatePage.
+func (mr *MockClientMockRecorder) UpdatePage(pageID, title, version, storage any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePage", reflect.TypeOf((*MockClient)(nil).UpdatePage), pageID, title, version, storage)
+}
+
+// UploadAttachment mocks base method.
+func (m *MockClient) UploadAttachment(pageID, fileName string, data []byte) (*model.ConfluenceAttachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadAttachment", pageID, fileName, data)
+	ret0, _ := ret[0].(*model.ConfluenceAttachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadAttachment indicates an expected call of UploadAttachment.
+func (mr *MockClientMockRecorder) UploadAttachment(pageID, fileName, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadAttachment", reflect.TypeOf((*MockClient)(nil).UploadAttachment), pageID, fileName, data)
+}
+
+// WithContext mocks base method.
+func (m *MockClient) WithContext(ctx context.Context) confluence.Client {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithContext", ctx)
+	ret0, _ := ret[0].(confluence.Client)
+	return ret0
+}
+
+// WithContext indicates an expected call of WithContext.
+func (mr *MockClientMockRecorder) WithContext(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithContext", reflect.TypeOf((*MockClient)(nil).WithContext), ctx)
+}
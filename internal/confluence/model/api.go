@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -50,22 +51,347 @@ type ConfluenceAPIPage struct {
 	} `json:"metadata"`
 	Children struct {
 		Attachment struct {
-			Results []struct {
-				ID      string `json:"id"`
-				Title   string `json:"title"`
-				Version struct {
-					Number int `json:"number"`
-				} `json:"version"`
-				Extensions struct {
-					MediaType string `json:"mediaType"`
-					FileSize  int64  `json:"fileSize"`
-				} `json:"extensions"`
-				Links struct {
-					Download string `json:"download"`
-				} `json:"_links"`
-			} `json:"results"`
+			Results []ConfluenceAPIAttachment `json:"results"`
 		} `json:"attachment"`
 	} `json:"children"`
+	Ancestors []struct {
+		ID string `json:"id"`
+	} `json:"ancestors"`
+}
+
+// ConfluenceAPIAttachment represents the API response structure for an attachment
+type ConfluenceAPIAttachment struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Extensions struct {
+		MediaType string `json:"mediaType"`
+		FileSize  int64  `json:"fileSize"`
+	} `json:"extensions"`
+	Links struct {
+		Download string `json:"download"`
+	} `json:"_links"`
+	Container struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	} `json:"container"`
+}
+
+// ConfluenceAttachmentResult represents the API response for attachment uploads and searches
+type ConfluenceAttachmentResult struct {
+	Results []ConfluenceAPIAttachment `json:"results"`
+	Limit   int                       `json:"limit"`
+	Links   ResultLinks               `json:"_links"`
+}
+
+// ResultLinks holds the link to the next page of a paginated listing, empty on the last page
+type ResultLinks struct {
+	Next string `json:"next"`
+}
+
+// PageUpdateRequest is the request body for updating a page's title and storage content
+type PageUpdateRequest struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Title   string `json:"title"`
+	Version struct {
+		Number int `json:"number"`
+	} `json:"version"`
+	Body struct {
+		Storage struct {
+			Value          string `json:"value"`
+			Representation string `json:"representation"`
+		} `json:"storage"`
+	} `json:"body"`
+}
+
+// PageCreateRequest is the request body for creating a page under a parent page
+type PageCreateRequest struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	Space struct {
+		Key string `json:"key"`
+	} `json:"space"`
+	Ancestors []PageAncestor `json:"ancestors,omitempty"`
+	Body      struct {
+		Storage struct {
+			Value          string `json:"value"`
+			Representation string `json:"representation"`
+		} `json:"storage"`
+	} `json:"body"`
+}
+
+// PageAncestor references a parent page in a create request
+type PageAncestor struct {
+	ID string `json:"id"`
+}
+
+// ComalaStatusResponse represents the Comala Document Management page status response
+type ComalaStatusResponse struct {
+	WorkflowName string `json:"workflowName"`
+	State        struct {
+		Name string `json:"name"`
+	} `json:"state"`
+	Approvals []struct {
+		Name      string `json:"name"`
+		Approvers []struct {
+			User struct {
+				Name        string `json:"name"`
+				DisplayName string `json:"displayName"`
+			} `json:"user"`
+			Approved bool      `json:"approved"`
+			Date     time.Time `json:"date"`
+		} `json:"approvers"`
+	} `json:"approvals"`
+}
+
+// ConvertComalaStatusToModel flattens a Comala status response into the approvers of the current state
+func ConvertComalaStatusToModel(resp *ComalaStatusResponse) *WorkflowStatus {
+	status := &WorkflowStatus{Workflow: resp.WorkflowName, State: resp.State.Name}
+	for _, approval := range resp.Approvals {
+		for _, approver := range approval.Approvers {
+			if !approver.Approved {
+				continue
+			}
+			name := approver.User.DisplayName
+			if name == "" {
+				name = approver.User.Name
+			}
+			status.Approvers = append(status.Approvers, name)
+			if approver.Date.After(status.ApprovedAt) {
+				status.ApprovedAt = approver.Date
+			}
+		}
+	}
+	return status
+}
+
+// ConfluenceSpaceListResult represents the API response for listing spaces
+type ConfluenceSpaceListResult struct {
+	Results []struct {
+		Key      string `json:"key"`
+		Name     string `json:"name"`
+		Type     string `json:"type"`
+		Homepage struct {
+			ID string `json:"id"`
+		} `json:"homepage"`
+	} `json:"results"`
+	Start int `json:"start"`
+	Limit int `json:"limit"`
+	Size  int `json:"size"`
+}
+
+// ConfluenceSpaceResult represents the API response for a single space with description, labels and permissions expanded
+type ConfluenceSpaceResult struct {
+	Key      string `json:"key"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Homepage struct {
+		ID string `json:"id"`
+	} `json:"homepage"`
+	Description struct {
+		Plain struct {
+			Value string `json:"value"`
+		} `json:"plain"`
+	} `json:"description"`
+	Metadata struct {
+		Labels struct {
+			Results []struct {
+				Prefix string `json:"prefix"`
+				Name   string `json:"name"`
+			} `json:"results"`
+		} `json:"labels"`
+	} `json:"metadata"`
+	Permissions []struct {
+		Operation struct {
+			Operation  string `json:"operation"`
+			TargetType string `json:"targetType"`
+		} `json:"operation"`
+		Subjects struct {
+			User struct {
+				Results []struct {
+					DisplayName string `json:"displayName"`
+					Username    string `json:"username"`
+				} `json:"results"`
+			} `json:"user"`
+			Group struct {
+				Results []struct {
+					Name string `json:"name"`
+				} `json:"results"`
+			} `json:"group"`
+		} `json:"subjects"`
+	} `json:"permissions"`
+}
+
+// ToConfluenceSpace converts the API response to the space model, sorting space categories out of the labels
+func (r *ConfluenceSpaceResult) ToConfluenceSpace() ConfluenceSpace {
+	space := ConfluenceSpace{
+		Key:         r.Key,
+		Name:        r.Name,
+		Type:        r.Type,
+		HomepageID:  r.Homepage.ID,
+		Description: r.Description.Plain.Value,
+	}
+	for _, label := range r.Metadata.Labels.Results {
+		if label.Prefix == "team" {
+			space.Categories = append(space.Categories, label.Name)
+		} else {
+			space.Labels = append(space.Labels, label.Name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, permission := range r.Permissions {
+		if permission.Operation.Operation != "administer" || permission.Operation.TargetType != "space" {
+			continue
+		}
+		var names []string
+		for _, user := range permission.Subjects.User.Results {
+			name := user.DisplayName
+			if name == "" {
+				name = user.Username
+			}
+			names = append(names, name)
+		}
+		for _, group := range permission.Subjects.Group.Results {
+			names = append(names, group.Name)
+		}
+		for _, name := range names {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				space.Admins = append(space.Admins, name)
+			}
+		}
+	}
+	return space
+}
+
+// QuestionsUser is the author of a Confluence Questions question or answer
+type QuestionsUser struct {
+	Name     string `json:"name"`
+	FullName string `json:"fullName"`
+}
+
+// DisplayName returns the full name, falling back to the user name
+func (u QuestionsUser) DisplayName() string {
+	if u.FullName != "" {
+		return u.FullName
+	}
+	return u.Name
+}
+
+// QuestionsBody is the storage format body of a question or answer
+type QuestionsBody struct {
+	Content string `json:"content"`
+}
+
+// ConfluenceQuestionResult represents a question in the Questions REST API; dates are epoch milliseconds
+type ConfluenceQuestionResult struct {
+	ID               json.Number   `json:"id"`
+	Title            string        `json:"title"`
+	Body             QuestionsBody `json:"body"`
+	Author           QuestionsUser `json:"author"`
+	DateAsked        int64         `json:"dateAsked"`
+	VoteScore        int           `json:"voteScore"`
+	AnswersCount     int           `json:"answersCount"`
+	AcceptedAnswerID json.Number   `json:"acceptedAnswerId"`
+	Topics           []struct {
+		Name string `json:"name"`
+	} `json:"topics"`
+}
+
+// ToQuestion converts the API response to the question model
+func (r *ConfluenceQuestionResult) ToQuestion() Question {
+	question := Question{
+		ID:               r.ID.String(),
+		Title:            r.Title,
+		Body:             r.Body.Content,
+		Author:           r.Author.DisplayName(),
+		AskedAt:          time.UnixMilli(r.DateAsked).UTC(),
+		Votes:            r.VoteScore,
+		AnswerCount:      r.AnswersCount,
+		AcceptedAnswerID: r.AcceptedAnswerID.String(),
+	}
+	if question.AcceptedAnswerID == "0" {
+		question.AcceptedAnswerID = ""
+	}
+	for _, topic := range r.Topics {
+		question.Topics = append(question.Topics, topic.Name)
+	}
+	return question
+}
+
+// ConfluenceAnswerResult represents an answer in the Questions REST API; dates are epoch milliseconds
+type ConfluenceAnswerResult struct {
+	ID           json.Number   `json:"id"`
+	Body         QuestionsBody `json:"body"`
+	Author       QuestionsUser `json:"author"`
+	DateAnswered int64         `json:"dateAnswered"`
+	VoteScore    int           `json:"voteScore"`
+	Accepted     bool          `json:"accepted"`
+}
+
+// ToAnswer converts the API response to the answer model
+func (r *ConfluenceAnswerResult) ToAnswer() Answer {
+	return Answer{
+		ID:         r.ID.String(),
+		Body:       r.Body.Content,
+		Author:     r.Author.DisplayName(),
+		AnsweredAt: time.UnixMilli(r.DateAnswered).UTC(),
+		Votes:      r.VoteScore,
+		Accepted:   r.Accepted,
+	}
+}
+
+// ConfluenceViewsResult represents the Analytics API response for the views of a page
+type ConfluenceViewsResult struct {
+	ID    int64 `json:"id"`
+	Count int   `json:"count"`
+}
+
+// ConfluenceTemplateListResult represents the API response for listing page or blueprint templates
+type ConfluenceTemplateListResult struct {
+	Results []struct {
+		TemplateID   string `json:"templateId"`
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		TemplateType string `json:"templateType"`
+		Labels       []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+		Space *struct {
+			Key string `json:"key"`
+		} `json:"space"`
+		Body struct {
+			Storage struct {
+				Value string `json:"value"`
+			} `json:"storage"`
+		} `json:"body"`
+	} `json:"results"`
+	Start int `json:"start"`
+	Limit int `json:"limit"`
+	Size  int `json:"size"`
+}
+
+// ConfluenceRestrictionsResponse represents the restriction/byOperation API response, keyed by operation
+type ConfluenceRestrictionsResponse map[string]struct {
+	Operation    string `json:"operation"`
+	Restrictions struct {
+		User struct {
+			Results []struct {
+				AccountID   string `json:"accountId"`
+				Username    string `json:"username"`
+				DisplayName string `json:"displayName"`
+			} `json:"results"`
+		} `json:"user"`
+		Group struct {
+			Results []struct {
+				Name string `json:"name"`
+			} `json:"results"`
+		} `json:"group"`
+	} `json:"restrictions"`
 }
 
 // ConfluenceSearchResult represents the API response for search queries
@@ -74,6 +400,7 @@ type ConfluenceSearchResult struct {
 	Start   int                 `json:"start"`
 	Limit   int                 `json:"limit"`
 	Size    int                 `json:"size"`
+	Links   ResultLinks         `json:"_links"`
 }
 
 // ConfluenceErrorResponse represents an error response from the API
@@ -93,6 +420,18 @@ type ConfluenceUser struct {
 	DisplayName string `json:"displayName"`
 }
 
+// IsAnonymized reports whether the account was deleted or anonymized and no longer carries a usable name
+func (u *ConfluenceUser) IsAnonymized() bool {
+	if u.AccountType == "unknown" {
+		return true
+	}
+	switch u.DisplayName {
+	case "", "Former user", "Unknown user":
+		return u.PublicName == "" || u.PublicName == u.DisplayName
+	}
+	return false
+}
+
 // ConvertAPIPageToModel converts the API response to our domain model
 func ConvertAPIPageToModel(apiPage *ConfluenceAPIPage) *ConfluencePage {
 	// Convert labels
@@ -105,15 +444,13 @@ func ConvertAPIPageToModel(apiPage *ConfluenceAPIPage) *ConfluencePage {
 	}
 
 	var attachments []ConfluenceAttachment
-	for _, att := range apiPage.Children.Attachment.Results {
-		attachments = append(attachments, ConfluenceAttachment{
-			ID:           att.ID,
-			Title:        att.Title,
-			MediaType:    att.Extensions.MediaType,
-			FileSize:     att.Extensions.FileSize,
-			DownloadLink: att.Links.Download,
-			Version:      att.Version.Number,
-		})
+	for i := range apiPage.Children.Attachment.Results {
+		attachments = append(attachments, ConvertAPIAttachmentToModel(&apiPage.Children.Attachment.Results[i]))
+	}
+
+	var ancestorIDs []string
+	for _, ancestor := range apiPage.Ancestors {
+		ancestorIDs = append(ancestorIDs, ancestor.ID)
 	}
 
 	return &ConfluencePage{
@@ -132,6 +469,7 @@ func ConvertAPIPageToModel(apiPage *ConfluenceAPIPage) *ConfluencePage {
 			Properties: make(map[string]string), // TODO: Extract properties if needed
 		},
 		Attachments: attachments,
+		AncestorIDs: ancestorIDs,
 		CreatedAt:   apiPage.History.CreatedDate,
 		UpdatedAt:   apiPage.Version.When,
 		CreatedBy: User{
@@ -146,3 +484,47 @@ func ConvertAPIPageToModel(apiPage *ConfluenceAPIPage) *ConfluencePage {
 		},
 	}
 }
+
+// ConvertAPIAttachmentToModel converts an attachment API response to our domain model
+func ConvertAPIAttachmentToModel(att *ConfluenceAPIAttachment) ConfluenceAttachment {
+	return ConfluenceAttachment{
+		ID:           att.ID,
+		Title:        att.Title,
+		MediaType:    att.Extensions.MediaType,
+		FileSize:     att.Extensions.FileSize,
+		DownloadLink: att.Links.Download,
+		Version:      att.Version.Number,
+
+		ContainerID:   att.Container.ID,
+		ContainerType: att.Container.Type,
+	}
+}
+
+// CalendarEventsResponse represents the Team Calendars events API response
+type CalendarEventsResponse struct {
+	Success bool            `json:"success"`
+	Events  []CalendarEvent `json:"events"`
+}
+
+// CalendarEvent represents a single Team Calendars event
+type CalendarEvent struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	AllDay   bool   `json:"allDay"`
+	Location string `json:"where"`
+}
+
+// SpaceSidebarResponse represents the space sidebar (information architecture) API response
+type SpaceSidebarResponse struct {
+	SpaceKey   string          `json:"spaceKey"`
+	QuickLinks []SpaceShortcut `json:"quickLinks"`
+}
+
+// SpaceShortcut represents a quick link configured by space admins in the sidebar
+type SpaceShortcut struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
@@ -19,6 +19,70 @@ type ConfluencePage struct {
 	UpdatedAt   time.Time              `json:"updatedAt"`
 	CreatedBy   User                   `json:"createdBy"`
 	UpdatedBy   User                   `json:"updatedBy"`
+	AncestorIDs []string               `json:"ancestorIds,omitempty"` // root first, only set when ancestors are expanded
+}
+
+// ConfluenceSpace represents a space readable by the API token.
+// Description, labels, categories and admins are only set by GetSpace.
+type ConfluenceSpace struct {
+	Key         string   `json:"key"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"` // "global" or "personal"
+	HomepageID  string   `json:"homepageId"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	Categories  []string `json:"categories,omitempty"` // space categories, stored as labels with the "team" prefix
+	Admins      []string `json:"admins,omitempty"`     // users and groups with the space administer permission
+}
+
+// PageTemplate is a space or global page template, or a blueprint template, with its storage format body
+type PageTemplate struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Type        string   `json:"type"`               // "page" or "blueprint"
+	SpaceKey    string   `json:"spaceKey,omitempty"` // empty for global templates
+	Labels      []string `json:"labels,omitempty"`
+	Body        string   `json:"body"`
+}
+
+// Question is a question asked with the Confluence Questions app
+type Question struct {
+	ID               string    `json:"id"`
+	Title            string    `json:"title"`
+	Body             string    `json:"body"` // storage format
+	Author           string    `json:"author"`
+	AskedAt          time.Time `json:"askedAt"`
+	Votes            int       `json:"votes"`
+	AnswerCount      int       `json:"answerCount"`
+	AcceptedAnswerID string    `json:"acceptedAnswerId,omitempty"`
+	Topics           []string  `json:"topics,omitempty"`
+}
+
+// Answer is an answer to a Confluence Questions question
+type Answer struct {
+	ID         string    `json:"id"`
+	Body       string    `json:"body"` // storage format
+	Author     string    `json:"author"`
+	AnsweredAt time.Time `json:"answeredAt"`
+	Votes      int       `json:"votes"`
+	Accepted   bool      `json:"accepted"`
+}
+
+// PageRestriction grants one user or group an operation ("read" or "update") on a restricted page
+type PageRestriction struct {
+	Operation     string `json:"operation"`
+	PrincipalType string `json:"principalType"` // "user" or "group"
+	Principal     string `json:"principal"`     // display name or group name
+	AccountID     string `json:"accountId,omitempty"`
+}
+
+// WorkflowStatus is the Comala Document Management workflow state of a page
+type WorkflowStatus struct {
+	Workflow   string    `json:"workflow"`
+	State      string    `json:"state"` // e.g. "Draft" or "Approved"
+	Approvers  []string  `json:"approvers,omitempty"`
+	ApprovedAt time.Time `json:"approvedAt,omitempty"` // most recent approval of the current state
 }
 
 // ConfluenceContent represents the content structure from Confluence
@@ -52,6 +116,9 @@ type ConfluenceAttachment struct {
 	FileSize     int64  `json:"fileSize"`
 	DownloadLink string `json:"downloadLink"`
 	Version      int    `json:"version"`
+
+	ContainerID   string `json:"containerId,omitempty"`   // page or blog post the attachment belongs to, when listed by space
+	ContainerType string `json:"containerType,omitempty"` // "page" or "blogpost"
 }
 
 // User represents a Confluence user
@@ -0,0 +1,54 @@
+package confluence
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// probeBodyLimit is how much of a probe response body is kept for diagnostics
+const probeBodyLimit = 2048
+
+// ProbeResult is the raw response to a diagnostic request
+type ProbeResult struct {
+	StatusCode         int
+	Duration           time.Duration
+	Header             http.Header
+	Body               []byte // start of the response body, at most probeBodyLimit bytes
+	RateLimit          int    // X-RateLimit-Limit, -1 when not reported
+	RateLimitRemaining int    // X-RateLimit-Remaining, -1 when not reported
+}
+
+// Probe sends an authenticated GET to path below the base URL and reports the response without
+// interpreting it, for diagnostics. Only failures to reach Confluence are returned as errors.
+func (c *client) Probe(path string) (*ProbeResult, error) {
+	start := time.Now()
+	resp, err := c.makeRequest("GET", c.baseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", c.baseURL, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, probeBodyLimit))
+	return &ProbeResult{
+		StatusCode:         resp.StatusCode,
+		Duration:           time.Since(start),
+		Header:             resp.Header,
+		Body:               body,
+		RateLimit:          headerInt(resp.Header, "X-RateLimit-Limit"),
+		RateLimitRemaining: headerInt(resp.Header, "X-RateLimit-Remaining"),
+	}, nil
+}
+
+// headerInt parses a numeric header, returning -1 when it is missing or not a number
+func headerInt(header http.Header, name string) int {
+	value, err := strconv.Atoi(header.Get(name))
+	if err != nil {
+		return -1
+	}
+	return value
+}
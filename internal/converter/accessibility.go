@@ -0,0 +1,101 @@
+package converter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+var (
+	a11yImageRegex     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	a11yHTMLImageRegex = regexp.MustCompile(`<img\s[^>]*>`)
+	a11yHTMLAltRegex   = regexp.MustCompile(`\balt="[^"]*\S[^"]*"`)
+	a11yEmptyLinkRegex = regexp.MustCompile(`(^|[^!\]])\[\s*\]\(([^)\s]+)\)`)
+	a11yHeadingRegex   = regexp.MustCompile(`^(#{1,6})(\s+.*)$`)
+)
+
+// WithAccessibilityCheck reports images without alt text, skipped heading levels and links without text.
+// With fix set, the issues are also repaired: alt text is derived from the file name, headings are
+// raised to the next level and empty links show their URL.
+func WithAccessibilityCheck(fix bool) Option {
+	return func(c *Converter) {
+		c.accessibilityCheck = true
+		c.accessibilityFix = fix
+	}
+}
+
+// checkAccessibility returns the markdown, repaired when fix is set, and the issues found outside code blocks
+func checkAccessibility(markdown string, fix bool) (string, []model.AccessibilityIssue) {
+	var issues []model.AccessibilityIssue
+	report := func(kind string, line int, format string, args ...any) {
+		issues = append(issues, model.AccessibilityIssue{Kind: kind, Line: line, Message: fmt.Sprintf(format, args...), Fixed: fix})
+	}
+
+	lines := strings.Split(markdown, "\n")
+	inFence := false
+	previousLevel := 0
+	for i, line := range lines {
+		lineNumber := i + 1
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		if match := a11yHeadingRegex.FindStringSubmatch(line); match != nil {
+			level := len(match[1])
+			if previousLevel > 0 && level > previousLevel+1 {
+				report("heading-order", lineNumber, "heading level jumps from H%d to H%d", previousLevel, level)
+				if fix {
+					level = previousLevel + 1
+					line = strings.Repeat("#", level) + match[2]
+				}
+			}
+			previousLevel = level
+		}
+
+		line = a11yImageRegex.ReplaceAllStringFunc(line, func(image string) string {
+			match := a11yImageRegex.FindStringSubmatch(image)
+			alt, src := strings.TrimSpace(match[1]), match[2]
+			fileName := path.Base(src)
+			if alt != "" && alt != fileName {
+				return image
+			}
+			report("image-alt", lineNumber, "image %s has no alt text", fileName)
+			if !fix {
+				return image
+			}
+			return fmt.Sprintf("![%s](%s)", altFromFileName(fileName), src)
+		})
+
+		for _, image := range a11yHTMLImageRegex.FindAllString(line, -1) {
+			if !a11yHTMLAltRegex.MatchString(image) {
+				issues = append(issues, model.AccessibilityIssue{Kind: "image-alt", Line: lineNumber, Message: fmt.Sprintf("HTML image %s has no alt text", image)})
+			}
+		}
+
+		line = a11yEmptyLinkRegex.ReplaceAllStringFunc(line, func(link string) string {
+			match := a11yEmptyLinkRegex.FindStringSubmatch(link)
+			report("empty-link", lineNumber, "link to %s has no text", match[2])
+			if !fix {
+				return link
+			}
+			return fmt.Sprintf("%s[%s](%s)", match[1], match[2], match[2])
+		})
+
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n"), issues
+}
+
+// altFromFileName turns an image file name such as release-flow_v2.png into "release flow v2"
+func altFromFileName(fileName string) string {
+	name := strings.TrimSuffix(fileName, path.Ext(fileName))
+	name = strings.NewReplacer("-", " ", "_", " ", "+", " ", "%20", " ").Replace(name)
+	return strings.Join(strings.Fields(name), " ")
+}
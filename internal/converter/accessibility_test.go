@@ -0,0 +1,40 @@
+package converter
+
+import "testing"
+
+func TestCheckAccessibility(t *testing.T) {
+	input := "## Setup\n\n![](assets/release-flow_v2.png)\n\n#### Details\n\nSee [](https://example.com/docs) and ![Flow chart](assets/flow.png)\n\n```\n#### not a heading []( x)\n```\n\n<img src=\"assets/x.png\">"
+
+	got, issues := checkAccessibility(input, false)
+	if got != input {
+		t.Fatalf("expected check-only mode to leave the content unchanged, got %q", got)
+	}
+	wantKinds := []string{"image-alt", "heading-order", "empty-link", "image-alt"}
+	if len(issues) != len(wantKinds) {
+		t.Fatalf("got %d issues, want %d: %#v", len(issues), len(wantKinds), issues)
+	}
+	for i, kind := range wantKinds {
+		if issues[i].Kind != kind {
+			t.Fatalf("issue %d kind = %q, want %q", i, issues[i].Kind, kind)
+		}
+	}
+	if issues[1].Line != 5 {
+		t.Fatalf("heading issue line = %d, want 5", issues[1].Line)
+	}
+
+	fixed, issues := checkAccessibility(input, true)
+	want := "## Setup\n\n![release flow v2](assets/release-flow_v2.png)\n\n### Details\n\nSee [https://example.com/docs](https://example.com/docs) and ![Flow chart](assets/flow.png)\n\n```\n#### not a heading []( x)\n```\n\n<img src=\"assets/x.png\">"
+	if fixed != want {
+		t.Fatalf("fixed content = %q, want %q", fixed, want)
+	}
+	if !issues[0].Fixed || issues[3].Fixed {
+		t.Fatalf("expected Markdown issues to be fixed and HTML images only reported, got %#v", issues)
+	}
+}
+
+func TestCheckAccessibilityFileNameAlt(t *testing.T) {
+	_, issues := checkAccessibility("![diagram.png](assets/diagram.png)", false)
+	if len(issues) != 1 || issues[0].Kind != "image-alt" {
+		t.Fatalf("expected file name alt text to be reported, got %#v", issues)
+	}
+}
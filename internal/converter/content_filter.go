@@ -0,0 +1,111 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+)
+
+// ErrExcludedPage is returned by ConvertPage for pages carrying one of the content filter's labels
+var ErrExcludedPage = errors.New("page is excluded from export")
+
+// ContentFilter removes content marked as internal so it does not leak into public exports
+type ContentFilter struct {
+	Labels  []string // pages with one of these labels are not exported
+	Macros  []string // macros removed together with their body
+	Markers []string // sections whose heading starts with one of these markers are removed
+}
+
+// DefaultContentFilter is the convention enabled by --strip-marked: the exclude-from-export label,
+// noprint macros, and sections whose heading starts with [DRAFT] or [WIP]
+var DefaultContentFilter = ContentFilter{
+	Labels:  []string{"exclude-from-export"},
+	Macros:  []string{"noprint"},
+	Markers: []string{"[DRAFT]", "[WIP]", "DRAFT:", "WIP:"},
+}
+
+// WithContentFilter skips excluded pages and strips marked macros and sections from the output
+func WithContentFilter(filter ContentFilter) Option {
+	return func(c *Converter) {
+		c.contentFilter = &filter
+		c.pluginOptions = append(c.pluginOptions, plugin.WithExcludedMacros(filter.Macros))
+	}
+}
+
+// excludedLabel returns the page label that excludes the page from export, or ""
+func (f *ContentFilter) excludedLabel(page *confluenceModel.ConfluencePage) string {
+	for _, name := range page.GetLabelNames() {
+		for _, label := range f.Labels {
+			if strings.EqualFold(name, label) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// checkExcluded returns ErrExcludedPage when the filter excludes the page
+func (c *Converter) checkExcluded(page *confluenceModel.ConfluencePage) error {
+	if c.contentFilter == nil {
+		return nil
+	}
+	if label := c.contentFilter.excludedLabel(page); label != "" {
+		return fmt.Errorf("%w: labeled %s", ErrExcludedPage, label)
+	}
+	return nil
+}
+
+// stripMarkedSections removes every section whose heading starts with a marker, up to the next heading
+// of the same or a higher level. Headings inside fenced code blocks are ignored.
+func stripMarkedSections(markdown string, markers []string) string {
+	if len(markers) == 0 {
+		return markdown
+	}
+
+	var kept []string
+	inFence := false
+	stripLevel := 0 // level of the marked heading being stripped, 0 when keeping lines
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		} else if level, text := headingLevel(line); !inFence && level > 0 {
+			if stripLevel > 0 && level <= stripLevel {
+				stripLevel = 0
+			}
+			if stripLevel == 0 && hasMarker(text, markers) {
+				stripLevel = level
+			}
+		}
+
+		if stripLevel == 0 {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// headingLevel returns the level and text of an ATX heading line, or 0 when the line is no heading
+func headingLevel(line string) (int, string) {
+	level := 0
+	for level < len(line) && level < 6 && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level == len(line) || line[level] != ' ' {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(line[level:])
+}
+
+// hasMarker reports whether the heading text starts with one of the markers, ignoring case
+func hasMarker(text string, markers []string) bool {
+	text = strings.ToUpper(text)
+	for _, marker := range markers {
+		if marker = strings.ToUpper(strings.TrimSpace(marker)); marker != "" && strings.HasPrefix(text, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package converter
+
+import (
+	"errors"
+	"testing"
+
+	confModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestStripMarkedSections(t *testing.T) {
+	input := "# Guide\n\nIntro\n\n## [DRAFT] Pricing\n\nSecret\n\n### Tiers\n\nMore secret\n\n## Usage\n\n```\n## WIP: not a heading\n```\n\n## wip: Roadmap\n\nLater"
+	want := "# Guide\n\nIntro\n\n## Usage\n\n```\n## WIP: not a heading\n```\n"
+	if got := stripMarkedSections(input, DefaultContentFilter.Markers); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestConvertPageExcludedLabel(t *testing.T) {
+	conv := NewConverter(nil, WithContentFilter(DefaultContentFilter))
+	page := &confModel.ConfluencePage{
+		ID:       "1",
+		Title:    "Internal",
+		SpaceKey: "DOCS",
+		Version:  1,
+		Content:  confModel.ConfluenceContent{Storage: confModel.ContentStorage{Value: "<p>x</p>"}},
+		Metadata: confModel.ConfluenceMetadata{Labels: []confModel.Label{{Name: "Exclude-From-Export"}}},
+	}
+
+	if _, err := conv.ConvertPage(page, "https://example.atlassian.net", t.TempDir()); !errors.Is(err, ErrExcludedPage) {
+		t.Fatalf("expected ErrExcludedPage, got %v", err)
+	}
+}
@@ -1,9 +1,12 @@
 package converter
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"net/url"
 	"path/filepath"
+	"strings"
 
 	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
 	"github.com/JohannesKaufmann/html-to-markdown/v2/plugin/base"
@@ -13,6 +16,7 @@ import (
 	"github.com/jackchuka/confluence-md/internal/converter/model"
 	"github.com/jackchuka/confluence-md/internal/converter/plugin"
 	"github.com/jackchuka/confluence-md/internal/converter/plugin/attachments"
+	"github.com/jackchuka/confluence-md/internal/writefs"
 )
 
 const maxImageSizeBytes = 50 * 1024 * 1024
@@ -22,28 +26,234 @@ type Converter struct {
 	mdConverter *converter.Converter
 	plugin      *plugin.ConfluencePlugin
 	attachments attachments.Resolver
+	client      confluence.Client
+	fs          writefs.FS
+	events      Events
 
 	// options
-	imageFolder string
+	imageFolder      string
+	assetLimits      PathLimits // fits downloaded image and emoji paths into the path length limits
+	downloadEmojis   bool
+	downloadProgress func(fileName string, written, total int64)
+	numberHeadings   bool
+	headingIDs       plugin.HeadingIDStyle
+	linkRewrites     []LinkRewriteRule
+	linkMap          LinkMap
+	linkPolicy       LinkPolicy
+	workflowStatus   bool // fetch the Comala workflow state of each page
+	workflowBanner   bool // show the workflow state at the top of the body
+	labels           plugin.Labels
+	titleRules       TitleRules // rename frontmatter titles
+	pluginOptions    []plugin.Option
+
+	accessibilityCheck bool // report alt text, heading order and link text issues
+	accessibilityFix   bool // repair the reported issues
+	contentFilter      *ContentFilter
+	redactions         []RedactionRule
+	ctx                context.Context // cancels API requests and stops between conversion steps, nil for none
 }
 
 type Option func(*Converter)
 
+// WithAssetPathLimits shortens the paths of downloaded images and emojis, and the links to them, to fit the limits
+func WithAssetPathLimits(limits PathLimits) Option {
+	return func(c *Converter) {
+		c.assetLimits = limits
+	}
+}
+
 func WithDownloadAttachments(imageFolder string) Option {
 	return func(c *Converter) {
 		c.imageFolder = imageFolder
 	}
 }
 
+// WithDownloadProgress reports the bytes written for each downloaded image, with total -1 when unknown
+func WithDownloadProgress(progress func(fileName string, written, total int64)) Option {
+	return func(c *Converter) {
+		c.downloadProgress = progress
+	}
+}
+
+// WithEmojiImages downloads custom emoji images into the image folder and embeds them inline
+func WithEmojiImages() Option {
+	return func(c *Converter) {
+		c.downloadEmojis = true
+		c.pluginOptions = append(c.pluginOptions, plugin.WithEmojiImages())
+	}
+}
+
+// WithCalendarEvents lists Team Calendars events for the given number of upcoming days
+func WithCalendarEvents(days int) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithCalendarEvents(days))
+	}
+}
+
+// WithSearchExecution runs search and content-by-label macro queries once and renders their results as static lists
+func WithSearchExecution() Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithSearchExecution())
+	}
+}
+
+// WithNumberedHeadings prefixes every heading with hierarchical numbers (1., 1.1, 1.1.1)
+func WithNumberedHeadings() Option {
+	return func(c *Converter) {
+		c.numberHeadings = true
+		c.pluginOptions = append(c.pluginOptions, plugin.WithNumberedHeadings())
+	}
+}
+
+// WithSourceComments annotates converted macros and tables with comments referencing their Confluence source
+func WithSourceComments() Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithSourceComments())
+	}
+}
+
+// WithJiraBaseURL sets the Jira instance that jira macro issue keys link to
+func WithJiraBaseURL(jiraBaseURL string) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithJiraBaseURL(jiraBaseURL))
+	}
+}
+
+// WithUnresolvedUserPlaceholder renders mentions of deleted or anonymized users as @placeholder
+func WithUnresolvedUserPlaceholder(placeholder string) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithUnresolvedUserPlaceholder(placeholder))
+	}
+}
+
+// WithLinkRewrites rewrites URL prefixes in all converted links and images
+func WithLinkRewrites(rules []LinkRewriteRule) Option {
+	return func(c *Converter) {
+		c.linkRewrites = append(c.linkRewrites, rules...)
+	}
+}
+
+// WithLinkPolicy strips external links whose host is denied by the policy
+func WithLinkPolicy(policy LinkPolicy) Option {
+	return func(c *Converter) {
+		c.linkPolicy = policy
+	}
+}
+
+// WithUnknownMacroMode sets what macros without a dedicated handler are rendered as
+func WithUnknownMacroMode(mode plugin.UnknownMacroMode) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithUnknownMacroMode(mode))
+	}
+}
+
+// WithTableMode writes tables as Markdown pipe tables, cleaned HTML, or HTML only where cells are too complex to flatten
+func WithTableMode(mode plugin.TableMode) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithTableMode(mode))
+	}
+}
+
+// WithDialect writes GFM, strict CommonMark or Pandoc Markdown, downgrading constructs the dialect lacks
+func WithDialect(dialect plugin.Dialect) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithDialect(dialect))
+	}
+}
+
+// WithFencedDivs writes panels, expands and page layouts as Pandoc fenced divs
+func WithFencedDivs() Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithFencedDivs())
+	}
+}
+
+// WithInlineNoformat writes noformat macros with a single line of at most maxLength characters as inline code
+func WithInlineNoformat(maxLength int) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithInlineNoformat(maxLength))
+	}
+}
+
+// WithRowHeaderStyle sets how tables with headers only in the first column are written
+func WithRowHeaderStyle(style plugin.RowHeaderStyle) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithRowHeaderStyle(style))
+	}
+}
+
+// WithAnchorStyle sets how anchor names and links to anchors and headings are normalized
+func WithAnchorStyle(style plugin.AnchorStyle) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithAnchorStyle(style))
+	}
+}
+
+// WithAnchorSlugger generates anchor names in the slug anchor style with slugger, e.g. the one used for file names
+func WithAnchorSlugger(slugger plugin.Slugger) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithSlugger(slugger))
+	}
+}
+
+// WithHeadingIDs preserves Confluence's heading anchors as explicit ids so existing deep links keep working
+func WithHeadingIDs(style plugin.HeadingIDStyle) Option {
+	return func(c *Converter) {
+		c.headingIDs = style
+	}
+}
+
+// WithLabels sets the words injected into the output, such as panel titles and the workflow banner
+func WithLabels(labels plugin.Labels) Option {
+	return func(c *Converter) {
+		c.labels = labels
+		c.pluginOptions = append(c.pluginOptions, plugin.WithLabels(labels))
+	}
+}
+
+// WithMacroFilter strips the drop macros and, when only is not empty, every macro not listed in it
+func WithMacroFilter(drop, only []string) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithMacroFilter(drop, only))
+	}
+}
+
+// WithMacroHandlers renders the named macros with custom handlers, e.g. from plugin.NewTemplateMacroHandler
+func WithMacroHandlers(handlers map[string]plugin.MacroHandler) Option {
+	return func(c *Converter) {
+		c.pluginOptions = append(c.pluginOptions, plugin.WithMacroHandlers(handlers))
+	}
+}
+
+// WithContext cancels the converter's API requests once ctx is done, e.g. at a per-page deadline,
+// and stops the conversion at the next step
+func WithContext(ctx context.Context) Option {
+	return func(c *Converter) {
+		c.ctx = ctx
+	}
+}
+
+// WithFS writes downloaded images to fsys instead of the local disk
+func WithFS(fsys writefs.FS) Option {
+	return func(c *Converter) {
+		c.fs = fsys
+	}
+}
+
 // NewConverter creates a new HTML to Markdown converter
 func NewConverter(client confluence.Client, opts ...Option) *Converter {
-	c := &Converter{}
+	c := &Converter{client: client, fs: writefs.OS, events: NopEvents{}}
 
 	for _, opt := range opts {
 		if opt != nil {
 			opt(c)
 		}
 	}
+	c.pluginOptions = append(c.pluginOptions, plugin.WithWarningHandler(c.events.Warning))
+	if c.ctx != nil && client != nil {
+		client = client.WithContext(c.ctx)
+		c.client = client
+	}
 
 	var resolver attachments.Resolver
 	if client != nil {
@@ -52,10 +262,10 @@ func NewConverter(client confluence.Client, opts ...Option) *Converter {
 			c.attachments = resolver
 		}
 		// Use the client-aware plugin constructor for user resolution
-		c.plugin = plugin.NewConfluencePluginWithClient(client, resolver, c.imageFolder)
+		c.plugin = plugin.NewConfluencePluginWithClient(client, resolver, c.imageFolder, c.pluginOptions...)
 	} else {
 		// Use the basic plugin constructor when no client available
-		c.plugin = plugin.NewConfluencePlugin(resolver, c.imageFolder)
+		c.plugin = plugin.NewConfluencePlugin(resolver, c.imageFolder, c.pluginOptions...)
 	}
 	conv := converter.NewConverter(
 		converter.WithPlugins(
@@ -85,6 +295,10 @@ func (c *Converter) ConvertPage(
 	if err := page.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid page: %w", err)
 	}
+	if err := c.checkExcluded(page); err != nil {
+		return nil, err
+	}
+	c.events.PageStarted(page)
 	c.plugin.SetCurrentPage(page)
 	c.plugin.SetBaseURL(baseURL)
 
@@ -93,6 +307,7 @@ func (c *Converter) ConvertPage(
 	if err != nil {
 		return nil, fmt.Errorf("failed to create markdown document: %w", err)
 	}
+	doc.Frontmatter.Title = c.titleRules.Apply(doc.Frontmatter.Title)
 
 	htmlContent := page.Content.Storage.Value
 
@@ -100,20 +315,77 @@ func (c *Converter) ConvertPage(
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert HTML to Markdown: %w", err)
 	}
-	doc.Content = markdown
+	if err := c.contextErr(); err != nil {
+		return nil, err
+	}
+	if len(c.redactions) > 0 {
+		markdown, doc.Redactions = redact(markdown, c.redactions)
+		doc.Redact = c.redactor(doc)
+	}
+	doc.Content, doc.ExternalLinks = auditExternalLinks(markdown, baseURL, c.linkPolicy)
+	doc.UnresolvedUsers = c.plugin.UnresolvedUsers()
+	doc.Fidelity = scoreFidelity(page, c.plugin.UnsupportedMacros(), doc.UnresolvedUsers)
+	if c.accessibilityCheck {
+		doc.Content, doc.AccessibilityIssues = checkAccessibility(doc.Content, c.accessibilityFix)
+		for _, issue := range doc.AccessibilityIssues {
+			c.events.Warning(page, fmt.Sprintf("accessibility: line %d: %s", issue.Line, issue.Message))
+		}
+	}
+	c.applyWorkflow(page, doc)
 	// Extract image references for downloading
 	imageRefs := c.extractImageReferences(htmlContent, doc.Frontmatter.Confluence.PageID, baseURL)
-	doc.Images = imageRefs
+	doc.Images = appendDiagramPreviews(imageRefs, c.plugin.DiagramPreviews(), doc.Frontmatter.Confluence.PageID, baseURL)
 
 	if c.attachments != nil {
+		if err := c.contextErr(); err != nil {
+			return nil, err
+		}
+		c.limitAssetLinks(doc, outputDir)
 		if err := c.downloadImages(doc, page, outputDir); err != nil {
 			return nil, fmt.Errorf("failed to download images: %w", err)
 		}
+		if c.downloadEmojis {
+			c.downloadEmojiImages(page, c.plugin.Emojis(), outputDir, baseURL)
+		}
 	}
 
+	c.events.PageConverted(page, doc)
 	return doc, nil
 }
 
+// WriteOptions controls how ConvertPageTo renders a page
+type WriteOptions struct {
+	BaseURL     string // Confluence base URL used to resolve links
+	OutputDir   string // Directory images are downloaded into when attachment downloads are enabled
+	Frontmatter bool   // Prefix the Markdown with YAML frontmatter
+}
+
+// contextErr returns the context's error once the WithContext context is done
+func (c *Converter) contextErr() error {
+	if c.ctx == nil {
+		return nil
+	}
+	return c.ctx.Err()
+}
+
+// ConvertPageTo converts a Confluence page and streams the Markdown to w, e.g. an HTTP response or a pipe
+func (c *Converter) ConvertPageTo(w io.Writer, page *confluenceModel.ConfluencePage, opts WriteOptions) error {
+	doc, err := c.ConvertPage(page, opts.BaseURL, opts.OutputDir)
+	if err != nil {
+		return err
+	}
+
+	content, err := doc.Render(opts.Frontmatter)
+	if err != nil {
+		return fmt.Errorf("failed to render frontmatter: %w", err)
+	}
+
+	if _, err := io.WriteString(w, content); err != nil {
+		return fmt.Errorf("failed to write markdown: %w", err)
+	}
+	return nil
+}
+
 // downloadImages fetches referenced images via the attachment service and writes them to disk.
 func (c *Converter) downloadImages(doc *model.MarkdownDocument, page *confluenceModel.ConfluencePage, outputDir string) error {
 	if doc == nil {
@@ -129,29 +401,127 @@ func (c *Converter) downloadImages(doc *model.MarkdownDocument, page *confluence
 	}
 
 	for i := range doc.Images {
+		if err := c.contextErr(); err != nil {
+			return err
+		}
 		imageRef := &doc.Images[i]
-		attachment, data, err := c.attachments.DownloadAttachment(page, imageRef.FileName, 0)
+		filePath := c.assetPath(outputDir, filepath.Join(c.imageFolder, imageRef.FileName))
+
+		var attachment *confluenceModel.ConfluenceAttachment
+		written, err := c.writeStream(filePath, func(w io.Writer) (int64, error) {
+			var written int64
+			var err error
+			attachment, written, err = c.attachments.DownloadAttachmentTo(page, imageRef.FileName, 0, w, maxImageSizeBytes, c.progressFor(imageRef.FileName))
+			return written, err
+		})
 		if err != nil {
 			return fmt.Errorf("failed to download image %s: %w", imageRef.FileName, err)
 		}
 
-		if attachment.FileSize > maxImageSizeBytes {
-			return fmt.Errorf("image %s too large: %d bytes (max %d)", imageRef.FileName, attachment.FileSize, maxImageSizeBytes)
+		imageRef.ContentType = attachment.MediaType
+		imageRef.Size = written
+		c.events.AttachmentDownloaded(page, imageRef.FileName, filePath, written)
+	}
+
+	return nil
+}
+
+// assetPath returns where an image or emoji at relPath below outputDir is written, fitted into the path limits
+func (c *Converter) assetPath(outputDir, relPath string) string {
+	shortened, _ := c.assetLimits.Shorten(outputDir, relPath)
+	return filepath.Join(outputDir, shortened)
+}
+
+// limitAssetLinks points the links to downloaded images and emojis at their shortened files
+func (c *Converter) limitAssetLinks(doc *model.MarkdownDocument, outputDir string) {
+	if !c.assetLimits.Enabled() {
+		return
+	}
+
+	links := make([]string, 0, len(doc.Images))
+	for _, image := range doc.Images {
+		links = append(links, c.imageFolder+"/"+image.FileName)
+	}
+	if c.downloadEmojis {
+		for _, emoji := range c.plugin.Emojis() {
+			links = append(links, c.imageFolder+"/emoji/"+emoji.FileName)
+		}
+	}
+	for _, link := range links {
+		if shortened, changed := c.assetLimits.Shorten(outputDir, filepath.FromSlash(link)); changed {
+			doc.Content = strings.ReplaceAll(doc.Content, link, filepath.ToSlash(shortened))
 		}
+	}
+}
 
-		imageRef.ContentType = attachment.MediaType
-		imageRef.Size = attachment.FileSize
+// writeStream creates filePath and lets download stream into it without buffering the content.
+// A partially written file is removed.
+func (c *Converter) writeStream(filePath string, download func(io.Writer) (int64, error)) (int64, error) {
+	if err := c.fs.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := c.fs.Create(filePath, 0644)
+	if err != nil {
+		return 0, err
+	}
+	written, err := download(file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = c.fs.Remove(filePath)
+		return 0, err
+	}
+	return written, nil
+}
+
+// progressFor adapts the WithDownloadProgress callback to one file, or returns nil when none is set
+func (c *Converter) progressFor(fileName string) confluence.ProgressFunc {
+	if c.downloadProgress == nil {
+		return nil
+	}
+	return func(written, total int64) {
+		c.downloadProgress(fileName, written, total)
+	}
+}
 
-		filePath := filepath.Join(outputDir, c.imageFolder, imageRef.FileName)
-		fmt.Println("Downloading image:", imageRef.FileName, "to", filePath)
-		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
-			return fmt.Errorf("failed to create image directory: %w", err)
+// downloadEmojiImages fetches custom emoji images into <imageFolder>/emoji, skipping files that already exist.
+// Images hosted outside baseURL are fetched without credentials; a failed image is reported as a warning.
+func (c *Converter) downloadEmojiImages(page *confluenceModel.ConfluencePage, emojis []plugin.EmojiRef, outputDir, baseURL string) {
+	for _, emoji := range emojis {
+		filePath := c.assetPath(outputDir, filepath.Join(c.imageFolder, "emoji", emoji.FileName))
+		if _, err := c.fs.Stat(filePath); err == nil {
+			continue
 		}
 
-		if err := os.WriteFile(filePath, data, 0644); err != nil {
-			return fmt.Errorf("failed to write image %s: %w", imageRef.FileName, err)
+		written, err := c.writeStream(filePath, func(w io.Writer) (int64, error) {
+			if !sameHost(emoji.URL, baseURL) {
+				return c.client.DownloadPublic(emoji.URL, w, maxImageSizeBytes)
+			}
+			attachment := &confluenceModel.ConfluenceAttachment{
+				Title:        emoji.FileName,
+				DownloadLink: emoji.URL,
+			}
+			return c.client.DownloadAttachmentTo(attachment, w, maxImageSizeBytes, c.progressFor(emoji.FileName))
+		})
+		if err != nil {
+			c.events.Warning(page, fmt.Sprintf("failed to download emoji %s: %v", emoji.FileName, err))
+			continue
 		}
+		c.events.AttachmentDownloaded(page, emoji.FileName, filePath, written)
 	}
+}
 
-	return nil
+// sameHost reports whether link is relative or points at the host of baseURL
+func sameHost(link, baseURL string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	if parsed.Host == "" {
+		return true
+	}
+	base, err := url.Parse(baseURL)
+	return err == nil && strings.EqualFold(parsed.Host, base.Host)
 }
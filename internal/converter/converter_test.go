@@ -1,15 +1,21 @@
 package converter
 
 import (
+	"context"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/jackchuka/confluence-md/internal/confluence"
 	confModel "github.com/jackchuka/confluence-md/internal/confluence/model"
 	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
 	mock_attachments "github.com/jackchuka/confluence-md/internal/converter/plugin/attachments/mock"
+	"github.com/jackchuka/confluence-md/internal/writefs"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -78,6 +84,40 @@ func TestConverterConvertPage(t *testing.T) {
 	}
 }
 
+func TestConverterConvertPageTo(t *testing.T) {
+	conv := NewConverter(nil)
+
+	page := &confModel.ConfluencePage{
+		ID:       "123",
+		Title:    "Sample Page",
+		SpaceKey: "SPACE",
+		Version:  1,
+		Content: confModel.ConfluenceContent{
+			Storage: confModel.ContentStorage{Value: "<p>Hello World</p>", Representation: "storage"},
+		},
+	}
+
+	var plain strings.Builder
+	if err := conv.ConvertPageTo(&plain, page, WriteOptions{BaseURL: "https://example.atlassian.net"}); err != nil {
+		t.Fatalf("ConvertPageTo returned error: %v", err)
+	}
+	if strings.HasPrefix(plain.String(), "---\n") || !strings.Contains(plain.String(), "Hello World") {
+		t.Fatalf("unexpected markdown without frontmatter: %q", plain.String())
+	}
+
+	var withFrontmatter strings.Builder
+	if err := conv.ConvertPageTo(&withFrontmatter, page, WriteOptions{BaseURL: "https://example.atlassian.net", Frontmatter: true}); err != nil {
+		t.Fatalf("ConvertPageTo returned error: %v", err)
+	}
+	if !strings.HasPrefix(withFrontmatter.String(), "---\n") || !strings.Contains(withFrontmatter.String(), "title: \"Sample Page\"") {
+		t.Fatalf("expected frontmatter, got %q", withFrontmatter.String())
+	}
+
+	if err := conv.ConvertPageTo(&plain, &confModel.ConfluencePage{Title: "Missing ID"}, WriteOptions{}); err == nil {
+		t.Fatal("expected error for invalid page")
+	}
+}
+
 func TestConverterDownloadImages(t *testing.T) {
 	data := []byte("image-bytes")
 	attachment := &confModel.ConfluenceAttachment{Title: "diagram.png", MediaType: "image/png", FileSize: int64(len(data))}
@@ -85,11 +125,18 @@ func TestConverterDownloadImages(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 	mockResolver := mock_attachments.NewMockResolver(ctrl)
-	mockResolver.EXPECT().DownloadAttachment(gomock.Any(), "diagram.png", 0).Return(attachment, data, nil)
+	mockResolver.EXPECT().DownloadAttachmentTo(gomock.Any(), "diagram.png", 0, gomock.Any(), int64(maxImageSizeBytes), gomock.Any()).
+		DoAndReturn(func(_ *confModel.ConfluencePage, _ string, _ int, w io.Writer, _ int64, _ confluence.ProgressFunc) (*confModel.ConfluenceAttachment, int64, error) {
+			n, err := w.Write(data)
+			return attachment, int64(n), err
+		})
 
+	mem := writefs.NewMemFS()
 	conv := &Converter{
 		imageFolder: "images",
 		attachments: mockResolver,
+		fs:          mem,
+		events:      NopEvents{},
 	}
 
 	doc := &convModel.MarkdownDocument{
@@ -102,14 +149,12 @@ func TestConverterDownloadImages(t *testing.T) {
 		Attachments: []confModel.ConfluenceAttachment{{Title: "diagram.png"}},
 	}
 
-	tmpDir := t.TempDir()
-
-	if err := conv.downloadImages(doc, page, tmpDir); err != nil {
+	if err := conv.downloadImages(doc, page, "out"); err != nil {
 		t.Fatalf("DownloadImages returned error: %v", err)
 	}
 
-	imagePath := filepath.Join(tmpDir, "images", "diagram.png")
-	got, err := os.ReadFile(imagePath)
+	imagePath := filepath.Join("out", "images", "diagram.png")
+	got, err := mem.ReadFile(imagePath)
 	if err != nil {
 		t.Fatalf("failed to read downloaded image: %v", err)
 	}
@@ -124,6 +169,27 @@ func TestConverterDownloadImages(t *testing.T) {
 	}
 }
 
+func TestConverterDownloadImagesRemovesPartialFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockResolver := mock_attachments.NewMockResolver(ctrl)
+	mockResolver.EXPECT().DownloadAttachmentTo(gomock.Any(), "huge.png", 0, gomock.Any(), int64(maxImageSizeBytes), gomock.Any()).
+		DoAndReturn(func(_ *confModel.ConfluencePage, _ string, _ int, w io.Writer, _ int64, _ confluence.ProgressFunc) (*confModel.ConfluenceAttachment, int64, error) {
+			_, _ = w.Write([]byte("partial"))
+			return nil, 7, confluence.ErrAttachmentTooLarge
+		})
+
+	mem := writefs.NewMemFS()
+	conv := &Converter{imageFolder: "images", attachments: mockResolver, fs: mem, events: NopEvents{}}
+
+	doc := &convModel.MarkdownDocument{Images: []convModel.ImageRef{{FileName: "huge.png"}}}
+	if err := conv.downloadImages(doc, &confModel.ConfluencePage{}, "out"); !errors.Is(err, confluence.ErrAttachmentTooLarge) {
+		t.Fatalf("expected ErrAttachmentTooLarge, got %v", err)
+	}
+	if paths := mem.Paths(); len(paths) != 0 {
+		t.Fatalf("expected the partial file to be removed, got %v", paths)
+	}
+}
+
 func TestSaveMarkdownDocument(t *testing.T) {
 	tmpDir := t.TempDir()
 	doc := &convModel.MarkdownDocument{
@@ -142,7 +208,7 @@ func TestSaveMarkdownDocument(t *testing.T) {
 	}
 
 	plainPath := filepath.Join(tmpDir, "doc.md")
-	if err := SaveMarkdownDocument(doc, plainPath, false); err != nil {
+	if err := SaveMarkdownDocument(writefs.OS, doc, plainPath, false); err != nil {
 		t.Fatalf("SaveMarkdownDocument returned error: %v", err)
 	}
 
@@ -157,7 +223,7 @@ func TestSaveMarkdownDocument(t *testing.T) {
 	// Reset content and save with frontmatter
 	doc.Content = "body"
 	frontPath := filepath.Join(tmpDir, "doc-with-frontmatter.md")
-	if err := SaveMarkdownDocument(doc, frontPath, true); err != nil {
+	if err := SaveMarkdownDocument(writefs.OS, doc, frontPath, true); err != nil {
 		t.Fatalf("SaveMarkdownDocument with frontmatter returned error: %v", err)
 	}
 
@@ -242,3 +308,133 @@ func TestFixNestedListSpacing(t *testing.T) {
 		t.Fatalf("fixNestedListSpacing(%q) = %q, want %q", input, got, want)
 	}
 }
+
+func TestConverterSourceComments(t *testing.T) {
+	conv := NewConverter(nil, WithSourceComments())
+	page := &confModel.ConfluencePage{
+		ID:       "42",
+		Title:    "Traced",
+		SpaceKey: "SPACE",
+		Content: confModel.ConfluenceContent{
+			Storage: confModel.ContentStorage{
+				Value: `<ac:structured-macro ac:name="info"><ac:rich-text-body><p>Heads up</p></ac:rich-text-body></ac:structured-macro><table><tbody><tr><th>A</th></tr><tr><td>1</td></tr></tbody></table>`,
+			},
+		},
+	}
+
+	doc, err := conv.ConvertPage(page, "https://example.atlassian.net", t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(doc.Content, "<!-- source: page=42 macro=info -->\n> ") {
+		t.Fatalf("expected macro source comment, got %q", doc.Content)
+	}
+	if !strings.Contains(doc.Content, "<!-- source: page=42 table=1 -->\n\n| A |") {
+		t.Fatalf("expected table source comment, got %q", doc.Content)
+	}
+}
+
+func TestConverterFailedMacroHandlerConvertsBodyOnce(t *testing.T) {
+	failing := func(macro plugin.Macro) (string, error) {
+		if !strings.Contains(macro.Body(), "Inside") {
+			t.Errorf("unexpected body %q", macro.Body())
+		}
+		return "", errors.New("boom")
+	}
+	conv := NewConverter(nil, WithMacroHandlers(map[string]plugin.MacroHandler{"info": failing}))
+	page := &confModel.ConfluencePage{
+		ID:       "42",
+		Title:    "Vendor",
+		SpaceKey: "SPACE",
+		Content: confModel.ConfluenceContent{
+			Storage: confModel.ContentStorage{
+				Value: `<ac:structured-macro ac:name="info"><ac:rich-text-body><p>Inside</p><ac:structured-macro ac:name="roadmap"></ac:structured-macro></ac:rich-text-body></ac:structured-macro>`,
+			},
+		},
+	}
+
+	doc, err := conv.ConvertPage(page, "https://example.atlassian.net", t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The nested macro is recorded once although the handler and the built-in panel both used the body
+	if got := strings.Join(doc.Fidelity.UnsupportedMacros, ","); got != "roadmap" {
+		t.Fatalf("unexpected unsupported macros %q", got)
+	}
+}
+
+func TestConverterLimitAssetLinks(t *testing.T) {
+	conv := &Converter{imageFolder: "assets", assetLimits: PathLimits{MaxSegment: 20}}
+	long := "a-very-long-screenshot-name.png"
+	doc := &convModel.MarkdownDocument{
+		Content: "![shot](assets/" + long + ") ![ok](assets/ok.png)",
+		Images:  []convModel.ImageRef{{FileName: long}, {FileName: "ok.png"}},
+	}
+
+	conv.limitAssetLinks(doc, "out")
+	path := conv.assetPath("out", filepath.Join("assets", long))
+	if filepath.Dir(path) != filepath.Join("out", "assets") || len(filepath.Base(path)) > 20 {
+		t.Fatalf("unexpected asset path %s", path)
+	}
+	if want := "![shot](assets/" + filepath.Base(path) + ") ![ok](assets/ok.png)"; doc.Content != want {
+		t.Fatalf("limitAssetLinks() = %q, want %q", doc.Content, want)
+	}
+}
+
+func TestRewriteLinks(t *testing.T) {
+	rules, err := ParseLinkRewriteRules([]string{"https://old.example.com=https://new.example.com", "http://intranet/=https://docs/"})
+	if err != nil {
+		t.Fatalf("ParseLinkRewriteRules returned error: %v", err)
+	}
+
+	input := "[a](https://old.example.com/x) ![b](http://intranet/img.png) <https://old.example.com> [c](https://other.example.com)"
+	want := "[a](https://new.example.com/x) ![b](https://docs/img.png) <https://new.example.com> [c](https://other.example.com)"
+	if got := rewriteLinks(input, rules); got != want {
+		t.Fatalf("rewriteLinks() = %q, want %q", got, want)
+	}
+
+	if _, err := ParseLinkRewriteRules([]string{"missing-separator"}); err == nil {
+		t.Fatal("expected error for rule without separator")
+	}
+}
+
+func TestAuditExternalLinks(t *testing.T) {
+	input := "[wiki](https://example.atlassian.net/wiki/x) [docs](https://docs.example.com/a) [secret](https://jira.corp.internal/b) ![img](https://cdn.corp.internal/i.png) <https://docs.example.com/a>"
+	policy := LinkPolicy{Deny: []string{"*.corp.internal"}}
+
+	got, links := auditExternalLinks(input, "https://example.atlassian.net", policy)
+	want := "[wiki](https://example.atlassian.net/wiki/x) [docs](https://docs.example.com/a) secret  <https://docs.example.com/a>"
+	if got != want {
+		t.Fatalf("auditExternalLinks() = %q, want %q", got, want)
+	}
+	if len(links) != 3 {
+		t.Fatalf("expected 3 unique external links, got %#v", links)
+	}
+	if links[0].URL != "https://docs.example.com/a" || links[0].Stripped {
+		t.Fatalf("unexpected first link: %#v", links[0])
+	}
+	if !links[1].Stripped || !links[2].Stripped {
+		t.Fatalf("expected denied links to be stripped: %#v", links)
+	}
+
+	_, links = auditExternalLinks("[docs](https://docs.example.com/a) [other](https://other.example.com)", "", LinkPolicy{Allow: []string{"docs.example.com"}})
+	if links[0].Stripped || !links[1].Stripped {
+		t.Fatalf("unexpected allow list result: %#v", links)
+	}
+}
+
+func TestConverterWithCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conv := NewConverter(nil, WithContext(ctx))
+	page := &confModel.ConfluencePage{
+		ID:       "1",
+		Title:    "Slow",
+		SpaceKey: "SPACE",
+		Content:  confModel.ConfluenceContent{Storage: confModel.ContentStorage{Value: "<p>body</p>"}},
+	}
+	if _, err := conv.ConvertPage(page, "https://example.atlassian.net", t.TempDir()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled context to stop the conversion, got %v", err)
+	}
+}
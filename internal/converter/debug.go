@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// Debug render file names, in the order the conversion produces them
+const (
+	DebugStorageFile      = "storage.html"
+	DebugPreprocessedFile = "preprocessed.html"
+	DebugMarkdownFile     = "output.md"
+)
+
+// RenderDebug converts a page and writes each stage of the conversion into dir: the raw storage format, the HTML
+// handed to the Markdown converter after preprocessing, and the final Markdown. It returns the written paths.
+func (c *Converter) RenderDebug(page *confluenceModel.ConfluencePage, opts WriteOptions, dir string) ([]string, error) {
+	doc, err := c.ConvertPage(page, opts.BaseURL, opts.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	markdown, err := doc.Render(opts.Frontmatter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render frontmatter: %w", err)
+	}
+
+	storage := page.Content.Storage.Value
+	preprocessed := c.preprocessCDATA(storage)
+	if len(c.redactions) > 0 {
+		// The raw stages hold the same secrets as the Markdown
+		storage, _ = redact(storage, c.redactions)
+		preprocessed, _ = redact(preprocessed, c.redactions)
+	}
+
+	stages := []struct {
+		name    string
+		content string
+	}{
+		{DebugStorageFile, storage},
+		{DebugPreprocessedFile, preprocessed},
+		{DebugMarkdownFile, markdown},
+	}
+
+	if err := c.fs.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create debug directory: %w", err)
+	}
+	written := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		path := filepath.Join(dir, stage.name)
+		if err := c.fs.WriteFile(path, []byte(stage.content), 0644); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", stage.name, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
@@ -0,0 +1,49 @@
+package converter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	confModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+func TestConverterRenderDebug(t *testing.T) {
+	fsys := writefs.NewMemFS()
+	conv := NewConverter(nil, WithFS(fsys))
+
+	page := &confModel.ConfluencePage{
+		ID:       "123",
+		Title:    "Sample Page",
+		SpaceKey: "SPACE",
+		Version:  1,
+		Content: confModel.ConfluenceContent{
+			Storage: confModel.ContentStorage{Value: "<p>Hello</p><pre><![CDATA[a < b]]></pre>", Representation: "storage"},
+		},
+	}
+
+	dir := filepath.Join("debug", "123")
+	written, err := conv.RenderDebug(page, WriteOptions{BaseURL: "https://example.atlassian.net"}, dir)
+	if err != nil {
+		t.Fatalf("RenderDebug returned error: %v", err)
+	}
+	if len(written) != 3 {
+		t.Fatalf("expected 3 files, got %v", written)
+	}
+
+	want := map[string]string{
+		DebugStorageFile:      "<![CDATA[a < b]]>",
+		DebugPreprocessedFile: "<pre data-cdata='true'>a &lt; b</pre>",
+		DebugMarkdownFile:     "Hello",
+	}
+	for name, fragment := range want {
+		data, err := fsys.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", name, err)
+		}
+		if !strings.Contains(string(data), fragment) {
+			t.Errorf("%s = %q, want it to contain %q", name, data, fragment)
+		}
+	}
+}
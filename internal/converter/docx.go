@@ -0,0 +1,67 @@
+package converter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+// PandocFormat returns the pandoc input format that reads Markdown of the dialect together with its YAML frontmatter
+func PandocFormat(dialect plugin.Dialect) string {
+	switch dialect {
+	case plugin.DialectPandoc:
+		return "markdown"
+	case plugin.DialectCommonMark:
+		return "commonmark+yaml_metadata_block"
+	default:
+		return "gfm+yaml_metadata_block"
+	}
+}
+
+// DocxPath returns the path of the Word document written for a Markdown file
+func DocxPath(markdownPath string) string {
+	return strings.TrimSuffix(markdownPath, filepath.Ext(markdownPath)) + ".docx"
+}
+
+// pandocTimeout bounds every pandoc run, so a hung process cannot stall an export without --page-timeout
+const pandocTimeout = 5 * time.Minute
+
+// ConvertToDocx converts a written Markdown file into a .docx beside it with a pandoc command, embedding the
+// images it references from the Markdown file's directory. The frontmatter title becomes the document title.
+// pandoc is killed once ctx is done or after pandocTimeout, whichever comes first.
+func ConvertToDocx(ctx context.Context, fsys writefs.FS, pandoc, markdownPath string, dialect plugin.Dialect) (string, error) {
+	markdown, err := fsys.ReadFile(markdownPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read markdown file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, pandocTimeout)
+	defer cancel()
+
+	var docx, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, pandoc, "--from", PandocFormat(dialect), "--to", "docx", "--output", "-",
+		"--resource-path", filepath.Dir(markdownPath))
+	cmd.Stdin = bytes.NewReader(markdown)
+	cmd.Stdout = &docx
+	cmd.Stderr = &stderr
+	cmd.WaitDelay = time.Second // don't wait on processes pandoc started that still hold its output open
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("failed to convert to docx with %s: %w", pandoc, ctx.Err())
+		}
+		return "", fmt.Errorf("failed to convert to docx with %s: %w: %s", pandoc, err, strings.TrimSpace(stderr.String()))
+	}
+
+	docxPath := DocxPath(markdownPath)
+	if err := fsys.WriteFile(docxPath, docx.Bytes(), 0644); err != nil {
+		return "", fmt.Errorf("failed to write docx file: %w", err)
+	}
+	return docxPath, nil
+}
@@ -0,0 +1,96 @@
+package converter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+func TestPandocFormat(t *testing.T) {
+	tests := map[plugin.Dialect]string{
+		"":                       "gfm+yaml_metadata_block",
+		plugin.DialectGFM:        "gfm+yaml_metadata_block",
+		plugin.DialectCommonMark: "commonmark+yaml_metadata_block",
+		plugin.DialectPandoc:     "markdown",
+	}
+	for dialect, want := range tests {
+		if got := PandocFormat(dialect); got != want {
+			t.Errorf("PandocFormat(%q) = %q, want %q", dialect, got, want)
+		}
+	}
+}
+
+func TestConvertToDocx(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the pandoc command")
+	}
+
+	// The fake pandoc echoes its arguments followed by the Markdown it reads from stdin
+	pandoc := filepath.Join(t.TempDir(), "pandoc")
+	if err := os.WriteFile(pandoc, []byte("#!/bin/sh\necho \"$@\"\ncat\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake pandoc: %v", err)
+	}
+
+	fsys := writefs.NewMemFS()
+	markdownPath := filepath.Join("out", "guide.md")
+	if err := fsys.WriteFile(markdownPath, []byte("# Guide\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	docxPath, err := ConvertToDocx(context.Background(), fsys, pandoc, markdownPath, plugin.DialectPandoc)
+	if err != nil {
+		t.Fatalf("ConvertToDocx() error = %v", err)
+	}
+	if docxPath != filepath.Join("out", "guide.docx") {
+		t.Errorf("docx path = %q", docxPath)
+	}
+
+	data, err := fsys.ReadFile(docxPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	want := "--from markdown --to docx --output - --resource-path out\n# Guide\n"
+	if string(data) != want {
+		t.Errorf("docx content = %q, want %q", data, want)
+	}
+
+	if _, err := ConvertToDocx(context.Background(), fsys, filepath.Join(t.TempDir(), "missing"), markdownPath, ""); err == nil || !strings.Contains(err.Error(), "failed to convert to docx") {
+		t.Errorf("missing pandoc error = %v", err)
+	}
+}
+
+func TestConvertToDocxDeadline(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the pandoc command")
+	}
+
+	// The fake pandoc hangs until it is killed
+	pandoc := filepath.Join(t.TempDir(), "pandoc")
+	if err := os.WriteFile(pandoc, []byte("#!/bin/sh\nexec sleep 60\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake pandoc: %v", err)
+	}
+
+	fsys := writefs.NewMemFS()
+	if err := fsys.WriteFile("guide.md", []byte("# Guide\n"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := ConvertToDocx(ctx, fsys, pandoc, "guide.md", "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ConvertToDocx() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("ConvertToDocx() returned after %s, want pandoc killed at the deadline", elapsed)
+	}
+}
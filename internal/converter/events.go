@@ -0,0 +1,37 @@
+package converter
+
+import (
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// Events receives progress notifications while pages are converted, for progress UIs and metrics.
+// Embed NopEvents to handle only some of them.
+type Events interface {
+	// PageStarted is called before a page is converted
+	PageStarted(page *confluenceModel.ConfluencePage)
+	// PageConverted is called once a page's Markdown and images are ready
+	PageConverted(page *confluenceModel.ConfluencePage, doc *model.MarkdownDocument)
+	// AttachmentDownloaded is called after an image or emoji was written to path
+	AttachmentDownloaded(page *confluenceModel.ConfluencePage, fileName, path string, size int64)
+	// Warning reports a non-fatal problem; page is nil outside page conversion
+	Warning(page *confluenceModel.ConfluencePage, message string)
+}
+
+// NopEvents ignores every event
+type NopEvents struct{}
+
+func (NopEvents) PageStarted(*confluenceModel.ConfluencePage) {}
+
+func (NopEvents) PageConverted(*confluenceModel.ConfluencePage, *model.MarkdownDocument) {}
+
+func (NopEvents) AttachmentDownloaded(*confluenceModel.ConfluencePage, string, string, int64) {}
+
+func (NopEvents) Warning(*confluenceModel.ConfluencePage, string) {}
+
+// WithEvents sends conversion progress and warnings to events
+func WithEvents(events Events) Option {
+	return func(c *Converter) {
+		c.events = events
+	}
+}
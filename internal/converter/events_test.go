@@ -0,0 +1,76 @@
+package converter
+
+import (
+	"io"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	mock_attachments "github.com/jackchuka/confluence-md/internal/converter/plugin/attachments/mock"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// recordingEvents remembers the events it received
+type recordingEvents struct {
+	NopEvents
+	started     []string
+	converted   []string
+	attachments []string
+}
+
+func (r *recordingEvents) PageStarted(page *confModel.ConfluencePage) {
+	r.started = append(r.started, page.ID)
+}
+
+func (r *recordingEvents) PageConverted(page *confModel.ConfluencePage, _ *convModel.MarkdownDocument) {
+	r.converted = append(r.converted, page.ID)
+}
+
+func (r *recordingEvents) AttachmentDownloaded(_ *confModel.ConfluencePage, fileName, _ string, _ int64) {
+	r.attachments = append(r.attachments, fileName)
+}
+
+func TestConvertPageEmitsEvents(t *testing.T) {
+	events := &recordingEvents{}
+	conv := NewConverter(nil, WithEvents(events))
+
+	page := &confModel.ConfluencePage{
+		ID:       "123",
+		Title:    "Sample Page",
+		SpaceKey: "SPACE",
+		Content:  confModel.ConfluenceContent{Storage: confModel.ContentStorage{Value: "<p>Hello</p>", Representation: "storage"}},
+	}
+	if _, err := conv.ConvertPage(page, "https://example.atlassian.net", "."); err != nil {
+		t.Fatalf("ConvertPage returned error: %v", err)
+	}
+
+	if len(events.started) != 1 || events.started[0] != "123" {
+		t.Fatalf("unexpected PageStarted events: %v", events.started)
+	}
+	if len(events.converted) != 1 || events.converted[0] != "123" {
+		t.Fatalf("unexpected PageConverted events: %v", events.converted)
+	}
+}
+
+func TestDownloadImagesEmitsAttachmentEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockResolver := mock_attachments.NewMockResolver(ctrl)
+	mockResolver.EXPECT().DownloadAttachmentTo(gomock.Any(), "diagram.png", 0, gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ *confModel.ConfluencePage, _ string, _ int, w io.Writer, _ int64, _ confluence.ProgressFunc) (*confModel.ConfluenceAttachment, int64, error) {
+			n, err := io.WriteString(w, "png")
+			return &confModel.ConfluenceAttachment{Title: "diagram.png", FileSize: 3}, int64(n), err
+		})
+
+	events := &recordingEvents{}
+	conv := &Converter{imageFolder: "images", attachments: mockResolver, fs: writefs.NewMemFS(), events: events}
+
+	doc := &convModel.MarkdownDocument{Images: []convModel.ImageRef{{FileName: "diagram.png"}}}
+	if err := conv.downloadImages(doc, &confModel.ConfluencePage{ID: "1"}, "out"); err != nil {
+		t.Fatalf("downloadImages returned error: %v", err)
+	}
+	if len(events.attachments) != 1 || events.attachments[0] != "diagram.png" {
+		t.Fatalf("unexpected AttachmentDownloaded events: %v", events.attachments)
+	}
+}
@@ -0,0 +1,96 @@
+package converter
+
+import (
+	"strings"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	"golang.org/x/net/html"
+)
+
+// linkTargets are the resource identifiers of links the converter writes as plain text
+var linkTargets = map[string]bool{
+	"ri:page":           true,
+	"ri:blog-post":      true,
+	"ri:attachment":     true,
+	"ri:content-entity": true,
+	"ri:space":          true,
+}
+
+// scoreFidelity counts the macros, images and links in a page's storage format and the ones the conversion lost:
+// unsupported macros reported by the plugin, links to Confluence content and unresolved user mentions, which lose
+// their target, and images whose attachment is not listed on the page.
+func scoreFidelity(page *confluenceModel.ConfluencePage, unsupported, unresolvedUsers []string) model.Fidelity {
+	fidelity := model.Fidelity{UnsupportedMacros: unsupported}
+
+	root, err := html.Parse(strings.NewReader(page.Content.Storage.Value))
+	if err != nil {
+		return fidelity.Scored()
+	}
+
+	attachments := make(map[string]bool, len(page.Attachments))
+	for _, attachment := range page.Attachments {
+		attachments[attachment.Title] = true
+	}
+	unresolved := make(map[string]bool, len(unresolvedUsers))
+	for _, accountID := range unresolvedUsers {
+		unresolved[accountID] = true
+	}
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "ac:structured-macro", "ac:macro":
+				fidelity.Elements++
+			case "ac:image":
+				fidelity.Elements++
+				if name := missingAttachment(n, attachments); name != "" {
+					fidelity.MissingAttachments = append(fidelity.MissingAttachments, name)
+				}
+			case "ac:link":
+				fidelity.Elements++
+				if linkLosesTarget(n, unresolved) {
+					fidelity.UnresolvedLinks++
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	return fidelity.Scored()
+}
+
+// missingAttachment returns the file name of an image attached to this page that is not among its attachments
+func missingAttachment(image *html.Node, attachments map[string]bool) string {
+	for child := image.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.Data != "ri:attachment" || child.FirstChild != nil {
+			// Attachments of other pages carry an ri:page child and are not checked
+			continue
+		}
+		if name := plugin.GetAttr(child, "ri:filename"); name != "" && !attachments[name] {
+			return name
+		}
+	}
+	return ""
+}
+
+// linkLosesTarget reports whether an ac:link is written without its target
+func linkLosesTarget(link *html.Node, unresolvedUsers map[string]bool) bool {
+	for child := link.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if child.Data == "ri:user" {
+			return unresolvedUsers[plugin.GetAttr(child, "ri:account-id")]
+		}
+		if linkTargets[child.Data] {
+			return true
+		}
+	}
+	return false
+}
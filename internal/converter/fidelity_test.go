@@ -0,0 +1,53 @@
+package converter
+
+import (
+	"reflect"
+	"testing"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+func TestScoreFidelity(t *testing.T) {
+	page := &confluenceModel.ConfluencePage{
+		Attachments: []confluenceModel.ConfluenceAttachment{{Title: "diagram.png"}},
+	}
+	page.Content.Storage.Value = `<p>` +
+		`<ac:structured-macro ac:name="info"></ac:structured-macro>` +
+		`<ac:structured-macro ac:name="gliffy"></ac:structured-macro>` +
+		`<ac:image><ri:attachment ri:filename="diagram.png" /></ac:image>` +
+		`<ac:image><ri:attachment ri:filename="lost.png" /></ac:image>` +
+		`<ac:image><ri:attachment ri:filename="other.png"><ri:page ri:content-title="Other" /></ri:attachment></ac:image>` +
+		`<ac:link><ri:page ri:content-title="Setup" /></ac:link>` +
+		`<ac:link><ri:user ri:account-id="known" /></ac:link>` +
+		`<ac:link><ri:user ri:account-id="gone" /></ac:link>` +
+		`</p>`
+
+	got := scoreFidelity(page, []string{"gliffy"}, []string{"gone"})
+	want := model.Fidelity{
+		Score:              0.5,
+		Elements:           8,
+		UnsupportedMacros:  []string{"gliffy"},
+		UnresolvedLinks:    2,
+		MissingAttachments: []string{"lost.png"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("scoreFidelity() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFidelityCombine(t *testing.T) {
+	parent := model.Fidelity{Elements: 3}.Scored()
+	if parent.Score != 1 {
+		t.Fatalf("score without losses = %v, want 1", parent.Score)
+	}
+
+	combined := parent.Combine(model.Fidelity{Elements: 1, UnsupportedMacros: []string{"roadmap"}})
+	if combined.Elements != 4 || combined.Score != 0.75 || len(combined.UnsupportedMacros) != 1 {
+		t.Fatalf("Combine() = %+v", combined)
+	}
+
+	if empty := (model.Fidelity{}).Scored(); empty.Score != 1 {
+		t.Fatalf("score of a page without elements = %v, want 1", empty.Score)
+	}
+}
@@ -0,0 +1,108 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// linkPageIDRegex extracts the page ID from Confluence page URLs and converted confluence:// links
+var linkPageIDRegex = regexp.MustCompile(`(?:/pages/|pageId[=/])(\d+)`)
+
+// LinkMap maps Confluence page IDs and URLs onto their destination URLs, for example on a new docs site
+type LinkMap map[string]string
+
+// ParseLinkMap parses source,target rows (format "csv") or "source: target" lines (format "yaml").
+// Sources are page IDs or Confluence URLs; URLs containing a page ID are keyed by that ID.
+func ParseLinkMap(data []byte, format string) (LinkMap, error) {
+	var pairs [][2]string
+	switch format {
+	case "csv":
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		reader.Comment = '#'
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse link map: %w", err)
+		}
+		for i, record := range records {
+			if len(record) < 2 {
+				return nil, fmt.Errorf("link map row %d must be source,target", i+1)
+			}
+			if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "source") {
+				continue // header row
+			}
+			pairs = append(pairs, [2]string{record[0], record[1]})
+		}
+	case "yaml":
+		for i, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			// Split at ": " so URL schemes like https:// stay intact
+			source, target, ok := strings.Cut(line, ": ")
+			if !ok {
+				return nil, fmt.Errorf("link map line %d must be source: target", i+1)
+			}
+			pairs = append(pairs, [2]string{unquoteYAML(source), unquoteYAML(target)})
+		}
+	default:
+		return nil, fmt.Errorf("link map format must be csv or yaml, got: %s", format)
+	}
+
+	links := make(LinkMap, len(pairs))
+	for _, pair := range pairs {
+		source, target := strings.TrimSpace(pair[0]), strings.TrimSpace(pair[1])
+		if source == "" || target == "" {
+			return nil, fmt.Errorf("link map entry %q has an empty source or target", source+" -> "+target)
+		}
+		links[linkMapKey(source)] = target
+	}
+	return links, nil
+}
+
+// unquoteYAML strips the quotes around a YAML scalar
+func unquoteYAML(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// linkMapKey returns the page ID of a source when it has one, and the URL without a trailing slash otherwise
+func linkMapKey(source string) string {
+	if match := linkPageIDRegex.FindStringSubmatch(source); match != nil {
+		return match[1]
+	}
+	return strings.TrimSuffix(source, "/")
+}
+
+// WithLinkMap rewrites links to mapped Confluence pages to their destination URLs
+func WithLinkMap(links LinkMap) Option {
+	return func(c *Converter) {
+		c.linkMap = links
+	}
+}
+
+// mapLinks replaces link targets found in the map, keeping the original #fragment when the destination has none
+func mapLinks(markdown string, links LinkMap) string {
+	if len(links) == 0 {
+		return markdown
+	}
+
+	return rewriteLinkTargets(markdown, func(target string) string {
+		base, fragment, hasFragment := strings.Cut(target, "#")
+		destination, ok := links[linkMapKey(base)]
+		if !ok {
+			return target
+		}
+		if hasFragment && !strings.Contains(destination, "#") {
+			destination += "#" + fragment
+		}
+		return destination
+	})
+}
@@ -0,0 +1,36 @@
+package converter
+
+import "testing"
+
+func TestParseLinkMap(t *testing.T) {
+	csvMap, err := ParseLinkMap([]byte("source,target\n123,https://docs.example.com/guide\nhttps://example.atlassian.net/wiki/spaces/DOCS/pages/456/FAQ,/faq\n"), "csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	yamlMap, err := ParseLinkMap([]byte("# migrated pages\n\"123\": https://docs.example.com/guide\nhttps://example.atlassian.net/wiki/spaces/DOCS/pages/456/FAQ: /faq\n"), "yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for name, links := range map[string]LinkMap{"csv": csvMap, "yaml": yamlMap} {
+		if links["123"] != "https://docs.example.com/guide" || links["456"] != "/faq" || len(links) != 2 {
+			t.Fatalf("%s: unexpected link map %v", name, links)
+		}
+	}
+
+	if _, err := ParseLinkMap([]byte("123\n"), "csv"); err == nil {
+		t.Fatal("expected an error for a row without a target")
+	}
+	if _, err := ParseLinkMap(nil, "toml"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestMapLinks(t *testing.T) {
+	links := LinkMap{"123": "https://docs.example.com/guide", "https://example.com/old": "https://example.com/new"}
+	input := "[Guide](confluence://pageId/123) [Setup](https://example.atlassian.net/wiki/spaces/DOCS/pages/123/Guide#Setup) <a href=\"https://example.com/old/\">old</a> [Other](confluence://pageId/999)"
+	want := "[Guide](https://docs.example.com/guide) [Setup](https://docs.example.com/guide#Setup) <a href=\"https://example.com/new\">old</a> [Other](confluence://pageId/999)"
+	if got := mapLinks(input, links); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
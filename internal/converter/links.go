@@ -0,0 +1,173 @@
+package converter
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// LinkRewriteRule replaces a URL prefix in converted links
+type LinkRewriteRule struct {
+	From string
+	To   string
+}
+
+var (
+	markdownLinkTargetRegex = regexp.MustCompile(`(\]\()([^)\s]+)`)
+	htmlLinkTargetRegex     = regexp.MustCompile(`((?:href|src)=")([^"]+)`)
+	autolinkTargetRegex     = regexp.MustCompile(`(<)(https?://[^>\s]+)`)
+
+	externalMarkdownLinkRegex = regexp.MustCompile(`(!?)\[([^\]]*)\]\((https?://[^)\s]+)\)`)
+	externalHTMLLinkRegex     = regexp.MustCompile(`<a [^>]*href="(https?://[^"]+)"[^>]*>(.*?)</a>`)
+	externalAutolinkRegex     = regexp.MustCompile(`<(https?://[^>\s]+)>`)
+)
+
+// linkTextEscaper escapes the characters that would end or nest a link's text
+var linkTextEscaper = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`)
+
+// linkTargetEscaper escapes the characters that would end a <…> link destination
+var linkTargetEscaper = strings.NewReplacer(`\`, `\\`, "<", `\<`, ">", `\>`)
+
+// EscapeLinkText escapes a title for use as link text or in a heading, so brackets in it cannot form or break a link
+func EscapeLinkText(text string) string {
+	return linkTextEscaper.Replace(text)
+}
+
+// MarkdownLink formats a link to a relative path, escaping the text and wrapping targets that
+// contain spaces, parentheses or angle brackets in <…> so they stay one destination
+func MarkdownLink(text, target string) string {
+	if strings.ContainsAny(target, " ()<>\\") {
+		target = "<" + linkTargetEscaper.Replace(target) + ">"
+	}
+	return "[" + EscapeLinkText(text) + "](" + target + ")"
+}
+
+// LinkPolicy decides which external links are kept, using glob patterns matched against link hosts.
+// When Allow is set only matching hosts are kept; hosts matching Deny are always stripped.
+type LinkPolicy struct {
+	Allow []string
+	Deny  []string
+}
+
+func (p LinkPolicy) denies(host string) bool {
+	if matchesHost(host, p.Deny) {
+		return true
+	}
+	return len(p.Allow) > 0 && !matchesHost(host, p.Allow)
+}
+
+func matchesHost(host string, patterns []string) bool {
+	host = strings.ToLower(host)
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.ToLower(strings.TrimSpace(pattern)), host); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// auditExternalLinks collects links pointing outside the Confluence instance and strips those the policy denies,
+// keeping their link text
+func auditExternalLinks(markdown, baseURL string, policy LinkPolicy) (string, []model.LinkRef) {
+	internalHost := ""
+	if base, err := url.Parse(baseURL); err == nil {
+		internalHost = strings.ToLower(base.Host)
+	}
+
+	var links []model.LinkRef
+	seen := make(map[string]int)
+	check := func(target string) (external, denied bool) {
+		parsed, err := url.Parse(target)
+		if err != nil || parsed.Host == "" || strings.ToLower(parsed.Host) == internalHost {
+			return false, false
+		}
+		denied = policy.denies(parsed.Hostname())
+		if i, ok := seen[target]; ok {
+			links[i].Stripped = links[i].Stripped || denied
+		} else {
+			seen[target] = len(links)
+			links = append(links, model.LinkRef{URL: target, Stripped: denied})
+		}
+		return true, denied
+	}
+
+	markdown = externalMarkdownLinkRegex.ReplaceAllStringFunc(markdown, func(match string) string {
+		parts := externalMarkdownLinkRegex.FindStringSubmatch(match)
+		if _, denied := check(parts[3]); denied {
+			if parts[1] == "!" {
+				return ""
+			}
+			return parts[2]
+		}
+		return match
+	})
+	markdown = externalHTMLLinkRegex.ReplaceAllStringFunc(markdown, func(match string) string {
+		parts := externalHTMLLinkRegex.FindStringSubmatch(match)
+		if _, denied := check(parts[1]); denied {
+			return parts[2]
+		}
+		return match
+	})
+	markdown = externalAutolinkRegex.ReplaceAllStringFunc(markdown, func(match string) string {
+		parts := externalAutolinkRegex.FindStringSubmatch(match)
+		if _, denied := check(parts[1]); denied {
+			return ""
+		}
+		return match
+	})
+
+	return markdown, links
+}
+
+// ParseLinkRewriteRules parses rules in the form old-prefix=new-prefix
+func ParseLinkRewriteRules(values []string) ([]LinkRewriteRule, error) {
+	var rules []LinkRewriteRule
+	for _, value := range values {
+		from, to, ok := strings.Cut(value, "=")
+		from = strings.TrimSpace(from)
+		if !ok || from == "" {
+			return nil, fmt.Errorf("link rewrite rule must be old-prefix=new-prefix, got: %s", value)
+		}
+		rules = append(rules, LinkRewriteRule{From: from, To: strings.TrimSpace(to)})
+	}
+	return rules, nil
+}
+
+// rewriteLinks applies the first matching prefix rule to every link and image target in the markdown
+func rewriteLinks(markdown string, rules []LinkRewriteRule) string {
+	if len(rules) == 0 {
+		return markdown
+	}
+
+	return rewriteLinkTargets(markdown, func(target string) string {
+		return applyLinkRewriteRules(target, rules)
+	})
+}
+
+// rewriteLinkTargets replaces every Markdown link, image, href/src and autolink target with rewrite(target)
+func rewriteLinkTargets(markdown string, rewrite func(target string) string) string {
+	replace := func(re *regexp.Regexp, input string) string {
+		return re.ReplaceAllStringFunc(input, func(match string) string {
+			parts := re.FindStringSubmatch(match)
+			return parts[1] + rewrite(parts[2])
+		})
+	}
+
+	markdown = replace(markdownLinkTargetRegex, markdown)
+	markdown = replace(htmlLinkTargetRegex, markdown)
+	markdown = replace(autolinkTargetRegex, markdown)
+	return markdown
+}
+
+func applyLinkRewriteRules(target string, rules []LinkRewriteRule) string {
+	for _, rule := range rules {
+		if strings.HasPrefix(target, rule.From) {
+			return rule.To + strings.TrimPrefix(target, rule.From)
+		}
+	}
+	return target
+}
@@ -0,0 +1,21 @@
+package converter
+
+import "testing"
+
+func TestMarkdownLink(t *testing.T) {
+	tests := []struct {
+		text, target, want string
+	}{
+		{"Guide", "guide.md", "[Guide](guide.md)"},
+		{"Release [beta]", "../release.md", `[Release \[beta\]](../release.md)`},
+		{`C:\Temp`, "temp.md", `[C:\\Temp](temp.md)`},
+		{"Guide", "../my guide.md", "[Guide](<../my guide.md>)"},
+		{"Guide", "faq (old).md", "[Guide](<faq (old).md>)"},
+		{"Guide", "a<b>.md", `[Guide](<a\<b\>.md>)`},
+	}
+	for _, tt := range tests {
+		if got := MarkdownLink(tt.text, tt.target); got != tt.want {
+			t.Errorf("MarkdownLink(%q, %q) = %q, want %q", tt.text, tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package converter
+
+import (
+	"path/filepath"
+	"regexp"
+)
+
+// pageLinkRegex matches the confluence://pageId/<id> links written for links to other pages
+var pageLinkRegex = regexp.MustCompile(`^confluence://pageId/(\d+)(#.*)?$`)
+
+// ResolveLocalLinks rewrites confluence://pageId/<id> links in the Markdown of the file at fromPath to the
+// relative path of the target page's file in paths, keyed by page ID. Links to pages missing from paths are kept.
+// It reports whether any link was rewritten.
+func ResolveLocalLinks(markdown, fromPath string, paths map[string]string) (string, bool) {
+	changed := false
+	resolved := rewriteLinkTargets(markdown, func(target string) string {
+		match := pageLinkRegex.FindStringSubmatch(target)
+		if match == nil {
+			return target
+		}
+		targetPath, ok := paths[match[1]]
+		if !ok {
+			return target
+		}
+		rel, err := filepath.Rel(filepath.Dir(fromPath), targetPath)
+		if err != nil {
+			return target
+		}
+		changed = true
+		return filepath.ToSlash(rel) + match[2]
+	})
+	return resolved, changed
+}
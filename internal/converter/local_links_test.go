@@ -0,0 +1,25 @@
+package converter
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveLocalLinks(t *testing.T) {
+	paths := map[string]string{
+		"100": filepath.Join("out", "home.md"),
+		"200": filepath.Join("out", "home", "guides", "setup.md"),
+	}
+	from := filepath.Join("out", "home", "intro.md")
+
+	markdown := "See [home](confluence://pageId/100), [setup](confluence://pageId/200#install) and [other](confluence://pageId/300)."
+	got, changed := ResolveLocalLinks(markdown, from, paths)
+	want := "See [home](../home.md), [setup](guides/setup.md#install) and [other](confluence://pageId/300)."
+	if got != want || !changed {
+		t.Fatalf("ResolveLocalLinks() = %q, %v, want %q, true", got, changed, want)
+	}
+
+	if _, changed := ResolveLocalLinks("[other](confluence://pageId/300)", from, paths); changed {
+		t.Fatal("expected no change for a page that was not exported")
+	}
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseMarkdownDocument reads a markdown file written by WithFrontmatter back into a document,
+// so push, sync, and diff can find the Confluence page, space, and version a file came from.
+// The frontmatter is decoded as YAML, so hand-edited files may use any YAML syntax; keys the document
+// has no field for are kept in Custom. Files exported before the round-trip schema (pageId/spaceKey)
+// are accepted as well.
+func ParseMarkdownDocument(content string) (*MarkdownDocument, error) {
+	// Files edited on Windows may use CRLF line endings
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(content, "---\n") {
+		return nil, fmt.Errorf("document has no frontmatter")
+	}
+	end := strings.Index(content[4:], "\n---\n")
+	if end < 0 {
+		return nil, fmt.Errorf("document frontmatter is not terminated")
+	}
+	end += 4
+
+	doc := &MarkdownDocument{
+		Content: strings.TrimPrefix(content[end+len("\n---\n"):], "\n"),
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(content[4:end]), &root); err != nil {
+		return nil, fmt.Errorf("invalid frontmatter: %w", err)
+	}
+	if root.Kind == 0 {
+		return doc, nil
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) != 1 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid frontmatter: expected a mapping of keys to values")
+	}
+
+	mapping := root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i].Value, mapping.Content[i+1]
+		if err := setFrontmatterField(&doc.Frontmatter, key, value); err != nil {
+			return nil, fmt.Errorf("invalid frontmatter line %d: %w", mapping.Content[i].Line, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// confluenceYAML is the confluence frontmatter section with the keys of both the current and the legacy schema
+type confluenceYAML struct {
+	PageID         string `yaml:"page_id"`
+	LegacyPageID   string `yaml:"pageId"`
+	SpaceKey       string `yaml:"space"`
+	LegacySpaceKey string `yaml:"spaceKey"`
+	ParentID       string `yaml:"parent_id"`
+	LegacyParentID string `yaml:"parentId"`
+	Version        int    `yaml:"version"`
+	URL            string `yaml:"url"`
+}
+
+// workflowYAML is the workflow frontmatter section, with the timestamp kept as written
+type workflowYAML struct {
+	Name       string   `yaml:"name"`
+	State      string   `yaml:"state"`
+	Approvers  []string `yaml:"approvers"`
+	ApprovedAt string   `yaml:"approvedAt"`
+}
+
+// exportYAML is the export frontmatter section, with the timestamp kept as written
+type exportYAML struct {
+	RunID       string `yaml:"runId"`
+	Tool        string `yaml:"tool"`
+	ToolVersion string `yaml:"toolVersion"`
+	Source      string `yaml:"source"`
+	ExportedAt  string `yaml:"exportedAt"`
+}
+
+func setFrontmatterField(fm *Frontmatter, key string, value *yaml.Node) error {
+	switch key {
+	case "title":
+		return value.Decode(&fm.Title)
+	case "author":
+		return value.Decode(&fm.Author)
+	case "date", "exportedAt":
+		parsed, err := decodeTimestamp(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		if key == "date" {
+			fm.Date = parsed
+		} else {
+			fm.ExportedAt = parsed
+		}
+	case "labels":
+		return decodeStringList(value, &fm.Labels)
+	case "aliases":
+		return decodeStringList(value, &fm.Aliases)
+	case "confluence":
+		var ref confluenceYAML
+		if err := value.Decode(&ref); err != nil {
+			return fmt.Errorf("invalid confluence: %w", err)
+		}
+		fm.Confluence = ConfluenceRef{
+			PageID:   cmp.Or(ref.PageID, ref.LegacyPageID),
+			SpaceKey: cmp.Or(ref.SpaceKey, ref.LegacySpaceKey),
+			ParentID: cmp.Or(ref.ParentID, ref.LegacyParentID),
+			Version:  ref.Version,
+			URL:      ref.URL,
+		}
+	case "workflow":
+		var ref workflowYAML
+		if err := value.Decode(&ref); err != nil {
+			return fmt.Errorf("invalid workflow: %w", err)
+		}
+		fm.Workflow = WorkflowRef{Name: ref.Name, State: ref.State, Approvers: ref.Approvers}
+		if ref.ApprovedAt != "" {
+			parsed, err := time.Parse(time.RFC3339, ref.ApprovedAt)
+			if err != nil {
+				return fmt.Errorf("invalid approvedAt: %w", err)
+			}
+			fm.Workflow.ApprovedAt = parsed
+		}
+	case "export":
+		var ref exportYAML
+		if err := value.Decode(&ref); err != nil {
+			return fmt.Errorf("invalid export: %w", err)
+		}
+		fm.Export = ExportRef{RunID: ref.RunID, Tool: ref.Tool, ToolVersion: ref.ToolVersion, Source: ref.Source}
+		if ref.ExportedAt != "" {
+			parsed, err := time.Parse(time.RFC3339, ref.ExportedAt)
+			if err != nil {
+				return fmt.Errorf("invalid exportedAt: %w", err)
+			}
+			fm.Export.ExportedAt = parsed
+		}
+	default:
+		var custom any
+		if err := value.Decode(&custom); err != nil {
+			return fmt.Errorf("invalid %s: %w", key, err)
+		}
+		if fm.Custom == nil {
+			fm.Custom = make(map[string]any)
+		}
+		fm.Custom[key] = custom
+	}
+	return nil
+}
+
+// decodeTimestamp reads an RFC 3339 timestamp, quoted as WithFrontmatter writes it or unquoted
+func decodeTimestamp(value *yaml.Node) (time.Time, error) {
+	if value.Kind != yaml.ScalarNode {
+		return time.Time{}, fmt.Errorf("expected a timestamp")
+	}
+	return time.Parse(time.RFC3339, value.Value)
+}
+
+// decodeStringList reads a block or flow sequence of strings, or a single string as a one-item list
+func decodeStringList(value *yaml.Node, list *[]string) error {
+	if value.Kind == yaml.ScalarNode {
+		var item string
+		if err := value.Decode(&item); err != nil {
+			return err
+		}
+		if item != "" {
+			*list = []string{item}
+		}
+		return nil
+	}
+	return value.Decode(list)
+}
+
+// yamlValue renders a custom frontmatter value on one line with the YAML encoder, so it parses back
+// to the same value: strings are quoted when needed and collections are written in flow style
+func yamlValue(value any) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return "", err
+	}
+	oneLine(&node)
+	out, err := yaml.Marshal(&node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// oneLine styles a node so the encoder writes it on a single line
+func oneLine(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.SequenceNode, yaml.MappingNode:
+		node.Style |= yaml.FlowStyle
+	case yaml.ScalarNode:
+		if strings.Contains(node.Value, "\n") {
+			node.Style = yaml.DoubleQuotedStyle
+		}
+	}
+	for _, child := range node.Content {
+		oneLine(child)
+	}
+}
+
+// YAMLString renders s as a double-quoted YAML scalar
+func YAMLString(s string) string {
+	out, _ := yaml.Marshal(&yaml.Node{Kind: yaml.ScalarNode, Style: yaml.DoubleQuotedStyle, Value: s})
+	return strings.TrimSuffix(string(out), "\n")
+}
+
+// YAMLFlowList renders values as a YAML flow sequence of double-quoted scalars, e.g. ["a", "b"]
+func YAMLFlowList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = YAMLString(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
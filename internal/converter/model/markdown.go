@@ -2,6 +2,8 @@ package model
 
 import (
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
@@ -10,9 +12,16 @@ import (
 
 // MarkdownDocument represents the output document structure
 type MarkdownDocument struct {
-	Frontmatter Frontmatter `yaml:",inline"`
-	Content     string      `yaml:"-"`
-	Images      []ImageRef  `yaml:"-"`
+	Frontmatter     Frontmatter `yaml:",inline"`
+	Content         string      `yaml:"-"`
+	Images          []ImageRef  `yaml:"-"`
+	ExternalLinks   []LinkRef   `yaml:"-"`
+	UnresolvedUsers []string    `yaml:"-"` // accountIDs of mentioned users that were deleted or anonymized
+
+	AccessibilityIssues []AccessibilityIssue `yaml:"-"`
+	Fidelity            Fidelity             `yaml:"-"`
+	Redactions          map[string]int       `yaml:"-"` // matches replaced per redaction rule name
+	Redact              func(string) string  `yaml:"-"` // applied by Render to the whole file, nil to leave it unchanged
 }
 
 // Frontmatter represents YAML frontmatter for the Markdown document
@@ -21,18 +30,51 @@ type Frontmatter struct {
 	Author     string         `yaml:"author"`
 	Date       time.Time      `yaml:"date"`
 	Labels     []string       `yaml:"labels,omitempty"`
+	Aliases    []string       `yaml:"aliases,omitempty"` // previous URL paths of a renamed page (Hugo/Docusaurus style)
 	Confluence ConfluenceRef  `yaml:"confluence"`
+	Workflow   WorkflowRef    `yaml:"workflow,omitempty"`   // only set for pages under a Comala workflow
+	ExportedAt time.Time      `yaml:"exportedAt,omitempty"` // only set with --export-timestamp, changes every run
+	Export     ExportRef      `yaml:"export,omitempty"`     // only set with --provenance, changes every run
 	Custom     map[string]any `yaml:",inline,omitempty"`
 }
 
-// ConfluenceRef contains reference information back to the original Confluence page
+// ConfluenceRef contains reference information back to the original Confluence page.
+// These keys form the round-trip schema read back by ParseMarkdownDocument.
 type ConfluenceRef struct {
-	PageID   string `yaml:"pageId"`
-	SpaceKey string `yaml:"spaceKey"`
+	PageID   string `yaml:"page_id"`
+	SpaceKey string `yaml:"space"`
+	ParentID string `yaml:"parent_id,omitempty"` // parent page, used by push --create to publish the file again
 	Version  int    `yaml:"version"`
 	URL      string `yaml:"url"`
 }
 
+// WorkflowRef is the Comala Document Management workflow state of the page
+type WorkflowRef struct {
+	Name       string    `yaml:"name"`
+	State      string    `yaml:"state"`
+	Approvers  []string  `yaml:"approvers,omitempty,flow"`
+	ApprovedAt time.Time `yaml:"approvedAt,omitempty"`
+}
+
+// IsZero reports whether the page has no workflow
+func (w WorkflowRef) IsZero() bool {
+	return w.Name == "" && w.State == ""
+}
+
+// ExportRef records which export run mirrored the page, with which tool version and from which site
+type ExportRef struct {
+	RunID       string    `yaml:"runId"`
+	Tool        string    `yaml:"tool"`
+	ToolVersion string    `yaml:"toolVersion"`
+	Source      string    `yaml:"source"`
+	ExportedAt  time.Time `yaml:"exportedAt"`
+}
+
+// IsZero reports whether the document has no provenance
+func (e ExportRef) IsZero() bool {
+	return e.RunID == ""
+}
+
 // ImageRef represents a reference to a downloaded image
 type ImageRef struct {
 	OriginalURL string `json:"originalUrl"`
@@ -41,32 +83,139 @@ type ImageRef struct {
 	Size        int64  `json:"size"`
 }
 
+// LinkRef represents an external link found in the converted content
+type LinkRef struct {
+	URL      string `json:"url"`
+	Stripped bool   `json:"stripped,omitempty"`
+}
+
+// Fidelity is a heuristic measure of how much of a page's Confluence markup was converted natively,
+// so pages that lost the most can be reviewed first
+type Fidelity struct {
+	Score              float64  `json:"score"`                        // 0 to 1, 1 when every element was converted
+	Elements           int      `json:"elements"`                     // macros, images and links in the page source
+	UnsupportedMacros  []string `json:"unsupportedMacros,omitempty"`  // macros written as a comment, warning or raw dump, or dropped
+	UnresolvedLinks    int      `json:"unresolvedLinks,omitempty"`    // links to pages, attachments and users written as plain text
+	MissingAttachments []string `json:"missingAttachments,omitempty"` // images whose attachment is not listed on the page
+}
+
+// Scored returns the fidelity with its score computed from the element and problem counts
+func (f Fidelity) Scored() Fidelity {
+	f.Score = 1
+	if f.Elements > 0 {
+		lost := len(f.UnsupportedMacros) + f.UnresolvedLinks + len(f.MissingAttachments)
+		f.Score = math.Max(0, math.Round((1-float64(lost)/float64(f.Elements))*100)/100)
+	}
+	return f
+}
+
+// Combine adds the counts of another document, e.g. an inlined child page, and scores the total
+func (f Fidelity) Combine(other Fidelity) Fidelity {
+	f.Elements += other.Elements
+	f.UnsupportedMacros = append(append([]string(nil), f.UnsupportedMacros...), other.UnsupportedMacros...)
+	f.UnresolvedLinks += other.UnresolvedLinks
+	f.MissingAttachments = append(append([]string(nil), f.MissingAttachments...), other.MissingAttachments...)
+	return f.Scored()
+}
+
+// AccessibilityIssue is a problem found by the accessibility check, located by its line in the content
+type AccessibilityIssue struct {
+	Kind    string `json:"kind"` // "image-alt", "heading-order" or "empty-link"
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+	Fixed   bool   `json:"fixed,omitempty"`
+}
+
+// Render returns the file content, prefixed with frontmatter when requested, after applying Redact
+func (md *MarkdownDocument) Render(withFrontmatter bool) (string, error) {
+	content := md.Content
+	if withFrontmatter {
+		rendered, err := md.WithFrontmatter()
+		if err != nil {
+			return "", err
+		}
+		content = rendered
+	}
+	if md.Redact != nil {
+		content = md.Redact(content)
+	}
+	return content, nil
+}
+
 func (md *MarkdownDocument) WithFrontmatter() (string, error) {
 	var builder strings.Builder
 
 	// Write YAML frontmatter
 	builder.WriteString("---\n")
-	builder.WriteString(fmt.Sprintf("title: %q\n", md.Frontmatter.Title))
-	builder.WriteString(fmt.Sprintf("author: %q\n", md.Frontmatter.Author))
-	builder.WriteString(fmt.Sprintf("date: %q\n", md.Frontmatter.Date.Format(time.RFC3339)))
+	builder.WriteString(fmt.Sprintf("title: %s\n", YAMLString(md.Frontmatter.Title)))
+	builder.WriteString(fmt.Sprintf("author: %s\n", YAMLString(md.Frontmatter.Author)))
+	builder.WriteString(fmt.Sprintf("date: %s\n", YAMLString(md.Frontmatter.Date.Format(time.RFC3339))))
 
 	if len(md.Frontmatter.Labels) > 0 {
 		builder.WriteString("labels:\n")
 		for _, label := range md.Frontmatter.Labels {
-			builder.WriteString(fmt.Sprintf("  - %q\n", label))
+			builder.WriteString(fmt.Sprintf("  - %s\n", YAMLString(label)))
+		}
+	}
+
+	if len(md.Frontmatter.Aliases) > 0 {
+		builder.WriteString("aliases:\n")
+		for _, alias := range md.Frontmatter.Aliases {
+			builder.WriteString(fmt.Sprintf("  - %s\n", YAMLString(alias)))
 		}
 	}
 
 	// Confluence reference
 	builder.WriteString("confluence:\n")
-	builder.WriteString(fmt.Sprintf("  pageId: %q\n", md.Frontmatter.Confluence.PageID))
-	builder.WriteString(fmt.Sprintf("  spaceKey: %q\n", md.Frontmatter.Confluence.SpaceKey))
+	builder.WriteString(fmt.Sprintf("  page_id: %s\n", YAMLString(md.Frontmatter.Confluence.PageID)))
+	builder.WriteString(fmt.Sprintf("  space: %s\n", YAMLString(md.Frontmatter.Confluence.SpaceKey)))
+	if md.Frontmatter.Confluence.ParentID != "" {
+		builder.WriteString(fmt.Sprintf("  parent_id: %s\n", YAMLString(md.Frontmatter.Confluence.ParentID)))
+	}
 	builder.WriteString(fmt.Sprintf("  version: %d\n", md.Frontmatter.Confluence.Version))
-	builder.WriteString(fmt.Sprintf("  url: %q\n", md.Frontmatter.Confluence.URL))
+	builder.WriteString(fmt.Sprintf("  url: %s\n", YAMLString(md.Frontmatter.Confluence.URL)))
+
+	if workflow := md.Frontmatter.Workflow; !workflow.IsZero() {
+		builder.WriteString("workflow:\n")
+		builder.WriteString(fmt.Sprintf("  name: %s\n", YAMLString(workflow.Name)))
+		builder.WriteString(fmt.Sprintf("  state: %s\n", YAMLString(workflow.State)))
+		if len(workflow.Approvers) > 0 {
+			builder.WriteString(fmt.Sprintf("  approvers: %s\n", YAMLFlowList(workflow.Approvers)))
+		}
+		if !workflow.ApprovedAt.IsZero() {
+			builder.WriteString(fmt.Sprintf("  approvedAt: %s\n", YAMLString(workflow.ApprovedAt.UTC().Format(time.RFC3339))))
+		}
+	}
 
-	// Custom fields
-	for key, value := range md.Frontmatter.Custom {
-		builder.WriteString(fmt.Sprintf("%s: %v\n", key, value))
+	if !md.Frontmatter.ExportedAt.IsZero() {
+		builder.WriteString(fmt.Sprintf("exportedAt: %s\n", YAMLString(md.Frontmatter.ExportedAt.UTC().Format(time.RFC3339))))
+	}
+
+	if export := md.Frontmatter.Export; !export.IsZero() {
+		builder.WriteString("export:\n")
+		builder.WriteString(fmt.Sprintf("  runId: %s\n", YAMLString(export.RunID)))
+		builder.WriteString(fmt.Sprintf("  tool: %s\n", YAMLString(export.Tool)))
+		builder.WriteString(fmt.Sprintf("  toolVersion: %s\n", YAMLString(export.ToolVersion)))
+		builder.WriteString(fmt.Sprintf("  source: %s\n", YAMLString(export.Source)))
+		builder.WriteString(fmt.Sprintf("  exportedAt: %s\n", YAMLString(export.ExportedAt.UTC().Format(time.RFC3339))))
+	}
+
+	// Custom fields, sorted so repeated exports are byte-identical
+	keys := make([]string, 0, len(md.Frontmatter.Custom))
+	for key := range md.Frontmatter.Custom {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name, err := yamlValue(key)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode frontmatter key %s: %w", key, err)
+		}
+		value, err := yamlValue(md.Frontmatter.Custom[key])
+		if err != nil {
+			return "", fmt.Errorf("failed to encode frontmatter %s: %w", key, err)
+		}
+		builder.WriteString(fmt.Sprintf("%s: %s\n", name, value))
 	}
 
 	builder.WriteString("---\n\n")
@@ -84,15 +233,24 @@ func NewMarkdownDocument(page *model.ConfluencePage, baseURL string) (*MarkdownD
 		return nil, fmt.Errorf("failed to generate page URL: %w", err)
 	}
 
+	labels := page.GetLabelNames()
+	sort.Strings(labels)
+
+	parentID := ""
+	if n := len(page.AncestorIDs); n > 0 {
+		parentID = page.AncestorIDs[n-1]
+	}
+
 	doc := &MarkdownDocument{
 		Frontmatter: Frontmatter{
 			Title:  page.Title,
 			Author: page.CreatedBy.DisplayName,
 			Date:   page.UpdatedAt,
-			Labels: page.GetLabelNames(),
+			Labels: labels,
 			Confluence: ConfluenceRef{
 				PageID:   page.ID,
 				SpaceKey: page.SpaceKey,
+				ParentID: parentID,
 				Version:  page.Version,
 				URL:      pageURL,
 			},
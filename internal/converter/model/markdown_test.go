@@ -1,6 +1,7 @@
 package model
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -36,7 +37,8 @@ func TestMarkdownDocumentWithFrontmatter(t *testing.T) {
 		"author: \"Author\"",
 		"date: \"2024-01-02T03:04:05Z\"",
 		"- \"one\"",
-		"pageId: \"123\"",
+		"page_id: \"123\"",
+		"space: \"SPACE\"",
 		"custom: value",
 		"Body",
 	}
@@ -81,3 +83,199 @@ func TestNewMarkdownDocument(t *testing.T) {
 		t.Fatalf("unexpected labels: %#v", doc.Frontmatter.Labels)
 	}
 }
+
+func TestWithFrontmatterIsDeterministic(t *testing.T) {
+	doc := &MarkdownDocument{
+		Frontmatter: Frontmatter{
+			Title:  "Sample",
+			Custom: map[string]any{"zeta": 1, "alpha": 2, "mid": 3},
+		},
+	}
+
+	out, err := doc.WithFrontmatter()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "alpha: 2\nmid: 3\nzeta: 1\n") {
+		t.Fatalf("expected custom keys in sorted order, got %q", out)
+	}
+	if strings.Contains(out, "exportedAt") {
+		t.Fatalf("expected no export timestamp by default, got %q", out)
+	}
+
+	doc.Frontmatter.ExportedAt = time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	if out, _ := doc.WithFrontmatter(); !strings.Contains(out, "exportedAt: \"2024-05-06T07:08:09Z\"") {
+		t.Fatalf("expected export timestamp, got %q", out)
+	}
+}
+
+func TestParseMarkdownDocumentRoundTrip(t *testing.T) {
+	doc := &MarkdownDocument{
+		Frontmatter: Frontmatter{
+			Title:   "Sample: \"quoted\"",
+			Author:  "Author",
+			Date:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Labels:  []string{"one", "two"},
+			Aliases: []string{"/docs/old-title/"},
+			Confluence: ConfluenceRef{
+				PageID:   "123",
+				SpaceKey: "SPACE",
+				ParentID: "100",
+				Version:  5,
+				URL:      "https://example/wiki/spaces/SPACE/pages/123/Sample",
+			},
+			Workflow: WorkflowRef{
+				Name:       "Review",
+				State:      "Approved",
+				Approvers:  []string{"Jane \"JD\" Doe", "Raj"},
+				ApprovedAt: time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC),
+			},
+			Export: ExportRef{
+				RunID:       "20240105T060708Z-1a2b3c4d",
+				Tool:        "confluence-md",
+				ToolVersion: "v1.2.3",
+				Source:      "https://example",
+				ExportedAt:  time.Date(2024, 1, 5, 6, 7, 8, 0, time.UTC),
+			},
+			Custom: map[string]any{"custom": "value"},
+		},
+		Content: "# Body\n\ntext",
+	}
+
+	out, err := doc.WithFrontmatter()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	parsed, err := ParseMarkdownDocument(out)
+	if err != nil {
+		t.Fatalf("ParseMarkdownDocument() error = %v", err)
+	}
+	if parsed.Frontmatter.Title != doc.Frontmatter.Title || !parsed.Frontmatter.Date.Equal(doc.Frontmatter.Date) {
+		t.Fatalf("unexpected frontmatter: %#v", parsed.Frontmatter)
+	}
+	if parsed.Frontmatter.Confluence != doc.Frontmatter.Confluence {
+		t.Fatalf("unexpected confluence ref: %#v", parsed.Frontmatter.Confluence)
+	}
+	if strings.Join(parsed.Frontmatter.Labels, ",") != "one,two" || parsed.Frontmatter.Custom["custom"] != "value" {
+		t.Fatalf("unexpected labels or custom fields: %#v", parsed.Frontmatter)
+	}
+	if workflow := parsed.Frontmatter.Workflow; workflow.State != "Approved" || strings.Join(workflow.Approvers, ",") != "Jane \"JD\" Doe,Raj" || !workflow.ApprovedAt.Equal(doc.Frontmatter.Workflow.ApprovedAt) {
+		t.Fatalf("unexpected workflow: %#v", workflow)
+	}
+	if parsed.Frontmatter.Export != doc.Frontmatter.Export {
+		t.Fatalf("unexpected export ref: %#v", parsed.Frontmatter.Export)
+	}
+	if strings.Join(parsed.Frontmatter.Aliases, ",") != "/docs/old-title/" {
+		t.Fatalf("unexpected aliases: %#v", parsed.Frontmatter.Aliases)
+	}
+	if parsed.Content != "# Body\n\ntext" {
+		t.Fatalf("unexpected content: %q", parsed.Content)
+	}
+
+	crlf, err := ParseMarkdownDocument(strings.ReplaceAll(out, "\n", "\r\n"))
+	if err != nil {
+		t.Fatalf("ParseMarkdownDocument() with CRLF line endings error = %v", err)
+	}
+	if crlf.Frontmatter.Confluence != doc.Frontmatter.Confluence || crlf.Content != parsed.Content {
+		t.Fatalf("unexpected CRLF document: %#v", crlf)
+	}
+
+	legacy, err := ParseMarkdownDocument("---\ntitle: \"Old\"\nconfluence:\n  pageId: \"9\"\n  spaceKey: \"OLD\"\n  version: 2\n---\n\nbody")
+	if err != nil {
+		t.Fatalf("ParseMarkdownDocument() legacy error = %v", err)
+	}
+	if legacy.Frontmatter.Confluence.PageID != "9" || legacy.Frontmatter.Confluence.SpaceKey != "OLD" || legacy.Frontmatter.Confluence.Version != 2 {
+		t.Fatalf("unexpected legacy confluence ref: %#v", legacy.Frontmatter.Confluence)
+	}
+
+	if _, err := ParseMarkdownDocument("no frontmatter"); err == nil {
+		t.Fatalf("expected error for document without frontmatter")
+	}
+}
+
+func TestParseRenderParseRoundTrip(t *testing.T) {
+	input := "---\n" +
+		"title: Hand edited\n" +
+		"date: 2024-01-02T03:04:05Z\n" +
+		"labels: [one, \"two words\"]\n" +
+		"confluence:\n" +
+		"  page_id: \"123\"\n" +
+		"  space: DOCS\n" +
+		"  version: 4\n" +
+		"description: \"a: b # c\"\n" +
+		"notes: |\n" +
+		"  first line\n" +
+		"  second line\n" +
+		"owners:\n" +
+		"  - Ada\n" +
+		"  - \"Grace: admin\"\n" +
+		"weight: 3\n" +
+		"---\n\nbody"
+
+	first, err := ParseMarkdownDocument(input)
+	if err != nil {
+		t.Fatalf("ParseMarkdownDocument() error = %v", err)
+	}
+	rendered, err := first.WithFrontmatter()
+	if err != nil {
+		t.Fatalf("WithFrontmatter() error = %v", err)
+	}
+	second, err := ParseMarkdownDocument(rendered)
+	if err != nil {
+		t.Fatalf("ParseMarkdownDocument() of rendered document error = %v\n%s", err, rendered)
+	}
+
+	if !reflect.DeepEqual(first.Frontmatter, second.Frontmatter) {
+		t.Fatalf("frontmatter changed in the round trip:\n%#v\n%#v\n%s", first.Frontmatter, second.Frontmatter, rendered)
+	}
+	if got := second.Frontmatter.Custom["description"]; got != "a: b # c" {
+		t.Errorf("description = %#v, want %q", got, "a: b # c")
+	}
+	if got := second.Frontmatter.Custom["notes"]; got != "first line\nsecond line\n" {
+		t.Errorf("notes = %#v", got)
+	}
+	if got := second.Frontmatter.Custom["owners"]; !reflect.DeepEqual(got, []any{"Ada", "Grace: admin"}) {
+		t.Errorf("owners = %#v", got)
+	}
+	if got := second.Frontmatter.Custom["weight"]; got != 3 {
+		t.Errorf("weight = %#v, want 3", got)
+	}
+	if got := strings.Join(second.Frontmatter.Labels, ","); got != "one,two words" {
+		t.Errorf("labels = %q", got)
+	}
+	if second.Frontmatter.Confluence.PageID != "123" || second.Frontmatter.Confluence.Version != 4 || second.Content != "body" {
+		t.Errorf("unexpected document: %#v", second)
+	}
+
+	if _, err := ParseMarkdownDocument("---\ntitle: [unterminated\n---\n"); err == nil {
+		t.Errorf("expected an error for invalid YAML")
+	}
+}
+
+func TestYAMLString(t *testing.T) {
+	tests := map[string]string{
+		"plain":          `"plain"`,
+		`say "hi" \ bye`: `"say \"hi\" \\ bye"`,
+		"bell\x07":       `"bell\a"`,
+		"next\u0085line": `"next\Nline"`,
+	}
+	for value, want := range tests {
+		if got := YAMLString(value); got != want {
+			t.Errorf("YAMLString(%q) = %s, want %s", value, got, want)
+		}
+	}
+
+	doc := &MarkdownDocument{Frontmatter: Frontmatter{Title: "next\u0085line\x01", Labels: []string{"tab\there"}}}
+	out, err := doc.WithFrontmatter()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	parsed, err := ParseMarkdownDocument(out)
+	if err != nil {
+		t.Fatalf("ParseMarkdownDocument() error = %v", err)
+	}
+	if parsed.Frontmatter.Title != doc.Frontmatter.Title || strings.Join(parsed.Frontmatter.Labels, ",") != "tab\there" {
+		t.Fatalf("unexpected round trip: %#v", parsed.Frontmatter)
+	}
+}
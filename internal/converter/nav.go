@@ -0,0 +1,47 @@
+package converter
+
+import (
+	"fmt"
+	"strings"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// NavEntry is a page listed in a generated navigation file
+type NavEntry struct {
+	Title string
+	Path  string // link target relative to the navigation file, empty when the page has no file of its own
+	Depth int
+}
+
+// RenderSidebar renders a navigation document with the space shortcuts followed by the page hierarchy.
+// Shortcut URLs relative to the Confluence instance are made absolute using baseURL.
+func RenderSidebar(title, baseURL string, shortcuts []confluenceModel.SpaceShortcut, entries []NavEntry) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# %s\n", title)
+
+	if len(shortcuts) > 0 {
+		builder.WriteString("\n## Shortcuts\n\n")
+		for _, shortcut := range shortcuts {
+			link := shortcut.URL
+			if strings.HasPrefix(link, "/") {
+				link = strings.TrimSuffix(baseURL, "/") + link
+			}
+			fmt.Fprintf(&builder, "- [%s](%s)\n", shortcut.Title, link)
+		}
+	}
+
+	if len(entries) > 0 {
+		builder.WriteString("\n## Pages\n\n")
+		for _, entry := range entries {
+			indent := strings.Repeat("  ", entry.Depth)
+			if entry.Path == "" {
+				fmt.Fprintf(&builder, "%s- %s\n", indent, entry.Title)
+				continue
+			}
+			fmt.Fprintf(&builder, "%s- [%s](%s)\n", indent, entry.Title, entry.Path)
+		}
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,26 @@
+package converter
+
+import (
+	"testing"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestRenderSidebar(t *testing.T) {
+	shortcuts := []confluenceModel.SpaceShortcut{
+		{Title: "Runbook", URL: "/display/OPS/Runbook"},
+		{Title: "Status", URL: "https://status.example.com"},
+	}
+	entries := []NavEntry{
+		{Title: "Home", Path: "home.md"},
+		{Title: "Guides", Path: "home/guides.md", Depth: 1},
+		{Title: "Inlined", Depth: 2},
+	}
+
+	got := RenderSidebar("OPS", "https://example.com/", shortcuts, entries)
+	want := "# OPS\n\n## Shortcuts\n\n- [Runbook](https://example.com/display/OPS/Runbook)\n- [Status](https://status.example.com)\n\n" +
+		"## Pages\n\n- [Home](home.md)\n  - [Guides](home/guides.md)\n    - Inlined\n"
+	if got != want {
+		t.Fatalf("RenderSidebar() = %q, want %q", got, want)
+	}
+}
@@ -6,8 +6,8 @@ import (
 	"strings"
 	"text/template"
 
-	"github.com/gosimple/slug"
 	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
 )
 
 // OutputNamer generates a filename for a converted Confluence page.
@@ -22,8 +22,11 @@ func (f outputNamerFunc) FileName(page *confluenceModel.ConfluencePage) (string,
 }
 
 // DefaultOutputNamer returns the built-in filename generator.
-func DefaultOutputNamer() OutputNamer {
-	return outputNamerFunc(defaultFileName)
+func DefaultOutputNamer(opts ...NamingOption) OutputNamer {
+	config := newNamingConfig(opts)
+	return outputNamerFunc(func(page *confluenceModel.ConfluencePage) (string, error) {
+		return defaultFileName(config.titleRules.renamed(page), config.slugger)
+	})
 }
 
 // GenerateFileName resolves the filename for a page using the provided namer or the default.
@@ -62,38 +65,44 @@ func GenerateFileName(page *confluenceModel.ConfluencePage, namer OutputNamer) (
 	return name, nil
 }
 
-func defaultFileName(page *confluenceModel.ConfluencePage) (string, error) {
+func defaultFileName(page *confluenceModel.ConfluencePage, slugger plugin.Slugger) (string, error) {
 	title := strings.TrimSpace(page.Title)
-	slugified := slug.MakeLang(title, "en")
+	slugified := slugger.Slug(title, page)
 	if slugified == "" {
 		slugified = "untitled"
 	}
 	return slugified + ".md", nil
 }
 
-var templateFuncMap = template.FuncMap{
-	"slug": func(value string) string {
-		return slug.MakeLang(value, "en")
-	},
+// templateFuncMap returns the template functions; slug uses the configured language, not the page's
+func templateFuncMap(slugger plugin.Slugger) template.FuncMap {
+	return template.FuncMap{
+		"slug": func(value string) string {
+			return slugger.Slug(value, nil)
+		},
+	}
 }
 
 // TemplateOutputNamer renders filenames from a text/template string.
 type TemplateOutputNamer struct {
-	tmpl *template.Template
+	tmpl       *template.Template
+	slugger    plugin.Slugger
+	titleRules TitleRules
 }
 
 // NewTemplateOutputNamer creates a template-driven output namer.
-func NewTemplateOutputNamer(tmpl string) (OutputNamer, error) {
+func NewTemplateOutputNamer(tmpl string, opts ...NamingOption) (OutputNamer, error) {
 	if strings.TrimSpace(tmpl) == "" {
 		return nil, fmt.Errorf("template cannot be empty")
 	}
 
-	parsed, err := template.New("output_name").Funcs(templateFuncMap).Parse(tmpl)
+	config := newNamingConfig(opts)
+	parsed, err := template.New("output_name").Funcs(templateFuncMap(config.slugger)).Parse(tmpl)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse output name template: %w", err)
 	}
 
-	return &TemplateOutputNamer{tmpl: parsed}, nil
+	return &TemplateOutputNamer{tmpl: parsed, slugger: config.slugger, titleRules: config.titleRules}, nil
 }
 
 func (n *TemplateOutputNamer) FileName(page *confluenceModel.ConfluencePage) (string, error) {
@@ -101,9 +110,11 @@ func (n *TemplateOutputNamer) FileName(page *confluenceModel.ConfluencePage) (st
 		return "", fmt.Errorf("page cannot be nil")
 	}
 
+	page = n.titleRules.renamed(page)
 	data := outputTemplateData{
 		Page:      page,
-		SlugTitle: slug.MakeLang(strings.TrimSpace(page.Title), "en"),
+		SlugTitle: n.slugger.Slug(strings.TrimSpace(page.Title), page),
+		SpaceKey:  page.SpaceKey,
 	}
 
 	var builder strings.Builder
@@ -117,4 +128,5 @@ func (n *TemplateOutputNamer) FileName(page *confluenceModel.ConfluencePage) (st
 type outputTemplateData struct {
 	Page      *confluenceModel.ConfluencePage
 	SlugTitle string
+	SpaceKey  string
 }
@@ -58,3 +58,18 @@ func TestGenerateFileName_TemplateAddsExtension(t *testing.T) {
 		t.Fatalf("expected docs.md, got %q", name)
 	}
 }
+
+func TestGenerateFileName_TemplateSpaceKey(t *testing.T) {
+	namer, err := NewTemplateOutputNamer("{{ .SpaceKey }}-{{ .SlugTitle }}")
+	if err != nil {
+		t.Fatalf("NewTemplateOutputNamer returned error: %v", err)
+	}
+
+	name, err := GenerateFileName(&confluenceModel.ConfluencePage{Title: "Home", SpaceKey: "DOCS"}, namer)
+	if err != nil {
+		t.Fatalf("GenerateFileName returned error: %v", err)
+	}
+	if name != "DOCS-home.md" {
+		t.Fatalf("expected DOCS-home.md, got %q", name)
+	}
+}
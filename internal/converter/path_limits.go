@@ -0,0 +1,112 @@
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// WindowsMaxPath is the classic Windows MAX_PATH limit, including the drive and the terminating NUL
+	WindowsMaxPath = 259
+
+	// pathHashLength is the number of hex characters of the hash appended to shortened segments
+	pathHashLength = 8
+
+	// minShortSegment is the shortest a segment is cut to: a few characters of the title, a dash and the hash
+	minShortSegment = 4 + 1 + pathHashLength
+)
+
+// PathLimits bounds the length of output paths, which deep hierarchies with long titles easily exceed on Windows.
+// Shortened names keep the start of the original name and end in a hash of the full name, so they stay unique
+// and are the same on every export.
+type PathLimits struct {
+	MaxSegment int // longest file or directory name in characters, 0 for no limit
+	MaxPath    int // longest absolute path in characters, 0 for no limit
+}
+
+// Enabled reports whether any limit is set
+func (l PathLimits) Enabled() bool {
+	return l.MaxSegment > 0 || l.MaxPath > 0
+}
+
+// Shorten fits relPath below baseDir into the limits and reports whether it changed. File names are cut
+// first; directories are cut starting with the deepest, so the fewest other pages are affected.
+// Paths that cannot be made short enough are shortened as far as possible.
+func (l PathLimits) Shorten(baseDir, relPath string) (string, bool) {
+	if !l.Enabled() {
+		return relPath, false
+	}
+
+	segments := strings.Split(filepath.Clean(relPath), string(filepath.Separator))
+	lengths := make([]int, len(segments))
+	for i, segment := range segments {
+		lengths[i] = len([]rune(segment))
+		if l.MaxSegment > 0 && lengths[i] > l.MaxSegment {
+			lengths[i] = max(l.MaxSegment, minShortSegment+len(filepath.Ext(segment)))
+		}
+	}
+
+	if l.MaxPath > 0 {
+		prefix := baseDir
+		if abs, err := filepath.Abs(baseDir); err == nil {
+			prefix = abs
+		}
+		total := len([]rune(prefix))
+		for _, length := range lengths {
+			total += 1 + length
+		}
+
+		// File name first, then directories from the deepest up
+		for i := len(segments) - 1; i >= 0 && total > l.MaxPath; i-- {
+			shortest := minShortSegment
+			if i == len(segments)-1 {
+				shortest += len(filepath.Ext(segments[i]))
+			}
+			if lengths[i] <= shortest {
+				continue
+			}
+			cut := min(total-l.MaxPath, lengths[i]-shortest)
+			lengths[i] -= cut
+			total -= cut
+		}
+	}
+
+	changed := false
+	for i, segment := range segments {
+		if lengths[i] < len([]rune(segment)) {
+			segments[i] = shortenSegment(segment, lengths[i], i == len(segments)-1)
+			changed = true
+		}
+	}
+	if !changed {
+		return relPath, false
+	}
+	return filepath.Join(segments...), true
+}
+
+// shortenSegment cuts a name to length characters, keeping the start and the file extension and
+// appending a hash of the full name
+func shortenSegment(segment string, length int, isFile bool) string {
+	ext := ""
+	if isFile {
+		ext = filepath.Ext(segment)
+	}
+	sum := sha256.Sum256([]byte(segment))
+	hash := hex.EncodeToString(sum[:])[:pathHashLength]
+
+	keep := length - len(ext) - len(hash) - 1
+	name := []rune(strings.TrimSuffix(segment, ext))
+	if keep > len(name) {
+		keep = len(name)
+	}
+	if keep < 0 {
+		keep = 0
+	}
+	head := strings.TrimRight(string(name[:keep]), "-_. ")
+	if head == "" {
+		return hash + ext
+	}
+	return head + "-" + hash + ext
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPathLimitsShortenSegments(t *testing.T) {
+	limits := PathLimits{MaxSegment: 20}
+	rel := filepath.Join("a-very-long-directory-name-indeed", "short", "an-equally-long-page-title.md")
+
+	got, changed := limits.Shorten("out", rel)
+	if !changed {
+		t.Fatal("expected the path to be shortened")
+	}
+	segments := strings.Split(got, string(filepath.Separator))
+	if len(segments) != 3 || segments[1] != "short" || !strings.HasSuffix(segments[2], ".md") {
+		t.Fatalf("unexpected shortened path %q", got)
+	}
+	for _, segment := range segments {
+		if len(segment) > 20 {
+			t.Fatalf("segment %q exceeds the limit", segment)
+		}
+	}
+	if !strings.HasPrefix(segments[0], "a-very-long") {
+		t.Fatalf("expected the start of the name to be kept, got %q", segments[0])
+	}
+
+	// Shortening is deterministic so every page below a directory lands in the same place
+	if again, _ := limits.Shorten("out", rel); again != got {
+		t.Fatalf("Shorten() is not stable: %q != %q", again, got)
+	}
+	if other, _ := limits.Shorten("out", filepath.Join("a-very-long-directory-name-indeed", "x.md")); !strings.HasPrefix(other, segments[0]+string(filepath.Separator)) {
+		t.Fatalf("expected the same shortened directory, got %q", other)
+	}
+
+	if unchanged, changed := limits.Shorten("out", "short.md"); changed || unchanged != "short.md" {
+		t.Fatalf("Shorten() changed a short path: %q", unchanged)
+	}
+}
+
+func TestPathLimitsShortenTotalLength(t *testing.T) {
+	base := t.TempDir()
+	rel := filepath.Join(strings.Repeat("d", 60), strings.Repeat("e", 60), strings.Repeat("f", 80)+".md")
+	limit := len(base) + 120
+
+	got, changed := PathLimits{MaxPath: limit}.Shorten(base, rel)
+	if !changed {
+		t.Fatal("expected the path to be shortened")
+	}
+	if total := len(filepath.Join(base, got)); total > limit {
+		t.Fatalf("shortened path has %d characters, limit %d: %q", total, limit, got)
+	}
+	if !strings.HasPrefix(got, strings.Repeat("d", 60)+string(filepath.Separator)) {
+		t.Fatalf("expected the shallowest directory to be kept, got %q", got)
+	}
+
+	if _, changed := (PathLimits{}).Shorten(base, rel); changed {
+		t.Fatal("expected no shortening without limits")
+	}
+}
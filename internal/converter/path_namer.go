@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+)
+
+// PathNamer decides where a converted page is written, relative to the output directory and
+// including any directories. ancestors holds the titles from the export root down to the page's parent.
+type PathNamer interface {
+	RelativePath(page *confluenceModel.ConfluencePage, ancestors []string) (string, error)
+}
+
+// PathNamerFunc adapts a function to the PathNamer interface.
+type PathNamerFunc func(page *confluenceModel.ConfluencePage, ancestors []string) (string, error)
+
+func (f PathNamerFunc) RelativePath(page *confluenceModel.ConfluencePage, ancestors []string) (string, error) {
+	return f(page, ancestors)
+}
+
+// HierarchyPathNamer returns the built-in layout: one directory per ancestor title and the
+// file name from namer (the default namer when nil).
+func HierarchyPathNamer(namer OutputNamer, opts ...NamingOption) PathNamer {
+	config := newNamingConfig(opts)
+	if namer == nil {
+		namer = DefaultOutputNamer(opts...)
+	}
+	return PathNamerFunc(func(page *confluenceModel.ConfluencePage, ancestors []string) (string, error) {
+		fileName, err := GenerateFileName(page, namer)
+		if err != nil {
+			return "", err
+		}
+
+		parts := make([]string, 0, len(ancestors)+1)
+		for _, ancestor := range ancestors {
+			parts = append(parts, dirName(config.slugger, config.titleRules.Apply(ancestor)))
+		}
+		return filepath.Join(append(parts, fileName)...), nil
+	})
+}
+
+// DirName turns a page title into a directory name.
+func DirName(title string) string {
+	return dirName(plugin.DefaultSlugger, title)
+}
+
+// DirName turns a page title into a directory name using the configured language.
+// Ancestor directories carry only titles, so page language labels do not apply.
+func (t *Transliteration) DirName(title string) string {
+	return dirName(t, title)
+}
+
+// dirName turns a page title into a directory name with slugger
+func dirName(slugger plugin.Slugger, title string) string {
+	if title == "" {
+		return "untitled"
+	}
+	if slugified := slugger.Slug(title, nil); slugified != "" {
+		return slugified
+	}
+	return title
+}
+
+// GenerateRelativePath resolves the relative output path for a page using the provided namer or the
+// hierarchy default. Paths escaping the output directory are rejected.
+func GenerateRelativePath(page *confluenceModel.ConfluencePage, ancestors []string, namer PathNamer) (string, error) {
+	if page == nil {
+		return "", fmt.Errorf("page cannot be nil")
+	}
+
+	if namer == nil {
+		namer = HierarchyPathNamer(nil)
+	}
+
+	path, err := namer.RelativePath(page, ancestors)
+	if err != nil {
+		return "", err
+	}
+
+	path = filepath.Clean(filepath.FromSlash(strings.TrimSpace(path)))
+	if path == "." || path == ".." || filepath.IsAbs(path) || strings.HasPrefix(path, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("generated path %q is invalid", path)
+	}
+
+	if filepath.Ext(path) == "" {
+		path += ".md"
+	}
+
+	return path, nil
+}
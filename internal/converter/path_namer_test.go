@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"path/filepath"
+	"testing"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestHierarchyPathNamer(t *testing.T) {
+	page := &confluenceModel.ConfluencePage{Title: "Release Notes"}
+
+	got, err := GenerateRelativePath(page, []string{"Engineering Home", "Guides"}, nil)
+	if err != nil {
+		t.Fatalf("GenerateRelativePath() error = %v", err)
+	}
+	if want := filepath.Join("engineering-home", "guides", "release-notes.md"); got != want {
+		t.Fatalf("GenerateRelativePath() = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateRelativePathCustomNamer(t *testing.T) {
+	page := &confluenceModel.ConfluencePage{ID: "42", Title: "Page", SpaceKey: "DOCS"}
+
+	byID := PathNamerFunc(func(page *confluenceModel.ConfluencePage, _ []string) (string, error) {
+		return page.SpaceKey + "/" + page.ID, nil
+	})
+	got, err := GenerateRelativePath(page, []string{"Ignored"}, byID)
+	if err != nil {
+		t.Fatalf("GenerateRelativePath() error = %v", err)
+	}
+	if want := filepath.Join("DOCS", "42.md"); got != want {
+		t.Fatalf("GenerateRelativePath() = %q, want %q", got, want)
+	}
+
+	for _, invalid := range []string{"", "../outside.md", "/abs/page.md", "a/../../b.md"} {
+		namer := PathNamerFunc(func(*confluenceModel.ConfluencePage, []string) (string, error) {
+			return invalid, nil
+		})
+		if _, err := GenerateRelativePath(page, nil, namer); err == nil {
+			t.Errorf("GenerateRelativePath() accepted invalid path %q", invalid)
+		}
+	}
+}
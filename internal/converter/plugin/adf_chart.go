@@ -0,0 +1,279 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// The new editor stores its elements as ADF extensions in ac:adf-extension, holding the
+// extension node (ac:adf-node with ac:adf-attribute and ac:adf-parameter children) and an
+// ac:adf-fallback rendering for clients that do not understand it. Charts keep their data
+// either in a JSON parameter or in a table inside the node's ac:adf-content.
+
+// chartData is the decoded data of a chart: one column of categories followed by value series
+type chartData struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// handleADFExtension renders new-editor charts as Mermaid charts or data tables, and any other
+// extension as its fallback content
+func (p *ConfluencePlugin) handleADFExtension(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	node := findDescendant(n, "ac:adf-node")
+	if node != nil && isChartExtension(node) {
+		_, _ = w.WriteString(p.renderADFChart(node))
+		return converter.RenderSuccess
+	}
+
+	if fallback := findDescendant(n, "ac:adf-fallback"); fallback != nil {
+		for child := fallback.FirstChild; child != nil; child = child.NextSibling {
+			ctx.RenderNodes(ctx, w, child)
+		}
+	}
+	return converter.RenderSuccess
+}
+
+// isChartExtension reports whether an ADF node is a chart, e.g. extension type com.atlassian.chart
+func isChartExtension(node *html.Node) bool {
+	extensionType := strings.ToLower(adfAttribute(node, "extension-type"))
+	extensionKey := strings.ToLower(adfAttribute(node, "extension-key"))
+	return strings.Contains(extensionType, "chart") || strings.HasPrefix(extensionKey, "chart")
+}
+
+// adfAttribute returns the value of the node's ac:adf-attribute with the given key
+func adfAttribute(node *html.Node, key string) string {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:adf-attribute" && GetAttr(child, "key") == key {
+			return nodeText(child)
+		}
+	}
+	return ""
+}
+
+// adfParameters flattens the node's nested ac:adf-parameter elements into a map keyed by parameter name
+func adfParameters(node *html.Node) map[string]string {
+	params := make(map[string]string)
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode || child.Data == "ac:adf-content" {
+				continue
+			}
+			if child.Data == "ac:adf-parameter" && !hasParameterChild(child) {
+				params[GetAttr(child, "key")] = nodeText(child)
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(node)
+	return params
+}
+
+// hasParameterChild reports whether a parameter groups other parameters
+func hasParameterChild(n *html.Node) bool {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:adf-parameter" {
+			return true
+		}
+	}
+	return false
+}
+
+// firstOf returns the first non-empty value among keys
+func firstOf(params map[string]string, keys ...string) string {
+	for _, key := range keys {
+		if value := params[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// renderADFChart renders a chart as Mermaid when its type and data allow it, and as a data table otherwise
+func (p *ConfluencePlugin) renderADFChart(node *html.Node) string {
+	params := adfParameters(node)
+	title := firstOf(params, "title", "chartTitle", "chart-title")
+	chartType := strings.ToLower(firstOf(params, "chartType", "chart-type", "type"))
+
+	data := decodeChartData(firstOf(params, "data", "chartData", "chart-data"))
+	if data == nil {
+		if table := findDescendant(node, "table"); table != nil {
+			data = tableChartData(table)
+		}
+	}
+
+	comment := p.sourceComment("extension", "chart")
+	if comment != "" {
+		comment += "\n"
+	}
+
+	if data != nil {
+		if chart := mermaidChart(chartType, title, data); chart != "" {
+			return comment + "```mermaid\n" + chart + "```\n\n"
+		}
+	}
+
+	if title == "" {
+		title = p.labels.Get(LabelChart)
+	}
+	var result strings.Builder
+	result.WriteString(comment)
+	if data == nil {
+		fmt.Fprintf(&result, "📊 **%s** (%s)\n\n", title, p.labels.Get(LabelNotExported))
+		return result.String()
+	}
+	fmt.Fprintf(&result, "📊 **%s** (%s)\n\n", title, p.labels.Get(LabelChartNotExported))
+	result.WriteString(p.dataTable(title, data.Columns, data.Rows))
+	result.WriteString("\n")
+	return result.String()
+}
+
+// decodeChartData reads chart data stored as JSON, either {"columns": [...], "rows": [[...]]}
+// or an array of rows whose first row holds the column names
+func decodeChartData(raw string) *chartData {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	var object struct {
+		Columns []string `json:"columns"`
+		Rows    [][]any  `json:"rows"`
+	}
+	if err := json.Unmarshal([]byte(raw), &object); err == nil && len(object.Columns) > 0 {
+		return &chartData{Columns: object.Columns, Rows: stringRows(object.Rows)}
+	}
+
+	var rows [][]any
+	if err := json.Unmarshal([]byte(raw), &rows); err == nil && len(rows) > 1 {
+		all := stringRows(rows)
+		return &chartData{Columns: all[0], Rows: all[1:]}
+	}
+	return nil
+}
+
+// stringRows formats JSON cell values as strings
+func stringRows(rows [][]any) [][]string {
+	result := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		cells := make([]string, 0, len(row))
+		for _, cell := range row {
+			switch value := cell.(type) {
+			case string:
+				cells = append(cells, value)
+			case float64:
+				cells = append(cells, strconv.FormatFloat(value, 'f', -1, 64))
+			case nil:
+				cells = append(cells, "")
+			default:
+				cells = append(cells, fmt.Sprint(value))
+			}
+		}
+		result = append(result, cells)
+	}
+	return result
+}
+
+// tableChartData reads chart data from a table whose first row holds the column names
+func tableChartData(table *html.Node) *chartData {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode {
+				continue
+			}
+			if child.Data != "tr" {
+				walk(child)
+				continue
+			}
+			var cells []string
+			for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.Type == html.ElementNode && (cell.Data == "th" || cell.Data == "td") {
+					cells = append(cells, nodeText(cell))
+				}
+			}
+			rows = append(rows, cells)
+		}
+	}
+	walk(table)
+
+	if len(rows) < 2 {
+		return nil
+	}
+	return &chartData{Columns: rows[0], Rows: rows[1:]}
+}
+
+// mermaidChart renders pie charts as a Mermaid pie and bar and line charts as a Mermaid xychart.
+// It returns "" for other chart types and for data that is not numeric.
+func mermaidChart(chartType, title string, data *chartData) string {
+	if len(data.Columns) < 2 || len(data.Rows) == 0 {
+		return ""
+	}
+
+	series := make([][]string, len(data.Columns)-1)
+	labels := make([]string, 0, len(data.Rows))
+	for _, row := range data.Rows {
+		if len(row) != len(data.Columns) {
+			return ""
+		}
+		labels = append(labels, mermaidString(row[0]))
+		for i, cell := range row[1:] {
+			value, ok := chartNumber(cell)
+			if !ok {
+				return ""
+			}
+			series[i] = append(series[i], value)
+		}
+	}
+
+	var chart strings.Builder
+	switch chartType {
+	case "pie":
+		chart.WriteString("pie")
+		if title != "" {
+			chart.WriteString(" title " + title)
+		}
+		chart.WriteString("\n")
+		for i, label := range labels {
+			fmt.Fprintf(&chart, "    %s : %s\n", label, series[0][i])
+		}
+	case "bar", "column", "line", "":
+		mark := "bar"
+		if chartType == "line" {
+			mark = "line"
+		}
+		chart.WriteString("xychart-beta\n")
+		if title != "" {
+			fmt.Fprintf(&chart, "    title %s\n", mermaidString(title))
+		}
+		fmt.Fprintf(&chart, "    x-axis [%s]\n", strings.Join(labels, ", "))
+		for _, values := range series {
+			fmt.Fprintf(&chart, "    %s [%s]\n", mark, strings.Join(values, ", "))
+		}
+	default:
+		return ""
+	}
+	return chart.String()
+}
+
+// chartNumber normalizes a numeric cell, accepting thousands separators and percent signs
+func chartNumber(cell string) (string, bool) {
+	cleaned := strings.TrimSuffix(strings.ReplaceAll(strings.TrimSpace(cell), ",", ""), "%")
+	value, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return "", false
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64), true
+}
+
+// mermaidString quotes a label for Mermaid
+func mermaidString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
@@ -0,0 +1,62 @@
+package plugin
+
+import "testing"
+
+const adfChartTable = `<table><tbody><tr><th>Quarter</th><th>Revenue</th></tr><tr><td>Q1</td><td>1,200</td></tr><tr><td>Q2</td><td>1500</td></tr></tbody></table>`
+
+func adfChart(chartType, params, content string) string {
+	return `<ac:adf-extension><ac:adf-node type="bodied-extension">` +
+		`<ac:adf-attribute key="extension-key">chart:default</ac:adf-attribute>` +
+		`<ac:adf-attribute key="extension-type">com.atlassian.chart</ac:adf-attribute>` +
+		`<ac:adf-attribute key="parameters"><ac:adf-parameter key="chart-group"><ac:adf-parameter key="chartType">` + chartType + `</ac:adf-parameter>` +
+		`<ac:adf-parameter key="title">Revenue</ac:adf-parameter>` + params + `</ac:adf-parameter></ac:adf-attribute>` +
+		`<ac:adf-content>` + content + `</ac:adf-content></ac:adf-node>` +
+		`<ac:adf-fallback><p>fallback</p></ac:adf-fallback></ac:adf-extension>`
+}
+
+func TestRenderADFChart(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+
+	node := findNode(t, adfChart("bar", "", adfChartTable), "ac:adf-node")
+	want := "```mermaid\nxychart-beta\n    title \"Revenue\"\n    x-axis [\"Q1\", \"Q2\"]\n    bar [1200, 1500]\n```\n\n"
+	if got := plugin.renderADFChart(node); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	node = findNode(t, adfChart("pie", "", adfChartTable), "ac:adf-node")
+	want = "```mermaid\npie title Revenue\n    \"Q1\" : 1200\n    \"Q2\" : 1500\n```\n\n"
+	if got := plugin.renderADFChart(node); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	node = findNode(t, adfChart("area", "", adfChartTable), "ac:adf-node")
+	want = "📊 **Revenue** (chart not exported, source data below)\n\n| Quarter | Revenue |\n| --- | --- |\n| Q1 | 1,200 |\n| Q2 | 1500 |\n\n"
+	if got := plugin.renderADFChart(node); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	node = findNode(t, adfChart("line", `<ac:adf-parameter key="data">{"columns":["Month","Visits"],"rows":[["Jan",10],["Feb",12.5]]}</ac:adf-parameter>`, ""), "ac:adf-node")
+	if got := plugin.renderADFChart(node); got != "```mermaid\nxychart-beta\n    title \"Revenue\"\n    x-axis [\"Jan\", \"Feb\"]\n    line [10, 12.5]\n```\n\n" {
+		t.Fatalf("unexpected chart from JSON data: %q", got)
+	}
+}
+
+func TestIsChartExtension(t *testing.T) {
+	if !isChartExtension(findNode(t, adfChart("bar", "", ""), "ac:adf-node")) {
+		t.Fatal("expected com.atlassian.chart to be a chart")
+	}
+	other := `<ac:adf-extension><ac:adf-node type="extension"><ac:adf-attribute key="extension-type">com.atlassian.confluence.macro.core</ac:adf-attribute></ac:adf-node></ac:adf-extension>`
+	if isChartExtension(findNode(t, other, "ac:adf-node")) {
+		t.Fatal("expected other extensions not to be charts")
+	}
+}
+
+func TestDecodeChartData(t *testing.T) {
+	data := decodeChartData(`[["Team","Open"],["A",3],["B",null]]`)
+	if data == nil || len(data.Columns) != 2 || len(data.Rows) != 2 || data.Rows[1][1] != "" {
+		t.Fatalf("decodeChartData() = %+v", data)
+	}
+	if decodeChartData("not json") != nil {
+		t.Fatal("expected nil for data that is not JSON")
+	}
+}
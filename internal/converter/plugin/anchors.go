@@ -0,0 +1,74 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// AnchorStyle selects how anchor names and links to headings are normalized
+type AnchorStyle string
+
+const (
+	AnchorSlug  AnchorStyle = "slug"  // ASCII slug, emoji and accents dropped (default)
+	AnchorStrip AnchorStyle = "strip" // GitHub-style: lowercase, punctuation and emoji removed, spaces become hyphens
+	AnchorKeep  AnchorStyle = "keep"  // GitHub-style with emoji kept, for renderers that keep them in heading ids
+)
+
+// ParseAnchorStyle validates an anchor style; an empty name selects AnchorSlug
+func ParseAnchorStyle(name string) (AnchorStyle, error) {
+	switch style := AnchorStyle(name); style {
+	case "":
+		return AnchorSlug, nil
+	case AnchorSlug, AnchorStrip, AnchorKeep:
+		return style, nil
+	}
+	return "", fmt.Errorf("anchor style must be slug, strip or keep, got: %s", name)
+}
+
+// WithAnchorStyle sets how anchor names and anchor links are normalized
+func WithAnchorStyle(style AnchorStyle) Option {
+	return func(p *ConfluencePlugin) {
+		p.anchorStyle = style
+	}
+}
+
+// anchorID normalizes an anchor or heading text into the id used by the configured renderer convention
+func (p *ConfluencePlugin) anchorID(text string) string {
+	switch p.anchorStyle {
+	case AnchorStrip:
+		return githubAnchor(text, false)
+	case AnchorKeep:
+		return githubAnchor(text, true)
+	}
+	return p.slug(text, p.currentPage)
+}
+
+// githubAnchor applies GitHub's heading id rules: lowercase, drop punctuation, turn spaces into hyphens.
+// Emoji are dropped unless keepEmoji is set; the space that followed them still becomes a hyphen.
+func githubAnchor(text string, keepEmoji bool) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(text)) {
+		switch {
+		case r == ' ' || r == '-':
+			builder.WriteRune('-')
+		case r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r):
+			builder.WriteRune(r)
+		case keepEmoji && isEmojiRune(r):
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// isEmojiRune reports whether r is part of an emoji sequence: a pictograph, variation selector,
+// zero-width joiner or skin tone modifier
+func isEmojiRune(r rune) bool {
+	switch {
+	case r == 0xFE0F || r == 0x200D:
+		return true
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return true
+	}
+	return unicode.Is(unicode.So, r)
+}
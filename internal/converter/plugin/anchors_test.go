@@ -0,0 +1,34 @@
+package plugin
+
+import "testing"
+
+func TestAnchorID(t *testing.T) {
+	tests := []struct {
+		style AnchorStyle
+		text  string
+		want  string
+	}{
+		{AnchorSlug, "🚀 Launch Plan", "launch-plan"},
+		{AnchorStrip, "🚀 Launch Plan", "-launch-plan"},
+		{AnchorStrip, "FAQ: What's new?", "faq-whats-new"},
+		{AnchorKeep, "🚀 Launch Plan", "🚀-launch-plan"},
+		{AnchorKeep, "Team 👍🏽 Über", "team-👍🏽-über"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.style)+"/"+tt.text, func(t *testing.T) {
+			plugin := &ConfluencePlugin{anchorStyle: tt.style}
+			if got := plugin.anchorID(tt.text); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAnchorStyle(t *testing.T) {
+	if style, err := ParseAnchorStyle(""); err != nil || style != AnchorSlug {
+		t.Fatalf("expected slug for empty name, got %q, %v", style, err)
+	}
+	if _, err := ParseAnchorStyle("pandoc"); err == nil {
+		t.Fatal("expected an error for an unknown anchor style")
+	}
+}
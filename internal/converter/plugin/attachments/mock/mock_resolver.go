@@ -10,8 +10,10 @@
 package mock_attachments
 
 import (
+	io "io"
 	reflect "reflect"
 
+	confluence "github.com/jackchuka/confluence-md/internal/confluence"
 	model "github.com/jackchuka/confluence-md/internal/confluence/model"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,20 +42,20 @@ func (m *MockResolver) EXPECT() *MockResolverMockRecorder {
 	return m.recorder
 }
 
-// DownloadAttachment mocks base method.
-func (m *MockResolver) DownloadAttachment(page *model.ConfluencePage, filename string, revision int) (*model.ConfluenceAttachment, []byte, error) {
+// DownloadAttachmentTo mocks base method.
+func (m *MockResolver) DownloadAttachmentTo(page *model.ConfluencePage, filename string, revision int, w io.Writer, maxSize int64, progress confluence.ProgressFunc) (*model.ConfluenceAttachment, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DownloadAttachment", page, filename, revision)
+	ret := m.ctrl.Call(m, "DownloadAttachmentTo", page, filename, revision, w, maxSize, progress)
 	ret0, _ := ret[0].(*model.ConfluenceAttachment)
-	ret1, _ := ret[1].([]byte)
+	ret1, _ := ret[1].(int64)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
-// DownloadAttachment indicates an expected call of DownloadAttachment.
-func (mr *MockResolverMockRecorder) DownloadAttachment(page, filename, revision any) *gomock.Call {
+// DownloadAttachmentTo indicates an expected call of DownloadAttachmentTo.
+func (mr *MockResolverMockRecorder) DownloadAttachmentTo(page, filename, revision, w, maxSize, progress any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadAttachment", reflect.TypeOf((*MockResolver)(nil).DownloadAttachment), page, filename, revision)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadAttachmentTo", reflect.TypeOf((*MockResolver)(nil).DownloadAttachmentTo), page, filename, revision, w, maxSize, progress)
 }
 
 // Resolve mocks base method.
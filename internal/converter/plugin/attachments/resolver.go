@@ -3,6 +3,7 @@ package attachments
 
 import (
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/jackchuka/confluence-md/internal/confluence"
@@ -12,7 +13,7 @@ import (
 // Resolver provides attachment content for macros such as mermaid.
 type Resolver interface {
 	Resolve(page *model.ConfluencePage, filename string, revision int) (string, error)
-	DownloadAttachment(page *model.ConfluencePage, filename string, revision int) (*model.ConfluenceAttachment, []byte, error)
+	DownloadAttachmentTo(page *model.ConfluencePage, filename string, revision int, w io.Writer, maxSize int64, progress confluence.ProgressFunc) (*model.ConfluenceAttachment, int64, error)
 }
 
 // Service implements Resolver using a Confluence content downloader.
@@ -44,23 +45,24 @@ func (s *Service) Resolve(page *model.ConfluencePage, filename string, revision
 	return string(data), nil
 }
 
-// DownloadAttachment retrieves attachment bytes for the given filename and optional revision.
-func (s *Service) DownloadAttachment(page *model.ConfluencePage, filename string, revision int) (*model.ConfluenceAttachment, []byte, error) {
+// DownloadAttachmentTo streams the attachment with the given filename and optional revision into w,
+// aborting once it exceeds maxSize bytes (0 for no limit).
+func (s *Service) DownloadAttachmentTo(page *model.ConfluencePage, filename string, revision int, w io.Writer, maxSize int64, progress confluence.ProgressFunc) (*model.ConfluenceAttachment, int64, error) {
 	if page == nil {
-		return nil, nil, fmt.Errorf("page context not provided")
+		return nil, 0, fmt.Errorf("page context not provided")
 	}
 
 	attachment := selectAttachment(page.Attachments, filename, revision)
 	if attachment == nil {
-		return nil, nil, fmt.Errorf("attachment %s not found", filename)
+		return nil, 0, fmt.Errorf("attachment %s not found", filename)
 	}
 
-	data, err := s.client.DownloadAttachmentContent(attachment)
+	written, err := s.client.DownloadAttachmentTo(attachment, w, maxSize, progress)
 	if err != nil {
-		return nil, nil, err
+		return nil, 0, err
 	}
 
-	return attachment, data, nil
+	return attachment, written, nil
 }
 
 func selectAttachment(attachments []model.ConfluenceAttachment, filename string, revision int) *model.ConfluenceAttachment {
@@ -0,0 +1,107 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// cellPanelMacros are rendered as a labeled prefix instead of a blockquote inside table cells
+var cellPanelMacros = map[string]struct{ emoji, label string }{
+	"info":    {"ℹ️", LabelInfo},
+	"warning": {"⚠️", LabelWarning},
+	"note":    {"📝", LabelNote},
+	"tip":     {"💡", LabelTip},
+}
+
+// cellUnwrappedMacros only contribute their body inside table cells; expand and details wrappers are omitted
+var cellUnwrappedMacros = map[string]bool{
+	"expand":           true,
+	"details":          true,
+	"numberedheadings": true,
+	"div":              true,
+	"span":             true,
+	"metadata-list":    true,
+}
+
+// isCellBlockMacro reports whether a macro renders block Markdown that would break a table row
+func isCellBlockMacro(macroName string) bool {
+	switch macroName {
+	case "code", "noformat":
+		return true
+	}
+	_, panel := cellPanelMacros[macroName]
+	return panel || cellUnwrappedMacros[macroName]
+}
+
+// containsCellBlockMacro reports whether a block macro appears anywhere below n
+func containsCellBlockMacro(n *html.Node) bool {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		if child.Data == "ac:structured-macro" && isCellBlockMacro(GetAttr(child, "ac:name")) {
+			return true
+		}
+		if containsCellBlockMacro(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleCellMacro renders a macro inside a table cell so the output stays on the cell's single line
+func (p *ConfluencePlugin) handleCellMacro(ctx converter.Context, w *strings.Builder, n *html.Node) {
+	macroName := GetAttr(n, "ac:name")
+	if !p.macroAllowed(macroName) {
+		return
+	}
+
+	if panel, ok := cellPanelMacros[macroName]; ok {
+		fmt.Fprintf(w, "%s **%s:** ", panel.emoji, p.labels.Get(panel.label))
+		p.flattenMacroBody(ctx, w, n)
+		return
+	}
+	// Fenced divs cannot open inside a table row, so a panel only contributes its body there
+	if cellUnwrappedMacros[macroName] || macroName == "panel" && p.fencedDivs {
+		p.flattenMacroBody(ctx, w, n)
+		return
+	}
+	if macroName == "code" || macroName == "noformat" {
+		// A pipe would end the cell even inside <code>
+		w.WriteString(strings.ReplaceAll(cellCodeHTML(n), "|", `\|`))
+		return
+	}
+
+	var buf strings.Builder
+	p.handleMacro(ctx, &buf, n)
+	w.WriteString(cellSafe(buf.String()))
+}
+
+// cellCodeHTML renders a code or noformat macro body as one-line inline HTML code
+func cellCodeHTML(n *html.Node) string {
+	code := html.EscapeString(strings.TrimSpace(findPlainTextBody(n)))
+	return "<code>" + strings.ReplaceAll(code, "\n", "<br>") + "</code>"
+}
+
+// flattenMacroBody flattens the macro's rich text body into the cell
+func (p *ConfluencePlugin) flattenMacroBody(ctx converter.Context, w *strings.Builder, n *html.Node) {
+	if body := p.findRichTextBodyNode(n); body != nil {
+		p.flattenCellContent(ctx, w, body)
+	}
+}
+
+// cellSafe joins multi-line macro output with <br>, dropping blockquote markers and escaping pipes
+func cellSafe(output string) string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimPrefix(line, ">"))
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.ReplaceAll(strings.Join(lines, "<br>"), "|", `\|`)
+}
@@ -0,0 +1,32 @@
+package plugin
+
+import "testing"
+
+func TestHandleCellMacro(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	cell := findNode(t, `<table><tbody><tr><td><ac:structured-macro ac:name="info"><ac:rich-text-body><p>Heads up</p></ac:rich-text-body></ac:structured-macro><ac:structured-macro ac:name="expand"><ac:rich-text-body><p>Hidden</p></ac:rich-text-body></ac:structured-macro></td></tr></tbody></table>`, "td")
+
+	if !plugin.cellHasComplexContent(cell) {
+		t.Fatal("expected a cell with an info panel to need flattening")
+	}
+	if got, want := plugin.getCellHTMLContent(nil, cell), "ℹ️ **Info:** Heads up Hidden"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	cell = findNode(t, `<table><tbody><tr><td><ac:structured-macro ac:name="code"><ac:plain-text-body><!--[CDATA[a < b
+c]]></ac:plain-text-body></ac:structured-macro></td></tr></tbody></table>`, "td")
+	if got, want := plugin.getCellHTMLContent(nil, cell), "<code>a &lt; b<br>c</code>"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	cell = findNode(t, `<table><tbody><tr><td><ac:structured-macro ac:name="code"><ac:plain-text-body><!--[CDATA[a|b]]></ac:plain-text-body></ac:structured-macro></td></tr></tbody></table>`, "td")
+	if got, want := plugin.getCellHTMLContent(nil, cell), `<code>a\|b</code>`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestCellSafe(t *testing.T) {
+	if got, want := cellSafe("> **Label:** a | b\n>\n> second\n"), `**Label:** a \| b<br>second`; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+package plugin
+
+import (
+	"golang.org/x/net/html"
+)
+
+// Comala Document Management defines workflows, states and approvals with macros. They only
+// drive the Confluence UI, so they render nothing; the workflow name is kept for the frontmatter.
+
+// handleComalaMacro records the workflow a Comala macro belongs to and drops it from the body
+func (p *ConfluencePlugin) handleComalaMacro(n *html.Node, macroName string) string {
+	if macroName == "workflow" && p.workflowName == "" {
+		p.workflowName = findParameter(n, "name")
+	}
+	return ""
+}
+
+// WorkflowName returns the name of the Comala workflow defined on the current page, if any
+func (p *ConfluencePlugin) WorkflowName() string {
+	return p.workflowName
+}
@@ -0,0 +1,15 @@
+package plugin
+
+import "testing"
+
+func TestHandleComalaMacro(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	node := findNode(t, `<ac:structured-macro ac:name="workflow"><ac:parameter ac:name="name">Review</ac:parameter><ac:rich-text-body><ac:structured-macro ac:name="state"><ac:parameter ac:name="">Draft</ac:parameter></ac:structured-macro></ac:rich-text-body></ac:structured-macro>`, "ac:structured-macro")
+
+	if got := plugin.handleComalaMacro(node, "workflow"); got != "" {
+		t.Fatalf("expected workflow macro to render nothing, got %q", got)
+	}
+	if plugin.WorkflowName() != "Review" {
+		t.Fatalf("WorkflowName() = %q, want Review", plugin.WorkflowName())
+	}
+}
@@ -1,18 +1,20 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
 	"log"
 //	"net/url"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
-	"github.com/PuerkitoBio/goquery"
 	"github.com/jackchuka/confluence-md/internal/confluence"
 	"github.com/jackchuka/confluence-md/internal/confluence/model"
 	"github.com/jackchuka/confluence-md/internal/converter/plugin/attachments"
 	"golang.org/x/net/html"
-	"github.com/gosimple/slug"
 )
 
 type ConfluencePlugin struct {
@@ -22,30 +24,215 @@ type ConfluencePlugin struct {
 	currentPage        *model.ConfluencePage
 	baseURL            string
 	userCache          map[string]string // accountID -> displayName
+	unresolvedUsers    map[string]bool   // accountIDs of deleted or anonymized users
+	pageUnresolved     []string          // unresolved accountIDs mentioned on the current page
+	pageUnsupported    []string          // names of unsupported macros on the current page
+	emojis             []EmojiRef        // custom emoji images referenced on the current page
+	workflowName       string            // Comala workflow defined on the current page
+	diagramPreviews    []string          // preview attachments of diagram-app macros on the current page
+
+	// options
+	calendarEventDays int  // 0 disables fetching upcoming calendar events
+	executeSearch     bool // run search macro queries once via CQL
+	numberHeadings    bool // headings are numbered document-wide during post-processing
+	sourceComments    bool // annotate converted blocks with their Confluence origin
+	jiraBaseURL       string
+	userPlaceholder   string // rendered as @placeholder for unresolved users, empty keeps @user(accountID)
+	downloadEmojis    bool   // render custom emojis with image URLs as inline images in the asset folder
+	unknownMacro      UnknownMacroMode
+	tableMode         TableMode
+	dialect           Dialect
+	fencedDivs        bool // panels, expands and layouts as Pandoc fenced divs
+	inlineNoformat    int  // single-line noformat bodies up to this many characters become inline code
+	rowHeaders        RowHeaderStyle
+	anchorStyle       AnchorStyle
+	slugger           Slugger         // slugs anchors and emoji file names, DefaultSlugger when nil
+	labels            Labels          // generated words, English when nil
+	dropMacros        map[string]bool // macros removed from the output
+	onlyMacros        map[string]bool // when set, every other macro is removed
+	excludedMacros    map[string]bool // macros marking internal content, removed with their body
+	warn              func(page *model.ConfluencePage, message string)
+	macroHandlers     map[string]MacroHandler // custom handlers by lowercase macro name
+
+	tableIndex      int                   // tables rendered so far on the current page
+	convertedBodies map[*html.Node]string // macro bodies a failed custom handler already converted
+}
+
+// Option configures optional plugin behaviour
+type Option func(*ConfluencePlugin)
+
+// UnknownMacroMode selects what macros without a dedicated handler are rendered as
+type UnknownMacroMode string
+
+const (
+	UnknownMacroComment UnknownMacroMode = "comment" // HTML comment naming the macro (default)
+	UnknownMacroWarn    UnknownMacroMode = "warn"    // visible warning blockquote
+	UnknownMacroRaw     UnknownMacroMode = "raw"     // fenced block listing the macro parameters
+	UnknownMacroDrop    UnknownMacroMode = "drop"    // removed from the output
+)
+
+// ParseUnknownMacroMode validates an unknown macro mode; an empty name selects UnknownMacroComment
+func ParseUnknownMacroMode(name string) (UnknownMacroMode, error) {
+	switch mode := UnknownMacroMode(name); mode {
+	case "":
+		return UnknownMacroComment, nil
+	case UnknownMacroComment, UnknownMacroWarn, UnknownMacroRaw, UnknownMacroDrop:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown macro mode must be comment, warn, raw or drop, got: %s", name)
+}
+
+// WithCalendarEvents fetches upcoming Team Calendars events for the given number of days
+func WithCalendarEvents(days int) Option {
+	return func(p *ConfluencePlugin) {
+		p.calendarEventDays = days
+	}
+}
+
+// WithSearchExecution runs livesearch/search/search-results queries at export time and lists the results
+func WithSearchExecution() Option {
+	return func(p *ConfluencePlugin) {
+		p.executeSearch = true
+	}
+}
+
+// WithNumberedHeadings signals that headings are numbered for the whole document,
+// so numberedheadings macros only need to render their body
+func WithNumberedHeadings() Option {
+	return func(p *ConfluencePlugin) {
+		p.numberHeadings = true
+	}
+}
+
+// WithSourceComments annotates macros and tables with HTML comments pointing at their source element
+func WithSourceComments() Option {
+	return func(p *ConfluencePlugin) {
+		p.sourceComments = true
+	}
+}
+
+// WithJiraBaseURL sets the Jira instance used to link jira macro issue keys
+func WithJiraBaseURL(jiraBaseURL string) Option {
+	return func(p *ConfluencePlugin) {
+		p.jiraBaseURL = strings.TrimSuffix(jiraBaseURL, "/")
+	}
+}
+
+// WithUnresolvedUserPlaceholder renders mentions of deleted or anonymized users as @placeholder
+func WithUnresolvedUserPlaceholder(placeholder string) Option {
+	return func(p *ConfluencePlugin) {
+		p.userPlaceholder = strings.TrimPrefix(placeholder, "@")
+	}
+}
+
+// WithEmojiImages renders custom emojis that carry an image URL as small inline images
+// pointing into the asset folder; the caller downloads the images listed by Emojis
+func WithEmojiImages() Option {
+	return func(p *ConfluencePlugin) {
+		p.downloadEmojis = true
+	}
+}
+
+// WithUnknownMacroMode sets what unsupported macros are rendered as
+func WithUnknownMacroMode(mode UnknownMacroMode) Option {
+	return func(p *ConfluencePlugin) {
+		p.unknownMacro = mode
+	}
+}
+
+// WithMacroFilter removes the drop macros from the output and, when only is not empty,
+// every macro not listed in it, regardless of whether a handler exists
+func WithMacroFilter(drop, only []string) Option {
+	return func(p *ConfluencePlugin) {
+		p.dropMacros = macroSet(drop)
+		p.onlyMacros = macroSet(only)
+	}
+}
+
+// WithExcludedMacros removes the named macros and their body, e.g. noprint sections kept out of public exports
+func WithExcludedMacros(names []string) Option {
+	return func(p *ConfluencePlugin) {
+		p.excludedMacros = macroSet(names)
+	}
+}
+
+func macroSet(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	return set
+}
+
+// WithWarningHandler receives non-fatal problems, such as failed macro queries, instead of the log
+func WithWarningHandler(handler func(page *model.ConfluencePage, message string)) Option {
+	return func(p *ConfluencePlugin) {
+		p.warn = handler
+	}
+}
+
+// EmojiRef is a custom emoji image referenced by an ac:emoticon
+type EmojiRef struct {
+	URL      string
+	FileName string // file name below <imageFolder>/emoji
 }
 
 // NewConfluencePlugin creates a new plugin for Confluence elements
-func NewConfluencePlugin(resolver attachments.Resolver, imageFolder string) *ConfluencePlugin {
-	return &ConfluencePlugin{
+func NewConfluencePlugin(resolver attachments.Resolver, imageFolder string, opts ...Option) *ConfluencePlugin {
+	p := &ConfluencePlugin{
 		imageFolder:        imageFolder,
 		attachmentResolver: resolver,
 		userCache:          make(map[string]string),
+		unresolvedUsers:    make(map[string]bool),
 	}
+	p.applyOptions(opts)
+	return p
 }
 
 // NewConfluencePluginWithClient creates a plugin with API client access for user resolution
-func NewConfluencePluginWithClient(client confluence.Client, resolver attachments.Resolver, imageFolder string) *ConfluencePlugin {
-	return &ConfluencePlugin{
+func NewConfluencePluginWithClient(client confluence.Client, resolver attachments.Resolver, imageFolder string, opts ...Option) *ConfluencePlugin {
+	p := &ConfluencePlugin{
 		imageFolder:        imageFolder,
 		attachmentResolver: resolver,
 		client:             client,
 		userCache:          make(map[string]string),
+		unresolvedUsers:    make(map[string]bool),
+	}
+	p.applyOptions(opts)
+	return p
+}
+
+func (p *ConfluencePlugin) applyOptions(opts []Option) {
+	for _, opt := range opts {
+		if opt != nil {
+			opt(p)
+		}
 	}
 }
 
+// warnf reports a non-fatal problem on the current page
+func (p *ConfluencePlugin) warnf(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if p.warn == nil {
+		log.Print(message)
+		return
+	}
+	p.warn(p.currentPage, message)
+}
+
 // SetCurrentPage records which page is currently being converted
 func (p *ConfluencePlugin) SetCurrentPage(page *model.ConfluencePage) {
 	p.currentPage = page
+	p.tableIndex = 0
+	p.convertedBodies = nil
+	p.pageUnresolved = nil
+	p.pageUnsupported = nil
+	p.emojis = nil
+	p.workflowName = ""
+	p.diagramPreviews = nil
 
 	// Populate user cache from page metadata
 	if page != nil {
@@ -61,10 +248,20 @@ func (p *ConfluencePlugin) SetCurrentPage(page *model.ConfluencePage) {
 	}
 }
 
+// CurrentPage returns the page being converted, nil outside page conversion
+func (p *ConfluencePlugin) CurrentPage() *model.ConfluencePage {
+	return p.currentPage
+}
+
 func (p *ConfluencePlugin) SetBaseURL(baseURL string) {
 	p.baseURL = baseURL
 }
 
+// BaseURL returns the Confluence base URL of the page being converted
+func (p *ConfluencePlugin) BaseURL() string {
+	return p.baseURL
+}
+
 // extractAndCacheUsers finds all user references in the page HTML and adds them to cache
 func (p *ConfluencePlugin) extractAndCacheUsers(page *model.ConfluencePage) {
 	html := page.Content.Storage.Value
@@ -75,8 +272,17 @@ func (p *ConfluencePlugin) extractAndCacheUsers(page *model.ConfluencePage) {
 			if _, ok := p.userCache[accountID]; ok {
 				continue
 			}
+			if p.unresolvedUsers[accountID] {
+				p.pageUnresolved = append(p.pageUnresolved, accountID)
+				continue
+			}
 
 			user, err := p.client.GetUser(accountID)
+			if errors.Is(err, confluence.ErrNotFound) || (err == nil && user.IsAnonymized()) {
+				p.unresolvedUsers[accountID] = true
+				p.pageUnresolved = append(p.pageUnresolved, accountID)
+				continue
+			}
 			if err != nil {
 				continue
 			}
@@ -91,6 +297,25 @@ func (p *ConfluencePlugin) extractAndCacheUsers(page *model.ConfluencePage) {
 	log.Printf("Cached users: %+v", p.userCache)
 }
 
+// Emojis returns the custom emoji images referenced on the current page
+func (p *ConfluencePlugin) Emojis() []EmojiRef {
+	return p.emojis
+}
+
+// UnresolvedUsers returns the sorted accountIDs of deleted or anonymized users mentioned on the current page
+func (p *ConfluencePlugin) UnresolvedUsers() []string {
+	users := append([]string(nil), p.pageUnresolved...)
+	sort.Strings(users)
+	return users
+}
+
+// UnsupportedMacros returns the names of the macros on the current page that could not be converted
+func (p *ConfluencePlugin) UnsupportedMacros() []string {
+	macros := append([]string(nil), p.pageUnsupported...)
+	sort.Strings(macros)
+	return macros
+}
+
 // ExtractUserAccountIDs finds all user account IDs in the HTML
 func ExtractUserAccountIDs(html string) []string {
 	accountIDs := make(map[string]bool)
@@ -123,6 +348,7 @@ func ExtractUserAccountIDs(html string) []string {
 	for id := range accountIDs {
 		result = append(result, id)
 	}
+	sort.Strings(result)
 
 	return result
 }
@@ -138,10 +364,19 @@ func (p *ConfluencePlugin) Init(conv *converter.Converter) error {
 	conv.Register.RendererFor("ac:image", converter.TagTypeInline, p.handleImage, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:emoticon", converter.TagTypeInline, p.handleEmoticon, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:structured-macro", converter.TagTypeBlock, p.handleMacro, converter.PriorityStandard)
+	conv.Register.RendererFor("ac:adf-extension", converter.TagTypeBlock, p.handleADFExtension, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:link", converter.TagTypeInline, p.handleLink, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:inline-comment-marker", converter.TagTypeInline, p.handleInlineComment, converter.PriorityStandard)
 	conv.Register.RendererFor("ac:placeholder", converter.TagTypeInline, p.handlePlaceholder, converter.PriorityStandard)
+	conv.Register.RendererFor("at:var", converter.TagTypeInline, p.handleTemplateVariable, converter.PriorityStandard)
+	conv.Register.RendererFor("at:declarations", converter.TagTypeBlock, p.handleTemplateDeclarations, converter.PriorityStandard)
 	conv.Register.RendererFor("time", converter.TagTypeInline, p.handleTime, converter.PriorityStandard)
+	conv.Register.RendererFor("ac:layout-section", converter.TagTypeBlock, p.handleLayoutSection, converter.PriorityStandard)
+	conv.Register.RendererFor("ac:layout-cell", converter.TagTypeBlock, p.handleLayoutCell, converter.PriorityStandard)
+	conv.Register.RendererFor("ac:task-list", converter.TagTypeBlock, p.handleTaskList, converter.PriorityStandard)
+	for _, tag := range []string{"s", "del", "strike"} {
+		conv.Register.RendererFor(tag, converter.TagTypeInline, p.handleStrikethrough, converter.PriorityStandard)
+	}
 
 	// Register custom table handler with higher priority to override default
 	conv.Register.RendererFor("table", converter.TagTypeBlock, p.handleTable, converter.PriorityEarly)
@@ -151,6 +386,11 @@ func (p *ConfluencePlugin) Init(conv *converter.Converter) error {
 
 // cellHasComplexContent checks if a single cell contains complex elements
 func (p *ConfluencePlugin) cellHasComplexContent(cell *html.Node) bool {
+	if containsCellBlockMacro(cell) {
+		// Panels and code blocks need their cell-safe rendering
+		return true
+	}
+
 	blockElementCount := 0
 
 	for child := cell.FirstChild; child != nil; child = child.NextSibling {
@@ -251,13 +491,16 @@ func (p *ConfluencePlugin) flattenCellContent(ctx converter.Context, w *strings.
 			case "ac:task-list":
 				// Handle Confluence task lists
 				p.flattenTaskList(ctx, w, child)
-			case "strong", "b", "em", "i", "code", "a":
+			case "strong", "b", "em", "i", "code", "a", "s", "del":
 				// Preserve these inline elements
 				var buf strings.Builder
 				_ = html.Render(&buf, child)
 				w.WriteString(buf.String())
 			case "ac:structured-macro":
-				p.handleMacro(ctx, w, child)
+				p.handleCellMacro(ctx, w, child)
+				if isCellBlockMacro(GetAttr(child, "ac:name")) && child.NextSibling != nil {
+					w.WriteString(" ")
+				}
 			case "ac:emoticon":
 				p.handleEmoticon(ctx, w, child)
 				p.flattenCellContent(ctx, w, child)
@@ -383,6 +626,8 @@ func (p *ConfluencePlugin) handleTable(ctx converter.Context, w converter.Writer
 	// Extract table data
 	var rows [][]string
 	var isHeaderRow []bool
+	rowHeaderTable := true // every row is a <th> followed by <td> cells
+	columns := 0           // widest row, counting the columns spanned by merged cells
 
 	// Find tbody
 	var tbody *html.Node
@@ -397,6 +642,15 @@ func (p *ConfluencePlugin) handleTable(ctx converter.Context, w converter.Writer
 		return converter.RenderTryNext // Let default handler try
 	}
 
+	if p.useHTMLTable(tbody) {
+		p.tableIndex++
+		if comment := p.sourceComment("table", strconv.Itoa(p.tableIndex)); comment != "" {
+			_, _ = w.WriteString(comment + "\n\n")
+		}
+		p.writeHTMLTable(ctx, w, n)
+		return converter.RenderSuccess
+	}
+
 	// Process rows
 	for tr := tbody.FirstChild; tr != nil; tr = tr.NextSibling {
 		if tr.Type != html.ElementNode || tr.Data != "tr" {
@@ -406,6 +660,8 @@ func (p *ConfluencePlugin) handleTable(ctx converter.Context, w converter.Writer
 		var row []string
 		hasOnlyHeaders := true
 		hasSomeTd := false
+		firstCellHeader := false
+		width := 0
 
 		for cell := tr.FirstChild; cell != nil; cell = cell.NextSibling {
 			if cell.Type != html.ElementNode {
@@ -416,8 +672,14 @@ func (p *ConfluencePlugin) handleTable(ctx converter.Context, w converter.Writer
 				hasSomeTd = true
 				hasOnlyHeaders = false
 			}
+			if cell.Data == "th" && len(row) == 0 {
+				firstCellHeader = true
+			} else if cell.Data == "th" {
+				rowHeaderTable = false
+			}
 
 			if cell.Data == "td" || cell.Data == "th" {
+				width += cellColspan(cell)
 				var cellContent string
 
 				if p.cellHasComplexContent(cell) {
@@ -447,6 +709,10 @@ func (p *ConfluencePlugin) handleTable(ctx converter.Context, w converter.Writer
 		}
 
 		if len(row) > 0 {
+			columns = max(columns, width)
+			if !firstCellHeader || !hasSomeTd {
+				rowHeaderTable = false
+			}
 			rows = append(rows, row)
 			// Only treat as header row if ALL cells are <th> (no <td>)
 			isHeaderRow = append(isHeaderRow, hasOnlyHeaders && !hasSomeTd)
@@ -457,6 +723,11 @@ func (p *ConfluencePlugin) handleTable(ctx converter.Context, w converter.Writer
 		return converter.RenderTryNext
 	}
 
+	p.tableIndex++
+	if comment := p.sourceComment("table", strconv.Itoa(p.tableIndex)); comment != "" {
+		_, _ = w.WriteString(comment + "\n\n")
+	}
+
 	// Determine max columns
 	maxCols := 0
 	for _, row := range rows {
@@ -481,6 +752,11 @@ func (p *ConfluencePlugin) handleTable(ctx converter.Context, w converter.Writer
 		}
 	}
 
+	if !hasHeaderRow && rowHeaderTable && p.rowHeaders != RowHeaderNone {
+		p.writeRowHeaderTable(w, rows, columns)
+		return converter.RenderSuccess
+	}
+
 	// Write table
 	for i, row := range rows {
 		_, _ = w.WriteString("| ")
@@ -518,9 +794,9 @@ func (p *ConfluencePlugin) handleImage(ctx converter.Context, w converter.Writer
 	}
 
 	if filename == "" {
-		var buf strings.Builder
-		_ = html.Render(&buf, n)
-		filename = ParseConfluenceImage(buf.String())
+		if attachment := findDescendant(n, "ri:attachment"); attachment != nil {
+			filename = GetAttr(attachment, "ri:filename")
+		}
 	}
 
 	if filename == "" {
@@ -531,12 +807,29 @@ func (p *ConfluencePlugin) handleImage(ctx converter.Context, w converter.Writer
 	// Build local path for the image
 	localPath := p.imageFolder + "/" + filename
 
-	_, _ = fmt.Fprintf(w, "![%s](%s)", filename, localPath) //url.PathEscape(localPath))
+	// Prefer the alt text set in the editor over the file name
+	alt := strings.TrimSpace(GetAttr(n, "ac:alt"))
+	if alt == "" {
+		alt = filename
+	}
+
+	_, _ = fmt.Fprintf(w, "![%s](%s)", alt, localPath) //url.PathEscape(localPath))
 
 	return converter.RenderSuccess
 }
 
 func (p *ConfluencePlugin) handleEmoticon(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if src := GetAttr(n, "ac:emoji-url"); src != "" && p.downloadEmojis && p.imageFolder != "" {
+		name := strings.Trim(GetAttr(n, "ac:emoji-shortname"), ":")
+		if name == "" {
+			name = GetAttr(n, "ac:name")
+		}
+		fileName := emojiFileName(p.slug(name, nil), name, src)
+		p.addEmoji(EmojiRef{URL: src, FileName: fileName})
+		_, _ = fmt.Fprintf(w, `<img src="%s/emoji/%s" alt=":%s:" height="20"> `, p.imageFolder, fileName, name)
+		return converter.RenderTryNext
+	}
+
 	for _, attr := range n.Attr {
 		if attr.Key == "ac:emoji-fallback" && attr.Val != "" {
 			_, _ = w.WriteString(attr.Val + " ")
@@ -562,6 +855,16 @@ func (p *ConfluencePlugin) handleEmoticon(ctx converter.Context, w converter.Wri
 	return converter.RenderTryNext
 }
 
+// addEmoji records an emoji image for download once per page
+func (p *ConfluencePlugin) addEmoji(ref EmojiRef) {
+	for _, existing := range p.emojis {
+		if existing.FileName == ref.FileName {
+			return
+		}
+	}
+	p.emojis = append(p.emojis, ref)
+}
+
 func (p *ConfluencePlugin) handleMacro(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
 	macroName := ""
 	for _, attr := range n.Attr {
@@ -575,27 +878,40 @@ func (p *ConfluencePlugin) handleMacro(ctx converter.Context, w converter.Writer
 		macroName = "unknown"
 	}
 
+	if !p.macroAllowed(macroName) {
+		return converter.RenderSuccess
+	}
+
+	if handler := p.macroHandler(macroName); handler != nil {
+		if result, ok := p.handleCustomMacro(ctx, n, macroName, handler); ok {
+			_, _ = w.WriteString(p.withSourceComment(macroName, result))
+			return converter.RenderSuccess
+		}
+	}
+
 	tryNext := false
 
 	// Handle different macro types
 	var result string
 	switch macroName {
 	case "info":
-		result = p.handleBlockquoteMacro(ctx, n, "ℹ️", "Info")
+		result = p.handleBlockquoteMacro(ctx, n, "ℹ️", p.labels.Get(LabelInfo))
 	case "warning":
-		result = p.handleBlockquoteMacro(ctx, n, "⚠️", "Warning")
+		result = p.handleBlockquoteMacro(ctx, n, "⚠️", p.labels.Get(LabelWarning))
 	case "note":
-		result = p.handleBlockquoteMacro(ctx, n, "📝", "Note")
+		result = p.handleBlockquoteMacro(ctx, n, "📝", p.labels.Get(LabelNote))
 	case "tip":
-		result = p.handleBlockquoteMacro(ctx, n, "💡", "Tip")
+		result = p.handleBlockquoteMacro(ctx, n, "💡", p.labels.Get(LabelTip))
 	case "code":
 		result = p.handleCodeMacro(n)
 	case "noformat":
-		result = p.handleCodeMacro(n)
+		result = p.handleNoformatMacro(n)
 	case "mermaid-macro":
 		result = p.handleMermaidMacro(n)
 	case "expand":
 		result = p.handleExpandMacro(ctx, n)
+	case "panel":
+		result = p.handlePanelMacro(ctx, n)
 	case "toc":
 		result, tryNext = p.handleTocMacro(n)
 	case "details":
@@ -610,18 +926,70 @@ func (p *ConfluencePlugin) handleMacro(ctx converter.Context, w converter.Writer
 		result = p.handleViewFileMacro(n)
 	case "anchor":
 		result = p.handleAnchorMacro(n)
+	case "calendar":
+		result = p.handleCalendarMacro(n)
+	case "livesearch", "search", "search-results":
+		result = p.handleSearchMacro(n, macroName)
+	case "numberedheadings":
+		result = p.handleNumberedHeadingsMacro(ctx, n)
+	case "text-data", "table-data":
+		result = p.handleScaffoldingDataMacro(ctx, n, macroName)
+	case "list-data":
+		result = p.handleScaffoldingListMacro(n)
+	case "live-template":
+		result = p.handleLiveTemplateMacro(n)
+	case "workflow", "approval", "pagestatus":
+		result = p.handleComalaMacro(n, macroName)
+	case "mockup", "balsamiq", "lucidchart", "miro", "realtimeboard":
+		result = p.handleDiagramMacro(n, macroName)
+	case "content-by-label":
+		result = p.handleContentByLabelMacro(n)
+	case "metadata":
+		result = p.handleMetadataMacro(ctx, n)
+	case "metadata-list", "div", "span":
+		result = p.handleWrapperMacro(ctx, n, macroName)
+	case "table-filter", "table-chart", "chart-from-table", "pivot-table", "table-transformer", "table-excerpt", "table-spreadsheet":
+		result = p.handleTableFilterMacro(ctx, n, macroName)
 	default:
-		result = fmt.Sprintf("<!-- Unsupported macro: %s -->", macroName)
+		result = p.handleUnknownMacro(n, macroName)
 	}
 
-	_, _ = w.WriteString(result)
+	_, _ = w.WriteString(p.withSourceComment(macroName, result))
 	if tryNext {
 		return converter.RenderTryNext
 	}
 	return converter.RenderSuccess
 }
 
+// withSourceComment prefixes a rendered macro with its source comment when enabled
+func (p *ConfluencePlugin) withSourceComment(macroName, result string) string {
+	comment := p.sourceComment("macro", macroName)
+	if comment == "" || result == "" {
+		return result
+	}
+	if strings.Contains(result, "\n") || strings.HasPrefix(result, ">") {
+		// Block output must start on its own line to keep its markdown structure
+		return comment + "\n" + result
+	}
+	return comment + result
+}
+
+// sourceComment returns an HTML comment tracing an element back to the current page, or "" when disabled
+func (p *ConfluencePlugin) sourceComment(kind, name string) string {
+	if !p.sourceComments {
+		return ""
+	}
+	if p.currentPage != nil && p.currentPage.ID != "" {
+		return fmt.Sprintf("<!-- source: page=%s %s=%s -->", p.currentPage.ID, kind, name)
+	}
+	return fmt.Sprintf("<!-- source: %s=%s -->", kind, name)
+}
+
 func (p *ConfluencePlugin) handleBlockquoteMacro(ctx converter.Context, n *html.Node, emoji, label string) string {
+	if p.fencedDivs {
+		return p.handleFencedDivMacro(ctx, n)
+	}
+
 	content := p.convertNestedHTML(ctx, n)
 	prefix := fmt.Sprintf("%s **%s:**", emoji, label)
 
@@ -645,23 +1013,41 @@ func (p *ConfluencePlugin) handleBlockquoteMacro(ctx converter.Context, n *html.
 	return fmt.Sprintf("> %s %s", prefix, content)
 }
 
-// handleCodeMacro converts code macros to code blocks
-func (p *ConfluencePlugin) handleCodeMacro(n *html.Node) string {
-	// Convert node to goquery selection for compatibility with existing logic
-	var buf strings.Builder
-	_ = html.Render(&buf, n)
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(buf.String()))
-	if err != nil {
-		return fmt.Sprintf("<!-- Error rendering macro: %s -->", err.Error())
+// macroAllowed reports whether the macro filter keeps the named macro
+func (p *ConfluencePlugin) macroAllowed(macroName string) bool {
+	name := strings.ToLower(macroName)
+	if p.dropMacros[name] || p.excludedMacros[name] {
+		return false
 	}
-	selection := doc.Selection
-	rawHTML, _ := selection.Html()
-	language := extractLanguageParameter(rawHTML)
+	return p.onlyMacros == nil || p.onlyMacros[name]
+}
 
-	code := extractPlainTextBodyContent(selection, rawHTML)
-	if code == "" {
-		code = extractCodeContent(rawHTML)
+// handleUnknownMacro renders a macro without a dedicated handler according to the unknown macro mode
+func (p *ConfluencePlugin) handleUnknownMacro(n *html.Node, macroName string) string {
+	p.pageUnsupported = append(p.pageUnsupported, macroName)
+	switch p.unknownMacro {
+	case UnknownMacroWarn:
+		return fmt.Sprintf("> ⚠️ **%s:** `%s` %s", p.labels.Get(LabelUnsupportedMacro), macroName, p.labels.Get(LabelNotConverted))
+	case UnknownMacroRaw:
+		var builder strings.Builder
+		builder.WriteString("macro: " + macroName + "\n")
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && child.Data == "ac:parameter" {
+				builder.WriteString(fmt.Sprintf("%s: %s\n", GetAttr(child, "ac:name"), nodeText(child)))
+			}
+		}
+		fence := codeFence(builder.String())
+		return fence + "\n" + builder.String() + fence + "\n"
+	case UnknownMacroDrop:
+		return ""
 	}
+	return fmt.Sprintf("<!-- Unsupported macro: %s -->", macroName)
+}
+
+// handleCodeMacro converts code macros to code blocks
+func (p *ConfluencePlugin) handleCodeMacro(n *html.Node) string {
+	language := findParameter(n, "language")
+	code := findPlainTextBody(n)
 
 	if language != "" {
 		return fmt.Sprintf("```%s\n%s\n```\n", language, code)
@@ -670,36 +1056,20 @@ func (p *ConfluencePlugin) handleCodeMacro(n *html.Node) string {
 }
 
 func (p *ConfluencePlugin) handleJiraMacro(n *html.Node) string {
-	var buf strings.Builder
-	_ = html.Render(&buf, n)
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(buf.String()))
-	if err != nil {
-		return fmt.Sprintf("<!-- Error rendering macro: %s -->", err.Error())
+	jira := findParameter(n, "key")
+	if jira == "" {
+		return "<!-- jira macro has no issue key -->"
 	}
-	selection := doc.Selection
-
-	jira := extractMacroParameter(selection, "key")
 
-	if p.baseURL != "" {
-		return fmt.Sprintf("[%s](%s/browse/%s)", 
-			jira, strings.Replace(p.baseURL, "confluence", "jira", 1), jira)
+	if p.jiraBaseURL != "" {
+		return fmt.Sprintf("[%s](%s/browse/%s)", jira, p.jiraBaseURL, jira)
 	}
 
-	return fmt.Sprintf("%s", jira)
+	return jira
 }
 
 func (p *ConfluencePlugin) handleMermaidMacro(n *html.Node) string {
-	var buf strings.Builder
-	_ = html.Render(&buf, n)
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(buf.String()))
-	if err != nil {
-		return fmt.Sprintf("<!-- Error rendering macro: %s -->", err.Error())
-	}
-	selection := doc.Selection
-
-	diagram := selection.Find("ac\\:plain-text-body").First().Text()
-
-	diagram = strings.TrimSpace(diagram)
+	diagram := strings.TrimSpace(findPlainTextBody(n))
 	if diagram == "" {
 		return "<!-- Empty mermaid macro -->"
 	}
@@ -707,35 +1077,173 @@ func (p *ConfluencePlugin) handleMermaidMacro(n *html.Node) string {
 }
 
 func (p *ConfluencePlugin) handleViewFileMacro(n *html.Node) string {
-	var buf strings.Builder
-	_ = html.Render(&buf, n)
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(buf.String()))
-	if err != nil {
-		return fmt.Sprintf("<!-- Error rendering macro: %s -->", err.Error())
+	attachment := findDescendant(n, "ri:attachment")
+	if attachment == nil || GetAttr(attachment, "ri:filename") == "" {
+		return "<!-- file attachment not found -->"
 	}
 
-	selection := doc.Find("ri\\:attachment")
+	filename := GetAttr(attachment, "ri:filename")
+	return fmt.Sprintf("[%s](%s/%s)", filename, p.imageFolder, filename)
+}
 
-	if filename, exists := selection.Attr("ri:filename"); exists {
-		return fmt.Sprintf("[%s](%s/%s)", filename, p.imageFolder, filename)
-  } else {
-		return "<!-- file attachment not found -->"
+func (p *ConfluencePlugin) handleAnchorMacro(n *html.Node) string {
+	anchor := nodeText(n)
+	if anchor == "" {
+		return "<!-- anchor macro has no anchor -->"
 	}
+	return p.anchorTag(p.anchorID(anchor))
 }
 
-func (p *ConfluencePlugin) handleAnchorMacro(n *html.Node) string {
-	var buf strings.Builder
-	_ = html.Render(&buf, n)
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(buf.String()))
+// handleCalendarMacro links to the Team Calendars view and optionally lists upcoming events
+func (p *ConfluencePlugin) handleCalendarMacro(n *html.Node) string {
+	var ids []string
+	for _, id := range strings.Split(findParameter(n, "id"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return "<!-- Team Calendar -->"
+	}
+
+	title := findParameter(n, "title")
+	if title == "" {
+		title = "Team Calendar"
+	}
+
+	var result strings.Builder
+	if p.baseURL != "" {
+		fmt.Fprintf(&result, "📅 **Calendar:** [%s](%s/calendar/previewcalendar.action?subCalendarId=%s)",
+			title, strings.TrimSuffix(p.baseURL, "/"), ids[0])
+	} else {
+		fmt.Fprintf(&result, "📅 **Calendar:** %s", title)
+	}
+
+	if p.client == nil || p.calendarEventDays <= 0 {
+		return result.String()
+	}
+
+	start := time.Now().UTC()
+	end := start.AddDate(0, 0, p.calendarEventDays)
+
+	var events []model.CalendarEvent
+	for _, id := range ids {
+		subEvents, err := p.client.GetCalendarEvents(id, start, end)
+		if err != nil {
+			p.warnf("Failed to fetch calendar events for %s: %v", id, err)
+			continue
+		}
+		events = append(events, subEvents...)
+	}
+
+	if len(events) == 0 {
+		fmt.Fprintf(&result, "\n\n_No upcoming events in the next %d days._", p.calendarEventDays)
+		return result.String()
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Start < events[j].Start
+	})
+
+	header := []string{p.labels.Get(LabelStart), p.labels.Get(LabelEnd), p.labels.Get(LabelEvent), p.labels.Get(LabelLocation)}
+	if p.dialect != DialectGFM && p.dialect != "" {
+		rows := make([][]string, 0, len(events))
+		for _, event := range events {
+			rows = append(rows, []string{formatCalendarTime(event.Start, event.AllDay), formatCalendarTime(event.End, event.AllDay), event.Title, event.Location})
+		}
+		result.WriteString("\n\n" + p.dataTable(title, header, rows))
+		return result.String()
+	}
+
+	fmt.Fprintf(&result, "\n\n| %s |\n|---|---|---|---|\n", strings.Join(header, " | "))
+	for _, event := range events {
+		fmt.Fprintf(&result, "| %s | %s | %s | %s |\n",
+			formatCalendarTime(event.Start, event.AllDay),
+			formatCalendarTime(event.End, event.AllDay),
+			escapeTableCell(event.Title),
+			escapeTableCell(event.Location))
+	}
+
+	return result.String()
+}
+
+// handleSearchMacro renders search macros as a static list of results or a placeholder describing the query
+func (p *ConfluencePlugin) handleSearchMacro(n *html.Node, macroName string) string {
+	spaceKey := findParameter(n, "spaceKey")
+	if spaceKey == "" {
+		spaceKey = findParameter(n, "spacekey")
+	}
+	cql := buildSearchCQL(
+		findParameter(n, "query"),
+		spaceKey,
+		findParameter(n, "labels"),
+		findParameter(n, "type"),
+	)
+	if cql == "" {
+		return fmt.Sprintf("<!-- Search (%s) -->", macroName)
+	}
+
+	limit := 10
+	if maxLimit, err := strconv.Atoi(findParameter(n, "maxLimit")); err == nil && maxLimit > 0 {
+		limit = maxLimit
+	}
+
+	return p.renderSearchResults(fmt.Sprintf("<!-- Search (%s): %s -->", macroName, commentText(cql)), cql, limit)
+}
+
+// renderSearchResults runs the CQL query once when search execution is enabled and lists the
+// matching pages below the placeholder comment; otherwise only the placeholder is returned
+func (p *ConfluencePlugin) renderSearchResults(placeholder, cql string, limit int) string {
+	if !p.executeSearch || p.client == nil {
+		return placeholder
+	}
+
+	pages, err := p.client.SearchContent(cql, limit)
 	if err != nil {
-		return fmt.Sprintf("<!-- Error rendering macro: %s -->", err.Error())
+		p.warnf("Failed to execute search %q: %v", cql, err)
+		return placeholder
 	}
 
-	anchor := doc.Text()
-	if anchor == "" {
-		return "<!-- anchor macro has no anchor -->"
+	var result strings.Builder
+	result.WriteString(placeholder)
+	result.WriteString("\n\n")
+	if len(pages) == 0 {
+		result.WriteString("_No results._\n")
+		return result.String()
+	}
+	for _, page := range pages {
+		pageURL, err := page.GetURL(p.baseURL)
+		if err != nil || p.baseURL == "" {
+			fmt.Fprintf(&result, "- %s\n", page.Title)
+			continue
+		}
+		fmt.Fprintf(&result, "- [%s](%s)\n", page.Title, pageURL)
 	}
-	return fmt.Sprintf("<a name=%s></a>", slug.Make(anchor))
+
+	return result.String()
+}
+
+// handleNumberedHeadingsMacro renders the macro body with hierarchically numbered headings
+func (p *ConfluencePlugin) handleNumberedHeadingsMacro(ctx converter.Context, n *html.Node) string {
+	content := p.convertNestedHTML(ctx, n)
+	if content == "" {
+		return ""
+	}
+
+	if !p.numberHeadings {
+		startLevel := 0
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != html.ElementNode || child.Data != "ac:parameter" || GetAttr(child, "ac:name") != "start-numbering-at" {
+				continue
+			}
+			if level := headingLevel(nodeText(child)); level > 0 {
+				startLevel = level
+			}
+		}
+		content = NumberHeadings(content, startLevel)
+	}
+
+	return content + "\n\n"
 }
 
 func (p *ConfluencePlugin) handleTocMacro(n *html.Node) (string, bool) {
@@ -760,6 +1268,10 @@ func (p *ConfluencePlugin) handleTocMacro(n *html.Node) (string, bool) {
 }
 
 func (p *ConfluencePlugin) handleExpandMacro(ctx converter.Context, n *html.Node) string {
+	if p.fencedDivs {
+		return p.handleFencedDivMacro(ctx, n)
+	}
+
 	// Extract content from rich-text-body using recursive conversion
 	content := p.convertNestedHTML(ctx, n)
 
@@ -773,6 +1285,11 @@ func (p *ConfluencePlugin) handleExpandMacro(ctx converter.Context, n *html.Node
 
 // convertNestedHTML recursively converts HTML content within macro nodes
 func (p *ConfluencePlugin) convertNestedHTML(ctx converter.Context, n *html.Node) string {
+	if body, ok := p.convertedBodies[n]; ok {
+		delete(p.convertedBodies, n)
+		return body
+	}
+
 	// Find ac:rich-text-body node
 	richTextBody := p.findRichTextBodyNode(n)
 	if richTextBody == nil {
@@ -827,34 +1344,6 @@ func (p *ConfluencePlugin) findRichTextBodyNode(n *html.Node) *html.Node {
 	return nil
 }
 
-func extractPlainTextBodyContent(selection *goquery.Selection, rawHTML string) string {
-	plainTextBody := selection.Find("ac\\:plain-text-body").First()
-	if plainTextBody.Length() == 0 {
-		return extractCodeContent(rawHTML)
-	}
-
-	preTag := plainTextBody.Find("pre[data-cdata='true']").First()
-	if preTag.Length() > 0 {
-		content := preTag.Text()
-
-		content = strings.ReplaceAll(content, "&lt;", "<")
-		content = strings.ReplaceAll(content, "&gt;", ">")
-		content = strings.ReplaceAll(content, "&amp;", "&")
-
-		return strings.TrimSpace(content)
-	}
-
-	return extractCodeContent(rawHTML)
-}
-
-func extractMacroParameter(selection *goquery.Selection, name string) string {
-	param := selection.Find(fmt.Sprintf("ac\\:parameter[ac\\:name='%s']", name)).First()
-	if param.Length() == 0 {
-		return ""
-	}
-	return strings.TrimSpace(param.Text())
-}
-
 // handleDetailsMacro extracts and returns the content without wrapping
 func (p *ConfluencePlugin) handleDetailsMacro(ctx converter.Context, n *html.Node) string {
 	content := p.convertNestedHTML(ctx, n)
@@ -917,25 +1406,21 @@ func (p *ConfluencePlugin) handleStatusMacro(n *html.Node) string {
 }
 
 func (p *ConfluencePlugin) handleAnchorLink(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
-	var buf strings.Builder
-	_ = html.Render(&buf, n)
-
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(buf.String()))
-	if err != nil {
+	anchor := GetAttr(n, "ac:anchor")
+	if anchor == "" {
 		return converter.RenderTryNext
 	}
 
-	selection := doc.Find("ac\\:link")
-	if anchor, exists := selection.Attr("ac:anchor"); exists {
-		linkText := selection.Find("ac\\:plain-text-link-body").Text()
-		linkText = strings.TrimSpace(linkText)
-		if linkText == "" {
-			return converter.RenderTryNext
-		}
-		_, _ = fmt.Fprintf(w, "[%s](#%s)", linkText, slug.Make(anchor))
-		return converter.RenderSuccess
+	body := findDescendant(n, "ac:plain-text-link-body")
+	if body == nil {
+		return converter.RenderTryNext
 	}
-	return converter.RenderTryNext
+	linkText := nodeText(body)
+	if linkText == "" {
+		return converter.RenderTryNext
+	}
+	_, _ = fmt.Fprintf(w, "[%s](#%s)", linkText, p.anchorID(anchor))
+	return converter.RenderSuccess
 }
 
 // handleLink converts Confluence user links and other ac:link elements
@@ -957,6 +1442,8 @@ func (p *ConfluencePlugin) handleLink(ctx converter.Context, w converter.Writer,
 			if accountID != "" {
 				if displayName, ok := p.userCache[accountID]; ok {
 					_, _ = fmt.Fprintf(w, " @%s ", displayName)
+				} else if p.unresolvedUsers[accountID] && p.userPlaceholder != "" {
+					_, _ = fmt.Fprintf(w, " @%s ", p.userPlaceholder)
 				} else {
 					// Fallback to account ID
 					_, _ = fmt.Fprintf(w, " @user(%s) ", accountID)
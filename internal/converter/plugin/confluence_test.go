@@ -1,12 +1,15 @@
 package plugin
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
 	htmldom "golang.org/x/net/html"
 
 	convpkg "github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
 	"github.com/jackchuka/confluence-md/internal/confluence/model"
 	mock_attachments "github.com/jackchuka/confluence-md/internal/converter/plugin/attachments/mock"
 	gomock "go.uber.org/mock/gomock"
@@ -127,6 +130,58 @@ func TestHandleCodeMacro(t *testing.T) {
 	}
 }
 
+func TestHandleUnknownMacro(t *testing.T) {
+	node := findNode(t, `<ac:structured-macro ac:name="roadmap"><ac:parameter ac:name="source">abc</ac:parameter><ac:parameter ac:name="title">Q3</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+
+	for _, tt := range []struct {
+		mode UnknownMacroMode
+		want string
+	}{
+		{"", "<!-- Unsupported macro: roadmap -->"},
+		{UnknownMacroComment, "<!-- Unsupported macro: roadmap -->"},
+		{UnknownMacroWarn, "> ⚠️ **Unsupported macro:** `roadmap` was not converted"},
+		{UnknownMacroRaw, "```\nmacro: roadmap\nsource: abc\ntitle: Q3\n```\n"},
+		{UnknownMacroDrop, ""},
+	} {
+		plugin := &ConfluencePlugin{unknownMacro: tt.mode}
+		if got := plugin.handleUnknownMacro(node, "roadmap"); got != tt.want {
+			t.Errorf("mode %q: got %q, want %q", tt.mode, got, tt.want)
+		}
+	}
+
+	fenced := findNode(t, "<ac:structured-macro ac:name=\"roadmap\"><ac:parameter ac:name=\"query\">```x```</ac:parameter></ac:structured-macro>", "ac:structured-macro")
+	if got, want := (&ConfluencePlugin{unknownMacro: UnknownMacroRaw}).handleUnknownMacro(fenced, "roadmap"), "````\nmacro: roadmap\nquery: ```x```\n````\n"; got != want {
+		t.Errorf("raw mode with backticks: got %q, want %q", got, want)
+	}
+
+	if _, err := ParseUnknownMacroMode("hide"); err == nil {
+		t.Fatal("expected error for invalid mode")
+	}
+}
+
+func TestMacroFilter(t *testing.T) {
+	tracker := findNode(t, `<ac:structured-macro ac:name="viewtracker"></ac:structured-macro>`, "ac:structured-macro")
+
+	plugin := &ConfluencePlugin{}
+	WithMacroFilter([]string{"ViewTracker"}, nil)(plugin)
+	var out strings.Builder
+	plugin.handleMacro(nil, &out, tracker)
+	if out.Len() != 0 {
+		t.Fatalf("expected dropped macro to render nothing, got %q", out.String())
+	}
+
+	plugin = &ConfluencePlugin{}
+	WithMacroFilter(nil, []string{"info"})(plugin)
+	if !plugin.macroAllowed("info") || plugin.macroAllowed("jira") {
+		t.Fatal("expected only the info macro to be allowed")
+	}
+	out.Reset()
+	plugin.handleMacro(nil, &out, tracker)
+	if out.Len() != 0 {
+		t.Fatalf("expected macro outside --only-macros to render nothing, got %q", out.String())
+	}
+}
+
 func TestHandleMermaidCloudMacro(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	mockResolver := mock_attachments.NewMockResolver(ctrl)
@@ -152,6 +207,97 @@ func TestHandleMermaidCloudMacroMissingResolver(t *testing.T) {
 	}
 }
 
+func TestHandleAnchorLink(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	node := findNode(t, `<ac:link ac:anchor="Getting Started"><ac:plain-text-link-body><pre data-cdata='true'>Start here</pre></ac:plain-text-link-body></ac:link>`, "ac:link")
+	var out strings.Builder
+	if status := plugin.handleAnchorLink(nil, &out, node); status != convpkg.RenderSuccess {
+		t.Fatalf("expected render success, got %v", status)
+	}
+	if out.String() != "[Start here](#getting-started)" {
+		t.Fatalf("unexpected anchor link: %q", out.String())
+	}
+
+	out.Reset()
+	if status := plugin.handleAnchorLink(nil, &out, findNode(t, `<ac:link><ri:user ri:account-id="abc"></ri:user></ac:link>`, "ac:link")); status != convpkg.RenderTryNext {
+		t.Fatalf("expected try next for non-anchor link, got %v", status)
+	}
+}
+
+func TestHandleCalendarMacro(t *testing.T) {
+	markup := `<ac:structured-macro ac:name="calendar"><ac:parameter ac:name="id">cal-1</ac:parameter><ac:parameter ac:name="title">Team</ac:parameter></ac:structured-macro>`
+
+	plugin := &ConfluencePlugin{baseURL: "https://example.com"}
+	result := plugin.handleCalendarMacro(findNode(t, markup, "ac:structured-macro"))
+	expected := "📅 **Calendar:** [Team](https://example.com/calendar/previewcalendar.action?subCalendarId=cal-1)"
+	if result != expected {
+		t.Fatalf("unexpected calendar link: %q", result)
+	}
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_confluence.NewMockClient(ctrl)
+	mockClient.EXPECT().GetCalendarEvents("cal-1", gomock.Any(), gomock.Any()).Return([]model.CalendarEvent{
+		{Title: "Retro", Start: "2024-01-03T10:00:00Z", End: "2024-01-03T11:00:00Z"},
+		{Title: "Offsite", Start: "2024-01-02T00:00:00Z", End: "2024-01-02T00:00:00Z", AllDay: true, Location: "HQ"},
+	}, nil)
+	plugin = &ConfluencePlugin{baseURL: "https://example.com", client: mockClient, calendarEventDays: 30}
+	result = plugin.handleCalendarMacro(findNode(t, markup, "ac:structured-macro"))
+	if !strings.Contains(result, "| 2024-01-02 | 2024-01-02 | Offsite | HQ |\n| 2024-01-03 10:00 | 2024-01-03 11:00 | Retro |   |") {
+		t.Fatalf("unexpected calendar events table: %q", result)
+	}
+}
+
+func TestUnresolvedUserMentions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockClient := mock_confluence.NewMockClient(ctrl)
+	mockClient.EXPECT().GetUser("gone").Return(nil, fmt.Errorf("failed to get user gone: %w", confluence.ErrNotFound))
+	mockClient.EXPECT().GetUser("anon").Return(&model.ConfluenceUser{AccountID: "anon", AccountType: "unknown"}, nil)
+
+	plugin := NewConfluencePluginWithClient(mockClient, nil, "", WithUnresolvedUserPlaceholder("@former-user"))
+	plugin.SetCurrentPage(&model.ConfluencePage{ID: "123", Content: model.ConfluenceContent{Storage: model.ContentStorage{
+		Value: `<ac:link><ri:user ri:account-id="gone"></ri:user></ac:link><ac:link><ri:user ri:account-id="anon"></ri:user></ac:link>`,
+	}}})
+
+	if got := plugin.UnresolvedUsers(); strings.Join(got, ",") != "anon,gone" {
+		t.Fatalf("unexpected unresolved users: %v", got)
+	}
+
+	var out strings.Builder
+	plugin.handleLink(nil, &out, findNode(t, `<ac:link><ri:user ri:account-id="gone"></ri:user></ac:link>`, "ac:link"))
+	if out.String() != " @former-user " {
+		t.Fatalf("unexpected mention: %q", out.String())
+	}
+}
+
+func TestHandleEmoticonImage(t *testing.T) {
+	markup := `<ac:emoticon ac:name="blue-star" ac:emoji-shortname=":party-parrot:" ac:emoji-fallback=":party-parrot:" ac:emoji-url="https://example.com/emoji/parrot.GIF?v=2"></ac:emoticon>`
+
+	var out strings.Builder
+	plugin := &ConfluencePlugin{imageFolder: "assets"}
+	plugin.handleEmoticon(nil, &out, findNode(t, markup, "ac:emoticon"))
+	if out.String() != ":party-parrot: " {
+		t.Fatalf("expected fallback without emoji images, got %q", out.String())
+	}
+
+	out.Reset()
+	plugin = &ConfluencePlugin{imageFolder: "assets", downloadEmojis: true}
+	plugin.handleEmoticon(nil, &out, findNode(t, markup, "ac:emoticon"))
+	plugin.handleEmoticon(nil, &out, findNode(t, markup, "ac:emoticon"))
+	if !strings.HasPrefix(out.String(), `<img src="assets/emoji/party-parrot.gif" alt=":party-parrot:" height="20"> `) {
+		t.Fatalf("unexpected emoji image: %q", out.String())
+	}
+	if emojis := plugin.Emojis(); len(emojis) != 1 || emojis[0].URL != "https://example.com/emoji/parrot.GIF?v=2" {
+		t.Fatalf("unexpected emoji refs: %+v", emojis)
+	}
+
+	out.Reset()
+	plugin.handleEmoticon(nil, &out, findNode(t, `<ac:emoticon ac:emoji-shortname=":Party-Parrot:" ac:emoji-url="https://example.com/emoji/other.gif"></ac:emoticon>`, "ac:emoticon"))
+	emojis := plugin.Emojis()
+	if len(emojis) != 2 || emojis[1].FileName == emojis[0].FileName || !strings.HasPrefix(emojis[1].FileName, "party-parrot-") {
+		t.Fatalf("expected names that slug alike to get distinct files, got %+v", emojis)
+	}
+}
+
 func findNode(t *testing.T, markup, tag string) *htmldom.Node {
 	t.Helper()
 	node, err := htmldom.Parse(strings.NewReader(markup))
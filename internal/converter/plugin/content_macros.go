@@ -0,0 +1,81 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// handleContentByLabelMacro lists the pages carrying the macro's labels. The query only runs with
+// search execution enabled; otherwise the CQL is kept as a comment.
+func (p *ConfluencePlugin) handleContentByLabelMacro(n *html.Node) string {
+	cql := p.contentByLabelCQL(n)
+	if cql == "" {
+		return "<!-- Content by label -->"
+	}
+
+	limit := 5
+	if maxLimit, err := strconv.Atoi(findParameter(n, "max")); err == nil && maxLimit > 0 {
+		limit = maxLimit
+	}
+
+	return p.renderSearchResults(fmt.Sprintf("<!-- Content by label: %s -->", cql), cql, limit)
+}
+
+// contentByLabelCQL returns the macro's stored CQL, or builds it from the legacy labels/spaces parameters
+func (p *ConfluencePlugin) contentByLabelCQL(n *html.Node) string {
+	currentSpace := ""
+	if p.currentPage != nil {
+		currentSpace = p.currentPage.SpaceKey
+	}
+
+	if cql := findParameter(n, "cql"); cql != "" {
+		if currentSpace != "" {
+			cql = strings.ReplaceAll(cql, "currentSpace()", quoteCQL(currentSpace))
+		}
+		return cql
+	}
+
+	cql := buildSearchCQL("", "", firstParameter(n, "labels", "label"), findParameter(n, "type"))
+	if cql == "" {
+		return ""
+	}
+
+	var spaces []string
+	for _, key := range strings.Split(firstParameter(n, "spaces", "space"), ",") {
+		switch key = strings.TrimSpace(key); key {
+		case "":
+		case "@self":
+			if currentSpace != "" {
+				spaces = append(spaces, quoteCQL(currentSpace))
+			}
+		default:
+			spaces = append(spaces, quoteCQL(key))
+		}
+	}
+	if len(spaces) > 0 {
+		cql += fmt.Sprintf(" AND space in (%s)", strings.Join(spaces, ", "))
+	}
+	return cql
+}
+
+// handleMetadataMacro renders a metadata field as "**Name:** value"
+func (p *ConfluencePlugin) handleMetadataMacro(ctx converter.Context, n *html.Node) string {
+	name := firstParameter(n, "", "name")
+	if name == "" {
+		name = "Metadata"
+	}
+	return p.labeledValue(name, p.convertNestedHTML(ctx, n))
+}
+
+// handleWrapperMacro unwraps span/div styling macros, keeping span content inline
+func (p *ConfluencePlugin) handleWrapperMacro(ctx converter.Context, n *html.Node, macroName string) string {
+	content := p.convertNestedHTML(ctx, n)
+	if content == "" || macroName == "span" {
+		return content
+	}
+	return content + "\n\n"
+}
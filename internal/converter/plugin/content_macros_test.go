@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"testing"
+
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestContentByLabelCQL(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", SpaceKey: "DOCS"})
+
+	for _, tt := range []struct {
+		markup string
+		want   string
+	}{
+		{`<ac:structured-macro ac:name="content-by-label"><ac:parameter ac:name="cql">label = "howto" and space = currentSpace()</ac:parameter></ac:structured-macro>`, `label = "howto" and space = "DOCS"`},
+		{`<ac:structured-macro ac:name="content-by-label"><ac:parameter ac:name="labels">howto,faq</ac:parameter><ac:parameter ac:name="spaces">@self,OPS</ac:parameter></ac:structured-macro>`, `label in ("howto", "faq") AND space in ("DOCS", "OPS")`},
+		{`<ac:structured-macro ac:name="content-by-label"></ac:structured-macro>`, ``},
+	} {
+		if got := plugin.contentByLabelCQL(findNode(t, tt.markup, "ac:structured-macro")); got != tt.want {
+			t.Errorf("got %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestHandleContentByLabelMacro(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_confluence.NewMockClient(ctrl)
+	client.EXPECT().SearchContent(`label in ("howto")`, 3).Return([]*model.ConfluencePage{{ID: "7", Title: "Install"}}, nil)
+
+	plugin := &ConfluencePlugin{client: client, executeSearch: true, baseURL: "https://example.com"}
+	node := findNode(t, `<ac:structured-macro ac:name="content-by-label"><ac:parameter ac:name="labels">howto</ac:parameter><ac:parameter ac:name="max">3</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+	want := "<!-- Content by label: label in (\"howto\") -->\n\n- [Install](https://example.com/pages/viewpage.action?pageId=7)\n"
+	if got := plugin.handleContentByLabelMacro(node); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestHandleMetadataMacro(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	node := findNode(t, `<ac:structured-macro ac:name="metadata"><ac:parameter ac:name="">Owner</ac:parameter><ac:rich-text-body>Jane</ac:rich-text-body></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleMetadataMacro(nil, node), "**Owner:** Jane\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,105 @@
+package plugin
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// diagramApp describes how a third-party diagram macro names its board and where the board lives
+type diagramApp struct {
+	label      string
+	linkFormat string   // turns the board ID into a URL, empty when the app has no public board URL
+	idParams   []string // parameters holding the board ID
+}
+
+// diagramApps maps diagram-app macro names onto their apps
+var diagramApps = map[string]diagramApp{
+	"mockup":        {label: "Balsamiq mockup"},
+	"balsamiq":      {label: "Balsamiq mockup"},
+	"lucidchart":    {label: "Lucidchart diagram", linkFormat: "https://lucid.app/lucidchart/%s/view", idParams: []string{"documentId", "docId", "documentToken"}},
+	"miro":          {label: "Miro board", linkFormat: "https://miro.com/app/board/%s/", idParams: []string{"boardId", "board"}},
+	"realtimeboard": {label: "Miro board", linkFormat: "https://miro.com/app/board/%s/", idParams: []string{"boardId", "board"}},
+}
+
+// handleDiagramMacro renders a diagram-app embed as its stored preview image when the page has one,
+// and otherwise as a titled link to the external board
+func (p *ConfluencePlugin) handleDiagramMacro(n *html.Node, macroName string) string {
+	app := diagramApps[macroName]
+
+	title := firstParameter(n, "name", "Name", "title", "diagramName")
+	id := firstParameter(n, app.idParams...)
+	link := markdownURL(firstParameter(n, "url", "link", "boardUrl"))
+	if link == "" && id != "" && app.linkFormat != "" {
+		link = fmt.Sprintf(app.linkFormat, url.PathEscape(id))
+	}
+	if title == "" {
+		title = app.label
+	}
+
+	if preview := p.findDiagramPreview(firstParameter(n, "preview", "previewFile"), title, id); preview != "" {
+		p.addDiagramPreview(preview)
+		result := fmt.Sprintf("![%s](%s/%s)", title, p.imageFolder, url.PathEscape(preview))
+		if link != "" {
+			result += fmt.Sprintf("\n\n[%s %s](%s)", p.labels.Get(LabelOpen), app.label, link)
+		}
+		return result + "\n\n"
+	}
+
+	if link == "" {
+		return fmt.Sprintf("📐 **%s:** %s (%s)\n\n", app.label, title, p.labels.Get(LabelNotExported))
+	}
+	return fmt.Sprintf("📐 **%s:** [%s](%s)\n\n", app.label, title, link)
+}
+
+// findDiagramPreview returns the page attachment holding the diagram's preview image, if any
+func (p *ConfluencePlugin) findDiagramPreview(candidates ...string) string {
+	if p.currentPage == nil {
+		return ""
+	}
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+		names := []string{candidate}
+		if path.Ext(candidate) == "" {
+			names = []string{candidate + ".png", candidate + ".jpg", candidate + ".svg"}
+		}
+		for _, name := range names {
+			for _, attachment := range p.currentPage.Attachments {
+				if strings.EqualFold(attachment.Title, name) {
+					return attachment.Title
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// addDiagramPreview records a preview image for download once per page
+func (p *ConfluencePlugin) addDiagramPreview(fileName string) {
+	for _, existing := range p.diagramPreviews {
+		if existing == fileName {
+			return
+		}
+	}
+	p.diagramPreviews = append(p.diagramPreviews, fileName)
+}
+
+// DiagramPreviews returns the preview attachments of diagram-app macros on the current page
+func (p *ConfluencePlugin) DiagramPreviews() []string {
+	return p.diagramPreviews
+}
+
+// firstParameter returns the first non-empty macro parameter among names
+func firstParameter(n *html.Node, names ...string) string {
+	for _, name := range names {
+		if value := findParameter(n, name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
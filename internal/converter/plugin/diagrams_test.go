@@ -0,0 +1,40 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestHandleDiagramMacro(t *testing.T) {
+	plugin := &ConfluencePlugin{imageFolder: "assets"}
+	plugin.SetCurrentPage(&model.ConfluencePage{ID: "1", Attachments: []model.ConfluenceAttachment{{Title: "Checkout Flow.png"}}})
+
+	node := findNode(t, `<ac:structured-macro ac:name="mockup"><ac:parameter ac:name="Name">Checkout Flow</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleDiagramMacro(node, "mockup"), "![Checkout Flow](assets/Checkout%20Flow.png)\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if previews := plugin.DiagramPreviews(); len(previews) != 1 || previews[0] != "Checkout Flow.png" {
+		t.Fatalf("DiagramPreviews() = %v", previews)
+	}
+
+	node = findNode(t, `<ac:structured-macro ac:name="miro"><ac:parameter ac:name="boardId">uXjVO</ac:parameter><ac:parameter ac:name="title">Retro</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleDiagramMacro(node, "miro"), "📐 **Miro board:** [Retro](https://miro.com/app/board/uXjVO/)\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	node = findNode(t, `<ac:structured-macro ac:name="lucidchart"><ac:parameter ac:name="name">Network</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleDiagramMacro(node, "lucidchart"), "📐 **Lucidchart diagram:** Network (not exported)\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	node = findNode(t, `<ac:structured-macro ac:name="miro"><ac:parameter ac:name="url">https://miro.com/app/board/a b)(x/</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleDiagramMacro(node, "miro"), "📐 **Miro board:** [Miro board](https://miro.com/app/board/a%20b%29%28x/)\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	node = findNode(t, `<ac:structured-macro ac:name="lucidchart"><ac:parameter ac:name="url">javascript:alert(1)</ac:parameter><ac:parameter ac:name="documentId">a/b c</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleDiagramMacro(node, "lucidchart"), "📐 **Lucidchart diagram:** [Lucidchart diagram](https://lucid.app/lucidchart/a%2Fb%20c/view)\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
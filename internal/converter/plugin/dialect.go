@@ -0,0 +1,196 @@
+package plugin
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	nethtml "golang.org/x/net/html"
+)
+
+var headingAttrRegex = regexp.MustCompile(`\{#[^}]*\}\s*$`)
+
+// Dialect selects the Markdown flavour written, so the output only uses constructs the consuming renderer supports
+type Dialect string
+
+const (
+	DialectGFM        Dialect = "gfm"        // pipe tables, task lists and ~~strikethrough~~ (default)
+	DialectCommonMark Dialect = "commonmark" // strict CommonMark: tables and strikethrough as HTML, task lists as ☐/☑ items
+	DialectPandoc     Dialect = "pandoc"     // Pandoc Markdown: captioned pipe tables, {#id} heading attributes and []{#id} anchors
+)
+
+// ParseDialect validates a dialect name; an empty name selects DialectGFM
+func ParseDialect(name string) (Dialect, error) {
+	switch dialect := Dialect(strings.ToLower(name)); dialect {
+	case "":
+		return DialectGFM, nil
+	case DialectGFM, DialectCommonMark, DialectPandoc:
+		return dialect, nil
+	}
+	return "", fmt.Errorf("dialect must be gfm, commonmark or pandoc, got: %s", name)
+}
+
+// SupportsExtensions reports whether the dialect has pipe tables, task lists and strikethrough
+func (d Dialect) SupportsExtensions() bool {
+	return d != DialectCommonMark
+}
+
+// WithDialect sets the Markdown flavour written
+func WithDialect(dialect Dialect) Option {
+	return func(p *ConfluencePlugin) {
+		p.dialect = dialect
+	}
+}
+
+// handleTaskList renders a Confluence task list as a Markdown list with checkboxes
+func (p *ConfluencePlugin) handleTaskList(ctx converter.Context, w converter.Writer, n *nethtml.Node) converter.RenderStatus {
+	var items strings.Builder
+	for task := n.FirstChild; task != nil; task = task.NextSibling {
+		if task.Type != nethtml.ElementNode || task.Data != "ac:task" {
+			continue
+		}
+
+		complete := false
+		var body strings.Builder
+		for child := task.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != nethtml.ElementNode {
+				continue
+			}
+			switch child.Data {
+			case "ac:task-status":
+				complete = strings.TrimSpace(nodeText(child)) == "complete"
+			case "ac:task-body":
+				ctx.RenderChildNodes(ctx, &body, child)
+			}
+		}
+		items.WriteString(taskListItem(p.dialect, complete, body.String()))
+	}
+
+	_, _ = w.WriteString("\n\n" + items.String() + "\n")
+	return converter.RenderSuccess
+}
+
+// taskListItem writes one task as a list item, with continuation lines indented under the marker
+func taskListItem(dialect Dialect, complete bool, body string) string {
+	marker := "- [ ] "
+	switch {
+	case dialect.SupportsExtensions() && complete:
+		marker = "- [x] "
+	case !dialect.SupportsExtensions() && complete:
+		marker = "- ☑ "
+	case !dialect.SupportsExtensions():
+		marker = "- ☐ "
+	}
+	body = strings.TrimSpace(body)
+	return marker + strings.ReplaceAll(body, "\n", "\n  ") + "\n"
+}
+
+// handleStrikethrough writes <s>, <del> and <strike> as ~~text~~, or as <del> HTML in strict CommonMark
+func (p *ConfluencePlugin) handleStrikethrough(ctx converter.Context, w converter.Writer, n *nethtml.Node) converter.RenderStatus {
+	var content strings.Builder
+	ctx.RenderChildNodes(ctx, &content, n)
+	_, _ = w.WriteString(strikethrough(p.dialect, content.String()))
+	return converter.RenderSuccess
+}
+
+// strikethrough wraps the text, keeping surrounding whitespace outside the delimiters so they are recognized
+func strikethrough(dialect Dialect, text string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	start := strings.Index(text, trimmed)
+	open, closing := "~~", "~~"
+	if !dialect.SupportsExtensions() {
+		open, closing = "<del>", "</del>"
+	}
+	return text[:start] + open + trimmed + closing + text[start+len(trimmed):]
+}
+
+// dataTable writes generated rows as a pipe table, or as an HTML table in strict CommonMark.
+// The caption is kept where the dialect has one: a ": caption" line in Pandoc, <caption> in HTML.
+func (p *ConfluencePlugin) dataTable(caption string, header []string, rows [][]string) string {
+	if p.dialect.SupportsExtensions() {
+		table := pipeTable(header, rows)
+		if p.dialect == DialectPandoc && caption != "" {
+			table += "\n: " + caption + "\n"
+		}
+		return table
+	}
+
+	var table strings.Builder
+	table.WriteString("<table>\n")
+	if caption != "" {
+		table.WriteString("<caption>" + html.EscapeString(caption) + "</caption>\n")
+	}
+	table.WriteString("<thead>\n<tr>")
+	for _, cell := range header {
+		table.WriteString("<th>" + html.EscapeString(cell) + "</th>")
+	}
+	table.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for _, row := range rows {
+		table.WriteString("<tr>")
+		for i := range header {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			table.WriteString("<td>" + html.EscapeString(cell) + "</td>")
+		}
+		table.WriteString("</tr>\n")
+	}
+	table.WriteString("</tbody>\n</table>\n")
+	return table.String()
+}
+
+// pipeTable writes rows as a Markdown pipe table with as many columns as the header
+func pipeTable(header []string, rows [][]string) string {
+	line := func(cells []string) string {
+		padded := make([]string, len(header))
+		for i := range padded {
+			if i < len(cells) {
+				padded[i] = strings.TrimSpace(escapeTableCell(cells[i]))
+			}
+		}
+		return "| " + strings.Join(padded, " | ") + " |\n"
+	}
+
+	var table strings.Builder
+	table.WriteString(line(header))
+	table.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range rows {
+		table.WriteString(line(row))
+	}
+	return table.String()
+}
+
+// anchorTag writes an anchor target: a bracketed empty span with an id in Pandoc, an <a name> shim elsewhere
+func (p *ConfluencePlugin) anchorTag(id string) string {
+	if p.dialect == DialectPandoc {
+		return "[]{#" + id + "}"
+	}
+	return fmt.Sprintf("<a name=%s></a>", id)
+}
+
+// AddPandocHeadingIDs gives headings without an explicit id a {#id} attribute built like anchor links,
+// so links to headings resolve under Pandoc's own identifier rules. Other dialects are returned unchanged.
+func (p *ConfluencePlugin) AddPandocHeadingIDs(markdown string) string {
+	if p.dialect != DialectPandoc {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	ForEachHeading(lines, func(i int, level int, text string) {
+		if headingAttrRegex.MatchString(text) {
+			return
+		}
+		plain := headingLinkRegex.ReplaceAllString(text, "$1")
+		plain = strings.NewReplacer("**", "", "`", "").Replace(plain)
+		if id := p.anchorID(plain); id != "" {
+			lines[i] = fmt.Sprintf("%s %s {#%s}", strings.Repeat("#", level), text, id)
+		}
+	})
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,90 @@
+package plugin
+
+import "testing"
+
+func TestParseDialect(t *testing.T) {
+	if dialect, err := ParseDialect(""); err != nil || dialect != DialectGFM {
+		t.Fatalf("expected gfm for empty name, got %q, %v", dialect, err)
+	}
+	if dialect, err := ParseDialect("CommonMark"); err != nil || dialect != DialectCommonMark {
+		t.Fatalf("expected commonmark, got %q, %v", dialect, err)
+	}
+	if _, err := ParseDialect("markdown-extra"); err == nil {
+		t.Fatal("expected an error for an unknown dialect")
+	}
+}
+
+func TestTaskListItem(t *testing.T) {
+	tests := []struct {
+		dialect  Dialect
+		complete bool
+		want     string
+	}{
+		{DialectGFM, false, "- [ ] Ship it\n  today\n"},
+		{DialectPandoc, true, "- [x] Ship it\n  today\n"},
+		{DialectCommonMark, false, "- ☐ Ship it\n  today\n"},
+		{DialectCommonMark, true, "- ☑ Ship it\n  today\n"},
+	}
+	for _, tt := range tests {
+		if got := taskListItem(tt.dialect, tt.complete, " Ship it\ntoday\n"); got != tt.want {
+			t.Errorf("taskListItem(%s, %v) = %q, want %q", tt.dialect, tt.complete, got, tt.want)
+		}
+	}
+}
+
+func TestStrikethrough(t *testing.T) {
+	if got := strikethrough(DialectGFM, " old "); got != " ~~old~~ " {
+		t.Errorf("gfm strikethrough = %q", got)
+	}
+	if got := strikethrough(DialectCommonMark, "old"); got != "<del>old</del>" {
+		t.Errorf("commonmark strikethrough = %q", got)
+	}
+	if got := strikethrough(DialectGFM, " "); got != " " {
+		t.Errorf("blank strikethrough = %q, want it unchanged", got)
+	}
+}
+
+func TestDataTableDialects(t *testing.T) {
+	header := []string{"Name", "Value"}
+	rows := [][]string{{"a|b", "1"}, {"<c>"}}
+
+	gfm := &ConfluencePlugin{}
+	wantPipe := "| Name | Value |\n| --- | --- |\n| a\\|b | 1 |\n| <c> |  |\n"
+	if got := gfm.dataTable("Totals", header, rows); got != wantPipe {
+		t.Errorf("gfm dataTable = %q, want %q", got, wantPipe)
+	}
+
+	pandoc := &ConfluencePlugin{dialect: DialectPandoc}
+	if got, want := pandoc.dataTable("Totals", header, rows), wantPipe+"\n: Totals\n"; got != want {
+		t.Errorf("pandoc dataTable = %q, want %q", got, want)
+	}
+
+	strict := &ConfluencePlugin{dialect: DialectCommonMark}
+	wantHTML := "<table>\n<caption>Totals</caption>\n<thead>\n<tr><th>Name</th><th>Value</th></tr>\n</thead>\n<tbody>\n<tr><td>a|b</td><td>1</td></tr>\n<tr><td>&lt;c&gt;</td><td></td></tr>\n</tbody>\n</table>\n"
+	if got := strict.dataTable("Totals", header, rows); got != wantHTML {
+		t.Errorf("commonmark dataTable = %q, want %q", got, wantHTML)
+	}
+
+	simple := `<table><tbody><tr><td><p>Only</p></td></tr></tbody></table>`
+	if !strict.useHTMLTable(findNode(t, simple, "tbody")) {
+		t.Error("expected commonmark to write every table as HTML")
+	}
+}
+
+func TestAddPandocHeadingIDs(t *testing.T) {
+	markdown := "# Getting Started\n\n```\n# not a heading\n```\n\n## [Setup](http://x) **now** {#custom}\n\n## Install `cli`"
+
+	pandoc := &ConfluencePlugin{dialect: DialectPandoc, anchorStyle: AnchorStrip}
+	want := "# Getting Started {#getting-started}\n\n```\n# not a heading\n```\n\n## [Setup](http://x) **now** {#custom}\n\n## Install `cli` {#install-cli}"
+	if got := pandoc.AddPandocHeadingIDs(markdown); got != want {
+		t.Errorf("AddPandocHeadingIDs() = %q, want %q", got, want)
+	}
+
+	gfm := &ConfluencePlugin{anchorStyle: AnchorStrip}
+	if got := gfm.AddPandocHeadingIDs(markdown); got != markdown {
+		t.Errorf("expected gfm headings to stay unchanged, got %q", got)
+	}
+	if got := pandoc.anchorTag("intro"); got != "[]{#intro}" {
+		t.Errorf("anchorTag() = %q", got)
+	}
+}
@@ -0,0 +1,86 @@
+package plugin
+
+import (
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// WithFencedDivs writes panels, expands and page layout sections and cells as Pandoc fenced divs
+// (::: {.info title="..."}), keeping their meaning for later conversion to PDF or Docx
+func WithFencedDivs() Option {
+	return func(p *ConfluencePlugin) {
+		p.fencedDivs = true
+	}
+}
+
+// divAttr is a key="value" attribute of a fenced div
+type divAttr struct {
+	key, value string
+}
+
+// fencedDiv wraps content in a fenced div with the class and attributes. Nested divs can use the same
+// fence, as Pandoc tells opening fences apart by their attributes.
+func fencedDiv(class string, attrs []divAttr, content string) string {
+	var div strings.Builder
+	div.WriteString("::: {." + class)
+	for _, attr := range attrs {
+		div.WriteString(" " + attr.key + `="` + strings.ReplaceAll(attr.value, `"`, `\"`) + `"`)
+	}
+	div.WriteString("}\n")
+	if content = strings.TrimSpace(content); content != "" {
+		div.WriteString(content + "\n")
+	}
+	div.WriteString(":::\n\n")
+	return div.String()
+}
+
+// handleFencedDivMacro writes a panel-like macro as a div classed with the macro name and its parameters as attributes
+func (p *ConfluencePlugin) handleFencedDivMacro(ctx converter.Context, n *html.Node) string {
+	var attrs []divAttr
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode || child.Data != "ac:parameter" {
+			continue
+		}
+		key, value := GetAttr(child, "ac:name"), strings.TrimSpace(nodeText(child))
+		if key != "" && value != "" {
+			attrs = append(attrs, divAttr{key: key, value: value})
+		}
+	}
+	return fencedDiv(GetAttr(n, "ac:name"), attrs, p.convertNestedHTML(ctx, n))
+}
+
+// handlePanelMacro writes the generic panel macro as a fenced div; without fenced divs it is an unknown macro
+func (p *ConfluencePlugin) handlePanelMacro(ctx converter.Context, n *html.Node) string {
+	if !p.fencedDivs {
+		return p.handleUnknownMacro(n, "panel")
+	}
+	return p.handleFencedDivMacro(ctx, n)
+}
+
+// handleLayoutSection writes a page layout section as a div with its column arrangement, e.g. type="two_equal"
+func (p *ConfluencePlugin) handleLayoutSection(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if !p.fencedDivs {
+		return converter.RenderTryNext
+	}
+	var attrs []divAttr
+	if layoutType := GetAttr(n, "ac:type"); layoutType != "" {
+		attrs = append(attrs, divAttr{key: "type", value: layoutType})
+	}
+	var content strings.Builder
+	ctx.RenderChildNodes(ctx, &content, n)
+	_, _ = w.WriteString("\n\n" + fencedDiv("layout-section", attrs, content.String()))
+	return converter.RenderSuccess
+}
+
+// handleLayoutCell writes a column of a page layout section as a div
+func (p *ConfluencePlugin) handleLayoutCell(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if !p.fencedDivs {
+		return converter.RenderTryNext
+	}
+	var content strings.Builder
+	ctx.RenderChildNodes(ctx, &content, n)
+	_, _ = w.WriteString("\n\n" + fencedDiv("layout-cell", nil, content.String()))
+	return converter.RenderSuccess
+}
@@ -0,0 +1,33 @@
+package plugin
+
+import "testing"
+
+func TestFencedDiv(t *testing.T) {
+	got := fencedDiv("panel", []divAttr{{"title", `Say "hi"`}, {"bgColor", "#fff"}}, "\nBody\n")
+	want := "::: {.panel title=\"Say \\\"hi\\\"\" bgColor=\"#fff\"}\nBody\n:::\n\n"
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	if got := fencedDiv("layout-cell", nil, " "); got != "::: {.layout-cell}\n:::\n\n" {
+		t.Fatalf("empty div = %q", got)
+	}
+}
+
+func TestFencedDivMacros(t *testing.T) {
+	info := `<ac:structured-macro ac:name="info"><ac:parameter ac:name="title">Heads up</ac:parameter><ac:rich-text-body>Read this first</ac:rich-text-body></ac:structured-macro>`
+	panel := `<ac:structured-macro ac:name="panel"><ac:parameter ac:name="borderStyle">dashed</ac:parameter><ac:rich-text-body>Boxed</ac:rich-text-body></ac:structured-macro>`
+
+	divs := &ConfluencePlugin{fencedDivs: true}
+	if got, want := divs.handleBlockquoteMacro(nil, findNode(t, info, "ac:structured-macro"), "ℹ️", "Info"), "::: {.info title=\"Heads up\"}\nRead this first\n:::\n\n"; got != want {
+		t.Errorf("info div = %q, want %q", got, want)
+	}
+	if got, want := divs.handlePanelMacro(nil, findNode(t, panel, "ac:structured-macro")), "::: {.panel borderStyle=\"dashed\"}\nBoxed\n:::\n\n"; got != want {
+		t.Errorf("panel div = %q, want %q", got, want)
+	}
+
+	quotes := &ConfluencePlugin{}
+	if got, want := quotes.handleBlockquoteMacro(nil, findNode(t, info, "ac:structured-macro"), "ℹ️", "Info"), "> ℹ️ **Info:** Read this first"; got != want {
+		t.Errorf("info without fenced divs = %q, want %q", got, want)
+	}
+}
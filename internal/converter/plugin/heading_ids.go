@@ -0,0 +1,67 @@
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// HeadingIDStyle selects how Confluence's heading anchors are preserved for existing deep links
+type HeadingIDStyle string
+
+const (
+	HeadingIDNone   HeadingIDStyle = "none"   // no explicit ids (default)
+	HeadingIDAttr   HeadingIDStyle = "attr"   // "## Heading {#id}" attribute, for Hugo, Pandoc and MkDocs
+	HeadingIDAnchor HeadingIDStyle = "anchor" // <a name="id"></a> shim on the line before the heading
+)
+
+var headingLinkRegex = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+
+// ParseHeadingIDStyle validates a heading id style; an empty name selects HeadingIDNone
+func ParseHeadingIDStyle(name string) (HeadingIDStyle, error) {
+	switch style := HeadingIDStyle(name); style {
+	case "":
+		return HeadingIDNone, nil
+	case HeadingIDNone, HeadingIDAttr, HeadingIDAnchor:
+		return style, nil
+	}
+	return "", fmt.Errorf("heading id style must be none, attr or anchor, got: %s", name)
+}
+
+// AddHeadingIDs gives every heading the anchor Confluence generated for it. Confluence Cloud uses the
+// heading text with spaces replaced by hyphens; Server and Data Center prefix the page title and drop
+// all whitespace, e.g. ReleaseNotes-GettingStarted.
+func AddHeadingIDs(markdown, pageTitle string, style HeadingIDStyle, cloud bool) string {
+	if style != HeadingIDAttr && style != HeadingIDAnchor {
+		return markdown
+	}
+
+	lines := strings.Split(markdown, "\n")
+	ForEachHeading(lines, func(i int, level int, text string) {
+		id := confluenceHeadingID(pageTitle, text, cloud)
+		if id == "" {
+			return
+		}
+		if style == HeadingIDAttr {
+			lines[i] = fmt.Sprintf("%s %s {#%s}", strings.Repeat("#", level), text, id)
+			return
+		}
+		lines[i] = fmt.Sprintf("<a name=\"%s\"></a>\n%s", id, lines[i])
+	})
+	return strings.Join(lines, "\n")
+}
+
+// confluenceHeadingID builds Confluence's anchor for a heading from its plain text
+func confluenceHeadingID(pageTitle, heading string, cloud bool) string {
+	heading = headingLinkRegex.ReplaceAllString(heading, "$1")
+	heading = strings.NewReplacer("**", "", "`", "", "\"", "").Replace(heading)
+	heading = strings.TrimSpace(heading)
+	if heading == "" {
+		return ""
+	}
+
+	if cloud {
+		return strings.Join(strings.Fields(heading), "-")
+	}
+	return strings.Join(strings.Fields(pageTitle), "") + "-" + strings.Join(strings.Fields(heading), "")
+}
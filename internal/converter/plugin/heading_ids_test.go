@@ -0,0 +1,25 @@
+package plugin
+
+import "testing"
+
+func TestAddHeadingIDs(t *testing.T) {
+	input := "# Getting Started\n\n```\n# not a heading\n```\n\n## Use **the** [CLI](https://example.com)"
+
+	tests := []struct {
+		name  string
+		style HeadingIDStyle
+		cloud bool
+		want  string
+	}{
+		{"server attr", HeadingIDAttr, false, "# Getting Started {#ReleaseNotes-GettingStarted}\n\n```\n# not a heading\n```\n\n## Use **the** [CLI](https://example.com) {#ReleaseNotes-UsetheCLI}"},
+		{"cloud anchor", HeadingIDAnchor, true, "<a name=\"Getting-Started\"></a>\n# Getting Started\n\n```\n# not a heading\n```\n\n<a name=\"Use-the-CLI\"></a>\n## Use **the** [CLI](https://example.com)"},
+		{"none", HeadingIDNone, false, input},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AddHeadingIDs(input, "Release Notes", tt.style, tt.cloud); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
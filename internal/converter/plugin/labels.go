@@ -0,0 +1,155 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Labels maps label IDs onto the words injected into the output, such as panel titles
+type Labels map[string]string
+
+// Label IDs of the generated words
+const (
+	LabelInfo             = "info"
+	LabelWarning          = "warning"
+	LabelNote             = "note"
+	LabelTip              = "tip"
+	LabelUnsupportedMacro = "unsupportedMacro"
+	LabelNotConverted     = "notConverted"
+	LabelLiveTemplate     = "liveTemplate"
+	LabelEmpty            = "empty"
+	LabelNotExported      = "notExported"
+	LabelOpen             = "open"
+	LabelChart            = "chart"
+	LabelChartNotExported = "chartNotExported"
+	LabelStatus           = "status"
+	LabelApprovedBy       = "approvedBy"
+	LabelApprovedOn       = "approvedOn"
+	LabelStart            = "start"
+	LabelEnd              = "end"
+	LabelEvent            = "event"
+	LabelLocation         = "location"
+	LabelAskedBy          = "askedBy"
+	LabelAnsweredBy       = "answeredBy"
+	LabelAnswer           = "answer"
+	LabelAcceptedAnswer   = "acceptedAnswer"
+	LabelVote             = "vote"
+	LabelVotes            = "votes"
+)
+
+// builtinLabels holds the translations available through --lang; missing entries fall back to English
+var builtinLabels = map[string]Labels{
+	"en": {
+		LabelInfo: "Info", LabelWarning: "Warning", LabelNote: "Note", LabelTip: "Tip",
+		LabelUnsupportedMacro: "Unsupported macro", LabelNotConverted: "was not converted",
+		LabelLiveTemplate: "Live template", LabelEmpty: "empty", LabelNotExported: "not exported", LabelOpen: "Open",
+		LabelChart: "Chart", LabelChartNotExported: "chart not exported, source data below",
+		LabelStatus: "Status", LabelApprovedBy: "by", LabelApprovedOn: "on",
+		LabelStart: "Start", LabelEnd: "End", LabelEvent: "Event", LabelLocation: "Location",
+		LabelAskedBy: "Asked by", LabelAnsweredBy: "Answered by", LabelAnswer: "Answer", LabelAcceptedAnswer: "Accepted Answer",
+		LabelVote: "vote", LabelVotes: "votes",
+	},
+	"de": {
+		LabelInfo: "Info", LabelWarning: "Warnung", LabelNote: "Hinweis", LabelTip: "Tipp",
+		LabelUnsupportedMacro: "Nicht unterstütztes Makro", LabelNotConverted: "wurde nicht konvertiert",
+		LabelLiveTemplate: "Live-Vorlage", LabelEmpty: "leer", LabelNotExported: "nicht exportiert", LabelOpen: "Öffnen",
+		LabelChart: "Diagramm", LabelChartNotExported: "Diagramm nicht exportiert, Quelldaten unten",
+		LabelStatus: "Status", LabelApprovedBy: "von", LabelApprovedOn: "am",
+		LabelStart: "Beginn", LabelEnd: "Ende", LabelEvent: "Termin", LabelLocation: "Ort",
+		LabelAskedBy: "Gefragt von", LabelAnsweredBy: "Beantwortet von", LabelAnswer: "Antwort", LabelAcceptedAnswer: "Akzeptierte Antwort",
+		LabelVote: "Stimme", LabelVotes: "Stimmen",
+	},
+	"fr": {
+		LabelInfo: "Info", LabelWarning: "Avertissement", LabelNote: "Remarque", LabelTip: "Astuce",
+		LabelUnsupportedMacro: "Macro non prise en charge", LabelNotConverted: "n'a pas été convertie",
+		LabelLiveTemplate: "Modèle dynamique", LabelEmpty: "vide", LabelNotExported: "non exporté", LabelOpen: "Ouvrir",
+		LabelChart: "Graphique", LabelChartNotExported: "graphique non exporté, données sources ci-dessous",
+		LabelStatus: "Statut", LabelApprovedBy: "par", LabelApprovedOn: "le",
+		LabelStart: "Début", LabelEnd: "Fin", LabelEvent: "Événement", LabelLocation: "Lieu",
+		LabelAskedBy: "Posée par", LabelAnsweredBy: "Répondu par", LabelAnswer: "Réponse", LabelAcceptedAnswer: "Réponse acceptée",
+		LabelVote: "vote", LabelVotes: "votes",
+	},
+	"es": {
+		LabelInfo: "Información", LabelWarning: "Advertencia", LabelNote: "Nota", LabelTip: "Consejo",
+		LabelUnsupportedMacro: "Macro no compatible", LabelNotConverted: "no se convirtió",
+		LabelLiveTemplate: "Plantilla dinámica", LabelEmpty: "vacío", LabelNotExported: "no exportado", LabelOpen: "Abrir",
+		LabelChart: "Gráfico", LabelChartNotExported: "gráfico no exportado, datos de origen abajo",
+		LabelStatus: "Estado", LabelApprovedBy: "por", LabelApprovedOn: "el",
+		LabelStart: "Inicio", LabelEnd: "Fin", LabelEvent: "Evento", LabelLocation: "Ubicación",
+		LabelAskedBy: "Preguntado por", LabelAnsweredBy: "Respondido por", LabelAnswer: "Respuesta", LabelAcceptedAnswer: "Respuesta aceptada",
+		LabelVote: "voto", LabelVotes: "votos",
+	},
+	"ja": {
+		LabelInfo: "情報", LabelWarning: "警告", LabelNote: "注記", LabelTip: "ヒント",
+		LabelUnsupportedMacro: "未対応のマクロ", LabelNotConverted: "は変換されませんでした",
+		LabelLiveTemplate: "ライブテンプレート", LabelEmpty: "空", LabelNotExported: "エクスポートされていません", LabelOpen: "開く",
+		LabelChart: "グラフ", LabelChartNotExported: "グラフはエクスポートされません、元データは下記",
+		LabelStatus: "ステータス", LabelApprovedBy: "承認者", LabelApprovedOn: "日付",
+		LabelStart: "開始", LabelEnd: "終了", LabelEvent: "イベント", LabelLocation: "場所",
+		LabelAskedBy: "質問者", LabelAnsweredBy: "回答者", LabelAnswer: "回答", LabelAcceptedAnswer: "承認された回答",
+		LabelVote: "票", LabelVotes: "票",
+	},
+	"zh": {
+		LabelInfo: "信息", LabelWarning: "警告", LabelNote: "注意", LabelTip: "提示",
+		LabelUnsupportedMacro: "不支持的宏", LabelNotConverted: "未转换",
+		LabelLiveTemplate: "实时模板", LabelEmpty: "空", LabelNotExported: "未导出", LabelOpen: "打开",
+		LabelChart: "图表", LabelChartNotExported: "图表未导出，源数据如下",
+		LabelStatus: "状态", LabelApprovedBy: "审批人", LabelApprovedOn: "日期",
+		LabelStart: "开始", LabelEnd: "结束", LabelEvent: "事件", LabelLocation: "地点",
+		LabelAskedBy: "提问者", LabelAnsweredBy: "回答者", LabelAnswer: "回答", LabelAcceptedAnswer: "已采纳的回答",
+		LabelVote: "票", LabelVotes: "票",
+	},
+}
+
+// LoadLabels returns the labels for lang with the custom strings applied on top.
+// An empty lang selects English; unknown label IDs in custom are rejected to catch typos.
+func LoadLabels(lang string, custom map[string]string) (Labels, error) {
+	if lang == "" {
+		lang = "en"
+	}
+	base, ok := builtinLabels[strings.ToLower(lang)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q, available: %s", lang, strings.Join(availableLanguages(), ", "))
+	}
+
+	labels := make(Labels, len(builtinLabels["en"]))
+	for id, text := range builtinLabels["en"] {
+		labels[id] = text
+	}
+	for id, text := range base {
+		labels[id] = text
+	}
+	for id, text := range custom {
+		if _, known := labels[id]; !known {
+			return nil, fmt.Errorf("unknown label %q", id)
+		}
+		labels[id] = text
+	}
+	return labels, nil
+}
+
+// availableLanguages lists the built-in languages in a stable order
+func availableLanguages() []string {
+	languages := make([]string, 0, len(builtinLabels))
+	for lang := range builtinLabels {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Get returns the label with the given ID, falling back to English
+func (l Labels) Get(id string) string {
+	if text, ok := l[id]; ok {
+		return text
+	}
+	return builtinLabels["en"][id]
+}
+
+// WithLabels sets the words injected into the output
+func WithLabels(labels Labels) Option {
+	return func(p *ConfluencePlugin) {
+		p.labels = labels
+	}
+}
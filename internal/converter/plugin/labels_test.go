@@ -0,0 +1,39 @@
+package plugin
+
+import "testing"
+
+func TestLoadLabels(t *testing.T) {
+	labels, err := LoadLabels("de", map[string]string{LabelTip: "Profi-Tipp"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := labels.Get(LabelWarning); got != "Warnung" {
+		t.Fatalf("expected the German warning label, got %q", got)
+	}
+	if got := labels.Get(LabelTip); got != "Profi-Tipp" {
+		t.Fatalf("expected the custom tip label, got %q", got)
+	}
+
+	if _, err := LoadLabels("xx", nil); err == nil {
+		t.Fatal("expected an error for an unsupported language")
+	}
+	if _, err := LoadLabels("en", map[string]string{"infoo": "Info"}); err == nil {
+		t.Fatal("expected an error for an unknown label ID")
+	}
+}
+
+func TestLabelsInOutput(t *testing.T) {
+	labels, err := LoadLabels("ja", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plugin := &ConfluencePlugin{labels: labels}
+	if got, want := plugin.labeledValue("Owner", ""), "**Owner:** _(空)_\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	var english Labels
+	if got := english.Get(LabelInfo); got != "Info" {
+		t.Fatalf("expected nil labels to fall back to English, got %q", got)
+	}
+}
@@ -0,0 +1,137 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+	"golang.org/x/net/html"
+)
+
+// Macro is what a custom macro handler sees of a macro: its name, its parameters and its body
+type Macro struct {
+	Name       string
+	Parameters map[string]string     // ac:parameter values by name; the unnamed default parameter has key ""
+	PlainBody  string                // plain text body, as in code-like macros
+	Page       *model.ConfluencePage // page being converted, nil for bare HTML
+
+	body func() string
+}
+
+// Body returns the rich text body converted to Markdown. It is converted on first use, so nested macros
+// are only rendered when the handler uses the body.
+func (m Macro) Body() string {
+	if m.body == nil {
+		return ""
+	}
+	return m.body()
+}
+
+// MacroHandler renders a macro as Markdown. An error falls back to the built-in rendering with a warning.
+type MacroHandler func(macro Macro) (string, error)
+
+var (
+	macroHandlersMu sync.RWMutex
+	macroHandlers   = make(map[string]MacroHandler)
+)
+
+// RegisterMacroHandler renders every macro named name (case-insensitive) with handler, taking precedence over the
+// built-in handlers in every plugin. A nil handler removes the registration.
+func RegisterMacroHandler(name string, handler MacroHandler) {
+	macroHandlersMu.Lock()
+	defer macroHandlersMu.Unlock()
+	if handler == nil {
+		delete(macroHandlers, strings.ToLower(name))
+		return
+	}
+	macroHandlers[strings.ToLower(name)] = handler
+}
+
+// WithMacroHandlers renders the named macros with these handlers, taking precedence over registered and
+// built-in handlers
+func WithMacroHandlers(handlers map[string]MacroHandler) Option {
+	return func(p *ConfluencePlugin) {
+		for name, handler := range handlers {
+			if p.macroHandlers == nil {
+				p.macroHandlers = make(map[string]MacroHandler)
+			}
+			p.macroHandlers[strings.ToLower(name)] = handler
+		}
+	}
+}
+
+// NewTemplateMacroHandler parses a Go text/template executed with the Macro as data, e.g.
+// "> **{{ .Parameters.title }}**\n> {{ .Body }}". The slug function slugs a value with slugger,
+// DefaultSlugger when nil.
+func NewTemplateMacroHandler(name, text string, slugger Slugger) (MacroHandler, error) {
+	if slugger == nil {
+		slugger = DefaultSlugger
+	}
+	funcs := template.FuncMap{
+		"slug": func(value string) string {
+			return slugger.Slug(value, nil)
+		},
+	}
+	tmpl, err := template.New(name).Option("missingkey=zero").Funcs(funcs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse macro template %s: %w", name, err)
+	}
+	return func(macro Macro) (string, error) {
+		var out strings.Builder
+		if err := tmpl.Execute(&out, macro); err != nil {
+			return "", fmt.Errorf("failed to execute macro template %s: %w", name, err)
+		}
+		return out.String(), nil
+	}, nil
+}
+
+// macroHandler returns the custom handler for a macro, or nil when it is rendered by the built-in handlers
+func (p *ConfluencePlugin) macroHandler(macroName string) MacroHandler {
+	name := strings.ToLower(macroName)
+	if handler, ok := p.macroHandlers[name]; ok {
+		return handler
+	}
+	macroHandlersMu.RLock()
+	defer macroHandlersMu.RUnlock()
+	return macroHandlers[name]
+}
+
+// handleCustomMacro renders a macro with a custom handler, reporting whether it succeeded
+func (p *ConfluencePlugin) handleCustomMacro(ctx converter.Context, n *html.Node, macroName string, handler MacroHandler) (string, bool) {
+	var body *string
+	macro := Macro{
+		Name:       macroName,
+		Parameters: make(map[string]string),
+		PlainBody:  findPlainTextBody(n),
+		Page:       p.currentPage,
+		body: func() string {
+			if body == nil {
+				converted := p.convertNestedHTML(ctx, n)
+				body = &converted
+			}
+			return *body
+		},
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:parameter" {
+			macro.Parameters[GetAttr(child, "ac:name")] = nodeText(child)
+		}
+	}
+
+	result, err := handler(macro)
+	if err != nil {
+		p.warnf("custom handler for macro %s failed, using the built-in rendering: %v", macroName, err)
+		if body != nil {
+			// The built-in rendering reuses the body instead of rendering nested macros and tables again
+			if p.convertedBodies == nil {
+				p.convertedBodies = make(map[*html.Node]string)
+			}
+			p.convertedBodies[n] = *body
+		}
+		return "", false
+	}
+	return result, true
+}
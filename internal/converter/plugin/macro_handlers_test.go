@@ -0,0 +1,73 @@
+package plugin
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestMacroHandlers(t *testing.T) {
+	vendor := findNode(t, `<ac:structured-macro ac:name="Vendor-Box"><ac:parameter ac:name="title">Heads up</ac:parameter><ac:plain-text-body><![CDATA[raw text]]></ac:plain-text-body></ac:structured-macro>`, "ac:structured-macro")
+
+	handler, err := NewTemplateMacroHandler("vendor-box", `> **{{ .Parameters.title }}** {{ .PlainBody }}{{ .Parameters.missing }}`, nil)
+	if err != nil {
+		t.Fatalf("NewTemplateMacroHandler() error = %v", err)
+	}
+	plugin := &ConfluencePlugin{}
+	WithMacroHandlers(map[string]MacroHandler{"vendor-box": handler})(plugin)
+
+	var out strings.Builder
+	plugin.handleMacro(nil, &out, vendor)
+	if want := "> **Heads up** raw text"; out.String() != want {
+		t.Fatalf("template handler = %q, want %q", out.String(), want)
+	}
+
+	// Registered handlers apply to every plugin, below the plugin's own handlers
+	RegisterMacroHandler("vendor-box", func(macro Macro) (string, error) {
+		return "registered " + macro.Name, nil
+	})
+	defer RegisterMacroHandler("vendor-box", nil)
+
+	out.Reset()
+	(&ConfluencePlugin{}).handleMacro(nil, &out, vendor)
+	if want := "registered Vendor-Box"; out.String() != want {
+		t.Fatalf("registered handler = %q, want %q", out.String(), want)
+	}
+	out.Reset()
+	plugin.handleMacro(nil, &out, vendor)
+	if want := "> **Heads up** raw text"; out.String() != want {
+		t.Fatalf("plugin handler = %q, want %q", out.String(), want)
+	}
+
+	// A failing handler falls back to the built-in rendering
+	var warnings []string
+	failing := &ConfluencePlugin{}
+	WithMacroHandlers(map[string]MacroHandler{"vendor-box": func(Macro) (string, error) {
+		return "", errors.New("boom")
+	}})(failing)
+	failing.warn = func(_ *model.ConfluencePage, message string) { warnings = append(warnings, message) }
+	out.Reset()
+	failing.handleMacro(nil, &out, vendor)
+	if out.String() != "<!-- Unsupported macro: Vendor-Box -->" || len(warnings) != 1 {
+		t.Fatalf("failing handler = %q, warnings %v", out.String(), warnings)
+	}
+}
+
+func TestNewTemplateMacroHandlerInvalid(t *testing.T) {
+	if _, err := NewTemplateMacroHandler("broken", "{{ .Name ", nil); err == nil {
+		t.Fatal("expected error for invalid template")
+	}
+}
+
+func TestTemplateMacroHandlerSlug(t *testing.T) {
+	handler, err := NewTemplateMacroHandler("anchor-box", `[{{ .Parameters.title }}](#{{ slug .Parameters.title }})`, nil)
+	if err != nil {
+		t.Fatalf("NewTemplateMacroHandler() error = %v", err)
+	}
+	got, err := handler(Macro{Parameters: map[string]string{"title": "Release Notes"}})
+	if err != nil || got != "[Release Notes](#release-notes)" {
+		t.Fatalf("handler() = %q, %v", got, err)
+	}
+}
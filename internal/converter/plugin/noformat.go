@@ -0,0 +1,54 @@
+package plugin
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// WithInlineNoformat writes noformat macros whose body is a single line of at most maxLength characters
+// as inline code instead of a fenced block. 0 keeps every noformat macro a block.
+func WithInlineNoformat(maxLength int) Option {
+	return func(p *ConfluencePlugin) {
+		p.inlineNoformat = maxLength
+	}
+}
+
+// handleNoformatMacro renders short single-line noformat macros as inline code and the rest as code blocks
+func (p *ConfluencePlugin) handleNoformatMacro(n *html.Node) string {
+	code := strings.TrimSpace(findPlainTextBody(n))
+	if code == "" || p.inlineNoformat <= 0 || strings.ContainsAny(code, "\r\n") || utf8.RuneCountInString(code) > p.inlineNoformat {
+		return p.handleCodeMacro(n)
+	}
+	return inlineCode(code)
+}
+
+// inlineCode wraps text in a code span whose backtick fence is longer than any backtick run in it
+func inlineCode(text string) string {
+	fence := strings.Repeat("`", longestBacktickRun(text)+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + text + fence
+}
+
+// codeFence returns a backtick fence for a code block around text: at least three backticks and
+// longer than any backtick run in text, so the content cannot close the block early
+func codeFence(text string) string {
+	return strings.Repeat("`", max(3, longestBacktickRun(text)+1))
+}
+
+// longestBacktickRun returns the length of the longest run of consecutive backticks in text
+func longestBacktickRun(text string) int {
+	longest, run := 0, 0
+	for _, r := range text {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
@@ -0,0 +1,43 @@
+package plugin
+
+import "testing"
+
+func TestHandleNoformatMacro(t *testing.T) {
+	short := `<ac:structured-macro ac:name="noformat"><ac:plain-text-body><![CDATA[npm install]]></ac:plain-text-body></ac:structured-macro>`
+	multiline := `<ac:structured-macro ac:name="noformat"><ac:plain-text-body><![CDATA[line one
+line two]]></ac:plain-text-body></ac:structured-macro>`
+
+	tests := []struct {
+		name      string
+		maxLength int
+		input     string
+		want      string
+	}{
+		{"disabled", 0, short, "```\nnpm install\n```\n"},
+		{"short line", 20, short, "`npm install`"},
+		{"too long", 5, short, "```\nnpm install\n```\n"},
+		{"several lines", 80, multiline, "```\nline one\nline two\n```\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &ConfluencePlugin{inlineNoformat: tt.maxLength}
+			if got := plugin.handleNoformatMacro(findNode(t, tt.input, "ac:structured-macro")); got != tt.want {
+				t.Errorf("handleNoformatMacro() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInlineCode(t *testing.T) {
+	tests := map[string]string{
+		"ls -la":   "`ls -la`",
+		"a `b` c":  "``a `b` c``",
+		"`quoted`": "`` `quoted` ``",
+		"x ``` y":  "````x ``` y````",
+	}
+	for input, want := range tests {
+		if got := inlineCode(input); got != want {
+			t.Errorf("inlineCode(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// Scaffolding (ServiceRocket) stores form fields as macros; the values recorded in the
+// page body are rendered as labeled Markdown so structured pages keep their data.
+
+// scaffoldingLabel returns the field name of a Scaffolding data macro, falling back to the macro name
+func scaffoldingLabel(n *html.Node, macroName string) string {
+	if name := findParameter(n, "name"); name != "" {
+		return name
+	}
+	return macroName
+}
+
+// handleScaffoldingDataMacro renders text-data and table-data fields as "**Name:** value"
+func (p *ConfluencePlugin) handleScaffoldingDataMacro(ctx converter.Context, n *html.Node, macroName string) string {
+	label := scaffoldingLabel(n, macroName)
+
+	value := p.convertNestedHTML(ctx, n)
+	if value == "" {
+		value = strings.TrimSpace(findPlainTextBody(n))
+	}
+	if value == "" {
+		value = findParameter(n, "content")
+	}
+
+	return p.labeledValue(label, value)
+}
+
+// handleScaffoldingListMacro renders a list-data field with its list-option values
+func (p *ConfluencePlugin) handleScaffoldingListMacro(n *html.Node) string {
+	label := scaffoldingLabel(n, "list-data")
+
+	var options []string
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && child.Data == "ac:structured-macro" && GetAttr(child, "ac:name") == "list-option" {
+				if value := nodeText(child); value != "" {
+					options = append(options, value)
+				}
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(n)
+
+	if len(options) == 0 {
+		return fmt.Sprintf("**%s:** _(%s)_\n\n", label, p.labels.Get(LabelEmpty))
+	}
+	return fmt.Sprintf("**%s:** %s\n\n", label, strings.Join(options, ", "))
+}
+
+// handleLiveTemplateMacro notes the template a live-template macro pulls its content from
+func (p *ConfluencePlugin) handleLiveTemplateMacro(n *html.Node) string {
+	template := findParameter(n, "template")
+	if template == "" {
+		return "<!-- Live template -->"
+	}
+	return fmt.Sprintf("> 📄 **%s:** %s\n", p.labels.Get(LabelLiveTemplate), template)
+}
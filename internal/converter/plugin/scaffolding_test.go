@@ -0,0 +1,33 @@
+package plugin
+
+import "testing"
+
+func TestHandleScaffoldingDataMacro(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+
+	node := findNode(t, `<ac:structured-macro ac:name="text-data"><ac:parameter ac:name="name">Owner</ac:parameter><ac:rich-text-body>Platform team</ac:rich-text-body></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleScaffoldingDataMacro(nil, node, "text-data"), "**Owner:** Platform team\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	node = findNode(t, `<ac:structured-macro ac:name="text-data"><ac:parameter ac:name="name">Notes</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleScaffoldingDataMacro(nil, node, "text-data"), "**Notes:** _(empty)_\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestHandleScaffoldingListMacro(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	node := findNode(t, `<ac:structured-macro ac:name="list-data"><ac:parameter ac:name="name">Status</ac:parameter><ac:rich-text-body><ac:structured-macro ac:name="list-option"><ac:rich-text-body>Open</ac:rich-text-body></ac:structured-macro><ac:structured-macro ac:name="list-option"><ac:rich-text-body>Closed</ac:rich-text-body></ac:structured-macro></ac:rich-text-body></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleScaffoldingListMacro(node), "**Status:** Open, Closed\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestHandleLiveTemplateMacro(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	node := findNode(t, `<ac:structured-macro ac:name="live-template"><ac:parameter ac:name="template">Release Notes</ac:parameter></ac:structured-macro>`, "ac:structured-macro")
+	if got, want := plugin.handleLiveTemplateMacro(node), "> 📄 **Live template:** Release Notes\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package plugin
+
+import (
+	"github.com/gosimple/slug"
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// Slugger turns text such as a page title or heading into a slug for file names and anchors.
+// page is the page the text belongs to, or nil when the slug must not depend on it.
+type Slugger interface {
+	Slug(text string, page *model.ConfluencePage) string
+}
+
+// SluggerFunc adapts a function to the Slugger interface.
+type SluggerFunc func(text string, page *model.ConfluencePage) string
+
+func (f SluggerFunc) Slug(text string, page *model.ConfluencePage) string {
+	return f(text, page)
+}
+
+// DefaultSlugger makes lowercase ASCII slugs with the English transliteration rules
+var DefaultSlugger Slugger = SluggerFunc(func(text string, _ *model.ConfluencePage) string {
+	return slug.MakeLang(text, "en")
+})
+
+// WithSlugger generates anchor names in the slug anchor style and emoji file names with slugger
+func WithSlugger(slugger Slugger) Option {
+	return func(p *ConfluencePlugin) {
+		p.slugger = slugger
+	}
+}
+
+// slug slugs text with the configured slugger
+func (p *ConfluencePlugin) slug(text string, page *model.ConfluencePage) string {
+	if p.slugger == nil {
+		return DefaultSlugger.Slug(text, page)
+	}
+	return p.slugger.Slug(text, page)
+}
@@ -0,0 +1,24 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestWithSlugger(t *testing.T) {
+	upper := SluggerFunc(func(text string, _ *model.ConfluencePage) string {
+		return strings.ToUpper(strings.ReplaceAll(text, " ", "_"))
+	})
+
+	p := NewConfluencePlugin(nil, "assets", WithSlugger(upper))
+	if got := p.anchorID("Release notes"); got != "RELEASE_NOTES" {
+		t.Errorf("anchorID() = %q, want %q", got, "RELEASE_NOTES")
+	}
+
+	p = NewConfluencePlugin(nil, "assets")
+	if got := p.anchorID("Release notes"); got != "release-notes" {
+		t.Errorf("default anchorID() = %q, want %q", got, "release-notes")
+	}
+}
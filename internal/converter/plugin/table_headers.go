@@ -0,0 +1,83 @@
+package plugin
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// RowHeaderStyle selects how tables whose headers are in the first column instead of the first row are written
+type RowHeaderStyle string
+
+const (
+	RowHeaderBold RowHeaderStyle = "bold" // empty header row with the first column in bold (default)
+	RowHeaderList RowHeaderStyle = "list" // two-column key-value tables become a list of "**Key:** value" items
+	RowHeaderNone RowHeaderStyle = "none" // the first row is used as the header row
+)
+
+// ParseRowHeaderStyle validates a row header style; an empty name selects RowHeaderBold
+func ParseRowHeaderStyle(name string) (RowHeaderStyle, error) {
+	switch style := RowHeaderStyle(name); style {
+	case "":
+		return RowHeaderBold, nil
+	case RowHeaderBold, RowHeaderList, RowHeaderNone:
+		return style, nil
+	}
+	return "", fmt.Errorf("row header style must be bold, list or none, got: %s", name)
+}
+
+// WithRowHeaderStyle sets how tables with headers only in the first column are written
+func WithRowHeaderStyle(style RowHeaderStyle) Option {
+	return func(p *ConfluencePlugin) {
+		p.rowHeaders = style
+	}
+}
+
+// writeRowHeaderTable writes a table whose first column holds the headers, so no data row is promoted to the header.
+// List mode only applies to tables exactly two columns wide, counting merged cells, so no cell is dropped.
+func (p *ConfluencePlugin) writeRowHeaderTable(w converter.Writer, rows [][]string, columns int) {
+	if p.rowHeaders == RowHeaderList && columns == 2 && !slices.ContainsFunc(rows, func(row []string) bool { return len(row) != 2 }) {
+		for _, row := range rows {
+			key := strings.TrimSpace(row[0])
+			value := strings.TrimSpace(row[1])
+			if value == "" {
+				_, _ = fmt.Fprintf(w, "- **%s:**\n", key)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "- **%s:** %s\n", key, value)
+		}
+		_, _ = w.WriteString("\n")
+		return
+	}
+
+	_, _ = w.WriteString("|" + strings.Repeat("   |", len(rows[0])) + "\n")
+	_, _ = w.WriteString("|" + strings.Repeat("---|", len(rows[0])) + "\n")
+	for _, row := range rows {
+		_, _ = w.WriteString("| " + boldCell(row[0]))
+		for _, cell := range row[1:] {
+			_, _ = w.WriteString(" | " + cell)
+		}
+		_, _ = w.WriteString(" |\n")
+	}
+	_, _ = w.WriteString("\n")
+}
+
+// boldCell wraps the cell content in bold unless it is empty or already bold
+func boldCell(cell string) string {
+	if strings.TrimSpace(cell) == "" || strings.HasPrefix(cell, "**") {
+		return cell
+	}
+	return "**" + cell + "**"
+}
+
+// cellColspan returns the number of columns a table cell spans
+func cellColspan(cell *html.Node) int {
+	if span, err := strconv.Atoi(strings.TrimSpace(GetAttr(cell, "colspan"))); err == nil && span > 1 {
+		return span
+	}
+	return 1
+}
@@ -0,0 +1,49 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteRowHeaderTable(t *testing.T) {
+	rows := [][]string{{"Owner", "@alice"}, {"Status", " "}}
+
+	tests := []struct {
+		style RowHeaderStyle
+		want  string
+	}{
+		{RowHeaderBold, "|   |   |\n|---|---|\n| **Owner** | @alice |\n| **Status** |   |\n\n"},
+		{RowHeaderList, "- **Owner:** @alice\n- **Status:**\n\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			var buf strings.Builder
+			plugin := &ConfluencePlugin{rowHeaders: tt.style}
+			plugin.writeRowHeaderTable(&buf, rows, 2)
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRowHeaderTableListNeedsTwoColumns(t *testing.T) {
+	var buf strings.Builder
+	plugin := &ConfluencePlugin{rowHeaders: RowHeaderList}
+	plugin.writeRowHeaderTable(&buf, [][]string{{"Q1", "1", "2"}}, 3)
+	if got, want := buf.String(), "|   |   |   |\n|---|---|---|\n| **Q1** | 1 | 2 |\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteRowHeaderTableListSkipsMergedColumns(t *testing.T) {
+	var buf strings.Builder
+	plugin := &ConfluencePlugin{rowHeaders: RowHeaderList}
+	plugin.writeRowHeaderTable(&buf, [][]string{{"Owner", "alice"}, {"Status", "open"}}, 3)
+	if got, want := buf.String(), "|   |   |\n|---|---|\n| **Owner** | alice |\n| **Status** | open |\n\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if got := cellColspan(findNode(t, `<table><tbody><tr><td colspan="2">alice</td></tr></tbody></table>`, "td")); got != 2 {
+		t.Fatalf("cellColspan() = %d, want 2", got)
+	}
+}
@@ -0,0 +1,266 @@
+package plugin
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// inlineMarkdownRegex matches the Markdown constructs macro output uses inline: bold, code spans,
+// images, links, HTML tags passed through and backslash escapes
+var inlineMarkdownRegex = regexp.MustCompile(`\*\*(.+?)\*\*|` + "`([^`]+)`" + `|!\[([^\]]*)\]\(([^)\s]*)\)|\[([^\]]*)\]\(([^)\s]*)\)|<[a-zA-Z/!][^<>]*>|\\([!-/:-@\[-` + "`" + `{-~])`)
+
+// TableMode selects whether tables are written as Markdown pipe tables or as cleaned HTML
+type TableMode string
+
+const (
+	TableModeAuto     TableMode = "auto"     // HTML only for tables whose cells cannot be flattened (default)
+	TableModeMarkdown TableMode = "markdown" // always pipe tables, flattening complex cells
+	TableModeHTML     TableMode = "html"     // always cleaned HTML
+)
+
+// ParseTableMode validates a table mode; an empty name selects TableModeAuto
+func ParseTableMode(name string) (TableMode, error) {
+	switch mode := TableMode(name); mode {
+	case "":
+		return TableModeAuto, nil
+	case TableModeAuto, TableModeMarkdown, TableModeHTML:
+		return mode, nil
+	}
+	return "", fmt.Errorf("table mode must be auto, markdown or html, got: %s", name)
+}
+
+// WithTableMode sets how tables are written
+func WithTableMode(mode TableMode) Option {
+	return func(p *ConfluencePlugin) {
+		p.tableMode = mode
+	}
+}
+
+// cleanHTMLAttrs are the attributes kept per element when a table is written as HTML
+var cleanHTMLAttrs = map[string][]string{
+	"a":  {"href"},
+	"td": {"colspan", "rowspan"},
+	"th": {"colspan", "rowspan"},
+}
+
+// cleanHTMLElements are the standard elements kept when a table is written as HTML
+var cleanHTMLElements = map[string]bool{
+	"table": true, "thead": true, "tbody": true, "tr": true, "th": true, "td": true,
+	"p": true, "br": true, "ul": true, "ol": true, "li": true, "pre": true, "blockquote": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"strong": true, "b": true, "em": true, "i": true, "u": true, "s": true, "del": true,
+	"code": true, "sub": true, "sup": true, "a": true, "hr": true,
+}
+
+// useHTMLTable reports whether the table body is written as HTML under the current table mode
+func (p *ConfluencePlugin) useHTMLTable(tbody *html.Node) bool {
+	if !p.dialect.SupportsExtensions() {
+		// CommonMark has no pipe tables
+		return true
+	}
+	switch p.tableMode {
+	case TableModeHTML:
+		return true
+	case TableModeMarkdown:
+		return false
+	}
+
+	for tr := tbody.FirstChild; tr != nil; tr = tr.NextSibling {
+		if tr.Type != html.ElementNode || tr.Data != "tr" {
+			continue
+		}
+		for cell := tr.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.Type == html.ElementNode && (cell.Data == "td" || cell.Data == "th") && cellNeedsHTML(cell) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cellNeedsHTML reports whether flattening the cell onto one line would lose its structure:
+// it holds a nested table or more than one block of content
+func cellNeedsHTML(cell *html.Node) bool {
+	if findDescendant(cell, "table") != nil {
+		return true
+	}
+
+	blocks := 0
+	for child := cell.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type != html.ElementNode {
+			continue
+		}
+		switch child.Data {
+		case "p", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "pre", "blockquote", "ac:task-list":
+			if strings.TrimSpace(nodeText(child)) != "" || child.FirstChild != nil && child.FirstChild.Type == html.ElementNode {
+				blocks++
+			}
+		}
+	}
+	return blocks > 1
+}
+
+// writeHTMLTable writes the table as HTML with Confluence markup converted and presentation attributes dropped
+func (p *ConfluencePlugin) writeHTMLTable(ctx converter.Context, w converter.Writer, n *html.Node) {
+	var buf strings.Builder
+	p.writeCleanHTML(ctx, &buf, n)
+
+	// A blank line would end the HTML block in CommonMark renderers
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.TrimSpace(line) != "" {
+			_, _ = w.WriteString(line + "\n")
+		}
+	}
+	_, _ = w.WriteString("\n")
+}
+
+// writeCleanHTML writes n with only standard elements and their structural attributes
+func (p *ConfluencePlugin) writeCleanHTML(ctx converter.Context, w *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.WriteString(html.EscapeString(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	switch n.Data {
+	case "colgroup", "col":
+		return
+	case "ac:structured-macro":
+		p.writeCellMacroHTML(ctx, w, n)
+		return
+	case "ac:link":
+		var buf strings.Builder
+		p.handleLink(ctx, &buf, n)
+		if buf.Len() == 0 {
+			// Page and attachment links keep their link text
+			w.WriteString(html.EscapeString(nodeText(n)))
+			return
+		}
+		w.WriteString(inlineMarkdownHTML(buf.String()))
+		return
+	case "ac:emoticon":
+		p.handleEmoticon(ctx, w, n)
+		return
+	case "ac:placeholder":
+		p.handlePlaceholder(ctx, w, n)
+		return
+	case "ac:task-list":
+		p.flattenTaskList(ctx, w, n)
+		return
+	case "time":
+		var buf strings.Builder
+		p.handleTime(ctx, &buf, n)
+		w.WriteString(html.EscapeString(buf.String()))
+		return
+	case "ac:image":
+		filename := GetAttr(n, "ri:filename")
+		if attachment := findDescendant(n, "ri:attachment"); filename == "" && attachment != nil {
+			filename = GetAttr(attachment, "ri:filename")
+		}
+		if filename != "" {
+			fmt.Fprintf(w, `<img src="%s" alt="%s">`, html.EscapeString(p.imageFolder+"/"+filename), html.EscapeString(filename))
+		}
+		return
+	}
+
+	if !cleanHTMLElements[n.Data] {
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			p.writeCleanHTML(ctx, w, child)
+		}
+		return
+	}
+
+	w.WriteString("<" + n.Data)
+	for _, key := range cleanHTMLAttrs[n.Data] {
+		if value := GetAttr(n, key); value != "" {
+			fmt.Fprintf(w, ` %s="%s"`, key, html.EscapeString(value))
+		}
+	}
+	w.WriteString(">")
+	switch n.Data {
+	case "br", "hr":
+		return
+	case "table", "thead", "tbody":
+		w.WriteString("\n")
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		p.writeCleanHTML(ctx, w, child)
+	}
+	w.WriteString("</" + n.Data + ">")
+	switch n.Data {
+	case "table", "thead", "tbody", "tr":
+		w.WriteString("\n")
+	}
+}
+
+// writeCellMacroHTML renders a macro inside an HTML table cell, keeping panel bodies as HTML
+func (p *ConfluencePlugin) writeCellMacroHTML(ctx converter.Context, w *strings.Builder, n *html.Node) {
+	macroName := GetAttr(n, "ac:name")
+	if !p.macroAllowed(macroName) {
+		return
+	}
+
+	panel, isPanel := cellPanelMacros[macroName]
+	if isPanel || cellUnwrappedMacros[macroName] || macroName == "panel" && p.fencedDivs {
+		if isPanel {
+			fmt.Fprintf(w, "%s <strong>%s:</strong> ", panel.emoji, html.EscapeString(p.labels.Get(panel.label)))
+		}
+		if body := p.findRichTextBodyNode(n); body != nil {
+			for child := body.FirstChild; child != nil; child = child.NextSibling {
+				p.writeCleanHTML(ctx, w, child)
+			}
+		}
+		return
+	}
+	if macroName == "code" || macroName == "noformat" {
+		w.WriteString(cellCodeHTML(n))
+		return
+	}
+
+	var buf strings.Builder
+	p.handleMacro(ctx, &buf, n)
+	var lines []string
+	for _, line := range strings.Split(buf.String(), "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ">"))
+		if line != "" {
+			lines = append(lines, inlineMarkdownHTML(strings.TrimSpace(line)))
+		}
+	}
+	w.WriteString(strings.Join(lines, "<br>"))
+}
+
+// inlineMarkdownHTML turns the inline Markdown written by macro and link handlers into HTML,
+// since Markdown is not parsed inside an HTML block
+func inlineMarkdownHTML(markdown string) string {
+	var out strings.Builder
+	last := 0
+	for _, m := range inlineMarkdownRegex.FindAllStringSubmatchIndex(markdown, -1) {
+		out.WriteString(html.EscapeString(markdown[last:m[0]]))
+		last = m[1]
+		group := func(i int) string { return markdown[m[2*i]:m[2*i+1]] }
+		switch {
+		case m[2] >= 0:
+			out.WriteString("<strong>" + inlineMarkdownHTML(group(1)) + "</strong>")
+		case m[4] >= 0:
+			out.WriteString("<code>" + html.EscapeString(group(2)) + "</code>")
+		case m[6] >= 0:
+			fmt.Fprintf(&out, `<img src="%s" alt="%s">`, html.EscapeString(group(4)), html.EscapeString(group(3)))
+		case m[10] >= 0:
+			fmt.Fprintf(&out, `<a href="%s">%s</a>`, html.EscapeString(group(6)), inlineMarkdownHTML(group(5)))
+		case m[14] >= 0:
+			out.WriteString(html.EscapeString(group(7)))
+		default:
+			out.WriteString(markdown[m[0]:m[1]])
+		}
+	}
+	out.WriteString(html.EscapeString(markdown[last:]))
+	return out.String()
+}
@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTableMode(t *testing.T) {
+	if mode, err := ParseTableMode(""); err != nil || mode != TableModeAuto {
+		t.Fatalf("expected auto for empty name, got %q, %v", mode, err)
+	}
+	if _, err := ParseTableMode("csv"); err == nil {
+		t.Fatal("expected an error for an unknown table mode")
+	}
+}
+
+func TestUseHTMLTable(t *testing.T) {
+	nested := `<table><tbody><tr><td><table><tbody><tr><td>inner</td></tr></tbody></table></td></tr></tbody></table>`
+	paragraphs := `<table><tbody><tr><td><p>First</p><p>Second</p></td></tr></tbody></table>`
+	simple := `<table><tbody><tr><td><p>Only</p></td></tr></tbody></table>`
+
+	tests := []struct {
+		name   string
+		markup string
+		mode   TableMode
+		want   bool
+	}{
+		{"nested table", nested, TableModeAuto, true},
+		{"several paragraphs", paragraphs, TableModeAuto, true},
+		{"single paragraph", simple, TableModeAuto, false},
+		{"forced markdown", nested, TableModeMarkdown, false},
+		{"forced html", simple, TableModeHTML, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plugin := &ConfluencePlugin{tableMode: tt.mode}
+			if got := plugin.useHTMLTable(findNode(t, tt.markup, "tbody")); got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteCleanHTML(t *testing.T) {
+	plugin := &ConfluencePlugin{imageFolder: "assets"}
+	table := findNode(t, `<table class="wrapped"><colgroup><col style="width: 50px"/></colgroup><tbody><tr><th colspan="2" style="color: red">Name</th></tr><tr><td><p>A &amp; B</p><ac:image><ri:attachment ri:filename="x.png"/></ac:image></td></tr></tbody></table>`, "table")
+
+	var buf strings.Builder
+	plugin.writeCleanHTML(nil, &buf, table)
+
+	want := "<table>\n<tbody>\n<tr><th colspan=\"2\">Name</th></tr>\n<tr><td><p>A &amp; B</p><img src=\"assets/x.png\" alt=\"x.png\"></td></tr>\n</tbody>\n</table>\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestWriteCleanHTMLInline(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	table := findNode(t, `<table><tbody><tr><td><ac:structured-macro ac:name="info"><ac:rich-text-body><p>Read <strong>this</strong></p></ac:rich-text-body></ac:structured-macro><ac:link ac:anchor="Setup Guide"><ac:plain-text-link-body>setup</ac:plain-text-link-body></ac:link> <time datetime="2024-01-02"></time></td></tr></tbody></table>`, "table")
+
+	var buf strings.Builder
+	plugin.writeCleanHTML(nil, &buf, table)
+
+	want := "<table>\n<tbody>\n<tr><td>ℹ️ <strong>Info:</strong> <p>Read <strong>this</strong></p><a href=\"#setup-guide\">setup</a> 2024-01-02 </td></tr>\n</tbody>\n</table>\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestInlineMarkdownHTML(t *testing.T) {
+	got := inlineMarkdownHTML("**Status:** [a & b](http://x/?q=1&r=2) `x<y` \\* <br> ![i](p.png)")
+	want := `<strong>Status:</strong> <a href="http://x/?q=1&amp;r=2">a &amp; b</a> <code>x&lt;y</code> * <br> <img src="p.png" alt="i">`
+	if got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// Table Filter and Charts wraps ordinary tables in macros that filter, pivot or chart them in the
+// browser. The wrapped table holds the data, so the macros are unwrapped and the table converted as usual.
+
+// tableChartMacros draw a chart from the wrapped table, which cannot be reproduced in Markdown
+var tableChartMacros = map[string]bool{"table-chart": true, "chart-from-table": true}
+
+// handleTableFilterMacro unwraps a Table Filter and Charts macro into its inner table
+func (p *ConfluencePlugin) handleTableFilterMacro(ctx converter.Context, n *html.Node, macroName string) string {
+	content := p.convertNestedHTML(ctx, n)
+	if content == "" {
+		return ""
+	}
+
+	var result strings.Builder
+	if tableChartMacros[macroName] {
+		title := firstParameter(n, "title", "chartTitle")
+		if title == "" {
+			title = p.labels.Get(LabelChart)
+		}
+		fmt.Fprintf(&result, "📊 **%s** (%s)\n\n", title, p.labels.Get(LabelChartNotExported))
+	}
+	if note := p.tableFilterNote(n, macroName); note != "" {
+		result.WriteString(note + "\n")
+	}
+	result.WriteString(content)
+	result.WriteString("\n\n")
+	return result.String()
+}
+
+// tableFilterNote lists the macro's configured filters as a comment when source comments are enabled
+func (p *ConfluencePlugin) tableFilterNote(n *html.Node, macroName string) string {
+	if !p.sourceComments {
+		return ""
+	}
+
+	var settings []string
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && child.Data == "ac:parameter" {
+			if value := nodeText(child); value != "" {
+				settings = append(settings, GetAttr(child, "ac:name")+"="+value)
+			}
+		}
+	}
+	if len(settings) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("<!-- %s: %s -->", macroName, strings.Join(settings, "; "))
+}
@@ -0,0 +1,17 @@
+package plugin
+
+import "testing"
+
+func TestTableFilterNote(t *testing.T) {
+	node := findNode(t, `<ac:structured-macro ac:name="table-filter"><ac:parameter ac:name="default">Open</ac:parameter><ac:parameter ac:name="column">Status</ac:parameter><ac:rich-text-body><table><tbody><tr><td>1</td></tr></tbody></table></ac:rich-text-body></ac:structured-macro>`, "ac:structured-macro")
+
+	plugin := &ConfluencePlugin{}
+	if note := plugin.tableFilterNote(node, "table-filter"); note != "" {
+		t.Fatalf("expected no note without source comments, got %q", note)
+	}
+
+	plugin = &ConfluencePlugin{sourceComments: true}
+	if got, want := plugin.tableFilterNote(node, "table-filter"), "<!-- table-filter: default=Open; column=Status -->"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
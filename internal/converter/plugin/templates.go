@@ -0,0 +1,21 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/JohannesKaufmann/html-to-markdown/v2/converter"
+	"golang.org/x/net/html"
+)
+
+// handleTemplateVariable renders a page template variable (at:var) as {{name}}
+func (p *ConfluencePlugin) handleTemplateVariable(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	if name := GetAttr(n, "at:name"); name != "" {
+		_, _ = fmt.Fprintf(w, "{{%s}}", name)
+	}
+	return converter.RenderSuccess
+}
+
+// handleTemplateDeclarations drops the variable declarations at the top of a page template
+func (p *ConfluencePlugin) handleTemplateDeclarations(ctx converter.Context, w converter.Writer, n *html.Node) converter.RenderStatus {
+	return converter.RenderSuccess
+}
@@ -0,0 +1,16 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHandleTemplateVariable(t *testing.T) {
+	plugin := &ConfluencePlugin{}
+	node := findNode(t, `<p>Owner: <at:var at:name="owner"></at:var></p>`, "at:var")
+	var out strings.Builder
+	plugin.handleTemplateVariable(nil, &out, node)
+	if out.String() != "{{owner}}" {
+		t.Fatalf("unexpected variable: %q", out.String())
+	}
+}
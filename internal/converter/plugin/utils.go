@@ -1,14 +1,29 @@
 package plugin
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"html"
+	"net/url"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	nethtml "golang.org/x/net/html"
+)
+
+var (
+	filenameRegex = regexp.MustCompile(`ri:filename="([^"]+)"`)
+	langRegex     = regexp.MustCompile(`<ac:parameter[^>]*ac:name="language"[^>]*>([^<]+)</ac:parameter>`)
+	bodyRegex     = regexp.MustCompile(`<ac:plain-text-body>([\s\S]*?)</ac:plain-text-body>`)
 )
 
 // ParseConfluenceImage extracts filename from Confluence ac:image elements
 func ParseConfluenceImage(html string) string {
-	filenameRegex := regexp.MustCompile(`ri:filename="([^"]+)"`)
 	matches := filenameRegex.FindStringSubmatch(html)
 	if len(matches) > 1 {
 		return matches[1]
@@ -18,7 +33,6 @@ func ParseConfluenceImage(html string) string {
 
 // extractLanguageParameter extracts the language from ac:parameter tags
 func extractLanguageParameter(rawHTML string) string {
-	langRegex := regexp.MustCompile(`<ac:parameter[^>]*ac:name="language"[^>]*>([^<]+)</ac:parameter>`)
 	matches := langRegex.FindStringSubmatch(rawHTML)
 	if len(matches) > 1 {
 		return matches[1]
@@ -29,7 +43,6 @@ func extractLanguageParameter(rawHTML string) string {
 // extractCodeContent extracts code from ac:plain-text-body, handling both CDATA and plain formats
 func extractCodeContent(rawHTML string) string {
 	// Extract content from ac:plain-text-body tag
-	bodyRegex := regexp.MustCompile(`<ac:plain-text-body>([\s\S]*?)</ac:plain-text-body>`)
 	matches := bodyRegex.FindStringSubmatch(rawHTML)
 	if len(matches) < 2 {
 		return ""
@@ -50,3 +63,287 @@ func extractCodeContent(rawHTML string) string {
 
 	return content
 }
+
+// formatCalendarTime renders a calendar event timestamp, dropping the time for all-day events
+func formatCalendarTime(value string, allDay bool) string {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	if allDay {
+		return parsed.Format("2006-01-02")
+	}
+	return parsed.Format("2006-01-02 15:04")
+}
+
+// escapeTableCell keeps a value on one line and escapes pipe characters for markdown tables
+func escapeTableCell(value string) string {
+	value = strings.ReplaceAll(value, "\n", " ")
+	value = strings.ReplaceAll(value, "|", "\\|")
+	if strings.TrimSpace(value) == "" {
+		return " "
+	}
+	return value
+}
+
+// buildSearchCQL assembles a CQL query from search macro parameters
+func buildSearchCQL(query, spaceKey, labels, contentType string) string {
+	var clauses []string
+
+	if query = strings.TrimSpace(query); query != "" {
+		clauses = append(clauses, fmt.Sprintf("text ~ %s", quoteCQL(query)))
+	}
+	if spaceKey = strings.TrimSpace(spaceKey); spaceKey != "" {
+		clauses = append(clauses, fmt.Sprintf("space = %s", quoteCQL(spaceKey)))
+	}
+
+	var quotedLabels []string
+	for _, label := range strings.Split(labels, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			quotedLabels = append(quotedLabels, quoteCQL(label))
+		}
+	}
+	if len(quotedLabels) > 0 {
+		clauses = append(clauses, fmt.Sprintf("label in (%s)", strings.Join(quotedLabels, ", ")))
+	}
+
+	if len(clauses) == 0 {
+		return ""
+	}
+
+	if contentType = strings.TrimSpace(contentType); contentType != "" {
+		clauses = append(clauses, fmt.Sprintf("type = %s", quoteCQL(contentType)))
+	}
+
+	return strings.Join(clauses, " AND ")
+}
+
+// labeledValue renders a named field as "**Label:** value"; block values start below the label
+func (p *ConfluencePlugin) labeledValue(label, value string) string {
+	switch {
+	case value == "":
+		return fmt.Sprintf("**%s:** _(%s)_\n\n", label, p.labels.Get(LabelEmpty))
+	case strings.Contains(value, "\n") || strings.HasPrefix(value, "|"):
+		// Tables and multi-paragraph values start below the label to keep their block structure
+		return fmt.Sprintf("**%s:**\n\n%s\n\n", label, value)
+	}
+	return fmt.Sprintf("**%s:** %s\n\n", label, value)
+}
+
+// quoteCQL wraps a value in double quotes for a CQL query
+func quoteCQL(value string) string {
+	return `"` + confluence.EscapeCQL(value) + `"`
+}
+
+// commentText keeps text from closing the HTML comment it is written into
+func commentText(text string) string {
+	return strings.ReplaceAll(text, "-->", "--&gt;")
+}
+
+var atxHeadingRegex = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+
+// NumberHeadings prefixes markdown ATX headings with hierarchical numbers (1., 1.1, 1.1.1).
+// Skipped levels are not numbered, so an h3 right below an h1 becomes 1.1 rather than 1.0.1.
+// Headings above startLevel are left untouched; a startLevel of 0 starts at the shallowest heading found.
+func NumberHeadings(markdown string, startLevel int) string {
+	lines := strings.Split(markdown, "\n")
+
+	if startLevel <= 0 {
+		startLevel = 7
+		ForEachHeading(lines, func(_ int, level int, _ string) {
+			if level < startLevel {
+				startLevel = level
+			}
+		})
+		if startLevel == 7 {
+			return markdown
+		}
+	}
+
+	// Open sections, outermost first; a heading continues the count of the position it takes
+	type section struct{ level, count int }
+	var open []section
+	ForEachHeading(lines, func(i int, level int, text string) {
+		if level < startLevel {
+			return
+		}
+		count := 1
+		for len(open) > 0 && open[len(open)-1].level >= level {
+			count = open[len(open)-1].count + 1
+			open = open[:len(open)-1]
+		}
+		open = append(open, section{level: level, count: count})
+
+		parts := make([]string, len(open))
+		for j, s := range open {
+			parts[j] = strconv.Itoa(s.count)
+		}
+		number := strings.Join(parts, ".")
+		if len(open) == 1 {
+			number += "."
+		}
+		lines[i] = fmt.Sprintf("%s %s %s", strings.Repeat("#", level), number, text)
+	})
+
+	return strings.Join(lines, "\n")
+}
+
+// ForEachHeading calls fn for every ATX heading line outside fenced code blocks, with its line index, level and text
+func ForEachHeading(lines []string, fn func(index int, level int, text string)) {
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		matches := atxHeadingRegex.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		fn(i, len(matches[1]), matches[2])
+	}
+}
+
+// headingLevel parses heading tags such as "h2" (or plain "2") into their level, returning 0 when invalid
+func headingLevel(value string) int {
+	value = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "h")
+	level, err := strconv.Atoi(value)
+	if err != nil || level < 1 || level > 6 {
+		return 0
+	}
+	return level
+}
+
+// markdownURLEscaper percent-encodes the characters that would end or break a Markdown link destination
+var markdownURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E", `"`, "%22")
+
+// markdownURL returns an absolute http(s) URL escaped for use as a Markdown link destination,
+// or "" when the value is not such a URL
+func markdownURL(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return markdownURLEscaper.Replace(u.String())
+}
+
+// emojiFileName builds a stable file name for a custom emoji image from its slugged name, keeping the URL's extension.
+// When slugging loses information, e.g. "Party" and "party", a short hash of the name keeps the files apart.
+func emojiFileName(base, name, src string) string {
+	ext := ".png"
+	if u, err := url.Parse(src); err == nil {
+		if e := strings.ToLower(path.Ext(u.Path)); e != "" {
+			ext = e
+		}
+	}
+
+	if base != "" && base == name {
+		return base + ext
+	}
+
+	key := name
+	if key == "" {
+		key = src
+	}
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:])[:8]
+	if base == "" {
+		base = "emoji"
+	}
+	return base + "-" + hash + ext
+}
+
+// GetAttr returns the value of the named attribute on a node, or "" when it is not set
+func GetAttr(n *nethtml.Node, key string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// nodeText returns the concatenated, trimmed text content of a node
+func nodeText(n *nethtml.Node) string {
+	var builder strings.Builder
+	var walk func(*nethtml.Node)
+	walk = func(node *nethtml.Node) {
+		if node.Type == nethtml.TextNode {
+			builder.WriteString(node.Data)
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.TrimSpace(builder.String())
+}
+
+// findDescendant returns the first element below n (n included) with the given tag
+func findDescendant(n *nethtml.Node, tag string) *nethtml.Node {
+	if n == nil {
+		return nil
+	}
+	if n.Type == nethtml.ElementNode && n.Data == tag {
+		return n
+	}
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if found := findDescendant(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// findParameter returns the trimmed value of the macro's own ac:parameter with the given name
+func findParameter(macro *nethtml.Node, name string) string {
+	for child := macro.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == nethtml.ElementNode && child.Data == "ac:parameter" && GetAttr(child, "ac:name") == name {
+			return nodeText(child)
+		}
+	}
+	return ""
+}
+
+// findPlainTextBody returns the content of the macro's ac:plain-text-body, handling both
+// preprocessed CDATA blocks and CDATA sections the HTML parser turned into comments
+func findPlainTextBody(macro *nethtml.Node) string {
+	body := findDescendant(macro, "ac:plain-text-body")
+	if body == nil {
+		return ""
+	}
+
+	if pre := findDescendant(body, "pre"); pre != nil && GetAttr(pre, "data-cdata") == "true" {
+		content := nodeText(pre)
+		content = strings.ReplaceAll(content, "&lt;", "<")
+		content = strings.ReplaceAll(content, "&gt;", ">")
+		content = strings.ReplaceAll(content, "&amp;", "&")
+		return content
+	}
+
+	var builder strings.Builder
+	for child := body.FirstChild; child != nil; child = child.NextSibling {
+		switch child.Type {
+		case nethtml.TextNode:
+			builder.WriteString(child.Data)
+		case nethtml.CommentNode:
+			// CDATA can't contain "]]>", so it marks the end when the comment swallowed the closing tags
+			content := strings.TrimPrefix(child.Data, "[CDATA[")
+			if end := strings.Index(content, "]]>"); end >= 0 {
+				content = content[:end]
+			} else {
+				content = strings.TrimSuffix(content, "]]")
+			}
+			builder.WriteString(html.UnescapeString(content))
+		}
+	}
+	return builder.String()
+}
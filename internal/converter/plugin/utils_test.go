@@ -72,6 +72,11 @@ func TestExtractCodeContent(t *testing.T) {
 			html: `<ac:plain-text-body><!--[CDATA[fmt.Println(&quot;ok&quot;)]]></ac:plain-text-body>`,
 			want: `fmt.Println("ok")`,
 		},
+		{
+			name: "converted cdata with brackets",
+			html: `<ac:structured-macro><ac:plain-text-body><!--[CDATA[x := m[k[0]]]]--></ac:plain-text-body></ac:structured-macro>`,
+			want: `x := m[k[0]]`,
+		},
 		{
 			name: "missing body",
 			html: `<div>no body</div>`,
@@ -87,3 +92,103 @@ func TestExtractCodeContent(t *testing.T) {
 		})
 	}
 }
+
+func TestFindParameter(t *testing.T) {
+	node := findNode(t, `<ac:structured-macro ac:name="code"><ac:parameter ac:name="language"> go </ac:parameter><ac:rich-text-body><ac:structured-macro ac:name="info"><ac:parameter ac:name="title">nested</ac:parameter></ac:structured-macro></ac:rich-text-body></ac:structured-macro>`, "ac:structured-macro")
+
+	if got := findParameter(node, "language"); got != "go" {
+		t.Fatalf("findParameter(language) = %q, want %q", got, "go")
+	}
+	if got := findParameter(node, "title"); got != "" {
+		t.Fatalf("findParameter(title) = %q, want nested parameter to be ignored", got)
+	}
+}
+
+func TestFindPlainTextBody(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "preprocessed cdata",
+			html: `<ac:structured-macro><ac:plain-text-body><pre data-cdata='true'>a &amp;lt; b</pre></ac:plain-text-body></ac:structured-macro>`,
+			want: "a < b",
+		},
+		{
+			name: "converted cdata",
+			html: `<ac:structured-macro><ac:plain-text-body><!--[CDATA[fmt.Println(&quot;ok&quot;)]]--></ac:plain-text-body></ac:structured-macro>`,
+			want: `fmt.Println("ok")`,
+		},
+		{
+			name: "missing body",
+			html: `<ac:structured-macro></ac:structured-macro>`,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := findNode(t, tt.html, "ac:structured-macro")
+			if got := findPlainTextBody(node); got != tt.want {
+				t.Fatalf("findPlainTextBody(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSearchCQL(t *testing.T) {
+	tests := []struct {
+		name                                 string
+		query, spaceKey, labels, contentType string
+		want                                 string
+	}{
+		{
+			name: "empty",
+			want: "",
+		},
+		{
+			name:        "type only",
+			contentType: "page",
+			want:        "",
+		},
+		{
+			name:        "all parameters",
+			query:       `say "hi"`,
+			spaceKey:    "DOCS",
+			labels:      "one, two",
+			contentType: "page",
+			want:        `text ~ "say \"hi\"" AND space = "DOCS" AND label in ("one", "two") AND type = "page"`,
+		},
+		{
+			name:  "backslash",
+			query: `C:\temp\`,
+			want:  `text ~ "C:\\temp\\"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildSearchCQL(tt.query, tt.spaceKey, tt.labels, tt.contentType); got != tt.want {
+				t.Fatalf("buildSearchCQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberHeadings(t *testing.T) {
+	markdown := "# Intro\n\ntext\n\n## Scope\n\n```\n# not a heading\n```\n\n### Detail\n\n## Goals\n\n# Usage"
+	want := "# 1. Intro\n\ntext\n\n## 1.1 Scope\n\n```\n# not a heading\n```\n\n### 1.1.1 Detail\n\n## 1.2 Goals\n\n# 2. Usage"
+	if got := NumberHeadings(markdown, 0); got != want {
+		t.Fatalf("NumberHeadings() = %q, want %q", got, want)
+	}
+
+	if got := NumberHeadings("# Title\n\n## Section", 2); got != "# Title\n\n## 1. Section" {
+		t.Fatalf("NumberHeadings() with start level = %q", got)
+	}
+
+	skipped := "# Intro\n\n### Detail\n\n## Scope\n\n# Usage\n\n### Example"
+	if got, want := NumberHeadings(skipped, 0), "# 1. Intro\n\n### 1.1 Detail\n\n## 1.2 Scope\n\n# 2. Usage\n\n### 2.1 Example"; got != want {
+		t.Fatalf("NumberHeadings() with skipped levels = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/publisher"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+// previewStylesheet is the minimal stylesheet embedded in every preview page
+const previewStylesheet = `body{max-width:860px;margin:2rem auto;padding:0 1rem;font:16px/1.6 -apple-system,BlinkMacSystemFont,"Segoe UI",Helvetica,Arial,sans-serif;color:#172b4d}
+a{color:#0052cc}
+img{max-width:100%}
+pre{background:#f4f5f7;padding:.75rem 1rem;overflow:auto;border-radius:4px}
+code{font-family:SFMono-Regular,Consolas,"Liberation Mono",Menlo,monospace;font-size:.9em}
+table{border-collapse:collapse;margin:1rem 0}
+th,td{border:1px solid #c1c7d0;padding:.35rem .6rem;vertical-align:top}
+th{background:#f4f5f7}
+blockquote{margin:1rem 0;padding:.25rem 1rem;border-left:4px solid #c1c7d0;color:#42526e}`
+
+// PreviewPath returns the path of the HTML preview written for a Markdown file
+func PreviewPath(markdownPath string) string {
+	return strings.TrimSuffix(markdownPath, filepath.Ext(markdownPath)) + ".html"
+}
+
+// RenderPreview renders a written Markdown file as a standalone HTML page beside it, titled from its frontmatter.
+// Images and links keep their relative paths, with links to other Markdown files pointing at their previews.
+func RenderPreview(fsys writefs.FS, markdownPath string) (string, error) {
+	data, err := fsys.ReadFile(markdownPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read markdown file: %w", err)
+	}
+
+	title := strings.TrimSuffix(filepath.Base(markdownPath), filepath.Ext(markdownPath))
+	content := string(data)
+	if doc, err := model.ParseMarkdownDocument(content); err == nil {
+		content = doc.Content
+		if doc.Frontmatter.Title != "" {
+			title = doc.Frontmatter.Title
+		}
+	}
+
+	var page strings.Builder
+	page.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	page.WriteString("<meta name=\"viewport\" content=\"width=device-width, initial-scale=1\">\n")
+	page.WriteString("<title>" + html.EscapeString(title) + "</title>\n")
+	page.WriteString("<style>\n" + previewStylesheet + "\n</style>\n</head>\n<body>\n")
+	page.WriteString(publisher.ToHTML(content))
+	page.WriteString("\n</body>\n</html>\n")
+
+	previewPath := PreviewPath(markdownPath)
+	if err := fsys.WriteFile(previewPath, []byte(page.String()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write preview file: %w", err)
+	}
+	return previewPath, nil
+}
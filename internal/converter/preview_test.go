@@ -0,0 +1,44 @@
+package converter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+func TestRenderPreview(t *testing.T) {
+	fsys := writefs.NewMemFS()
+	markdownPath := filepath.Join("out", "guide.md")
+	markdown := "---\ntitle: \"Guide <v2>\"\n---\n\n# Guide\n\nSee [setup](guide/setup.md).\n"
+	if err := fsys.WriteFile(markdownPath, []byte(markdown), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	previewPath, err := RenderPreview(fsys, markdownPath)
+	if err != nil {
+		t.Fatalf("RenderPreview() error = %v", err)
+	}
+	if previewPath != filepath.Join("out", "guide.html") {
+		t.Errorf("preview path = %q", previewPath)
+	}
+
+	data, err := fsys.ReadFile(previewPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	page := string(data)
+	for _, want := range []string{
+		"<title>Guide &lt;v2&gt;</title>",
+		"<h1>Guide</h1>",
+		`<a href="guide/setup.html">setup</a>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("preview missing %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "title:") {
+		t.Errorf("preview contains the frontmatter:\n%s", page)
+	}
+}
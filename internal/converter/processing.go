@@ -4,19 +4,30 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/jackchuka/confluence-md/internal/converter/model"
 	"github.com/jackchuka/confluence-md/internal/converter/plugin"
 )
 
+var (
+	excessBlankLinesRegex = regexp.MustCompile(`\n{3,}`)
+	riAttachmentRegex     = regexp.MustCompile(`<ri:attachment[^>]*(ri:filename="[^"]+)"`)
+	confLinkRegex         = regexp.MustCompile(`\[([^\]]+)\]\(/wiki/spaces/([^/]+)/pages/(\d+)/[^)]+\)`)
+	nestedListGapRegex    = regexp.MustCompile(`(\n\s*` + listMarkerPattern + `[^\n]*)\n\s*\n(\s{2,}` + listMarkerPattern + `)`)
+	cdataRegex            = regexp.MustCompile(`<!\[CDATA\[([\s\S]*?)\]\]>`)
+)
+
+const listMarkerPattern = `(?:[-*+]\s|\d+\.\s)`
+
 // convertHtml converts raw Confluence HTML into Markdown text.
 func (c *Converter) convertHtml(html string) (string, error) {
 	processedHTML := c.preprocessCDATA(html)
 
 	md, err := c.mdConverter.ConvertString(processedHTML)
 	if err != nil {
-		fmt.Printf("Conversion error: %v\n", err)
+		c.events.Warning(c.plugin.CurrentPage(), fmt.Sprintf("Conversion error: %v", err))
 	}
 
 	return c.postprocessMarkdown(md), nil
@@ -24,20 +35,46 @@ func (c *Converter) convertHtml(html string) (string, error) {
 
 // postprocessMarkdown normalizes whitespace and link formatting in Markdown output.
 func (c *Converter) postprocessMarkdown(markdown string) string {
-	markdown = regexp.MustCompile(`\n{3,}`).ReplaceAllString(markdown, "\n\n")
+	markdown = excessBlankLinesRegex.ReplaceAllString(markdown, "\n\n")
 	markdown = fixNestedListSpacing(markdown)
 	markdown = fixMarkdownLinks(markdown)
+	markdown = resolveShortLinks(markdown, c.plugin.BaseURL())
+	markdown = mapLinks(markdown, c.linkMap)
+	markdown = rewriteLinks(markdown, c.linkRewrites)
+	if c.contentFilter != nil {
+		markdown = stripMarkedSections(markdown, c.contentFilter.Markers)
+	}
+	if c.headingIDs != "" && c.headingIDs != plugin.HeadingIDNone {
+		markdown = plugin.AddHeadingIDs(markdown, c.pageTitle(), c.headingIDs, isCloudURL(c.plugin.BaseURL()))
+	}
+	markdown = c.plugin.AddPandocHeadingIDs(markdown)
+	if c.numberHeadings {
+		markdown = plugin.NumberHeadings(markdown, 0)
+	}
 
 	return strings.TrimSpace(markdown)
 }
 
+// pageTitle returns the title of the page being converted, empty for bare HTML conversions
+func (c *Converter) pageTitle() string {
+	if page := c.plugin.CurrentPage(); page != nil {
+		return page.Title
+	}
+	return ""
+}
+
+// isCloudURL reports whether a base URL points to Confluence Cloud, which uses a different heading anchor scheme
+func isCloudURL(baseURL string) bool {
+	parsed, err := url.Parse(baseURL)
+	return err == nil && strings.HasSuffix(parsed.Hostname(), ".atlassian.net")
+}
+
 // extractImageReferences finds image attachments referenced in the Confluence HTML.
 func (c *Converter) extractImageReferences(html, pageID, baseURL string) []model.ImageRef {
 	var imageRefs []model.ImageRef
 
-//	acImageRegex := regexp.MustCompile(`<ac:image[^>]*>[\s\S]*?</ac:image>`)
-	acImageRegex := regexp.MustCompile(`<ri:attachment[^>]*(ri:filename="[^"]+)"`)
-	matches := acImageRegex.FindAllString(html, -1)
+	//	acImageRegex := regexp.MustCompile(`<ac:image[^>]*>[\s\S]*?</ac:image>`)
+	matches := riAttachmentRegex.FindAllString(html, -1)
 
 	for _, imageHTML := range matches {
 		fileName := plugin.ParseConfluenceImage(imageHTML)
@@ -58,17 +95,28 @@ func (c *Converter) extractImageReferences(html, pageID, baseURL string) []model
 	return imageRefs
 }
 
+// appendDiagramPreviews adds the preview images of diagram-app macros that are not referenced already
+func appendDiagramPreviews(imageRefs []model.ImageRef, previews []string, pageID, baseURL string) []model.ImageRef {
+	for _, fileName := range previews {
+		if slices.ContainsFunc(imageRefs, func(ref model.ImageRef) bool { return ref.FileName == fileName }) {
+			continue
+		}
+		imageRefs = append(imageRefs, model.ImageRef{
+			OriginalURL: fmt.Sprintf("%s/download/attachments/%s/%s", strings.TrimSuffix(baseURL, "/"), pageID, url.QueryEscape(fileName)),
+			FileName:    fileName,
+		})
+	}
+	return imageRefs
+}
+
 // fixMarkdownLinks converts Confluence-specific links into internal references.
 func fixMarkdownLinks(markdown string) string {
-	confLinkRegex := regexp.MustCompile(`\[([^\]]+)\]\(/wiki/spaces/([^/]+)/pages/(\d+)/[^)]+\)`)
 	return confLinkRegex.ReplaceAllString(markdown, "[$1](confluence://pageId/$3)")
 }
 
 // fixNestedListSpacing removes extraneous blank lines in nested lists.
 func fixNestedListSpacing(markdown string) string {
-	listMarker := `(?:[-*+]\s|\d+\.\s)`
-	pattern := regexp.MustCompile(`(\n\s*` + listMarker + `[^\n]*)\n\s*\n(\s{2,}` + listMarker + `)`)
-	result := pattern.ReplaceAllString(markdown, "$1\n$2")
+	result := nestedListGapRegex.ReplaceAllString(markdown, "$1\n$2")
 	if result != markdown {
 		return fixNestedListSpacing(result)
 	}
@@ -77,15 +125,12 @@ func fixNestedListSpacing(markdown string) string {
 
 // preprocessCDATA preserves content inside CDATA nodes prior to HTML parsing.
 func (c *Converter) preprocessCDATA(html string) string {
-	cdataRegex := regexp.MustCompile(`<!\[CDATA\[([\s\S]*?)\]\]>`)
 	return cdataRegex.ReplaceAllStringFunc(html, func(match string) string {
-		if submatch := cdataRegex.FindStringSubmatch(match); len(submatch) > 1 {
-			content := submatch[1]
-			content = strings.ReplaceAll(content, "&", "&amp;")
-			content = strings.ReplaceAll(content, "<", "&lt;")
-			content = strings.ReplaceAll(content, ">", "&gt;")
-			return fmt.Sprintf("<pre data-cdata='true'>%s</pre>", content)
-		}
-		return match
+		// The regex guarantees the CDATA markers, so the content can be sliced out without re-matching
+		content := match[len("<![CDATA[") : len(match)-len("]]>")]
+		content = strings.ReplaceAll(content, "&", "&amp;")
+		content = strings.ReplaceAll(content, "<", "&lt;")
+		content = strings.ReplaceAll(content, ">", "&gt;")
+		return "<pre data-cdata='true'>" + content + "</pre>"
 	})
 }
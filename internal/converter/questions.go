@@ -0,0 +1,94 @@
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+)
+
+// ConvertQuestion converts a Confluence Questions question and its answers into a document.
+// The accepted answer comes first; other answers are only included with allAnswers, ordered by votes.
+func (c *Converter) ConvertQuestion(question *confluenceModel.Question, answers []confluenceModel.Answer, baseURL string, allAnswers bool) (*model.MarkdownDocument, error) {
+	body, err := c.ConvertHTML(question.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert question %s: %w", question.ID, err)
+	}
+
+	topics := append([]string(nil), question.Topics...)
+	sort.Strings(topics)
+
+	doc := &model.MarkdownDocument{
+		Frontmatter: model.Frontmatter{
+			Title:  question.Title,
+			Author: question.Author,
+			Date:   question.AskedAt,
+			Labels: topics,
+			Confluence: model.ConfluenceRef{
+				URL: fmt.Sprintf("%s/questions/%s", strings.TrimSuffix(baseURL, "/"), question.ID),
+			},
+			Custom: map[string]any{
+				"question_id": question.ID,
+				"votes":       question.Votes,
+				"answers":     question.AnswerCount,
+			},
+		},
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# %s\n\n", question.Title)
+	fmt.Fprintf(&builder, "*%s %s %s %s · %s*\n", c.labels.Get(plugin.LabelAskedBy), question.Author,
+		c.labels.Get(plugin.LabelApprovedOn), question.AskedAt.Format("2006-01-02"), votesLabel(question.Votes, c.labels))
+	if body = strings.TrimSpace(body); body != "" {
+		fmt.Fprintf(&builder, "\n%s\n", body)
+	}
+
+	sorted := append([]confluenceModel.Answer(nil), answers...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if accepted(sorted[i], question) != accepted(sorted[j], question) {
+			return accepted(sorted[i], question)
+		}
+		return sorted[i].Votes > sorted[j].Votes
+	})
+
+	for _, answer := range sorted {
+		isAccepted := accepted(answer, question)
+		if !isAccepted && !allAnswers {
+			continue
+		}
+		answerBody, err := c.ConvertHTML(answer.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert answer %s: %w", answer.ID, err)
+		}
+
+		heading := c.labels.Get(plugin.LabelAnswer)
+		if isAccepted {
+			heading = "✅ " + c.labels.Get(plugin.LabelAcceptedAnswer)
+			doc.Frontmatter.Custom["accepted_answer_id"] = answer.ID
+		}
+		fmt.Fprintf(&builder, "\n## %s\n\n", heading)
+		fmt.Fprintf(&builder, "*%s %s %s %s · %s*\n", c.labels.Get(plugin.LabelAnsweredBy), answer.Author,
+			c.labels.Get(plugin.LabelApprovedOn), answer.AnsweredAt.Format("2006-01-02"), votesLabel(answer.Votes, c.labels))
+		if answerBody = strings.TrimSpace(answerBody); answerBody != "" {
+			fmt.Fprintf(&builder, "\n%s\n", answerBody)
+		}
+	}
+
+	doc.Content = builder.String()
+	return doc, nil
+}
+
+// accepted reports whether the answer was accepted for the question
+func accepted(answer confluenceModel.Answer, question *confluenceModel.Question) bool {
+	return answer.Accepted || (question.AcceptedAnswerID != "" && answer.ID == question.AcceptedAnswerID)
+}
+
+func votesLabel(votes int, labels plugin.Labels) string {
+	if votes == 1 {
+		return "1 " + labels.Get(plugin.LabelVote)
+	}
+	return fmt.Sprintf("%d %s", votes, labels.Get(plugin.LabelVotes))
+}
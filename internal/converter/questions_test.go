@@ -0,0 +1,64 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+)
+
+func TestConvertQuestion(t *testing.T) {
+	question := &confluenceModel.Question{
+		ID:               "12",
+		Title:            "How to deploy?",
+		Author:           "Ada",
+		AskedAt:          time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Votes:            3,
+		AnswerCount:      2,
+		AcceptedAnswerID: "34",
+		Topics:           []string{"ops"},
+	}
+	answers := []confluenceModel.Answer{
+		{ID: "35", Author: "Carol", Votes: 9, AnsweredAt: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{ID: "34", Author: "Bob", Votes: 1, AnsweredAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	conv := NewConverter(nil)
+	doc, err := conv.ConvertQuestion(question, answers, "https://example.com/wiki", false)
+	if err != nil {
+		t.Fatalf("ConvertQuestion returned error: %v", err)
+	}
+	want := "# How to deploy?\n\n*Asked by Ada on 2024-01-01 · 3 votes*\n\n## ✅ Accepted Answer\n\n*Answered by Bob on 2024-01-02 · 1 vote*\n"
+	if doc.Content != want {
+		t.Fatalf("unexpected content:\n%q\nwant\n%q", doc.Content, want)
+	}
+	if doc.Frontmatter.Custom["accepted_answer_id"] != "34" || doc.Frontmatter.Confluence.URL != "https://example.com/wiki/questions/12" {
+		t.Fatalf("unexpected frontmatter: %#v", doc.Frontmatter)
+	}
+
+	doc, err = conv.ConvertQuestion(question, answers, "https://example.com/wiki", true)
+	if err != nil {
+		t.Fatalf("ConvertQuestion returned error: %v", err)
+	}
+	if accepted, other := strings.Index(doc.Content, "Bob"), strings.Index(doc.Content, "Carol"); accepted < 0 || other < accepted {
+		t.Fatalf("expected the accepted answer before other answers:\n%s", doc.Content)
+	}
+}
+
+func TestConvertQuestionLabels(t *testing.T) {
+	labels, err := plugin.LoadLabels("de", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	question := &confluenceModel.Question{ID: "12", Title: "Wie?", Author: "Ada", AskedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Votes: 1}
+
+	doc, err := NewConverter(nil, WithLabels(labels)).ConvertQuestion(question, nil, "https://example.com/wiki", false)
+	if err != nil {
+		t.Fatalf("ConvertQuestion returned error: %v", err)
+	}
+	if want := "*Gefragt von Ada am 2024-01-01 · 1 Stimme*\n"; !strings.Contains(doc.Content, want) {
+		t.Fatalf("expected %q in:\n%s", want, doc.Content)
+	}
+}
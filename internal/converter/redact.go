@@ -0,0 +1,89 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// RedactionRule replaces every match of Pattern in the converted Markdown with Replacement
+type RedactionRule struct {
+	Name        string // reported with the redaction counts, defaults to the pattern
+	Pattern     *regexp.Regexp
+	Replacement string // may reference capture groups as $1
+}
+
+// redactionConfigEntry is one rule of a redaction config file
+type redactionConfigEntry struct {
+	Name        string `json:"name"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+}
+
+// ParseRedactionRules parses a JSON array of {"name", "pattern", "replacement"} objects
+func ParseRedactionRules(data []byte) ([]RedactionRule, error) {
+	var entries []redactionConfigEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse redaction rules: %w", err)
+	}
+
+	rules := make([]RedactionRule, 0, len(entries))
+	for i, entry := range entries {
+		if entry.Pattern == "" {
+			return nil, fmt.Errorf("redaction rule %d has no pattern", i+1)
+		}
+		pattern, err := regexp.Compile(entry.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in redaction rule %d: %w", i+1, err)
+		}
+		name := entry.Name
+		if name == "" {
+			name = entry.Pattern
+		}
+		rules = append(rules, RedactionRule{Name: name, Pattern: pattern, Replacement: entry.Replacement})
+	}
+	return rules, nil
+}
+
+// WithRedactions applies the rules to each page's Markdown before links are audited, and again to the whole
+// rendered file, including frontmatter and content added after conversion, when the page is written
+func WithRedactions(rules []RedactionRule) Option {
+	return func(c *Converter) {
+		c.redactions = rules
+	}
+}
+
+// redact applies the rules in order and returns the redacted markdown with the number of matches per rule name
+func redact(markdown string, rules []RedactionRule) (string, map[string]int) {
+	var counts map[string]int
+	for _, rule := range rules {
+		matches := len(rule.Pattern.FindAllStringIndex(markdown, -1))
+		if matches == 0 {
+			continue
+		}
+		if counts == nil {
+			counts = make(map[string]int)
+		}
+		counts[rule.Name] += matches
+		markdown = rule.Pattern.ReplaceAllString(markdown, rule.Replacement)
+	}
+	return markdown, counts
+}
+
+// redactor returns the document's Redact hook, which counts matches into doc.Redactions. The body was
+// already redacted during conversion, so it only finds what was added since: frontmatter, banners and
+// the titles of inlined pages.
+func (c *Converter) redactor(doc *model.MarkdownDocument) func(string) string {
+	return func(content string) string {
+		content, counts := redact(content, c.redactions)
+		for name, count := range counts {
+			if doc.Redactions == nil {
+				doc.Redactions = make(map[string]int)
+			}
+			doc.Redactions[name] += count
+		}
+		return content
+	}
+}
@@ -0,0 +1,74 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	confModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+func TestRedact(t *testing.T) {
+	rules, err := ParseRedactionRules([]byte(`[
+		{"name": "emails", "pattern": "[\\w.]+@example\\.com", "replacement": "[email]"},
+		{"pattern": "OPS-(\\d+)", "replacement": "TICKET-$1"}
+	]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, counts := redact("Ask jane.doe@example.com or bob@example.com about OPS-12.", rules)
+	if want := "Ask [email] or [email] about TICKET-12."; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	if counts["emails"] != 2 || counts[`OPS-(\d+)`] != 1 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+
+	if _, counts = redact("nothing to hide", rules); counts != nil {
+		t.Fatalf("expected no counts without matches, got %v", counts)
+	}
+}
+
+func TestParseRedactionRulesErrors(t *testing.T) {
+	for _, config := range []string{`{}`, `[{"name": "empty"}]`, `[{"pattern": "("}]`} {
+		if _, err := ParseRedactionRules([]byte(config)); err == nil {
+			t.Fatalf("expected an error for %s", config)
+		}
+	}
+}
+
+func TestRedactWrittenFile(t *testing.T) {
+	rules, err := ParseRedactionRules([]byte(`[{"name": "codename", "pattern": "Falcon", "replacement": "[project]"}]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fsys := writefs.NewMemFS()
+	conv := NewConverter(nil, WithRedactions(rules), WithFS(fsys))
+	page := &confModel.ConfluencePage{
+		ID:       "42",
+		Title:    "Falcon launch",
+		SpaceKey: "SPACE",
+		Content:  confModel.ConfluenceContent{Storage: confModel.ContentStorage{Value: "<p>Falcon ships soon</p>"}},
+	}
+
+	doc, err := conv.ConvertPage(page, "https://example.atlassian.net", "out")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	AppendSection(doc, "Falcon details", "More")
+	if err := SaveMarkdownDocument(fsys, doc, "out/page.md", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := fsys.ReadFile("out/page.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(string(data), "Falcon") {
+		t.Fatalf("expected frontmatter and inlined titles to be redacted, got %q", data)
+	}
+	if doc.Redactions["codename"] != 3 {
+		t.Fatalf("expected body, title and section matches to be counted once each, got %v", doc.Redactions)
+	}
+}
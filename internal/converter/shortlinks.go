@@ -0,0 +1,63 @@
+package converter
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// shortLinkRegex matches Confluence tiny links such as /x/h9YS or https://example.atlassian.net/wiki/x/h9YS#Setup
+var shortLinkRegex = regexp.MustCompile(`^(https?://([^/]+))?(/wiki)?/x/([A-Za-z0-9_-]+)/?(#.*)?$`)
+
+// resolveShortLinks replaces tiny links to the Confluence instance at baseURL with page links, so they take
+// part in --link-map rewriting: relative ones become confluence://pageId/ links like other relative page
+// links, absolute ones point at the page's viewpage.action URL
+func resolveShortLinks(markdown, baseURL string) string {
+	internalHost := ""
+	if base, err := url.Parse(baseURL); err == nil {
+		internalHost = strings.ToLower(base.Host)
+	}
+
+	return rewriteLinkTargets(markdown, func(target string) string {
+		match := shortLinkRegex.FindStringSubmatch(target)
+		if match == nil || match[1] != "" && strings.ToLower(match[2]) != internalHost {
+			return target
+		}
+		pageID, err := decodeShortLink(match[4])
+		if err != nil {
+			return target
+		}
+		if match[1] != "" {
+			return fmt.Sprintf("%s%s/pages/viewpage.action?pageId=%s%s", match[1], match[3], pageID, match[5])
+		}
+		return "confluence://pageId/" + pageID + match[5]
+	})
+}
+
+// decodeShortLink decodes the page ID of a tiny link code. Confluence encodes the ID's little-endian bytes
+// as URL-safe base64 and drops the trailing padding and "A" (zero) characters, so no API request is needed.
+func decodeShortLink(code string) (string, error) {
+	code = strings.NewReplacer("-", "/", "_", "+").Replace(code)
+	for len(code)%4 != 0 {
+		code += "A"
+	}
+	data, err := base64.StdEncoding.DecodeString(code)
+	if err != nil {
+		return "", fmt.Errorf("invalid short link code: %w", err)
+	}
+	if len(data) > 8 {
+		return "", fmt.Errorf("invalid short link code: %d bytes", len(data))
+	}
+
+	var pageID uint64
+	for i := len(data) - 1; i >= 0; i-- {
+		pageID = pageID<<8 | uint64(data[i])
+	}
+	if pageID == 0 {
+		return "", fmt.Errorf("invalid short link code: empty page ID")
+	}
+	return strconv.FormatUint(pageID, 10), nil
+}
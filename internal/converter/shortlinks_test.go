@@ -0,0 +1,31 @@
+package converter
+
+import "testing"
+
+func TestDecodeShortLink(t *testing.T) {
+	tests := map[string]string{
+		"h9YS":    "1234567",
+		"EIA4Kg":  "708345872",
+		"AQ":      "1",
+		"mIFhdw":  "2002878872",
+		"n6AKDQE": "4513767583",
+	}
+	for code, want := range tests {
+		got, err := decodeShortLink(code)
+		if err != nil || got != want {
+			t.Fatalf("decodeShortLink(%q) = %q, %v, want %q", code, got, err, want)
+		}
+	}
+
+	if _, err := decodeShortLink("A"); err == nil {
+		t.Fatal("expected an error for a code without a page ID")
+	}
+}
+
+func TestResolveShortLinks(t *testing.T) {
+	input := "[Guide](/wiki/x/h9YS) [Setup](https://example.atlassian.net/wiki/x/h9YS#Setup) [Other](https://other.example.com/x/h9YS)"
+	want := "[Guide](confluence://pageId/1234567) [Setup](https://example.atlassian.net/wiki/pages/viewpage.action?pageId=1234567#Setup) [Other](https://other.example.com/x/h9YS)"
+	if got := resolveShortLinks(input, "https://example.atlassian.net"); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,62 @@
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// NewSpaceIndexDocument builds the index document of an exported space: the space description and its top-level pages,
+// with the space labels, categories and administrators in the frontmatter
+func NewSpaceIndexDocument(space *confluenceModel.ConfluenceSpace, baseURL string, entries []NavEntry) *model.MarkdownDocument {
+	title := space.Name
+	if title == "" {
+		title = space.Key
+	}
+
+	labels := append([]string(nil), space.Labels...)
+	sort.Strings(labels)
+
+	doc := &model.MarkdownDocument{
+		Frontmatter: model.Frontmatter{
+			Title:  title,
+			Labels: labels,
+			Confluence: model.ConfluenceRef{
+				SpaceKey: space.Key,
+				URL:      fmt.Sprintf("%s/spaces/%s", strings.TrimSuffix(baseURL, "/"), space.Key),
+			},
+			Custom: make(map[string]any),
+		},
+	}
+	if space.Description != "" {
+		doc.Frontmatter.Custom["description"] = space.Description
+	}
+	if len(space.Categories) > 0 {
+		doc.Frontmatter.Custom["categories"] = space.Categories
+	}
+	if len(space.Admins) > 0 {
+		doc.Frontmatter.Custom["space_admins"] = space.Admins
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# %s\n", title)
+	if space.Description != "" {
+		fmt.Fprintf(&builder, "\n%s\n", space.Description)
+	}
+	if len(entries) > 0 {
+		builder.WriteString("\n## Pages\n\n")
+		for _, entry := range entries {
+			if entry.Path == "" {
+				fmt.Fprintf(&builder, "- %s\n", entry.Title)
+				continue
+			}
+			fmt.Fprintf(&builder, "- [%s](%s)\n", entry.Title, entry.Path)
+		}
+	}
+	doc.Content = builder.String()
+
+	return doc
+}
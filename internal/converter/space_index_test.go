@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestNewSpaceIndexDocument(t *testing.T) {
+	space := &confluenceModel.ConfluenceSpace{
+		Key:         "DOCS",
+		Name:        "Docs",
+		Description: "Product documentation",
+		Labels:      []string{"public"},
+		Categories:  []string{"engineering"},
+		Admins:      []string{"Ada", "docs-admins"},
+	}
+
+	doc := NewSpaceIndexDocument(space, "https://example.com/wiki/", []NavEntry{{Title: "Home", Path: "home.md"}})
+	out, err := doc.WithFrontmatter()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"title: \"Docs\"",
+		"  space: \"DOCS\"",
+		"  url: \"https://example.com/wiki/spaces/DOCS\"",
+		"categories: [engineering]",
+		"description: Product documentation",
+		"space_admins: [Ada, docs-admins]",
+		"# Docs\n\nProduct documentation\n\n## Pages\n\n- [Home](home.md)\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package converter
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+// headingIDAttrRegex matches a trailing {#id} heading attribute, which is not part of the section title
+var headingIDAttrRegex = regexp.MustCompile(`\s*\{#[^}]*\}$`)
+
+// DocumentSection is a part of a markdown document starting at a heading
+type DocumentSection struct {
+	Title   string
+	Content string
+}
+
+// SplitByHeading breaks markdown into the content before the first heading and one section
+// per heading at or above the given level. Headings inside fenced code blocks are ignored.
+func SplitByHeading(markdown string, level int) (string, []DocumentSection) {
+	lines := strings.Split(markdown, "\n")
+
+	var starts []int
+	var sections []DocumentSection
+	plugin.ForEachHeading(lines, func(i int, headingLevel int, text string) {
+		if headingLevel <= level {
+			starts = append(starts, i)
+			sections = append(sections, DocumentSection{Title: headingIDAttrRegex.ReplaceAllString(strings.TrimSpace(text), "")})
+		}
+	})
+	if len(sections) == 0 {
+		return strings.TrimSpace(markdown), nil
+	}
+
+	for i, start := range starts {
+		end := len(lines)
+		if i+1 < len(starts) {
+			end = starts[i+1]
+		}
+		sections[i].Content = strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+	}
+	return strings.TrimSpace(strings.Join(lines[:starts[0]], "\n")), sections
+}
+
+// SaveSplitMarkdownDocument writes one markdown file per section next to outputPath and
+// an index linking to them at outputPath; doc itself is left unchanged.
+// It returns the paths of all written files, index first. Section titles are slugged with the
+// naming options' Slugger and shortened to their path limits.
+func SaveSplitMarkdownDocument(fsys writefs.FS, doc *model.MarkdownDocument, outputPath string, withFrontmatter bool, level int, opts ...NamingOption) ([]string, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("document cannot be nil")
+	}
+
+	preface, sections := SplitByHeading(doc.Content, level)
+	if len(sections) < 2 {
+		if err := SaveMarkdownDocument(fsys, doc, outputPath, withFrontmatter); err != nil {
+			return nil, err
+		}
+		return []string{outputPath}, nil
+	}
+
+	config := newNamingConfig(opts)
+	base := strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath))
+	dir := filepath.Dir(outputPath)
+
+	var index strings.Builder
+	if preface != "" {
+		index.WriteString(preface)
+		index.WriteString("\n\n")
+	}
+	index.WriteString("## Sections\n\n")
+
+	written := []string{outputPath}
+	for i, section := range sections {
+		sectionSlug := config.slugger.Slug(section.Title, nil)
+		if sectionSlug == "" {
+			sectionSlug = "section"
+		}
+		fileName, _ := config.pathLimits.Shorten(dir, fmt.Sprintf("%s-%02d-%s.md", base, i+1, sectionSlug))
+
+		sectionDoc := *doc
+		sectionDoc.Frontmatter.Title = fmt.Sprintf("%s - %s", doc.Frontmatter.Title, section.Title)
+		sectionDoc.Content = section.Content
+
+		sectionPath := filepath.Join(dir, fileName)
+		if err := SaveMarkdownDocument(fsys, &sectionDoc, sectionPath, withFrontmatter); err != nil {
+			return nil, fmt.Errorf("failed to save section %q: %w", section.Title, err)
+		}
+		written = append(written, sectionPath)
+
+		fmt.Fprintf(&index, "%d. %s\n", i+1, MarkdownLink(section.Title, fileName))
+	}
+
+	// The caller's document keeps its full content
+	indexDoc := *doc
+	indexDoc.Content = strings.TrimSpace(index.String())
+	if err := SaveMarkdownDocument(fsys, &indexDoc, outputPath, withFrontmatter); err != nil {
+		return nil, err
+	}
+
+	return written, nil
+}
+
+// AppendSection appends content to the document under a second-level heading,
+// demoting the content's own headings so they nest beneath it.
+func AppendSection(doc *model.MarkdownDocument, title, content string) {
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	plugin.ForEachHeading(lines, func(i int, level int, text string) {
+		lines[i] = strings.Repeat("#", min(level+2, 6)) + " " + text
+	})
+
+	var builder strings.Builder
+	builder.WriteString(strings.TrimRight(doc.Content, "\n"))
+	if builder.Len() > 0 {
+		builder.WriteString("\n\n")
+	}
+	fmt.Fprintf(&builder, "## %s", title)
+	if body := strings.TrimSpace(strings.Join(lines, "\n")); body != "" {
+		builder.WriteString("\n\n")
+		builder.WriteString(body)
+	}
+	doc.Content = builder.String()
+}
@@ -0,0 +1,116 @@
+package converter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+func TestSplitByHeading(t *testing.T) {
+	markdown := "Intro text\n\n# First\n\nbody\n\n```\n# comment\n```\n\n## Sub\n\nmore\n\n# Second\n\nend"
+
+	preface, sections := SplitByHeading(markdown, 1)
+	if preface != "Intro text" {
+		t.Fatalf("unexpected preface: %q", preface)
+	}
+	if len(sections) != 2 {
+		t.Fatalf("expected 2 sections, got %d", len(sections))
+	}
+	if sections[0].Title != "First" || !strings.Contains(sections[0].Content, "## Sub") || !strings.Contains(sections[0].Content, "# comment") {
+		t.Fatalf("unexpected first section: %#v", sections[0])
+	}
+	if sections[1].Content != "# Second\n\nend" {
+		t.Fatalf("unexpected second section: %q", sections[1].Content)
+	}
+
+	if _, sections := SplitByHeading(markdown, 2); len(sections) != 3 {
+		t.Fatalf("expected 3 sections at h2, got %d", len(sections))
+	}
+
+	if _, sections := SplitByHeading("# Setup {#Page-Setup}\n\ntext", 1); sections[0].Title != "Setup" {
+		t.Fatalf("expected heading id to be dropped from the title, got %q", sections[0].Title)
+	}
+}
+
+func TestSaveSplitMarkdownDocument(t *testing.T) {
+	mem := writefs.NewMemFS()
+	doc := &convModel.MarkdownDocument{
+		Content:     "# Alpha\n\na\n\n# Beta\n\nb",
+		Frontmatter: convModel.Frontmatter{Title: "Page"},
+	}
+
+	outputPath := filepath.Join("out", "page.md")
+	written, err := SaveSplitMarkdownDocument(mem, doc, outputPath, false, 1)
+	if err != nil {
+		t.Fatalf("SaveSplitMarkdownDocument returned error: %v", err)
+	}
+	if len(written) != 3 {
+		t.Fatalf("expected index and 2 sections, got %v", written)
+	}
+
+	index, err := mem.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("failed to read index: %v", err)
+	}
+	if !strings.Contains(string(index), "1. [Alpha](page-01-alpha.md)") || !strings.Contains(string(index), "2. [Beta](page-02-beta.md)") {
+		t.Fatalf("unexpected index content: %q", string(index))
+	}
+	if doc.Content != "# Alpha\n\na\n\n# Beta\n\nb" {
+		t.Fatalf("expected the document to keep its content, got %q", doc.Content)
+	}
+
+	section, err := mem.ReadFile(filepath.Join("out", "page-02-beta.md"))
+	if err != nil {
+		t.Fatalf("failed to read section: %v", err)
+	}
+	if string(section) != "# Beta\n\nb" {
+		t.Fatalf("unexpected section content: %q", string(section))
+	}
+}
+
+func TestSaveSplitMarkdownDocumentPathLimits(t *testing.T) {
+	mem := writefs.NewMemFS()
+	doc := &convModel.MarkdownDocument{Content: "# A very long section title indeed\n\na\n\n# Beta\n\nb"}
+
+	limits := PathLimits{MaxSegment: 20}
+	written, err := SaveSplitMarkdownDocument(mem, doc, filepath.Join("out", "page.md"), false, 1, WithPathLimits(limits))
+	if err != nil {
+		t.Fatalf("SaveSplitMarkdownDocument returned error: %v", err)
+	}
+	for _, path := range written {
+		if name := filepath.Base(path); len(name) > 20 {
+			t.Errorf("section file %s is longer than the limit", name)
+		}
+	}
+	index, _ := mem.ReadFile(filepath.Join("out", "page.md"))
+	if !strings.Contains(string(index), "("+filepath.Base(written[1])+")") {
+		t.Fatalf("index does not link the shortened file %s: %q", written[1], string(index))
+	}
+}
+
+func TestAppendSection(t *testing.T) {
+	doc := &convModel.MarkdownDocument{Content: "Parent body\n"}
+	AppendSection(doc, "Child", "# Heading\n\n```\n# code\n```\n\n##### Deep")
+
+	want := "Parent body\n\n## Child\n\n### Heading\n\n```\n# code\n```\n\n###### Deep"
+	if doc.Content != want {
+		t.Fatalf("AppendSection() = %q, want %q", doc.Content, want)
+	}
+}
+
+func TestSaveSplitMarkdownDocumentEscapesTitles(t *testing.T) {
+	mem := writefs.NewMemFS()
+	doc := &convModel.MarkdownDocument{Content: "# Use [brackets]\n\na\n\n# Beta\n\nb"}
+
+	outputPath := filepath.Join("out", "page.md")
+	if _, err := SaveSplitMarkdownDocument(mem, doc, outputPath, false, 1); err != nil {
+		t.Fatalf("SaveSplitMarkdownDocument returned error: %v", err)
+	}
+	index, _ := mem.ReadFile(outputPath)
+	if !strings.Contains(string(index), `1. [Use \[brackets\]](page-01-use-brackets.md)`) {
+		t.Fatalf("expected an escaped section title in the index: %q", string(index))
+	}
+}
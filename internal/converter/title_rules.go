@@ -0,0 +1,87 @@
+package converter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// TitleRule replaces every match of Pattern in a page title with Replacement
+type TitleRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string // may reference capture groups as $1
+}
+
+// TitleRules rename page titles before they become file names, frontmatter titles and navigation labels,
+// e.g. to drop a "[DOCS] " tag that every page of a space starts with. Pages keep their Confluence titles;
+// the namers (WithTitleRules) and the converter (WithDocumentTitleRules) apply the rules where titles are written.
+type TitleRules []TitleRule
+
+// ParseTitleRules builds rules that strip each literal prefix, followed by rename rules in the form
+// pattern=replacement, where pattern is a regular expression
+func ParseTitleRules(stripPrefixes, renames []string) (TitleRules, error) {
+	var rules TitleRules
+	for _, prefix := range stripPrefixes {
+		if prefix == "" {
+			return nil, fmt.Errorf("title prefix to strip must not be empty")
+		}
+		rules = append(rules, TitleRule{Pattern: regexp.MustCompile("^" + regexp.QuoteMeta(prefix))})
+	}
+
+	for _, value := range renames {
+		pattern, replacement, ok := strings.Cut(value, "=")
+		if !ok || pattern == "" {
+			return nil, fmt.Errorf("title rename rule must be pattern=replacement, got: %s", value)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern in title rename rule %q: %w", value, err)
+		}
+		rules = append(rules, TitleRule{Pattern: re, Replacement: replacement})
+	}
+	return rules, nil
+}
+
+// Apply runs the rules in order and trims the result. A title the rules would empty is kept unchanged.
+func (r TitleRules) Apply(title string) string {
+	if len(r) == 0 {
+		return title
+	}
+
+	renamed := title
+	for _, rule := range r {
+		renamed = rule.Pattern.ReplaceAllString(renamed, rule.Replacement)
+	}
+	renamed = strings.TrimSpace(renamed)
+	if renamed == "" {
+		return title
+	}
+	return renamed
+}
+
+// renamed returns a copy of page with the rules applied to its title, or page itself when nothing changes
+func (r TitleRules) renamed(page *confluenceModel.ConfluencePage) *confluenceModel.ConfluencePage {
+	title := r.Apply(page.Title)
+	if title == page.Title {
+		return page
+	}
+	copied := *page
+	copied.Title = title
+	return &copied
+}
+
+// WithTitleRules renames page and ancestor titles before they become file and directory names
+func WithTitleRules(rules TitleRules) NamingOption {
+	return func(c *namingConfig) {
+		c.titleRules = rules
+	}
+}
+
+// WithDocumentTitleRules renames the frontmatter titles of converted pages
+func WithDocumentTitleRules(rules TitleRules) Option {
+	return func(c *Converter) {
+		c.titleRules = rules
+	}
+}
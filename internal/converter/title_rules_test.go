@@ -0,0 +1,88 @@
+package converter
+
+import (
+	"path/filepath"
+	"testing"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+func TestTitleRulesApply(t *testing.T) {
+	rules, err := ParseTitleRules([]string{"[DOCS] "}, []string{`\s*\(draft\)$=`, `^(\d+)\.\s*=Step $1: `})
+	if err != nil {
+		t.Fatalf("ParseTitleRules() error = %v", err)
+	}
+
+	tests := map[string]string{
+		"[DOCS] Getting started":      "Getting started",
+		"[DOCS] Release plan (draft)": "Release plan",
+		"2. Install":                  "Step 2: Install",
+		"Not [DOCS] prefixed":         "Not [DOCS] prefixed",
+		"[DOCS] ":                     "[DOCS] ",
+	}
+	for title, want := range tests {
+		if got := rules.Apply(title); got != want {
+			t.Errorf("Apply(%q) = %q, want %q", title, got, want)
+		}
+	}
+
+	var none TitleRules
+	if got := none.Apply(" Untouched "); got != " Untouched " {
+		t.Errorf("empty rules Apply() = %q, want the title unchanged", got)
+	}
+}
+
+func TestParseTitleRulesInvalid(t *testing.T) {
+	for _, renames := range [][]string{{"no separator"}, {"=empty pattern"}, {"(unclosed=x"}} {
+		if _, err := ParseTitleRules(nil, renames); err == nil {
+			t.Errorf("ParseTitleRules(%q) error = nil, want an error", renames)
+		}
+	}
+	if _, err := ParseTitleRules([]string{""}, nil); err == nil {
+		t.Error("ParseTitleRules() with an empty prefix error = nil, want an error")
+	}
+}
+
+func TestTitleRulesNaming(t *testing.T) {
+	rules, err := ParseTitleRules([]string{"[DOCS] "}, nil)
+	if err != nil {
+		t.Fatalf("ParseTitleRules() error = %v", err)
+	}
+	page := &confluenceModel.ConfluencePage{ID: "1", Title: "[DOCS] Install", SpaceKey: "DOCS"}
+
+	path, err := GenerateRelativePath(page, []string{"[DOCS] Guides"}, HierarchyPathNamer(nil, WithTitleRules(rules)))
+	if err != nil {
+		t.Fatalf("GenerateRelativePath() error = %v", err)
+	}
+	if want := filepath.Join("guides", "install.md"); path != want {
+		t.Errorf("GenerateRelativePath() = %q, want %q", path, want)
+	}
+
+	namer, err := NewTemplateOutputNamer("{{ .Page.Title }}-{{ .SlugTitle }}", WithTitleRules(rules))
+	if err != nil {
+		t.Fatalf("NewTemplateOutputNamer() error = %v", err)
+	}
+	if name, err := GenerateFileName(page, namer); err != nil || name != "Install-install.md" {
+		t.Errorf("GenerateFileName() = %q, %v, want Install-install.md", name, err)
+	}
+	if page.Title != "[DOCS] Install" {
+		t.Errorf("namers changed the page title to %q", page.Title)
+	}
+}
+
+func TestConverterDocumentTitleRules(t *testing.T) {
+	rules, err := ParseTitleRules([]string{"[DOCS] "}, nil)
+	if err != nil {
+		t.Fatalf("ParseTitleRules() error = %v", err)
+	}
+	page := &confluenceModel.ConfluencePage{ID: "1", Title: "[DOCS] Install", SpaceKey: "DOCS"}
+	page.Content.Storage.Value = "<p>Steps</p>"
+
+	doc, err := NewConverter(nil, WithDocumentTitleRules(rules)).ConvertPage(page, "https://example.com", t.TempDir())
+	if err != nil {
+		t.Fatalf("ConvertPage() error = %v", err)
+	}
+	if doc.Frontmatter.Title != "Install" {
+		t.Errorf("frontmatter title = %q, want Install", doc.Frontmatter.Title)
+	}
+}
@@ -0,0 +1,114 @@
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gosimple/slug"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+)
+
+// pageLanguageLabelPrefixes mark page labels naming the page's language, e.g. lang-de or language-el
+var pageLanguageLabelPrefixes = []string{"lang-", "language-"}
+
+// Transliteration is the built-in Slugger: it controls how titles become ASCII slugs for file and
+// directory names. A nil Transliteration uses the English rules.
+type Transliteration struct {
+	Lang  string            // slug language such as de, el or tr, used for pages without a language label
+	Table map[string]string // custom substitutions applied before the language rules, e.g. "щ": "shch"
+}
+
+// NamingOption configures the built-in output and path namers
+type NamingOption func(*namingConfig)
+
+type namingConfig struct {
+	slugger    plugin.Slugger
+	pathLimits PathLimits
+	titleRules TitleRules
+}
+
+// WithTransliteration slugs titles with the given language and substitution table
+func WithTransliteration(t *Transliteration) NamingOption {
+	return func(c *namingConfig) {
+		if t != nil {
+			c.slugger = t
+		}
+	}
+}
+
+// WithSlugger slugs titles for file, directory and section file names with a custom Slugger,
+// replacing any transliteration set before it
+func WithSlugger(slugger plugin.Slugger) NamingOption {
+	return func(c *namingConfig) {
+		if slugger != nil {
+			c.slugger = slugger
+		}
+	}
+}
+
+// WithPathLimits fits split section file names into the path length limits
+func WithPathLimits(limits PathLimits) NamingOption {
+	return func(c *namingConfig) {
+		c.pathLimits = limits
+	}
+}
+
+func newNamingConfig(opts []NamingOption) *namingConfig {
+	config := &namingConfig{slugger: plugin.DefaultSlugger}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// Slug turns s into an ASCII slug using the page's language label when it has one, then the
+// configured language, then English. page may be nil.
+func (t *Transliteration) Slug(s string, page *confluenceModel.ConfluencePage) string {
+	if t == nil {
+		return slug.MakeLang(s, "en")
+	}
+
+	lang := PageLanguage(page)
+	if lang == "" {
+		lang = t.Lang
+	}
+	if lang == "" {
+		lang = "en"
+	}
+	if len(t.Table) > 0 {
+		s = slug.Substitute(s, t.Table)
+	}
+	return slug.MakeLang(s, lang)
+}
+
+// PageLanguage returns the language named by a lang-<code> or language-<code> page label, or ""
+func PageLanguage(page *confluenceModel.ConfluencePage) string {
+	if page == nil {
+		return ""
+	}
+	for _, label := range page.GetLabelNames() {
+		for _, prefix := range pageLanguageLabelPrefixes {
+			if lang, ok := strings.CutPrefix(strings.ToLower(label), prefix); ok && lang != "" {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+// ParseTransliterationTable parses a JSON object mapping characters or character sequences to their
+// replacement, e.g. {"ж": "zh", "Ж": "Zh"}
+func ParseTransliterationTable(data []byte) (map[string]string, error) {
+	var table map[string]string
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse transliteration table: %w", err)
+	}
+	for from := range table {
+		if from == "" {
+			return nil, fmt.Errorf("transliteration table has an empty key")
+		}
+	}
+	return table, nil
+}
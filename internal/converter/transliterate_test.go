@@ -0,0 +1,95 @@
+package converter
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+func TestTransliterationSlug(t *testing.T) {
+	var english *Transliteration
+	if got := english.Slug("Über uns", nil); got != "uber-uns" {
+		t.Fatalf("nil Slug() = %q, want uber-uns", got)
+	}
+
+	german := &Transliteration{Lang: "de"}
+	if got := german.Slug("Über uns", nil); got != "ueber-uns" {
+		t.Fatalf("German Slug() = %q, want ueber-uns", got)
+	}
+
+	// A language label on the page wins over the configured language
+	page := &confluenceModel.ConfluencePage{Metadata: confluenceModel.ConfluenceMetadata{Labels: []confluenceModel.Label{{Name: "lang-en"}}}}
+	if got := german.Slug("Über uns", page); got != "uber-uns" {
+		t.Fatalf("Slug() with lang-en label = %q, want uber-uns", got)
+	}
+
+	custom := &Transliteration{Table: map[string]string{"щ": "shch", "Щ": "Shch"}}
+	if got := custom.Slug("Щит", nil); got != "shchit" {
+		t.Fatalf("custom Slug() = %q, want shchit", got)
+	}
+}
+
+func TestTransliterationNamers(t *testing.T) {
+	option := WithTransliteration(&Transliteration{Lang: "de"})
+	page := &confluenceModel.ConfluencePage{Title: "Größen"}
+
+	got, err := GenerateRelativePath(page, []string{"Übersicht"}, HierarchyPathNamer(nil, option))
+	if err != nil {
+		t.Fatalf("GenerateRelativePath() error = %v", err)
+	}
+	if want := filepath.Join("uebersicht", "groessen.md"); got != want {
+		t.Fatalf("GenerateRelativePath() = %q, want %q", got, want)
+	}
+
+	namer, err := NewTemplateOutputNamer("{{ .SlugTitle }}-{{ slug \"Ä\" }}", option)
+	if err != nil {
+		t.Fatalf("NewTemplateOutputNamer() error = %v", err)
+	}
+	if name, err := GenerateFileName(page, namer); err != nil || name != "groessen-ae.md" {
+		t.Fatalf("GenerateFileName() = %q, %v", name, err)
+	}
+}
+
+func TestSluggerNamers(t *testing.T) {
+	keepDots := plugin.SluggerFunc(func(text string, _ *confluenceModel.ConfluencePage) string {
+		return strings.ToLower(strings.ReplaceAll(text, " ", "_"))
+	})
+	option := WithSlugger(keepDots)
+	page := &confluenceModel.ConfluencePage{Title: "Release 1.2"}
+
+	got, err := GenerateRelativePath(page, []string{"Team Docs"}, HierarchyPathNamer(nil, option))
+	if err != nil {
+		t.Fatalf("GenerateRelativePath() error = %v", err)
+	}
+	if want := filepath.Join("team_docs", "release_1.2.md"); got != want {
+		t.Fatalf("GenerateRelativePath() = %q, want %q", got, want)
+	}
+
+	mem := writefs.NewMemFS()
+	doc := &convModel.MarkdownDocument{Content: "# Step 1.1\n\na\n\n# Step 1.2\n\nb"}
+	written, err := SaveSplitMarkdownDocument(mem, doc, "page.md", false, 1, option)
+	if err != nil {
+		t.Fatalf("SaveSplitMarkdownDocument() error = %v", err)
+	}
+	if want := "page-02-step_1.2.md"; len(written) != 3 || written[2] != want {
+		t.Fatalf("SaveSplitMarkdownDocument() = %v, want %s last", written, want)
+	}
+}
+
+func TestParseTransliterationTable(t *testing.T) {
+	table, err := ParseTransliterationTable([]byte(`{"ж": "zh"}`))
+	if err != nil || table["ж"] != "zh" {
+		t.Fatalf("ParseTransliterationTable() = %v, %v", table, err)
+	}
+	if _, err := ParseTransliterationTable([]byte(`{"": "x"}`)); err == nil {
+		t.Fatal("expected an error for an empty key")
+	}
+	if _, err := ParseTransliterationTable([]byte(`[]`)); err == nil {
+		t.Fatal("expected an error for a table that is not an object")
+	}
+}
@@ -0,0 +1,65 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	"github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+)
+
+// WithWorkflowStatus fetches each page's Comala Document Management state into the frontmatter.
+// With banner set, the state and approvers are also shown at the top of the body.
+func WithWorkflowStatus(banner bool) Option {
+	return func(c *Converter) {
+		c.workflowStatus = true
+		c.workflowBanner = banner
+	}
+}
+
+// applyWorkflow records the page's Comala workflow in the document's frontmatter
+func (c *Converter) applyWorkflow(page *confluenceModel.ConfluencePage, doc *model.MarkdownDocument) {
+	doc.Frontmatter.Workflow.Name = c.plugin.WorkflowName()
+	if !c.workflowStatus || c.client == nil {
+		return
+	}
+
+	status, err := c.client.GetWorkflowStatus(page.ID)
+	if errors.Is(err, confluence.ErrNotFound) {
+		// Comala is not installed or the page has no workflow
+		return
+	}
+	if err != nil {
+		c.events.Warning(page, fmt.Sprintf("failed to fetch workflow status: %v", err))
+		return
+	}
+
+	workflow := &doc.Frontmatter.Workflow
+	if status.Workflow != "" {
+		workflow.Name = status.Workflow
+	}
+	workflow.State = status.State
+	workflow.Approvers = status.Approvers
+	workflow.ApprovedAt = status.ApprovedAt
+
+	if c.workflowBanner && workflow.State != "" {
+		doc.Content = workflowBanner(*workflow, c.labels) + doc.Content
+	}
+}
+
+// workflowBanner renders the workflow state as a blockquote, e.g. "> 🚦 **Status:** Approved by Jane on 2024-01-02"
+func workflowBanner(workflow model.WorkflowRef, labels plugin.Labels) string {
+	var builder strings.Builder
+	builder.WriteString("> 🚦 **" + labels.Get(plugin.LabelStatus) + ":** " + workflow.State)
+	if len(workflow.Approvers) > 0 {
+		builder.WriteString(" " + labels.Get(plugin.LabelApprovedBy) + " " + strings.Join(workflow.Approvers, ", "))
+	}
+	if !workflow.ApprovedAt.IsZero() {
+		builder.WriteString(" " + labels.Get(plugin.LabelApprovedOn) + " " + workflow.ApprovedAt.Format("2006-01-02"))
+	}
+	builder.WriteString("\n\n")
+	return builder.String()
+}
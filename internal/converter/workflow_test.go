@@ -0,0 +1,46 @@
+package converter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
+	confModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/converter/plugin"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestApplyWorkflow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mock_confluence.NewMockClient(ctrl)
+	approvedAt := time.Date(2024, 3, 4, 10, 0, 0, 0, time.UTC)
+	client.EXPECT().GetWorkflowStatus("1").Return(&confModel.WorkflowStatus{
+		Workflow:   "Review",
+		State:      "Approved",
+		Approvers:  []string{"Jane", "Raj"},
+		ApprovedAt: approvedAt,
+	}, nil)
+	client.EXPECT().GetWorkflowStatus("2").Return(nil, confluence.ErrNotFound)
+
+	conv := &Converter{client: client, plugin: plugin.NewConfluencePlugin(nil, ""), events: NopEvents{}}
+	WithWorkflowStatus(true)(conv)
+
+	doc := &convModel.MarkdownDocument{Content: "Body"}
+	conv.applyWorkflow(&confModel.ConfluencePage{ID: "1"}, doc)
+	want := convModel.WorkflowRef{Name: "Review", State: "Approved", Approvers: []string{"Jane", "Raj"}, ApprovedAt: approvedAt}
+	if got := doc.Frontmatter.Workflow; got.Name != want.Name || got.State != want.State || strings.Join(got.Approvers, ",") != "Jane,Raj" || !got.ApprovedAt.Equal(approvedAt) {
+		t.Fatalf("Workflow = %#v, want %#v", got, want)
+	}
+	if doc.Content != "> 🚦 **Status:** Approved by Jane, Raj on 2024-03-04\n\nBody" {
+		t.Fatalf("unexpected banner: %q", doc.Content)
+	}
+
+	doc = &convModel.MarkdownDocument{Content: "Body"}
+	conv.applyWorkflow(&confModel.ConfluencePage{ID: "2"}, doc)
+	if !doc.Frontmatter.Workflow.IsZero() || doc.Content != "Body" {
+		t.Fatalf("expected pages without a workflow to be unchanged, got %#v", doc)
+	}
+}
@@ -1,37 +1,99 @@
 package converter
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
-	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/writefs"
 )
 
-// SaveMarkdownDocument writes the markdown document to disk with optional frontmatter.
-func SaveMarkdownDocument(doc *model.MarkdownDocument, outputPath string, withFrontmatter bool) error {
+// volatileFrontmatterKeys change on every export and are ignored, with their nested lines, when comparing against existing files
+var volatileFrontmatterKeys = []string{"exportedAt", "exported_at", "export"}
+
+// SaveMarkdownDocument writes the markdown document to fsys with optional frontmatter.
+// The file is left untouched when its content hash matches the existing file.
+func SaveMarkdownDocument(fsys writefs.FS, doc *model.MarkdownDocument, outputPath string, withFrontmatter bool) error {
 	if doc == nil {
 		return fmt.Errorf("document cannot be nil")
 	}
 
 	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	if err := fsys.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	content := doc.Content
+	content, err := doc.Render(withFrontmatter)
+	if err != nil {
+		return fmt.Errorf("failed to convert document to markdown: %w", err)
+	}
 	if withFrontmatter {
-		rendered, err := doc.WithFrontmatter()
-		if err != nil {
-			return fmt.Errorf("failed to convert document to markdown: %w", err)
-		}
-		content = rendered
-		doc.Content = rendered
+		doc.Content = content
 	}
 
-	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+	if _, err := writeIfChanged(fsys, outputPath, []byte(content)); err != nil {
 		return fmt.Errorf("failed to write markdown file: %w", err)
 	}
 
 	return nil
 }
+
+// ContentHash returns the SHA-256 of a markdown file's content, ignoring volatile frontmatter keys
+func ContentHash(content []byte) string {
+	sum := sha256.Sum256(stripVolatileFrontmatter(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// writeIfChanged writes content unless the existing file hashes the same, reporting whether it wrote
+func writeIfChanged(fsys writefs.FS, path string, content []byte) (bool, error) {
+	if existing, err := fsys.ReadFile(path); err == nil && ContentHash(existing) == ContentHash(content) {
+		return false, nil
+	}
+
+	if err := fsys.WriteFile(path, content, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// stripVolatileFrontmatter removes volatile top-level keys from a leading YAML frontmatter block
+func stripVolatileFrontmatter(content []byte) []byte {
+	if !bytes.HasPrefix(content, []byte("---\n")) {
+		return content
+	}
+	end := bytes.Index(content[4:], []byte("\n---\n"))
+	if end < 0 {
+		return content
+	}
+	end += 4
+
+	var kept []string
+	volatile := false
+	for _, line := range strings.Split(string(content[4:end]), "\n") {
+		if !strings.HasPrefix(line, " ") {
+			volatile = isVolatileFrontmatterLine(line)
+		}
+		if !volatile {
+			kept = append(kept, line)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	buf.WriteString(strings.Join(kept, "\n"))
+	buf.Write(content[end:])
+	return buf.Bytes()
+}
+
+func isVolatileFrontmatterLine(line string) bool {
+	for _, key := range volatileFrontmatterKeys {
+		if strings.HasPrefix(line, key+":") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package converter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+	"github.com/jackchuka/confluence-md/internal/writefs"
+)
+
+func TestContentHashIgnoresVolatileFrontmatter(t *testing.T) {
+	first := ContentHash([]byte("---\ntitle: \"A\"\nexportedAt: \"2024-01-01T00:00:00Z\"\n---\n\nbody"))
+	second := ContentHash([]byte("---\ntitle: \"A\"\nexportedAt: \"2024-02-01T00:00:00Z\"\n---\n\nbody"))
+	if first != second {
+		t.Fatalf("expected volatile keys to be ignored")
+	}
+
+	withRun := ContentHash([]byte("---\ntitle: \"A\"\nexport:\n  runId: \"one\"\n  exportedAt: \"2024-01-01T00:00:00Z\"\n---\n\nbody"))
+	if withRun != first {
+		t.Fatalf("expected the export provenance block to be ignored")
+	}
+
+	if ContentHash([]byte("---\ntitle: \"B\"\n---\n\nbody")) == first {
+		t.Fatalf("expected title change to alter the hash")
+	}
+}
+
+func TestSaveMarkdownDocumentSkipsUnchangedFile(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "page.md")
+	doc := &convModel.MarkdownDocument{Content: "# Title\n\nbody"}
+	if err := SaveMarkdownDocument(writefs.OS, doc, outputPath, false); err != nil {
+		t.Fatalf("SaveMarkdownDocument() error = %v", err)
+	}
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(outputPath, past, past); err != nil {
+		t.Fatalf("failed to set mtime: %v", err)
+	}
+
+	if err := SaveMarkdownDocument(writefs.OS, &convModel.MarkdownDocument{Content: "# Title\n\nbody"}, outputPath, false); err != nil {
+		t.Fatalf("SaveMarkdownDocument() error = %v", err)
+	}
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		t.Fatalf("failed to stat output: %v", err)
+	}
+	if !info.ModTime().Equal(past) {
+		t.Fatalf("expected unchanged file not to be rewritten")
+	}
+
+	if err := SaveMarkdownDocument(writefs.OS, &convModel.MarkdownDocument{Content: "# Title\n\nchanged"}, outputPath, false); err != nil {
+		t.Fatalf("SaveMarkdownDocument() error = %v", err)
+	}
+	if data, _ := os.ReadFile(outputPath); string(data) != "# Title\n\nchanged" {
+		t.Fatalf("expected changed content to be written, got %q", string(data))
+	}
+}
@@ -0,0 +1,165 @@
+// Package metrics implements counters and histograms exposed in the Prometheus text format.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBuckets are histogram upper bounds in seconds suited to page conversions and API calls
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// metric writes itself in the Prometheus text exposition format
+type metric interface {
+	write(w *bufio.Writer, name string)
+}
+
+// Registry holds named metrics and renders them for scraping
+type Registry struct {
+	mu      sync.Mutex
+	names   []string
+	help    map[string]string
+	kinds   map[string]string
+	metrics map[string]metric
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		help:    make(map[string]string),
+		kinds:   make(map[string]string),
+		metrics: make(map[string]metric),
+	}
+}
+
+// Counter registers a monotonically increasing counter
+func (r *Registry) Counter(name, help string) *Counter {
+	counter := &Counter{}
+	r.register(name, help, "counter", counter)
+	return counter
+}
+
+// Histogram registers a histogram with the given bucket upper bounds
+func (r *Registry) Histogram(name, help string, buckets []float64) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	histogram := &Histogram{buckets: sorted, counts: make([]uint64, len(sorted))}
+	r.register(name, help, "histogram", histogram)
+	return histogram
+}
+
+func (r *Registry) register(name, help, kind string, m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.metrics[name]; exists {
+		panic(fmt.Sprintf("metrics: %s registered twice", name))
+	}
+	r.names = append(r.names, name)
+	r.help[name] = help
+	r.kinds[name] = kind
+	r.metrics[name] = m
+}
+
+// WriteTo writes every metric in registration order
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counting := &countingWriter{w: w}
+	buf := bufio.NewWriter(counting)
+	for _, name := range r.names {
+		fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, r.help[name], name, r.kinds[name])
+		r.metrics[name].write(buf, name)
+	}
+	err := buf.Flush()
+	return counting.n, err
+}
+
+// Handler serves the registry for Prometheus scrapes
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_, _ = r.WriteTo(w)
+	})
+}
+
+// Counter counts events
+type Counter struct {
+	value atomic.Uint64
+}
+
+// Inc adds one to the counter
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Value returns the current count
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
+
+func (c *Counter) write(w *bufio.Writer, name string) {
+	fmt.Fprintf(w, "%s %d\n", name, c.Value())
+}
+
+// Histogram counts observations into cumulative buckets
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64 // observations per bucket, not cumulative
+	count   uint64
+	sum     float64
+}
+
+// Observe records one value
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.count++
+	h.sum += value
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		h.counts[i]++
+	}
+}
+
+func (h *Histogram) write(w *bufio.Writer, name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var cumulative uint64
+	for i, bound := range h.buckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(w, "%s_bucket{le=\"%s\"} %d\n", name, formatFloat(bound), cumulative)
+	}
+	fmt.Fprintf(w, "%s_bucket{le=\"+Inf\"} %d\n", name, h.count)
+	fmt.Fprintf(w, "%s_sum %s\n", name, formatFloat(h.sum))
+	fmt.Fprintf(w, "%s_count %d\n", name, h.count)
+}
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// countingWriter tracks the bytes written for WriteTo
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRegistryExposition(t *testing.T) {
+	registry := NewRegistry()
+	pages := registry.Counter("pages_total", "Pages converted.")
+	latency := registry.Histogram("latency_seconds", "Conversion latency.", []float64{1, 0.1})
+
+	pages.Inc()
+	pages.Inc()
+	latency.Observe(0.05)
+	latency.Observe(0.5)
+	latency.Observe(3)
+
+	recorder := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	want := `# HELP pages_total Pages converted.
+# TYPE pages_total counter
+pages_total 2
+# HELP latency_seconds Conversion latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{le="0.1"} 1
+latency_seconds_bucket{le="1"} 2
+latency_seconds_bucket{le="+Inf"} 3
+latency_seconds_sum 3.55
+latency_seconds_count 3
+`
+	if got := recorder.Body.String(); got != want {
+		t.Fatalf("unexpected exposition:\n%s\nwant:\n%s", got, want)
+	}
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain") {
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+}
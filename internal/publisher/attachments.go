@@ -0,0 +1,93 @@
+package publisher
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// ImageAction describes what a push did with a local image
+type ImageAction string
+
+const (
+	ImageCreated   ImageAction = "created"
+	ImageUpdated   ImageAction = "updated"
+	ImageUnchanged ImageAction = "unchanged"
+)
+
+// ImageUpload records the outcome of uploading one local image
+type ImageUpload struct {
+	Path     string // as referenced in the Markdown
+	FileName string // attachment name on the page
+	Action   ImageAction
+	Error    error
+}
+
+// UploadImages attaches the local images referenced by the Markdown to the page.
+// Paths are resolved against baseDir and may not leave it. New files are attached, changed files are uploaded
+// as a new version of the existing attachment, and identical files are left alone.
+func UploadImages(client confluence.Client, page *confluenceModel.ConfluencePage, baseDir, markdown string) []ImageUpload {
+	existing := attachmentsByName(page)
+
+	var uploads []ImageUpload
+	claimed := make(map[string]string)
+	for _, image := range LocalImages(markdown) {
+		upload := ImageUpload{Path: image, FileName: AttachmentName(image)}
+
+		if !filepath.IsLocal(filepath.FromSlash(image)) {
+			// Absolute paths or .. would let a Markdown file attach any file the user can read
+			upload.Error = fmt.Errorf("image path %s leaves the file's directory", image)
+		} else if other, ok := claimed[upload.FileName]; ok {
+			// Storage format references attachments by file name, so two local files cannot share one
+			upload.Error = fmt.Errorf("attachment name %s is already used by %s", upload.FileName, other)
+		} else {
+			claimed[upload.FileName] = image
+			upload.Action, upload.Error = uploadImage(client, page.ID, existing[upload.FileName], filepath.Join(baseDir, filepath.FromSlash(image)), upload.FileName)
+		}
+
+		uploads = append(uploads, upload)
+	}
+	return uploads
+}
+
+// attachmentsByName indexes the page's attachments by file name
+func attachmentsByName(page *confluenceModel.ConfluencePage) map[string]*confluenceModel.ConfluenceAttachment {
+	existing := make(map[string]*confluenceModel.ConfluenceAttachment, len(page.Attachments))
+	for i := range page.Attachments {
+		existing[page.Attachments[i].Title] = &page.Attachments[i]
+	}
+	return existing
+}
+
+func uploadImage(client confluence.Client, pageID string, current *confluenceModel.ConfluenceAttachment, path, fileName string) (ImageAction, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+	return uploadData(client, pageID, current, fileName, data)
+}
+
+// uploadData attaches data as a new attachment, or as a new version of current when its content differs
+func uploadData(client confluence.Client, pageID string, current *confluenceModel.ConfluenceAttachment, fileName string, data []byte) (ImageAction, error) {
+	if current == nil {
+		if _, err := client.UploadAttachment(pageID, fileName, data); err != nil {
+			return "", err
+		}
+		return ImageCreated, nil
+	}
+
+	if current.FileSize == int64(len(data)) {
+		if remote, err := client.DownloadAttachmentContent(current); err == nil && bytes.Equal(remote, data) {
+			return ImageUnchanged, nil
+		}
+	}
+
+	if _, err := client.UpdateAttachment(pageID, current.ID, fileName, data); err != nil {
+		return "", err
+	}
+	return ImageUpdated, nil
+}
@@ -0,0 +1,56 @@
+package publisher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	mock_confluence "github.com/jackchuka/confluence-md/internal/confluence/mock"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+func TestUploadImages(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"assets/new.png":     "new",
+		"assets/same.png":    "same",
+		"assets/changed.png": "changed",
+		"other/new.png":      "clash",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page := &confluenceModel.ConfluencePage{ID: "123", Attachments: []confluenceModel.ConfluenceAttachment{
+		{ID: "att-same", Title: "same.png", FileSize: 4},
+		{ID: "att-changed", Title: "changed.png", FileSize: 3},
+	}}
+
+	ctrl := gomock.NewController(t)
+	mockClient := mock_confluence.NewMockClient(ctrl)
+	mockClient.EXPECT().UploadAttachment("123", "new.png", []byte("new")).Return(&confluenceModel.ConfluenceAttachment{}, nil)
+	mockClient.EXPECT().DownloadAttachmentContent(&page.Attachments[0]).Return([]byte("same"), nil)
+	mockClient.EXPECT().UpdateAttachment("123", "att-changed", "changed.png", []byte("changed")).Return(&confluenceModel.ConfluenceAttachment{}, nil)
+
+	markdown := "![a](assets/new.png) ![b](assets/same.png) ![c](assets/changed.png) ![d](other/new.png) ![e](assets/missing.png) ![f](../secret.png) ![g](/etc/passwd)"
+	uploads := UploadImages(mockClient, page, dir, markdown)
+
+	want := []ImageAction{ImageCreated, ImageUnchanged, ImageUpdated, "", "", "", ""}
+	if len(uploads) != len(want) {
+		t.Fatalf("expected %d uploads, got %d", len(want), len(uploads))
+	}
+	for i, upload := range uploads {
+		if upload.Action != want[i] {
+			t.Fatalf("upload %s: expected action %q, got %q (%v)", upload.Path, want[i], upload.Action, upload.Error)
+		}
+		if (upload.Action == "") != (upload.Error != nil) {
+			t.Fatalf("upload %s: unexpected error state: %v", upload.Path, upload.Error)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package publisher
+
+import (
+	"errors"
+	"fmt"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+// ErrConflict is returned when the remote page changed since the Markdown file was exported
+var ErrConflict = errors.New("page changed remotely since export")
+
+// CheckVersion compares the version recorded in the frontmatter against the remote page.
+// Files without a recorded version cannot be verified and are treated as conflicting.
+func CheckVersion(local convModel.ConfluenceRef, remote *confluenceModel.ConfluencePage) error {
+	if local.Version <= 0 {
+		return fmt.Errorf("%w: no version recorded in frontmatter for page %s", ErrConflict, remote.ID)
+	}
+	if remote.Version != local.Version {
+		return fmt.Errorf("%w: page %s is at version %d, file was exported from version %d",
+			ErrConflict, remote.ID, remote.Version, local.Version)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package publisher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+	convModel "github.com/jackchuka/confluence-md/internal/converter/model"
+)
+
+func TestCheckVersion(t *testing.T) {
+	remote := &confluenceModel.ConfluencePage{ID: "123", Version: 4}
+
+	if err := CheckVersion(convModel.ConfluenceRef{PageID: "123", Version: 4}, remote); err != nil {
+		t.Fatalf("expected matching versions to pass, got %v", err)
+	}
+
+	err := CheckVersion(convModel.ConfluenceRef{PageID: "123", Version: 3}, remote)
+	if !errors.Is(err, ErrConflict) || !strings.Contains(err.Error(), "version 4") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+
+	if err := CheckVersion(convModel.ConfluenceRef{PageID: "123"}, remote); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected missing version to conflict, got %v", err)
+	}
+}
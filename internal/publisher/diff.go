@@ -0,0 +1,156 @@
+package publisher
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const diffContext = 3
+
+// blockEndPattern matches the end of block-level storage elements, where DiffStorage breaks lines
+var blockEndPattern = regexp.MustCompile(`(</(?:p|h[1-6]|li|tr|thead|tbody|table|ul|ol|blockquote|pre|ac:structured-macro|ac:task)>|<hr\s*/>|<br\s*/>)`)
+
+// DiffStorage returns a unified diff between two storage format documents, one block element per line.
+// An empty string means the documents are identical.
+func DiffStorage(remote, local, remoteName, localName string) string {
+	return Diff(splitStorageBlocks(remote), splitStorageBlocks(local), remoteName, localName)
+}
+
+// splitStorageBlocks puts every block element of a storage document on its own line
+func splitStorageBlocks(storage string) []string {
+	if storage == "" {
+		return nil
+	}
+	split := blockEndPattern.ReplaceAllString(storage, "$1\n")
+
+	var lines []string
+	for _, line := range strings.Split(split, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// diffOp is one line of an edit script: ' ' kept, '-' removed, '+' added
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// Diff returns a unified diff turning a into b, or an empty string when they are equal
+func Diff(a, b []string, aName, bName string) string {
+	ops := editScript(a, b)
+
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", aName, bName))
+
+	for start := 0; start < len(ops); {
+		// Find the next change and grow the hunk until changes are more than 2*context lines apart
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		hunkStart := max(first-diffContext, start)
+
+		end := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+		hunkEnd := min(end+diffContext, len(ops))
+
+		writeHunk(&builder, ops, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+	return builder.String()
+}
+
+// writeHunk writes ops[from:to] with a header giving the line ranges in both inputs
+func writeHunk(builder *strings.Builder, ops []diffOp, from, to int) {
+	aLine, bLine := 1, 1
+	for _, op := range ops[:from] {
+		if op.kind != '+' {
+			aLine++
+		}
+		if op.kind != '-' {
+			bLine++
+		}
+	}
+
+	aCount, bCount := 0, 0
+	for _, op := range ops[from:to] {
+		if op.kind != '+' {
+			aCount++
+		}
+		if op.kind != '-' {
+			bCount++
+		}
+	}
+
+	builder.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", aLine, aCount, bLine, bCount))
+	for _, op := range ops[from:to] {
+		builder.WriteByte(op.kind)
+		builder.WriteString(op.line)
+		builder.WriteByte('\n')
+	}
+}
+
+// editScript computes a minimal line edit script from the longest common subsequence of a and b
+func editScript(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
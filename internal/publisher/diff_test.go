@@ -0,0 +1,42 @@
+package publisher
+
+import "testing"
+
+func TestDiffStorage(t *testing.T) {
+	remote := "<h1>Title</h1><p>one</p><p>two</p><p>three</p>"
+	local := "<h1>Title</h1><p>one</p><p>2</p><p>three</p><p>four</p>"
+
+	want := "--- remote\n+++ local\n" +
+		"@@ -1,4 +1,5 @@\n" +
+		" <h1>Title</h1>\n" +
+		" <p>one</p>\n" +
+		"-<p>two</p>\n" +
+		"+<p>2</p>\n" +
+		" <p>three</p>\n" +
+		"+<p>four</p>\n"
+	if got := DiffStorage(remote, local, "remote", "local"); got != want {
+		t.Fatalf("DiffStorage() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := DiffStorage(remote, remote, "remote", "local"); got != "" {
+		t.Fatalf("expected no diff for identical documents, got %q", got)
+	}
+}
+
+func TestDiffSplitsDistantHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		line := string(rune('a' + i))
+		a = append(a, line)
+		b = append(b, line)
+	}
+	b[1] = "B"
+	b[18] = "S"
+
+	want := "--- a\n+++ b\n" +
+		"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n" +
+		"@@ -16,5 +16,5 @@\n p\n q\n r\n-s\n+S\n t\n"
+	if got := Diff(a, b, "a", "b"); got != want {
+		t.Fatalf("Diff() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package publisher
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// htmlBlockPattern matches a line opening or closing a block of raw HTML, e.g. <details> or </table>
+	htmlBlockPattern = regexp.MustCompile(`^</?[a-zA-Z][a-zA-Z0-9-]*(\s[^>]*)?/?>`)
+	// inlineTagPattern matches a raw HTML tag within text, e.g. <del> or <br />
+	inlineTagPattern = regexp.MustCompile(`^</?[a-zA-Z][a-zA-Z0-9-]*(\s[^<>]*)?/?>`)
+)
+
+// ToHTML converts Markdown into standard HTML for previewing an export: code blocks become <pre><code>, images
+// keep their relative paths, raw HTML is passed through and relative links to .md files point at the .html files
+// rendered beside them.
+func ToHTML(markdown string) string {
+	r := &storageRenderer{plainHTML: true}
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	r.renderBlocks(lines)
+	return r.builder.String()
+}
+
+// preBlock renders a code block with the language as a class, as syntax highlighters expect
+func preBlock(language, code string) string {
+	class := ""
+	if language != "" {
+		class = ` class="language-` + html.EscapeString(language) + `"`
+	}
+	return "<pre><code" + class + ">" + html.EscapeString(code) + "</code></pre>"
+}
+
+// renderHTMLBlock copies raw HTML lines up to the next blank line and returns the index after them
+func (r *storageRenderer) renderHTMLBlock(lines []string, start int) int {
+	i := start
+	for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+		r.builder.WriteString(lines[i] + "\n")
+	}
+	return i
+}
+
+// linkTarget points relative links to exported Markdown files at their HTML previews
+func (r *storageRenderer) linkTarget(target string) string {
+	if !r.plainHTML || isRemoteURL(target) || strings.Contains(target, ":") {
+		return target
+	}
+	path, fragment, hasFragment := strings.Cut(target, "#")
+	if !strings.HasSuffix(strings.ToLower(path), ".md") {
+		return target
+	}
+	path = path[:len(path)-len(".md")] + ".html"
+	if hasFragment {
+		path += "#" + fragment
+	}
+	return path
+}
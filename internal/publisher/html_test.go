@@ -0,0 +1,40 @@
+package publisher
+
+import "testing"
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "links to exported pages point at their previews",
+			markdown: "[child](child/page.md#setup) [site](https://example.com/a.md) [file](notes.txt)",
+			want:     `<p><a href="child/page.html#setup">child</a> <a href="https://example.com/a.md">site</a> <a href="notes.txt">file</a></p>`,
+		},
+		{
+			name:     "images keep relative paths",
+			markdown: "![logo](assets/logo.png)",
+			want:     `<p><img src="assets/logo.png" alt="logo" /></p>`,
+		},
+		{
+			name:     "code block",
+			markdown: "```go\nif a < b {}\n```",
+			want:     `<pre><code class="language-go">if a &lt; b {}</code></pre>`,
+		},
+		{
+			name:     "raw html",
+			markdown: "<details>\n<summary>More</summary>\n\nHidden <del>old</del> text\n\n</details>",
+			want:     "<details>\n<summary>More</summary>\n<p>Hidden <del>old</del> text</p></details>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToHTML(tt.markdown); got != tt.want {
+				t.Fatalf("ToHTML() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
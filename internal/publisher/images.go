@@ -0,0 +1,62 @@
+package publisher
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var codeSpanPattern = regexp.MustCompile("`+[^`]*`+")
+
+// LocalImages returns the distinct local image paths referenced by the Markdown in document order.
+// Remote images and images inside code blocks or code spans are ignored.
+func LocalImages(markdown string) []string {
+	var images []string
+	seen := make(map[string]bool)
+	inFence := ""
+
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inFence != "" {
+			if strings.HasPrefix(trimmed, inFence) {
+				inFence = ""
+			}
+			continue
+		}
+		if isFence(trimmed) {
+			inFence = trimmed[:3]
+			continue
+		}
+
+		line = codeSpanPattern.ReplaceAllString(line, "")
+		for i := strings.Index(line, "!["); i >= 0; i = strings.Index(line, "![") {
+			_, target, n, ok := parseLink(line[i+1:])
+			if !ok {
+				line = line[i+2:]
+				continue
+			}
+			line = line[i+1+n:]
+
+			if target == "" || isRemoteURL(target) || strings.HasPrefix(target, "data:") {
+				continue
+			}
+			if unescaped, err := url.PathUnescape(target); err == nil {
+				target = unescaped
+			}
+			if !seen[target] {
+				seen[target] = true
+				images = append(images, target)
+			}
+		}
+	}
+	return images
+}
+
+// AttachmentName returns the attachment file name a local image reference is stored under
+func AttachmentName(target string) string {
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	return path.Base(target)
+}
@@ -0,0 +1,21 @@
+package publisher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocalImages(t *testing.T) {
+	markdown := "![a](assets/a.png) ![remote](https://example.com/b.png)\n" +
+		"`![code](assets/code.png)` ![again](assets/a.png) ![space](assets/my%20image.png)\n" +
+		"```\n![fenced](assets/fenced.png)\n```\n"
+
+	got := LocalImages(markdown)
+	want := []string{"assets/a.png", "assets/my image.png"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("LocalImages() = %v, want %v", got, want)
+	}
+	if name := AttachmentName("assets/my%20image.png"); name != "my image.png" {
+		t.Fatalf("AttachmentName() = %q", name)
+	}
+}
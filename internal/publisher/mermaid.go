@@ -0,0 +1,97 @@
+package publisher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackchuka/confluence-md/internal/confluence"
+	confluenceModel "github.com/jackchuka/confluence-md/internal/confluence/model"
+)
+
+// MermaidDiagrams returns the distinct mermaid code block sources in the Markdown in document order
+func MermaidDiagrams(markdown string) []string {
+	var diagrams []string
+	seen := make(map[string]bool)
+
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		opening := strings.TrimSpace(lines[i])
+		if !isFence(opening) {
+			continue
+		}
+		fence := opening[:3]
+		language := strings.TrimSpace(strings.TrimLeft(opening, fence[:1]))
+
+		var body []string
+		for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+			body = append(body, lines[i])
+		}
+
+		diagram := strings.Join(body, "\n")
+		if language == "mermaid" && !seen[diagram] {
+			seen[diagram] = true
+			diagrams = append(diagrams, diagram)
+		}
+	}
+	return diagrams
+}
+
+// MermaidFileName names the rendered image after the diagram's content,
+// so an unchanged diagram keeps its attachment across pushes
+func MermaidFileName(diagram string) string {
+	sum := sha256.Sum256([]byte(diagram))
+	return "mermaid-" + hex.EncodeToString(sum[:])[:12] + ".png"
+}
+
+// RenderMermaid renders a diagram to PNG with a mermaid-cli compatible command (called as <renderer> -i <in> -o <out>)
+func RenderMermaid(renderer, diagram string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "confluence-md-mermaid")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	input := filepath.Join(dir, "diagram.mmd")
+	output := filepath.Join(dir, "diagram.png")
+	if err := os.WriteFile(input, []byte(diagram), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write diagram: %w", err)
+	}
+
+	if out, err := exec.Command(renderer, "-i", input, "-o", output).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to render mermaid diagram with %s: %w: %s", renderer, err, strings.TrimSpace(string(out)))
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rendered diagram: %w", err)
+	}
+	return data, nil
+}
+
+// UploadMermaidImages renders each mermaid diagram in the Markdown and attaches it to the page
+// under MermaidFileName, for use with WithMermaidImages
+func UploadMermaidImages(client confluence.Client, page *confluenceModel.ConfluencePage, renderer, markdown string) []ImageUpload {
+	existing := attachmentsByName(page)
+
+	var uploads []ImageUpload
+	for _, diagram := range MermaidDiagrams(markdown) {
+		upload := ImageUpload{Path: "mermaid diagram", FileName: MermaidFileName(diagram)}
+		if existing[upload.FileName] != nil {
+			// The name is derived from the diagram source, so an existing attachment is already current
+			upload.Action = ImageUnchanged
+		} else if data, err := RenderMermaid(renderer, diagram); err != nil {
+			upload.Error = err
+		} else {
+			upload.Action, upload.Error = uploadData(client, page.ID, nil, upload.FileName, data)
+		}
+		uploads = append(uploads, upload)
+	}
+	return uploads
+}
@@ -0,0 +1,38 @@
+package publisher
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMermaidBlocks(t *testing.T) {
+	markdown := "Intro\n\n```mermaid\ngraph TD;\nA-->B;\n```\n\n```go\nx := 1\n```\n\n```mermaid\ngraph TD;\nA-->B;\n```"
+
+	diagrams := MermaidDiagrams(markdown)
+	if len(diagrams) != 1 || diagrams[0] != "graph TD;\nA-->B;" {
+		t.Fatalf("unexpected diagrams: %q", diagrams)
+	}
+
+	macro := ToStorage(markdown)
+	if !strings.Contains(macro, `<ac:structured-macro ac:name="mermaid-macro"><ac:plain-text-body><![CDATA[graph TD;`+"\n"+`A-->B;]]></ac:plain-text-body></ac:structured-macro>`) {
+		t.Fatalf("expected mermaid macro, got %s", macro)
+	}
+	if !strings.Contains(macro, `<ac:parameter ac:name="language">go</ac:parameter>`) {
+		t.Fatalf("expected other code blocks to stay code macros, got %s", macro)
+	}
+
+	name := MermaidFileName(diagrams[0])
+	if !strings.HasPrefix(name, "mermaid-") || !strings.HasSuffix(name, ".png") || name != MermaidFileName("graph TD;\nA-->B;") {
+		t.Fatalf("unexpected mermaid file name: %s", name)
+	}
+
+	images := ToStorage(markdown, WithMermaidImages())
+	if strings.Count(images, `<ri:attachment ri:filename="`+name+`" />`) != 2 || strings.Contains(images, "mermaid-macro") {
+		t.Fatalf("expected mermaid images, got %s", images)
+	}
+
+	failed := ToStorage(markdown, WithMermaidImages(), WithoutMermaidImages(name))
+	if strings.Contains(failed, name) || !strings.Contains(failed, `<ac:parameter ac:name="language">mermaid</ac:parameter>`) {
+		t.Fatalf("expected diagrams without images to stay code blocks, got %s", failed)
+	}
+}
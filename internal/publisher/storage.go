@@ -0,0 +1,515 @@
+package publisher
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	tableSepPattern = regexp.MustCompile(`^\s*\|?\s*:?-{3,}:?\s*(\|\s*:?-{3,}:?\s*)*\|?\s*$`)
+)
+
+// Option configures how Markdown is converted to storage format
+type Option func(*storageRenderer)
+
+// WithMermaidImages renders mermaid code blocks as attached images named by MermaidFileName
+// instead of mermaid-macro, for sites without the Mermaid app installed
+func WithMermaidImages() Option {
+	return func(r *storageRenderer) {
+		r.mermaidImages = true
+	}
+}
+
+// WithoutMermaidImages keeps the diagrams whose MermaidFileName is listed as code blocks under
+// WithMermaidImages, e.g. because their images failed to render
+func WithoutMermaidImages(fileNames ...string) Option {
+	return func(r *storageRenderer) {
+		if r.missingImages == nil {
+			r.missingImages = make(map[string]bool)
+		}
+		for _, name := range fileNames {
+			r.missingImages[name] = true
+		}
+	}
+}
+
+// storageRenderer accumulates the storage format output for one document
+type storageRenderer struct {
+	builder       strings.Builder
+	mermaidImages bool
+	missingImages map[string]bool // MermaidFileNames without an attachment
+	plainHTML     bool            // standard HTML for previews instead of storage format, see ToHTML
+}
+
+// ToStorage converts Markdown into Confluence storage format (XHTML with ac: macros)
+func ToStorage(markdown string, opts ...Option) string {
+	r := &storageRenderer{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	r.renderBlocks(lines)
+	return r.builder.String()
+}
+
+// renderBlocks renders block-level Markdown: headings, code fences, lists, tables, quotes, rules and paragraphs
+func (r *storageRenderer) renderBlocks(lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case isHTMLComment(trimmed):
+			i++
+
+		case isFence(trimmed):
+			i = r.renderCodeBlock(lines, i)
+
+		case r.plainHTML && htmlBlockPattern.MatchString(trimmed):
+			i = r.renderHTMLBlock(lines, i)
+
+		case headingPattern.MatchString(trimmed):
+			match := headingPattern.FindStringSubmatch(trimmed)
+			level := strconv.Itoa(len(match[1]))
+			r.builder.WriteString("<h" + level + ">" + r.renderInline(match[2]) + "</h" + level + ">")
+			i++
+
+		case isHorizontalRule(trimmed):
+			r.builder.WriteString("<hr />")
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				content := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(content, " "))
+			}
+			r.builder.WriteString("<blockquote>")
+			r.renderBlocks(quoted)
+			r.builder.WriteString("</blockquote>")
+
+		case listItemPattern.MatchString(line):
+			i = r.renderList(lines, i)
+
+		case strings.Contains(trimmed, "|") && i+1 < len(lines) && tableSepPattern.MatchString(lines[i+1]):
+			i = r.renderTable(lines, i)
+
+		default:
+			i = r.renderParagraph(lines, i)
+		}
+	}
+}
+
+func isFence(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+func isHTMLComment(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "<!--") && strings.HasSuffix(trimmed, "-->")
+}
+
+func isHorizontalRule(trimmed string) bool {
+	compact := strings.ReplaceAll(trimmed, " ", "")
+	if len(compact) < 3 {
+		return false
+	}
+	for _, marker := range []string{"-", "*", "_"} {
+		if strings.Trim(compact, marker) == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// startsBlock reports whether the line interrupts a paragraph
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || isFence(trimmed) || headingPattern.MatchString(trimmed) ||
+		isHorizontalRule(trimmed) || strings.HasPrefix(trimmed, ">") || listItemPattern.MatchString(line)
+}
+
+// renderCodeBlock renders a fenced code block as a code macro and returns the index after the closing fence
+func (r *storageRenderer) renderCodeBlock(lines []string, start int) int {
+	opening := strings.TrimSpace(lines[start])
+	fence := opening[:3]
+	language := strings.TrimSpace(strings.TrimLeft(opening, fence[:1]))
+
+	var body []string
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		body = append(body, lines[i])
+	}
+
+	code := strings.Join(body, "\n")
+	switch {
+	case r.plainHTML:
+		r.builder.WriteString(preBlock(language, code))
+	case language == "mermaid" && r.mermaidImages && r.missingImages[MermaidFileName(code)]:
+		r.builder.WriteString(codeMacro(language, code))
+	case language == "mermaid" && r.mermaidImages:
+		r.builder.WriteString(`<ac:image><ri:attachment ri:filename="` + MermaidFileName(code) + `" /></ac:image>`)
+	case language == "mermaid":
+		r.builder.WriteString(mermaidMacro(code))
+	default:
+		r.builder.WriteString(codeMacro(language, code))
+	}
+	return i
+}
+
+// codeMacro renders a code macro with the body wrapped in CDATA
+func codeMacro(language, code string) string {
+	var builder strings.Builder
+	builder.WriteString(`<ac:structured-macro ac:name="code">`)
+	if language != "" {
+		builder.WriteString(`<ac:parameter ac:name="language">` + html.EscapeString(language) + `</ac:parameter>`)
+	}
+	builder.WriteString(`<ac:plain-text-body>` + cdata(code) + `</ac:plain-text-body>`)
+	builder.WriteString(`</ac:structured-macro>`)
+	return builder.String()
+}
+
+// mermaidMacro renders a diagram as the Mermaid app's macro, the form the exporter reads back
+func mermaidMacro(diagram string) string {
+	return `<ac:structured-macro ac:name="mermaid-macro"><ac:plain-text-body>` + cdata(diagram) + `</ac:plain-text-body></ac:structured-macro>`
+}
+
+// cdata wraps text in a CDATA section, splitting any "]]>" it contains
+func cdata(text string) string {
+	return "<![CDATA[" + strings.ReplaceAll(text, "]]>", "]]]]><![CDATA[>") + "]]>"
+}
+
+// renderParagraph joins lines up to the next blank line or block start into a paragraph
+func (r *storageRenderer) renderParagraph(lines []string, start int) int {
+	var parts []string
+	i := start
+	for ; i < len(lines); i++ {
+		if i > start && startsBlock(lines[i]) {
+			break
+		}
+		line := lines[i]
+		hardBreak := strings.HasSuffix(line, "  ") || strings.HasSuffix(line, "\\")
+		text := r.renderInline(strings.TrimSuffix(strings.TrimSpace(line), "\\"))
+		if hardBreak && i+1 < len(lines) && !startsBlock(lines[i+1]) {
+			text += "<br />"
+		}
+		parts = append(parts, text)
+	}
+
+	r.builder.WriteString("<p>" + joinParagraphLines(parts) + "</p>")
+	return i
+}
+
+func joinParagraphLines(parts []string) string {
+	var builder strings.Builder
+	for i, part := range parts {
+		if i > 0 && !strings.HasSuffix(parts[i-1], "<br />") {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(part)
+	}
+	return builder.String()
+}
+
+// renderList renders a (possibly nested) list starting at lines[start] and returns the index after it
+func (r *storageRenderer) renderList(lines []string, start int) int {
+	first := listItemPattern.FindStringSubmatch(lines[start])
+	indent := len(first[1])
+	ordered := first[2][0] >= '0' && first[2][0] <= '9'
+
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	r.builder.WriteString("<" + tag + ">")
+
+	i := start
+	for i < len(lines) {
+		match := listItemPattern.FindStringSubmatch(lines[i])
+		if match == nil || len(match[1]) != indent {
+			break
+		}
+
+		// Collect the item body: the text after the marker plus deeper-indented continuation lines
+		body := []string{match[3]}
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) > indent {
+					body = append(body, "")
+					i++
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) <= indent {
+				break
+			}
+			body = append(body, dedent(line, indent+2))
+			i++
+		}
+
+		r.builder.WriteString("<li>")
+		if len(body) == 1 {
+			r.builder.WriteString(r.renderInline(body[0]))
+		} else {
+			r.renderListItemBody(body)
+		}
+		r.builder.WriteString("</li>")
+
+		// A blank line between items of the same list does not end it
+		if i+1 < len(lines) && strings.TrimSpace(lines[i]) == "" {
+			if next := listItemPattern.FindStringSubmatch(lines[i+1]); next != nil && len(next[1]) == indent {
+				i++
+			}
+		}
+	}
+
+	r.builder.WriteString("</" + tag + ">")
+	return i
+}
+
+// renderListItemBody keeps the first line of a multi-line item inline and renders nested blocks after it
+func (r *storageRenderer) renderListItemBody(body []string) {
+	i := 1
+	for ; i < len(body) && !startsBlock(body[i]); i++ {
+	}
+	r.builder.WriteString(r.renderInline(strings.Join(body[:i], " ")))
+	r.renderBlocks(body[i:])
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func dedent(line string, width int) string {
+	if leadingSpaces(line) < width {
+		return strings.TrimLeft(line, " \t")
+	}
+	return line[width:]
+}
+
+// renderTable renders a GFM pipe table, using the first row as header cells
+func (r *storageRenderer) renderTable(lines []string, start int) int {
+	r.builder.WriteString("<table><tbody>")
+	r.writeTableRow(lines[start], "th")
+
+	i := start + 2
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || !strings.Contains(trimmed, "|") {
+			break
+		}
+		r.writeTableRow(lines[i], "td")
+	}
+
+	r.builder.WriteString("</tbody></table>")
+	return i
+}
+
+func (r *storageRenderer) writeTableRow(line, cellTag string) {
+	r.builder.WriteString("<tr>")
+	for _, cell := range splitTableRow(line) {
+		r.builder.WriteString("<" + cellTag + ">" + r.renderInline(cell) + "</" + cellTag + ">")
+	}
+	r.builder.WriteString("</tr>")
+}
+
+// splitTableRow splits a pipe table row into trimmed cells, honouring escaped pipes
+func splitTableRow(line string) []string {
+	trimmed := strings.TrimSpace(line)
+	trimmed = strings.TrimPrefix(trimmed, "|")
+	if strings.HasSuffix(trimmed, "|") && !strings.HasSuffix(trimmed, `\|`) {
+		trimmed = trimmed[:len(trimmed)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case trimmed[i] == '\\' && i+1 < len(trimmed) && trimmed[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case trimmed[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(trimmed[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// renderInline renders inline Markdown: code spans, images, links, emphasis and escapes
+func (r *storageRenderer) renderInline(text string) string {
+	var builder strings.Builder
+	for i := 0; i < len(text); {
+		rest := text[i:]
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_{}[]()#+-.!|~<>", rune(rest[1])):
+			builder.WriteString(html.EscapeString(rest[1:2]))
+			i += 2
+			continue
+
+		case r.plainHTML && rest[0] == '<':
+			if tag := inlineTagPattern.FindString(rest); tag != "" {
+				builder.WriteString(tag)
+				i += len(tag)
+				continue
+			}
+
+		case rest[0] == '`':
+			ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+			if end := strings.Index(rest[ticks:], rest[:ticks]); end >= 0 {
+				code := strings.TrimSpace(rest[ticks : ticks+end])
+				builder.WriteString("<code>" + html.EscapeString(code) + "</code>")
+				i += 2*ticks + end
+				continue
+			}
+
+		case strings.HasPrefix(rest, "!["):
+			if label, target, n, ok := parseLink(rest[1:]); ok {
+				builder.WriteString(r.renderImage(label, target))
+				i += 1 + n
+				continue
+			}
+
+		case rest[0] == '[':
+			if label, target, n, ok := parseLink(rest); ok {
+				builder.WriteString(`<a href="` + html.EscapeString(r.linkTarget(target)) + `">` + r.renderInline(label) + `</a>`)
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if inner, n, ok := delimited(text, i, rest[:2]); ok {
+				builder.WriteString("<strong>" + r.renderInline(inner) + "</strong>")
+				i += n
+				continue
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if inner, n, ok := delimited(text, i, "~~"); ok {
+				builder.WriteString("<del>" + r.renderInline(inner) + "</del>")
+				i += n
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			if inner, n, ok := delimited(text, i, rest[:1]); ok {
+				builder.WriteString("<em>" + r.renderInline(inner) + "</em>")
+				i += n
+				continue
+			}
+		}
+
+		builder.WriteString(html.EscapeString(rest[:1]))
+		i++
+	}
+	return builder.String()
+}
+
+// delimited finds the span text[start:] wrapped in delim and returns its content and total length.
+// Underscores only count at word boundaries so snake_case identifiers stay intact.
+func delimited(text string, start int, delim string) (string, int, bool) {
+	if delim[0] == '_' && start > 0 && isWordChar(text[start-1]) {
+		return "", 0, false
+	}
+
+	rest := text[start+len(delim):]
+	end := strings.Index(rest, delim)
+	for end == 0 || (end > 0 && len(delim) == 1 && end+1 < len(rest) && rest[end+1] == delim[0]) {
+		// Skip empty spans and the first half of a doubled delimiter when looking for a single one
+		next := strings.Index(rest[end+2:], delim)
+		if next < 0 {
+			return "", 0, false
+		}
+		end += 2 + next
+	}
+	if end < 0 || strings.TrimSpace(rest[:end]) != rest[:end] {
+		return "", 0, false
+	}
+
+	closeAt := start + len(delim) + end + len(delim)
+	if delim[0] == '_' && closeAt < len(text) && isWordChar(text[closeAt]) {
+		return "", 0, false
+	}
+	return rest[:end], closeAt - start, true
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// parseLink parses "[label](target)" at the start of text and returns the consumed length
+func parseLink(text string) (string, string, int, bool) {
+	depth := 0
+	closeLabel := -1
+	for i := 0; i < len(text) && closeLabel < 0; i++ {
+		switch text[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				closeLabel = i
+			}
+		}
+	}
+	if closeLabel < 0 || closeLabel+1 >= len(text) || text[closeLabel+1] != '(' {
+		return "", "", 0, false
+	}
+
+	closeTarget := strings.IndexByte(text[closeLabel+2:], ')')
+	if closeTarget < 0 {
+		return "", "", 0, false
+	}
+	target := strings.TrimSpace(text[closeLabel+2 : closeLabel+2+closeTarget])
+	// Drop an optional link title: [label](url "title")
+	if space := strings.IndexAny(target, " \t"); space >= 0 {
+		target = target[:space]
+	}
+	target = strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+
+	return text[1:closeLabel], target, closeLabel + 3 + closeTarget, true
+}
+
+// renderImage renders remote images by URL and local images as page attachments
+func (r *storageRenderer) renderImage(alt, target string) string {
+	if r.plainHTML {
+		return `<img src="` + html.EscapeString(target) + `" alt="` + html.EscapeString(alt) + `" />`
+	}
+
+	var builder strings.Builder
+	builder.WriteString("<ac:image")
+	if alt != "" {
+		builder.WriteString(` ac:alt="` + html.EscapeString(alt) + `"`)
+	}
+	builder.WriteString(">")
+	if isRemoteURL(target) {
+		builder.WriteString(`<ri:url ri:value="` + html.EscapeString(target) + `" />`)
+	} else {
+		builder.WriteString(`<ri:attachment ri:filename="` + html.EscapeString(AttachmentName(target)) + `" />`)
+	}
+	builder.WriteString("</ac:image>")
+	return builder.String()
+}
+
+func isRemoteURL(target string) bool {
+	return strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://")
+}
@@ -0,0 +1,61 @@
+package publisher
+
+import "testing"
+
+func TestToStorage(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     string
+	}{
+		{
+			name:     "heading and paragraph",
+			markdown: "# Title\n\nSome **bold** and *em* text\nwrapped `code <b>`.",
+			want:     "<h1>Title</h1><p>Some <strong>bold</strong> and <em>em</em> text wrapped <code>code &lt;b&gt;</code>.</p>",
+		},
+		{
+			name:     "heading keeps trailing hash in text",
+			markdown: "## Using C#",
+			want:     "<h2>Using C#</h2>",
+		},
+		{
+			name:     "snake case is not emphasis",
+			markdown: "call some_func_name now",
+			want:     "<p>call some_func_name now</p>",
+		},
+		{
+			name:     "links and images",
+			markdown: "[docs](https://example.com/docs \"Docs\") ![logo](assets/logo.png) ![remote](https://example.com/a.png)",
+			want: `<p><a href="https://example.com/docs">docs</a> <ac:image ac:alt="logo"><ri:attachment ri:filename="logo.png" /></ac:image>` +
+				` <ac:image ac:alt="remote"><ri:url ri:value="https://example.com/a.png" /></ac:image></p>`,
+		},
+		{
+			name:     "code block",
+			markdown: "```go\nfmt.Println(\"]]>\")\n```",
+			want:     `<ac:structured-macro ac:name="code"><ac:parameter ac:name="language">go</ac:parameter><ac:plain-text-body><![CDATA[fmt.Println("]]]]><![CDATA[>")]]></ac:plain-text-body></ac:structured-macro>`,
+		},
+		{
+			name:     "nested lists",
+			markdown: "- one\n- two\n  1. a\n  2. b\n- three",
+			want:     "<ul><li>one</li><li>two<ol><li>a</li><li>b</li></ol></li><li>three</li></ul>",
+		},
+		{
+			name:     "table",
+			markdown: "| A | B |\n| --- | --- |\n| 1 | x \\| y |",
+			want:     "<table><tbody><tr><th>A</th><th>B</th></tr><tr><td>1</td><td>x | y</td></tr></tbody></table>",
+		},
+		{
+			name:     "quote and rule",
+			markdown: "> quoted\n> text\n\n---",
+			want:     "<blockquote><p>quoted text</p></blockquote><hr />",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToStorage(tt.markdown); got != tt.want {
+				t.Fatalf("ToStorage() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
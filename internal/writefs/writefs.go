@@ -0,0 +1,249 @@
+// Package writefs abstracts the filesystem that exported documents, images and manifests are written to.
+package writefs
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FS is a writable filesystem addressed with OS-style paths
+type FS interface {
+	ReadFile(name string) ([]byte, error)
+	Stat(name string) (fs.FileInfo, error)
+	// ReadDir lists the entries of a directory sorted by name
+	ReadDir(name string) ([]fs.DirEntry, error)
+	MkdirAll(path string, perm fs.FileMode) error
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+	// Create opens a file for streaming writes, truncating it if it exists. The file is complete once closed.
+	Create(name string, perm fs.FileMode) (io.WriteCloser, error)
+	Remove(name string) error
+}
+
+// WalkDir walks the file tree rooted at root in fsys like filepath.WalkDir, visiting entries in lexical order
+func WalkDir(fsys FS, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Stat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+func walkDir(fsys FS, path string, entry fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, entry, nil); err != nil || !entry.IsDir() {
+		if err == fs.SkipDir && entry.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		if err = fn(path, entry, err); err == fs.SkipDir {
+			err = nil
+		}
+		return err
+	}
+	for _, child := range entries {
+		if err := walkDir(fsys, filepath.Join(path, child.Name()), child, fn); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// OS writes to the local disk
+var OS FS = osFS{}
+
+type osFS struct{}
+
+func (osFS) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+func (osFS) Stat(name string) (fs.FileInfo, error) { return os.Stat(name) }
+
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+
+func (osFS) MkdirAll(path string, perm fs.FileMode) error { return os.MkdirAll(path, perm) }
+
+func (osFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (osFS) Create(name string, perm fs.FileMode) (io.WriteCloser, error) {
+	return os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+}
+
+func (osFS) Remove(name string) error { return os.Remove(name) }
+
+// MemFS keeps files in memory. Parent directories are created implicitly on write.
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string]memFile
+	dirs  map[string]bool
+}
+
+type memFile struct {
+	data    []byte
+	perm    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFS creates an empty in-memory filesystem
+func NewMemFS() *MemFS {
+	return &MemFS{files: make(map[string]memFile), dirs: make(map[string]bool)}
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	file, ok := m.files[filepath.Clean(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), file.data...), nil
+}
+
+func (m *MemFS) Stat(name string) (fs.FileInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name = filepath.Clean(name)
+	if file, ok := m.files[name]; ok {
+		return memFileInfo{name: filepath.Base(name), size: int64(len(file.data)), mode: file.perm, modTime: file.modTime}, nil
+	}
+	if m.dirs[name] {
+		return memFileInfo{name: filepath.Base(name), mode: fs.ModeDir | 0755}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *MemFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	name = filepath.Clean(name)
+	if name != "." && !m.dirs[name] {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	var entries []fs.DirEntry
+	for path, file := range m.files {
+		if filepath.Dir(path) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: filepath.Base(path), size: int64(len(file.data)), mode: file.perm, modTime: file.modTime}))
+		}
+	}
+	for dir := range m.dirs {
+		if filepath.Dir(dir) == name {
+			entries = append(entries, fs.FileInfoToDirEntry(memFileInfo{name: filepath.Base(dir), mode: fs.ModeDir | 0755}))
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
+
+func (m *MemFS) MkdirAll(path string, _ fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.addDirs(filepath.Clean(path))
+	return nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+	if m.dirs[name] {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	}
+	m.addDirs(filepath.Dir(name))
+	m.files[name] = memFile{data: append([]byte(nil), data...), perm: perm, modTime: time.Now()}
+	return nil
+}
+
+// Create buffers the written data and stores the file when it is closed
+func (m *MemFS) Create(name string, perm fs.FileMode) (io.WriteCloser, error) {
+	m.mu.RLock()
+	isDir := m.dirs[filepath.Clean(name)]
+	m.mu.RUnlock()
+	if isDir {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	}
+	return &memWriter{fs: m, name: name, perm: perm}, nil
+}
+
+// memWriter collects a file written through MemFS.Create
+type memWriter struct {
+	bytes.Buffer
+	fs   *MemFS
+	name string
+	perm fs.FileMode
+}
+
+func (w *memWriter) Close() error {
+	return w.fs.WriteFile(w.name, w.Bytes(), w.perm)
+}
+
+// Remove deletes a file; directories are left in place
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name = filepath.Clean(name)
+	if _, ok := m.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, name)
+	return nil
+}
+
+// Paths returns the paths of all files in sorted order
+func (m *MemFS) Paths() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	paths := make([]string, 0, len(m.files))
+	for path := range m.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// addDirs records dir and all of its parents; the caller must hold the lock
+func (m *MemFS) addDirs(dir string) {
+	for dir != "." && dir != string(filepath.Separator) && !m.dirs[dir] {
+		m.dirs[dir] = true
+		dir = filepath.Dir(dir)
+	}
+}
+
+// memFileInfo describes a MemFS file or directory
+type memFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) Mode() fs.FileMode  { return i.mode }
+func (i memFileInfo) ModTime() time.Time { return i.modTime }
+func (i memFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memFileInfo) Sys() any           { return nil }
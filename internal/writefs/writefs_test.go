@@ -0,0 +1,136 @@
+package writefs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMemFSWriteAndRead(t *testing.T) {
+	mem := NewMemFS()
+
+	path := filepath.Join("out", "docs", "page.md")
+	if err := mem.WriteFile(path, []byte("# Page"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	data, err := mem.ReadFile(filepath.Join("out", "docs", ".", "page.md"))
+	if err != nil || string(data) != "# Page" {
+		t.Fatalf("ReadFile() = %q, %v", data, err)
+	}
+
+	info, err := mem.Stat(filepath.Join("out", "docs"))
+	if err != nil || !info.IsDir() {
+		t.Fatalf("expected parent directory to exist, got %v, %v", info, err)
+	}
+
+	if _, err := mem.Stat("missing.md"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Stat() error = %v, want fs.ErrNotExist", err)
+	}
+
+	if got, want := mem.Paths(), []string{path}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Paths() = %v, want %v", got, want)
+	}
+}
+
+func TestCreateStreamsFile(t *testing.T) {
+	for name, fsys := range map[string]FS{"os": OS, "mem": NewMemFS()} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "image.png")
+			file, err := fsys.Create(path, 0644)
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			_, _ = io.WriteString(file, "chunk-1,")
+			_, _ = io.WriteString(file, "chunk-2")
+			if err := file.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			data, err := fsys.ReadFile(path)
+			if err != nil || string(data) != "chunk-1,chunk-2" {
+				t.Fatalf("ReadFile() = %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestMemFSRemove(t *testing.T) {
+	mem := NewMemFS()
+	path := filepath.Join("out", "page.md")
+	if err := mem.WriteFile(path, []byte("# Page"), 0644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := mem.Remove(path); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := mem.ReadFile(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("ReadFile() after Remove error = %v, want fs.ErrNotExist", err)
+	}
+	if err := mem.Remove(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("second Remove() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestMemFSRejectsWritingOverDirectory(t *testing.T) {
+	mem := NewMemFS()
+	if err := mem.MkdirAll(filepath.Join("out", "assets"), 0755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := mem.WriteFile(filepath.Join("out", "assets"), []byte("x"), 0644); err == nil {
+		t.Fatalf("expected writing over a directory to fail")
+	}
+}
+
+func TestMemFSReadDirAndWalkDir(t *testing.T) {
+	mem := NewMemFS()
+	for _, path := range []string{"out/b.md", "out/a/page.md", "out/a/assets/img.png", "other.md"} {
+		if err := mem.WriteFile(filepath.FromSlash(path), []byte("x"), 0644); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	entries, err := mem.ReadDir("out")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, fmt.Sprintf("%s:%v", entry.Name(), entry.IsDir()))
+	}
+	if got, want := strings.Join(names, ","), "a:true,b.md:false"; got != want {
+		t.Fatalf("ReadDir() = %s, want %s", got, want)
+	}
+	if _, err := mem.ReadDir("missing"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("ReadDir() of a missing directory error = %v, want fs.ErrNotExist", err)
+	}
+
+	var walked []string
+	err = WalkDir(mem, "out", func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Name() == "assets" {
+			return fs.SkipDir
+		}
+		walked = append(walked, filepath.ToSlash(path))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkDir() error = %v", err)
+	}
+	if got, want := strings.Join(walked, ","), "out,out/a,out/a/page.md,out/b.md"; got != want {
+		t.Fatalf("WalkDir() visited %s, want %s", got, want)
+	}
+
+	err = WalkDir(mem, "missing", func(_ string, _ fs.DirEntry, err error) error { return err })
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("WalkDir() of a missing root error = %v, want fs.ErrNotExist", err)
+	}
+}